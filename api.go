@@ -0,0 +1,238 @@
+package todoist
+
+import (
+	"context"
+	"io"
+	"net/http"
+)
+
+//go:generate go run ./internal/genmock -source api.go -out todoistmock/mocks.go
+
+// API is the interface implemented by Client. Code that talks to Todoist can
+// depend on API instead of *Client, so that it can be unit tested with the
+// mocks in the todoistmock package.
+type API interface {
+	// ProjectsAPI returns the service used for talking to projects.
+	ProjectsAPI() ProjectsAPI
+
+	// SectionsAPI returns the service used for talking to sections.
+	SectionsAPI() SectionsAPI
+
+	// TasksAPI returns the service used for talking to tasks.
+	TasksAPI() TasksAPI
+
+	// TemplatesAPI returns the service used for talking to template files.
+	TemplatesAPI() TemplatesAPI
+
+	// BackupsAPI returns the service used for talking to backups.
+	BackupsAPI() BackupsAPI
+
+	// UploadsAPI returns the service used for talking to file uploads.
+	UploadsAPI() UploadsAPI
+
+	// RESTTasksAPI returns the service used for talking to tasks through the
+	// REST API.
+	RESTTasksAPI() RESTTasksAPI
+
+	// RESTProjectsAPI returns the service used for talking to projects
+	// through the REST API.
+	RESTProjectsAPI() RESTProjectsAPI
+
+	// RESTSectionsAPI returns the service used for talking to sections
+	// through the REST API.
+	RESTSectionsAPI() RESTSectionsAPI
+
+	// RESTLabelsAPI returns the service used for talking to labels through
+	// the REST API.
+	RESTLabelsAPI() RESTLabelsAPI
+
+	// RESTCommentsAPI returns the service used for talking to comments
+	// through the REST API.
+	RESTCommentsAPI() RESTCommentsAPI
+
+	NewRequest(syncToken string, resourceTypes []string, commands []Command) (*http.Request, error)
+	Do(ctx context.Context, req *http.Request, v interface{}) (*http.Response, error)
+}
+
+// ProjectsAPI is the interface implemented by ProjectsService.
+type ProjectsAPI interface {
+	List(ctx context.Context, syncToken string) ([]Project, ReadResponse, error)
+	Add(ctx context.Context, syncToken string, addProject AddProject) ([]Project, CommandResponse, error)
+	Update(ctx context.Context, syncToken string, updateProject UpdateProject) ([]Project, CommandResponse, error)
+	Move(ctx context.Context, syncToken string, moveProject MoveProject) ([]Project, CommandResponse, error)
+	Delete(ctx context.Context, syncToken string, deleteProject DeleteProject) ([]Project, CommandResponse, error)
+	Archive(ctx context.Context, syncToken string, archiveProject ArchiveProject) ([]Project, CommandResponse, error)
+	Unarchive(ctx context.Context, syncToken string, unarchiveProject UnarchiveProject) ([]Project, CommandResponse, error)
+	Reorder(ctx context.Context, syncToken string, reorderProjects ReorderProjects) ([]Project, CommandResponse, error)
+	GetProjectInfo(ctx context.Context, syncToken string, ID ProjectID, allData bool) (ProjectInfo, error)
+	GetProjectData(ctx context.Context, syncToken string, projectID ProjectID) (ProjectData, error)
+	GetArchivedProjects(ctx context.Context, syncToken string, pagination *Pagination) ([]Project, error)
+	ImportTemplate(ctx context.Context, syncToken string, template *Template, target TemplateTarget) (ProjectID, CommandResponse, error)
+	Clone(ctx context.Context, projectID ProjectID, name string) (ProjectID, error)
+}
+
+// SectionsAPI is the interface implemented by SectionsService.
+type SectionsAPI interface {
+	List(ctx context.Context, syncToken string) ([]Section, ReadResponse, error)
+	Add(ctx context.Context, syncToken string, addSection AddSection) ([]Section, CommandResponse, error)
+	Update(ctx context.Context, syncToken string, updateSection UpdateSection) ([]Section, CommandResponse, error)
+	Move(ctx context.Context, syncToken string, moveSection MoveSection) ([]Section, CommandResponse, error)
+	Reorder(ctx context.Context, syncToken string, reorderSections ReorderSections) ([]Section, CommandResponse, error)
+	Delete(ctx context.Context, syncToken string, deleteSection DeleteSection) ([]Section, CommandResponse, error)
+	Archive(ctx context.Context, syncToken string, archiveSection ArchiveSection) ([]Section, CommandResponse, error)
+	Unarchive(ctx context.Context, syncToken string, unarchiveSection UnarchiveSection) ([]Section, CommandResponse, error)
+}
+
+// TasksAPI is the interface implemented by TasksService.
+type TasksAPI interface {
+	List(ctx context.Context, syncToken string) ([]Task, ReadResponse, error)
+	Add(ctx context.Context, syncToken string, addTask AddTask) ([]Task, CommandResponse, error)
+	Update(ctx context.Context, syncToken string, updateTask UpdateTask) ([]Task, CommandResponse, error)
+	Move(ctx context.Context, syncToken string, moveTask MoveTask) ([]Task, CommandResponse, error)
+	Delete(ctx context.Context, syncToken string, deleteTask DeleteTask) ([]Task, CommandResponse, error)
+	Complete(ctx context.Context, syncToken string, completeTask CompleteTask) ([]Task, CommandResponse, error)
+	Reorder(ctx context.Context, syncToken string, reorderTasks ReorderTasks) ([]Task, CommandResponse, error)
+	QuickAdd(ctx context.Context, text string, opts *QuickAddOptions) (Task, error)
+	ImportChecklist(ctx context.Context, syncToken string, checklist *Checklist) (CommandResponse, error)
+}
+
+// TemplatesAPI is the interface implemented by TemplatesService.
+type TemplatesAPI interface {
+	ImportIntoProject(ctx context.Context, projectID ProjectID, template *Template) (TemplateImport, error)
+	ImportFile(ctx context.Context, projectID ProjectID, fileName string, file io.Reader) (TemplateImport, error)
+	ExportAsFile(ctx context.Context, projectID ProjectID) (*Template, error)
+	ExportAsURL(ctx context.Context, projectID ProjectID) (TemplateFile, error)
+}
+
+// BackupsAPI is the interface implemented by BackupsService.
+type BackupsAPI interface {
+	List(ctx context.Context) ([]Backup, error)
+	Download(ctx context.Context, backup Backup, w io.Writer) error
+	Get(ctx context.Context, backup Backup) (*BackupArchive, error)
+}
+
+// UploadsAPI is the interface implemented by UploadsService.
+type UploadsAPI interface {
+	Add(ctx context.Context, fileName, contentType string, file io.Reader) (FileAttachment, error)
+	List(ctx context.Context, limit int) ([]FileAttachment, error)
+	Delete(ctx context.Context, fileURL string) error
+	AddToTask(ctx context.Context, syncToken string, taskID TaskID, content, fileName, contentType string, file io.Reader) (Comment, CommandResponse, error)
+}
+
+// RESTTasksAPI is the interface implemented by RESTTasksService.
+type RESTTasksAPI interface {
+	List(ctx context.Context, filter *TaskFilter) ([]Task, error)
+	Get(ctx context.Context, id TaskID) (Task, error)
+	Create(ctx context.Context, createTask CreateTaskParams) (Task, error)
+	Update(ctx context.Context, id TaskID, updateTask UpdateTaskParams) (Task, error)
+	Close(ctx context.Context, id TaskID) error
+	Reopen(ctx context.Context, id TaskID) error
+	Delete(ctx context.Context, id TaskID) error
+}
+
+// RESTProjectsAPI is the interface implemented by RESTProjectsService.
+type RESTProjectsAPI interface {
+	List(ctx context.Context) ([]Project, error)
+	Get(ctx context.Context, id ProjectID) (Project, error)
+	Create(ctx context.Context, createProject CreateProjectParams) (Project, error)
+	Update(ctx context.Context, id ProjectID, updateProject UpdateProjectParams) (Project, error)
+	Delete(ctx context.Context, id ProjectID) error
+}
+
+// RESTSectionsAPI is the interface implemented by RESTSectionsService.
+type RESTSectionsAPI interface {
+	List(ctx context.Context, projectID *ProjectID) ([]Section, error)
+	Get(ctx context.Context, id SectionID) (Section, error)
+	Create(ctx context.Context, createSection CreateSectionParams) (Section, error)
+	Update(ctx context.Context, id SectionID, updateSection UpdateSectionParams) (Section, error)
+	Delete(ctx context.Context, id SectionID) error
+}
+
+// RESTLabelsAPI is the interface implemented by RESTLabelsService.
+type RESTLabelsAPI interface {
+	List(ctx context.Context) ([]Label, error)
+	Get(ctx context.Context, id LabelID) (Label, error)
+	Create(ctx context.Context, createLabel CreateLabelParams) (Label, error)
+	Update(ctx context.Context, id LabelID, updateLabel UpdateLabelParams) (Label, error)
+	Delete(ctx context.Context, id LabelID) error
+}
+
+// RESTCommentsAPI is the interface implemented by RESTCommentsService.
+type RESTCommentsAPI interface {
+	List(ctx context.Context, filter CommentFilter) ([]Comment, error)
+	Get(ctx context.Context, id CommentID) (Comment, error)
+	Create(ctx context.Context, createComment CreateCommentParams) (Comment, error)
+	Update(ctx context.Context, id CommentID, updateComment UpdateCommentParams) (Comment, error)
+	Delete(ctx context.Context, id CommentID) error
+}
+
+var (
+	_ API          = (*Client)(nil)
+	_ ProjectsAPI  = (*ProjectsService)(nil)
+	_ SectionsAPI  = (*SectionsService)(nil)
+	_ TasksAPI     = (*TasksService)(nil)
+	_ TemplatesAPI = (*TemplatesService)(nil)
+	_ BackupsAPI   = (*BackupsService)(nil)
+	_ UploadsAPI   = (*UploadsService)(nil)
+
+	_ RESTTasksAPI    = (*RESTTasksService)(nil)
+	_ RESTProjectsAPI = (*RESTProjectsService)(nil)
+	_ RESTSectionsAPI = (*RESTSectionsService)(nil)
+	_ RESTLabelsAPI   = (*RESTLabelsService)(nil)
+	_ RESTCommentsAPI = (*RESTCommentsService)(nil)
+)
+
+// ProjectsAPI returns c.Projects as a ProjectsAPI.
+func (c *Client) ProjectsAPI() ProjectsAPI {
+	return c.Projects
+}
+
+// SectionsAPI returns c.Sections as a SectionsAPI.
+func (c *Client) SectionsAPI() SectionsAPI {
+	return c.Sections
+}
+
+// TasksAPI returns c.Tasks as a TasksAPI.
+func (c *Client) TasksAPI() TasksAPI {
+	return c.Tasks
+}
+
+// TemplatesAPI returns c.Templates as a TemplatesAPI.
+func (c *Client) TemplatesAPI() TemplatesAPI {
+	return c.Templates
+}
+
+// BackupsAPI returns c.Backups as a BackupsAPI.
+func (c *Client) BackupsAPI() BackupsAPI {
+	return c.Backups
+}
+
+// UploadsAPI returns c.Uploads as a UploadsAPI.
+func (c *Client) UploadsAPI() UploadsAPI {
+	return c.Uploads
+}
+
+// RESTTasksAPI returns c.REST.Tasks as a RESTTasksAPI.
+func (c *Client) RESTTasksAPI() RESTTasksAPI {
+	return c.REST.Tasks
+}
+
+// RESTProjectsAPI returns c.REST.Projects as a RESTProjectsAPI.
+func (c *Client) RESTProjectsAPI() RESTProjectsAPI {
+	return c.REST.Projects
+}
+
+// RESTSectionsAPI returns c.REST.Sections as a RESTSectionsAPI.
+func (c *Client) RESTSectionsAPI() RESTSectionsAPI {
+	return c.REST.Sections
+}
+
+// RESTLabelsAPI returns c.REST.Labels as a RESTLabelsAPI.
+func (c *Client) RESTLabelsAPI() RESTLabelsAPI {
+	return c.REST.Labels
+}
+
+// RESTCommentsAPI returns c.REST.Comments as a RESTCommentsAPI.
+func (c *Client) RESTCommentsAPI() RESTCommentsAPI {
+	return c.REST.Comments
+}
@@ -0,0 +1,394 @@
+package todoist
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// BackupsService handles the /backups endpoints of the Sync API. Todoist
+// backs up accounts automatically; each backup is a zip archive with a
+// template CSV file per project.
+//
+// Todoist API docs: https://developer.todoist.com/sync/v8/#backups
+type BackupsService service
+
+// Backup is an automatic backup of the account.
+type Backup struct {
+	// When the backup was made, as "YYYY-MM-DD HH:MM".
+	Version string `json:"version"`
+
+	// The URL the backup's zip archive can be downloaded from, with the account's token.
+	URL string `json:"url"`
+}
+
+// List the available backups.
+func (s *BackupsService) List(ctx context.Context) ([]Backup, error) {
+	s.client.Logln("---------- Backups.List")
+
+	req, err := s.client.newEndpointRequest("backups/get", url.Values{})
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []Backup
+	if _, err = s.client.Do(ctx, req, &backups); err != nil {
+		return nil, err
+	}
+
+	return backups, nil
+}
+
+// Download writes a backup's zip archive to w. Backups can only be downloaded
+// with the account's token, which is sent in the Authorization header.
+func (s *BackupsService) Download(ctx context.Context, backup Backup, w io.Writer) error {
+	s.client.Logln("---------- Backups.Download")
+
+	token, err := s.client.token()
+	if err != nil {
+		return err
+	}
+
+	s.client.Logf("%-15s %-30s\n", "url", backup.URL)
+	s.client.Logln()
+
+	req, err := http.NewRequest(http.MethodGet, backup.URL, nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+token)
+	if s.client.userAgent != "" {
+		req.Header.Set("User-Agent", s.client.userAgent)
+	}
+
+	_, err = s.client.Do(ctx, req, w)
+
+	return err
+}
+
+// Get downloads a backup and reads its projects. See ReadBackup.
+func (s *BackupsService) Get(ctx context.Context, backup Backup) (*BackupArchive, error) {
+	var buf bytes.Buffer
+	if err := s.Download(ctx, backup, &buf); err != nil {
+		return nil, err
+	}
+
+	return ReadBackup(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+}
+
+// BackupArchive holds the projects of a backup.
+type BackupArchive struct {
+	Projects []BackupProject
+}
+
+// BackupProject is a project read from a backup, as the same structs a sync
+// returns.
+//
+// Backups do not contain the IDs of sections, tasks and comments, so theirs
+// are made up from the name of the project's file and their row in it. They
+// link tasks to their sections and parents within the backup, and cannot be
+// used with the API.
+type BackupProject struct {
+	// The name of the project's CSV file in the archive.
+	FileName string
+
+	// The project, with its ID and name taken from the file name ("Name [ID].csv"). The ID is zero if the file name does not contain one.
+	Project Project
+
+	Sections     []Section
+	Tasks        []Task
+	Notes        []Comment
+	ProjectNotes []Comment
+
+	// The project's file, as read by ReadTemplate.
+	Template *Template
+}
+
+var backupFilePattern = regexp.MustCompile(`^(.*?)\s*\[(\d+)\]\.csv$`)
+
+// ReadBackup reads the projects of a backup's zip archive. Each CSV file in
+// the archive is read as a project template; other files are ignored.
+func ReadBackup(r io.ReaderAt, size int64) (*BackupArchive, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read backup")
+	}
+
+	b := &BackupArchive{}
+	for _, f := range zr.File {
+		name := path.Base(f.Name)
+		if f.FileInfo().IsDir() || !strings.EqualFold(path.Ext(name), ".csv") {
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to open %s", f.Name)
+		}
+		template, err := ReadTemplate(rc)
+		rc.Close()
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to read %s", f.Name)
+		}
+
+		project := Project{Name: strings.TrimSuffix(name, path.Ext(name))}
+		if m := backupFilePattern.FindStringSubmatch(name); m != nil {
+			project = Project{ID: ProjectID{NewID(m[2])}, Name: m[1]}
+		}
+
+		bp, err := newBackupProject(name, project, template)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to read %s", f.Name)
+		}
+		b.Projects = append(b.Projects, *bp)
+	}
+
+	return b, nil
+}
+
+// newBackupProject converts the template of a backed up project into the
+// structs a sync returns.
+func newBackupProject(fileName string, project Project, template *Template) (*BackupProject, error) {
+	bp := &BackupProject{FileName: fileName, Project: project, Template: template}
+	id := func(row int) ID {
+		return NewID(fmt.Sprintf("%s:%d", fileName, row))
+	}
+
+	var (
+		sectionID *SectionID
+		parents   []*Task // The last task at each level.
+		orders    = map[string]int{}
+	)
+	for i, row := range template.Rows {
+		switch row.Type {
+		case TemplateMeta:
+			if strings.HasPrefix(row.Content, "view_style=") {
+				bp.Project.ViewStyle = strings.TrimPrefix(row.Content, "view_style=")
+			}
+
+		case TemplateSection:
+			section := Section{ID: SectionID{id(i + 1)}, ProjectID: project.ID, Name: row.Content, SectionOrder: len(bp.Sections) + 1}
+			bp.Sections = append(bp.Sections, section)
+			sectionID = &section.ID
+			parents = parents[:0]
+
+		case TemplateTask:
+			indent := row.Indent
+			if indent == 0 {
+				indent = 1
+			}
+			if indent > len(parents)+1 {
+				return nil, errors.Errorf("row %d: indent %d has no parent task", i+1, indent)
+			}
+			parents = parents[:indent-1]
+
+			task := Task{
+				ID:          TaskID{id(i + 1)},
+				ProjectID:   project.ID,
+				Content:     row.Content,
+				Description: row.Description,
+				SectionID:   sectionID,
+				Priority:    1,
+			}
+			if row.Priority != 0 {
+				task.Priority = 5 - row.Priority
+			}
+			if row.Date != "" {
+				task.Due = &Due{String: row.Date, Lang: row.DateLang}
+				if row.Timezone != "" {
+					timezone := row.Timezone
+					task.Due.Timezone = &timezone
+				}
+			}
+			if m := templateUserPattern.FindStringSubmatch(row.Author); m != nil {
+				task.AddedByUID = &UserID{NewID(m[1])}
+			}
+			if m := templateUserPattern.FindStringSubmatch(row.Responsible); m != nil {
+				task.ResponsibleUID = &UserID{NewID(m[1])}
+			}
+
+			siblings := ""
+			if sectionID != nil {
+				siblings = sectionID.String()
+			}
+			if indent > 1 {
+				parentID := parents[indent-2].ID
+				task.ParentID = &parentID
+				siblings = parentID.String()
+			}
+			orders[siblings]++
+			task.ChildOrder = orders[siblings]
+
+			bp.Tasks = append(bp.Tasks, task)
+			parents = append(parents, &bp.Tasks[len(bp.Tasks)-1])
+
+		case TemplateNote:
+			note := Comment{ID: CommentID{id(i + 1)}, Content: row.Content}
+			if len(parents) == 0 {
+				projectID := project.ID
+				note.ProjectID = &projectID
+				bp.ProjectNotes = append(bp.ProjectNotes, note)
+				continue
+			}
+			taskID := parents[len(parents)-1].ID
+			note.TaskID = &taskID
+			bp.Notes = append(bp.Notes, note)
+		}
+	}
+
+	return bp, nil
+}
+
+// BackupDiffType is the kind of difference reported by a BackupDiff.
+type BackupDiffType string
+
+const (
+	// BackupTaskAdded reports a task that is not in the backup.
+	BackupTaskAdded BackupDiffType = "added"
+
+	// BackupTaskRemoved reports a task of the backup that is no longer
+	// active: it has been completed, deleted or changed beyond recognition.
+	BackupTaskRemoved BackupDiffType = "removed"
+
+	// BackupTaskChanged reports a task whose description, priority or due
+	// date differs from the backup.
+	BackupTaskChanged BackupDiffType = "changed"
+)
+
+// BackupDiff is a difference between a backup and the current state of the
+// account. Depending on the Type, one or both of Backup and Current are set.
+type BackupDiff struct {
+	Type BackupDiffType
+
+	// The name of the task's project.
+	Project string
+
+	// The task in the backup.
+	Backup *Task
+
+	// The task in the account.
+	Current *Task
+}
+
+// Diff compares the tasks of the backup with the current projects, sections
+// and tasks of the account (for example from a full sync). As backups do not
+// contain task IDs, tasks are matched by their project, section, parent tasks
+// and content. Backed up projects are matched by ID; only active tasks are
+// compared.
+func (b *BackupArchive) Diff(projects []Project, sections []Section, tasks []Task) []BackupDiff {
+	projectNames := map[string]string{}
+	for _, p := range projects {
+		if !bool(p.IsDeleted) && !bool(p.IsArchived) {
+			projectNames[p.ID.String()] = p.Name
+		}
+	}
+	sectionNames := map[string]string{}
+	for _, s := range sections {
+		sectionNames[s.ID.String()] = s.Name
+	}
+
+	// Tasks are keyed by the path to them; tasks with the same path are
+	// matched in order.
+	taskKeys := func(projectID string, sectionNames map[string]string, tasks []Task) []string {
+		byID := map[string]*Task{}
+		for i := range tasks {
+			byID[tasks[i].ID.String()] = &tasks[i]
+		}
+
+		keys := make([]string, len(tasks))
+		for i := range tasks {
+			task := &tasks[i]
+			var path []string
+			for t := task; t != nil; {
+				path = append([]string{t.Content}, path...)
+				if t.ParentID == nil {
+					break
+				}
+				t = byID[t.ParentID.String()]
+			}
+
+			section := ""
+			if task.SectionID != nil {
+				section = sectionNames[task.SectionID.String()]
+			}
+			pid := projectID
+			if pid == "" {
+				pid = task.ProjectID.String()
+			}
+			keys[i] = pid + "\x00" + section + "\x00" + strings.Join(path, "\x00")
+		}
+		return keys
+	}
+
+	var active []Task
+	for _, task := range tasks {
+		if !bool(task.Checked) && !bool(task.IsDeleted) {
+			if _, ok := projectNames[task.ProjectID.String()]; ok {
+				active = append(active, task)
+			}
+		}
+	}
+	current := map[string][]*Task{}
+	for i, key := range taskKeys("", sectionNames, active) {
+		current[key] = append(current[key], &active[i])
+	}
+	matched := map[*Task]bool{}
+
+	var diffs []BackupDiff
+	for _, p := range b.Projects {
+		backupSectionNames := map[string]string{}
+		for _, s := range p.Sections {
+			backupSectionNames[s.ID.String()] = s.Name
+		}
+
+		for i, key := range taskKeys(p.Project.ID.String(), backupSectionNames, p.Tasks) {
+			backupTask := &p.Tasks[i]
+
+			candidates := current[key]
+			if len(candidates) == 0 {
+				diffs = append(diffs, BackupDiff{Type: BackupTaskRemoved, Project: p.Project.Name, Backup: backupTask})
+				continue
+			}
+			currentTask := candidates[0]
+			current[key] = candidates[1:]
+			matched[currentTask] = true
+
+			if backupTask.Description != currentTask.Description || backupTask.Priority != currentTask.Priority || backupDueString(backupTask.Due) != backupDueString(currentTask.Due) {
+				diffs = append(diffs, BackupDiff{Type: BackupTaskChanged, Project: p.Project.Name, Backup: backupTask, Current: currentTask})
+			}
+		}
+	}
+
+	for i := range active {
+		if task := &active[i]; !matched[task] {
+			diffs = append(diffs, BackupDiff{Type: BackupTaskAdded, Project: projectNames[task.ProjectID.String()], Current: task})
+		}
+	}
+
+	sort.SliceStable(diffs, func(i, j int) bool { return diffs[i].Project < diffs[j].Project })
+
+	return diffs
+}
+
+// backupDueString returns the due date of a task the way backups store it.
+func backupDueString(due *Due) string {
+	if due == nil {
+		return ""
+	}
+	if due.String != "" {
+		return due.String
+	}
+
+	return due.Date
+}
@@ -0,0 +1,185 @@
+package todoist
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+)
+
+func Test_Backups(t *testing.T) {
+	ctx := context.Background()
+	client, srv := newFakeClient(t)
+	inbox := inboxProjectID(t, client)
+
+	var commands []Command
+	add := func(commandType, tempID string, args map[string]interface{}) {
+		commands = append(commands, Command{Type: commandType, Args: args, UUID: tempID + "-uuid", TempID: tempID})
+	}
+	temp := func(tempID string) ID { return NewTempID(tempID) }
+
+	add("project_add", "home", map[string]interface{}{"name": "Home"})
+	add("section_add", "garden", map[string]interface{}{"name": "Garden", "project_id": temp("home")})
+	add("item_add", "call", map[string]interface{}{"content": "Call mum", "project_id": inbox})
+	add("item_add", "rent", map[string]interface{}{"content": "Pay rent", "project_id": temp("home"), "priority": 4, "due": &Due{String: "every 1st"}})
+	add("item_add", "iban", map[string]interface{}{"content": "Find the IBAN", "project_id": temp("home"), "parent_id": temp("rent")})
+	add("item_add", "mow", map[string]interface{}{"content": "Mow the lawn", "project_id": temp("home"), "section_id": temp("garden")})
+
+	req, err := client.NewRequest("", nil, commands)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.Do(ctx, req, &CommandResponse{}); err != nil {
+		t.Fatal(err)
+	}
+
+	srv.AddBackup()
+	version := srv.AddBackup()
+
+	// Change the account after the backup.
+	commands = nil
+	add("item_complete", "", map[string]interface{}{"id": temp("call")})
+	add("item_update", "", map[string]interface{}{"id": temp("mow"), "priority": 3})
+	add("item_add", "water", map[string]interface{}{"content": "Water plants", "project_id": temp("home"), "section_id": temp("garden")})
+	req, err = client.NewRequest("", nil, commands)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.Do(ctx, req, &CommandResponse{}); err != nil {
+		t.Fatal(err)
+	}
+
+	backups, err := client.Backups.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 2 || backups[0].Version != version {
+		t.Fatalf("expected the newest of 2 backups first, received %+v", backups)
+	}
+
+	backup, err := client.Backups.Get(ctx, backups[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backup.Projects) != 2 {
+		t.Fatalf("expected 2 projects, received %d", len(backup.Projects))
+	}
+
+	inboxBackup, home := backup.Projects[0], backup.Projects[1]
+	if inboxBackup.Project.ID != inbox || inboxBackup.Project.Name != "Inbox" || inboxBackup.FileName != "Inbox ["+inbox.String()+"].csv" {
+		t.Errorf("unexpected project %+v from %s", inboxBackup.Project, inboxBackup.FileName)
+	}
+	if len(home.Sections) != 1 || home.Sections[0].Name != "Garden" || len(home.Tasks) != 3 {
+		t.Fatalf("unexpected project %+v", home)
+	}
+	rent, iban, mow := home.Tasks[0], home.Tasks[1], home.Tasks[2]
+	if rent.Content != "Pay rent" || rent.Priority != 4 || rent.Due == nil || rent.Due.String != "every 1st" || rent.ProjectID != home.Project.ID {
+		t.Errorf("unexpected task %+v", rent)
+	}
+	if iban.ParentID == nil || *iban.ParentID != rent.ID {
+		t.Errorf("expected a sub-task of %s, received %+v", rent.ID, iban)
+	}
+	if mow.SectionID == nil || *mow.SectionID != home.Sections[0].ID || mow.ChildOrder != 1 {
+		t.Errorf("expected a task in the section, received %+v", mow)
+	}
+
+	projects, _, err := client.Projects.List(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sections, _, err := client.Sections.List(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tasks, _, err := client.Tasks.List(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, d := range backup.Diff(projects, sections, tasks) {
+		var content string
+		if d.Backup != nil {
+			content = d.Backup.Content
+		} else {
+			content = d.Current.Content
+		}
+		got = append(got, string(d.Type)+" "+d.Project+" "+content)
+	}
+	want := []string{"changed Home Mow the lawn", "added Home Water plants", "removed Inbox Call mum"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected diffs %q, received %q", want, got)
+	}
+
+	// Backups cannot be downloaded without the account's token.
+	client.APIToken = "wrong"
+	if err = client.Backups.Download(ctx, backups[0], &bytes.Buffer{}); err == nil {
+		t.Error("expected an error downloading with the wrong token")
+	}
+}
+
+func Test_BackupArchive_Diff(t *testing.T) {
+	home := Project{ID: ProjectID{NewID("1")}, Name: "Home"}
+	work := Project{ID: ProjectID{NewID("2")}, Name: "Work"}
+	projects := []Project{home, work}
+	tasks := []Task{
+		{ID: TaskID{NewID("10")}, ProjectID: home.ID, Content: "Mow the lawn"},
+		{ID: TaskID{NewID("20")}, ProjectID: work.ID, Content: "File the report"},
+		{ID: TaskID{NewID("21")}, ProjectID: work.ID, Content: "Book a room", Priority: 2},
+	}
+
+	backup := &BackupArchive{Projects: []BackupProject{
+		{Project: home, Tasks: []Task{tasks[0]}},
+		{Project: work, Tasks: []Task{tasks[1], tasks[2]}},
+	}}
+	if diffs := backup.Diff(projects, nil, tasks); len(diffs) != 0 {
+		t.Errorf("expected no diffs for an identical backup, received %+v", diffs)
+	}
+
+	changed := append([]Task(nil), tasks...)
+	changed[2].Priority = 3
+	diffs := backup.Diff(projects, nil, changed)
+	if len(diffs) != 1 || diffs[0].Type != BackupTaskChanged || diffs[0].Project != "Work" || diffs[0].Current.Content != "Book a room" {
+		t.Errorf("expected the changed task in Work, received %+v", diffs)
+	}
+}
+
+func Test_ReadBackup(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	files := []struct{ name, content string }{
+		{"README.txt", "not a project"},
+		{"backup/Someday.csv", "TYPE,CONTENT,PRIORITY,INDENT\nmeta,view_style=board,,\ntask,Learn Go,1,1\nnote,Start with the tour,,\nnote,,,\n"},
+	}
+	for _, f := range files {
+		w, err := zw.Create(f.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _ = w.Write([]byte(f.content))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	backup, err := ReadBackup(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backup.Projects) != 1 {
+		t.Fatalf("expected 1 project, received %d", len(backup.Projects))
+	}
+
+	p := backup.Projects[0]
+	if p.Project.Name != "Someday" || !p.Project.ID.IsZero() || p.Project.ViewStyle != "board" {
+		t.Errorf("unexpected project %+v", p.Project)
+	}
+	if len(p.Tasks) != 1 || p.Tasks[0].Priority != 4 || len(p.Notes) != 2 || *p.Notes[0].TaskID != p.Tasks[0].ID {
+		t.Errorf("unexpected tasks %+v and notes %+v", p.Tasks, p.Notes)
+	}
+
+	if _, err = ReadBackup(bytes.NewReader([]byte("not a zip")), 9); err == nil {
+		t.Error("expected an error for a file that is not a zip archive")
+	}
+}
@@ -0,0 +1,363 @@
+package todoist
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Checklist is a plain outline of projects, sections and tasks, as read from
+// and written to Markdown (ReadMarkdown, Checklist.WriteMarkdown) and todo.txt
+// (ReadTodoTxt, Checklist.WriteTodoTxt). It refers to projects, sections and
+// labels by name, so that it can be moved between accounts.
+type Checklist struct {
+	Projects []*ChecklistProject
+}
+
+// ChecklistProject is a project of a checklist.
+type ChecklistProject struct {
+	// The name of the project, or "" for the Inbox.
+	Name string
+
+	// The top-level tasks of the project that are not in a section.
+	Tasks []*ChecklistTask
+
+	// The sections of the project.
+	Sections []*ChecklistSection
+}
+
+// ChecklistSection is a section of a checklist project.
+type ChecklistSection struct {
+	// The name of the section.
+	Name string
+
+	// The top-level tasks of the section.
+	Tasks []*ChecklistTask
+}
+
+// ChecklistTask is a task of a checklist.
+type ChecklistTask struct {
+	// The content of the task.
+	Content string
+
+	// The description of the task.
+	Description string
+
+	// Whether the task is completed.
+	Checked bool
+
+	// The priority of the task as shown in the apps, from 1 (p1, the highest) to 4 (p4), or 0 if not set. This is the reverse of Task.Priority.
+	Priority int
+
+	// The due string of the task, such as "every fri", for Todoist to parse.
+	Due string
+
+	// The due date of the task, as YYYY-MM-DD, when it is known.
+	DueDate string
+
+	// The names of the task's labels.
+	Labels []string
+
+	// The sub-tasks of the task.
+	Children []*ChecklistTask
+}
+
+// project returns the project of the checklist named name, adding it if there
+// is none.
+func (c *Checklist) project(name string) *ChecklistProject {
+	for _, p := range c.Projects {
+		if p.Name == name {
+			return p
+		}
+	}
+
+	p := &ChecklistProject{Name: name}
+	c.Projects = append(c.Projects, p)
+	return p
+}
+
+// section returns the section of the project named name, adding it if there
+// is none.
+func (p *ChecklistProject) section(name string) *ChecklistSection {
+	for _, s := range p.Sections {
+		if s.Name == name {
+			return s
+		}
+	}
+
+	s := &ChecklistSection{Name: name}
+	p.Sections = append(p.Sections, s)
+	return s
+}
+
+// NewChecklist builds a checklist from synced projects, sections and tasks.
+// Projects are listed depth-first in the order of the project list, with the
+// Inbox named "". Deleted and archived resources are left out; completed
+// tasks are kept, checked.
+//
+// Label IDs are resolved to names with labels. If labels is nil, the label IDs
+// are used as names as is, as the v9 API refers to labels by name.
+func NewChecklist(projects []Project, sections []Section, tasks []Task, labels []Label) *Checklist {
+	labelNames := map[string]string{}
+	for _, l := range labels {
+		labelNames[l.ID.String()] = l.Name
+	}
+
+	children := map[string][]*Task{}
+	roots := map[string][]*Task{}
+	active := map[string]bool{}
+	for i := range tasks {
+		if !bool(tasks[i].IsDeleted) {
+			active[tasks[i].ID.String()] = true
+		}
+	}
+	for i := range tasks {
+		task := &tasks[i]
+		switch {
+		case !active[task.ID.String()]:
+		case task.ParentID != nil && active[task.ParentID.String()]:
+			children[task.ParentID.String()] = append(children[task.ParentID.String()], task)
+		case task.SectionID != nil:
+			roots[task.SectionID.String()] = append(roots[task.SectionID.String()], task)
+		default:
+			roots[task.ProjectID.String()] = append(roots[task.ProjectID.String()], task)
+		}
+	}
+
+	var checklistTasks func(tasks []*Task) []*ChecklistTask
+	checklistTasks = func(tasks []*Task) []*ChecklistTask {
+		sort.SliceStable(tasks, func(i, j int) bool { return tasks[i].ChildOrder < tasks[j].ChildOrder })
+
+		var out []*ChecklistTask
+		for _, task := range tasks {
+			t := &ChecklistTask{
+				Content:     task.Content,
+				Description: task.Description,
+				Checked:     bool(task.Checked),
+				Children:    checklistTasks(children[task.ID.String()]),
+			}
+			if task.Priority >= 1 && task.Priority <= 4 {
+				t.Priority = 5 - task.Priority
+			}
+			if task.Due != nil {
+				t.Due = task.Due.String
+				if len(task.Due.Date) >= len("2006-01-02") {
+					t.DueDate = task.Due.Date[:len("2006-01-02")]
+				}
+			}
+			for _, id := range task.Labels {
+				name, ok := labelNames[id.String()]
+				if labels == nil {
+					name, ok = id.String(), true
+				}
+				if ok {
+					t.Labels = append(t.Labels, name)
+				}
+			}
+			out = append(out, t)
+		}
+		return out
+	}
+
+	projectSections := map[string][]*Section{}
+	for i := range sections {
+		s := &sections[i]
+		if !s.IsDeleted && !s.IsArchived {
+			projectSections[s.ProjectID.String()] = append(projectSections[s.ProjectID.String()], s)
+		}
+	}
+
+	subProjects := map[string][]*Project{}
+	var rootProjects []*Project
+	for i := range projects {
+		p := &projects[i]
+		switch {
+		case bool(p.IsDeleted) || bool(p.IsArchived):
+		case p.ParentID != nil:
+			subProjects[p.ParentID.String()] = append(subProjects[p.ParentID.String()], p)
+		default:
+			rootProjects = append(rootProjects, p)
+		}
+	}
+
+	c := &Checklist{}
+	var addProjects func(projects []*Project)
+	addProjects = func(projects []*Project) {
+		sort.SliceStable(projects, func(i, j int) bool { return projects[i].ChildOrder < projects[j].ChildOrder })
+		for _, p := range projects {
+			cp := &ChecklistProject{Name: p.Name, Tasks: checklistTasks(roots[p.ID.String()])}
+			if p.InboxProject != nil && *p.InboxProject {
+				cp.Name = ""
+			}
+
+			ss := projectSections[p.ID.String()]
+			sort.SliceStable(ss, func(i, j int) bool { return ss[i].SectionOrder < ss[j].SectionOrder })
+			for _, s := range ss {
+				cp.Sections = append(cp.Sections, &ChecklistSection{Name: s.Name, Tasks: checklistTasks(roots[s.ID.String()])})
+			}
+
+			c.Projects = append(c.Projects, cp)
+			addProjects(subProjects[p.ID.String()])
+		}
+	}
+	addProjects(rootProjects)
+
+	return c
+}
+
+// checklistLabel holds the arguments of a label_add command.
+type checklistLabel struct {
+	Name string `json:"name"`
+}
+
+// checklistItem holds the arguments of an item_complete command.
+type checklistItem struct {
+	ID TaskID `json:"id"`
+}
+
+// Commands returns the commands that add the checklist to an account with the
+// given projects and labels (for example from a full sync). The commands refer
+// to each other with temp IDs, so that they can be sent in a single request.
+//
+// Projects are matched to existing projects by name, ignoring case, and the
+// project named "" is the Inbox; other projects are added. Sections and tasks
+// are always added, and checked tasks are completed once added. Labels are
+// matched by name, and missing labels are added. If labels is nil, label names
+// are used as IDs as is, as the v9 API refers to labels by name.
+func (c *Checklist) Commands(projects []Project, labels []Label) ([]Command, error) {
+	var batch commandBatch
+
+	labelIDs := map[string]LabelID{}
+	for _, l := range labels {
+		if !bool(l.IsDeleted) {
+			labelIDs[strings.ToLower(l.Name)] = l.ID
+		}
+	}
+	labelID := func(name string) LabelID {
+		if labels == nil {
+			return LabelID{NewID(name)}
+		}
+		id, ok := labelIDs[strings.ToLower(name)]
+		if !ok {
+			id = LabelID{NewTempID(batch.add("label_add", checklistLabel{Name: name}))}
+			labelIDs[strings.ToLower(name)] = id
+		}
+		return id
+	}
+
+	var addTasks func(tasks []*ChecklistTask, projectID *ProjectID, sectionID *SectionID, parentID *TaskID) error
+	addTasks = func(tasks []*ChecklistTask, projectID *ProjectID, sectionID *SectionID, parentID *TaskID) error {
+		for _, task := range tasks {
+			if task.Content == "" {
+				return errors.New("task without content")
+			}
+
+			addTask := AddTask{
+				Content:     task.Content,
+				Description: task.Description,
+				ProjectID:   projectID,
+				SectionID:   sectionID,
+				ParentID:    parentID,
+			}
+			if task.Priority >= 1 && task.Priority <= 4 {
+				addTask.Priority = 5 - task.Priority
+			}
+			switch {
+			case task.Due != "":
+				addTask.Due = &Due{String: task.Due, Lang: "en"}
+			case task.DueDate != "":
+				addTask.Due = &Due{Date: task.DueDate}
+			}
+			for _, name := range task.Labels {
+				addTask.Labels = append(addTask.Labels, labelID(name))
+			}
+
+			id := TaskID{NewTempID(batch.add("item_add", addTask))}
+			if err := addTasks(task.Children, projectID, sectionID, &id); err != nil {
+				return err
+			}
+			if task.Checked {
+				batch.add("item_complete", checklistItem{ID: id})
+			}
+		}
+		return nil
+	}
+
+	for _, p := range c.Projects {
+		var projectID *ProjectID
+		for i := range projects {
+			existing := &projects[i]
+			if bool(existing.IsDeleted) || bool(existing.IsArchived) {
+				continue
+			}
+			isInbox := existing.InboxProject != nil && *existing.InboxProject
+			if (p.Name == "" && isInbox) || (p.Name != "" && strings.EqualFold(existing.Name, p.Name)) {
+				projectID = &existing.ID
+				break
+			}
+		}
+		if projectID == nil && p.Name != "" {
+			id := ProjectID{NewTempID(batch.add("project_add", AddProject{Name: p.Name}))}
+			projectID = &id
+		}
+
+		if err := addTasks(p.Tasks, projectID, nil, nil); err != nil {
+			return nil, errors.Wrapf(err, "project %q", p.Name)
+		}
+
+		for i, s := range p.Sections {
+			if projectID == nil {
+				return nil, errors.Errorf("section %q: no Inbox project to add it to", s.Name)
+			}
+			id := SectionID{NewTempID(batch.add("section_add", AddSection{Name: s.Name, ProjectID: *projectID, SectionOrder: i + 1}))}
+			if err := addTasks(s.Tasks, projectID, &id, nil); err != nil {
+				return nil, errors.Wrapf(err, "section %q", s.Name)
+			}
+		}
+	}
+
+	return batch.commands, nil
+}
+
+// ImportChecklist adds a checklist to the account in a single request, after
+// reading the account's projects and labels to match the checklist's names
+// against. See Checklist.Commands.
+func (s *TasksService) ImportChecklist(ctx context.Context, syncToken string, checklist *Checklist) (CommandResponse, error) {
+	s.client.Logln("---------- Tasks.ImportChecklist")
+
+	req, err := s.client.NewRequest("", []string{"projects", "labels"}, nil)
+	if err != nil {
+		return CommandResponse{}, err
+	}
+
+	var readResponse ReadResponse
+	if _, err = s.client.Do(ctx, req, &readResponse); err != nil {
+		return CommandResponse{}, err
+	}
+
+	labels := readResponse.Labels
+	if s.client.version == APIVersionV9 {
+		labels = nil
+	} else if labels == nil {
+		labels = []Label{}
+	}
+
+	commands, err := checklist.Commands(readResponse.Projects, labels)
+	if err != nil {
+		return CommandResponse{}, err
+	}
+
+	req, err = s.client.NewRequest(syncToken, []string{"projects", "sections", "items", "labels"}, commands)
+	if err != nil {
+		return CommandResponse{}, err
+	}
+
+	var commandResponse CommandResponse
+	if _, err = s.client.Do(ctx, req, &commandResponse); err != nil {
+		return commandResponse, err
+	}
+
+	return commandResponse, nil
+}
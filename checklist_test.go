@@ -0,0 +1,185 @@
+package todoist
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func Test_NewChecklist(t *testing.T) {
+	id := func(s string) ID { return NewID(s) }
+	inbox := true
+	inboxID, home, garden, archived := ProjectID{id("1")}, ProjectID{id("2")}, ProjectID{id("3")}, ProjectID{id("4")}
+	front := SectionID{id("20")}
+	parent := TaskID{id("101")}
+
+	projects := []Project{
+		{ID: home, Name: "Home", ChildOrder: 2},
+		{ID: garden, Name: "Garden", ParentID: &home},
+		{ID: inboxID, Name: "Inbox", InboxProject: &inbox, ChildOrder: 1},
+		{ID: archived, Name: "Old", IsArchived: true},
+	}
+	sections := []Section{
+		{ID: front, ProjectID: garden, Name: "Front"},
+		{ID: SectionID{id("21")}, ProjectID: garden, Name: "Deleted", IsDeleted: true},
+	}
+	tasks := []Task{
+		{ID: TaskID{id("102")}, ProjectID: home, ParentID: &parent, Content: "Find the IBAN", Checked: true, Priority: 1},
+		{ID: parent, ProjectID: home, Content: "Pay rent", Description: "Bank transfer", Priority: 4, Labels: []LabelID{{id("7")}, {id("8")}}, Due: &Due{Date: "2022-04-01", String: "every 1st", IsRecurring: true}},
+		{ID: TaskID{id("103")}, ProjectID: inboxID, Content: "Call mum", Priority: 3, Due: &Due{Date: "2022-03-10T18:00:00"}},
+		{ID: TaskID{id("104")}, ProjectID: garden, SectionID: &front, Content: "Mow the lawn"},
+		{ID: TaskID{id("105")}, ProjectID: home, Content: "Deleted", IsDeleted: true},
+		{ID: TaskID{id("106")}, ProjectID: archived, Content: "Archived"},
+	}
+	labels := []Label{{ID: LabelID{id("7")}, Name: "bills"}}
+
+	got := NewChecklist(projects, sections, tasks, labels)
+	want := &Checklist{Projects: []*ChecklistProject{
+		{Name: "", Tasks: []*ChecklistTask{
+			{Content: "Call mum", Priority: 2, DueDate: "2022-03-10"},
+		}},
+		{Name: "Home", Tasks: []*ChecklistTask{
+			{Content: "Pay rent", Description: "Bank transfer", Priority: 1, Due: "every 1st", DueDate: "2022-04-01", Labels: []string{"bills"}, Children: []*ChecklistTask{
+				{Content: "Find the IBAN", Checked: true, Priority: 4},
+			}},
+		}},
+		{Name: "Garden", Sections: []*ChecklistSection{
+			{Name: "Front", Tasks: []*ChecklistTask{{Content: "Mow the lawn"}}},
+		}},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected\n%s\nreceived\n%s", dumpChecklist(want), dumpChecklist(got))
+	}
+
+	// Without labels, label IDs are label names.
+	got = NewChecklist(projects, sections, tasks, nil)
+	if l := got.Projects[1].Tasks[0].Labels; !reflect.DeepEqual(l, []string{"7", "8"}) {
+		t.Errorf("expected label IDs as names, received %v", l)
+	}
+}
+
+func Test_Checklist_Commands(t *testing.T) {
+	inbox := true
+	inboxID, home := ProjectID{NewID("1")}, ProjectID{NewID("2")}
+	projects := []Project{
+		{ID: inboxID, Name: "Inbox", InboxProject: &inbox},
+		{ID: home, Name: "home"},
+	}
+	bills := LabelID{NewID("7")}
+	labels := []Label{{ID: bills, Name: "Bills"}}
+
+	c := &Checklist{Projects: []*ChecklistProject{
+		{Name: "", Tasks: []*ChecklistTask{{Content: "Call mum", DueDate: "2022-03-10"}}},
+		{Name: "Home", Tasks: []*ChecklistTask{
+			{Content: "Pay rent", Priority: 1, Due: "every 1st", Labels: []string{"bills", "monthly"}, Children: []*ChecklistTask{
+				{Content: "Find the IBAN", Checked: true, Labels: []string{"Monthly"}},
+			}},
+		}},
+		{Name: "Garden", Sections: []*ChecklistSection{{Name: "Front", Tasks: []*ChecklistTask{{Content: "Mow the lawn"}}}}},
+	}}
+
+	commands, err := c.Commands(projects, labels)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var types []string
+	for _, cmd := range commands {
+		types = append(types, cmd.Type)
+	}
+	wantTypes := []string{"item_add", "label_add", "item_add", "item_add", "item_complete", "project_add", "section_add", "item_add"}
+	if !reflect.DeepEqual(types, wantTypes) {
+		t.Fatalf("expected commands %v, received %v", wantTypes, types)
+	}
+
+	args := func(i int) map[string]interface{} {
+		b, err := json.Marshal(commands[i].Args)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var m map[string]interface{}
+		_ = json.Unmarshal(b, &m)
+		return m
+	}
+
+	monthly, payRent, findIBAN := commands[1].TempID, commands[2].TempID, commands[3].TempID
+	garden, front := commands[5].TempID, commands[6].TempID
+
+	checks := []struct {
+		i    int
+		want map[string]interface{}
+	}{
+		{0, map[string]interface{}{"content": "Call mum", "project_id": "1", "due": map[string]interface{}{"date": "2022-03-10"}}},
+		{1, map[string]interface{}{"name": "monthly"}},
+		{2, map[string]interface{}{"content": "Pay rent", "project_id": "2", "priority": float64(4), "labels": []interface{}{"7", monthly}, "due": map[string]interface{}{"string": "every 1st", "lang": "en"}}},
+		{3, map[string]interface{}{"content": "Find the IBAN", "project_id": "2", "parent_id": payRent, "labels": []interface{}{monthly}}},
+		{4, map[string]interface{}{"id": findIBAN}},
+		{5, map[string]interface{}{"name": "Garden"}},
+		{6, map[string]interface{}{"name": "Front", "project_id": garden, "section_order": float64(1)}},
+		{7, map[string]interface{}{"content": "Mow the lawn", "project_id": garden, "section_id": front}},
+	}
+	for _, c := range checks {
+		if got := args(c.i); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("command %d (%s): expected args %v, received %v", c.i, commands[c.i].Type, c.want, got)
+		}
+	}
+
+	// Without labels, label names are sent as is.
+	commands, err = c.Commands(projects, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if commands[1].Type != "item_add" || !reflect.DeepEqual(args(1)["labels"], []interface{}{"bills", "monthly"}) {
+		t.Errorf("expected label names, received %v", args(1))
+	}
+
+	c = &Checklist{Projects: []*ChecklistProject{{Name: "Home", Tasks: []*ChecklistTask{{Content: "Pay rent", Children: []*ChecklistTask{{}}}}}}}
+	if _, err = c.Commands(projects, labels); err == nil {
+		t.Error("expected an error for a task without content")
+	}
+}
+
+func Test_Tasks_ImportChecklist(t *testing.T) {
+	client, srv := newFakeClient(t)
+	ctx := context.Background()
+
+	c := &Checklist{Projects: []*ChecklistProject{
+		{Name: "", Tasks: []*ChecklistTask{{Content: "Call mum", Priority: 2}}},
+		{Name: "Home", Tasks: []*ChecklistTask{
+			{Content: "Pay rent", Children: []*ChecklistTask{{Content: "Find the IBAN", Checked: true}}},
+		}, Sections: []*ChecklistSection{{Name: "Garden", Tasks: []*ChecklistTask{{Content: "Mow the lawn"}}}}},
+	}}
+
+	before := len(srv.Requests())
+	if _, err := client.Tasks.ImportChecklist(ctx, "", c); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(srv.Requests()) - before; n != 2 {
+		t.Errorf("expected a read and a single write request, received %d requests", n)
+	}
+
+	projects, _, err := client.Projects.List(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sections, _, err := client.Sections.List(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tasks, _, err := client.Tasks.List(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Completed tasks are not returned by a full sync.
+	want := &Checklist{Projects: []*ChecklistProject{
+		{Name: "", Tasks: []*ChecklistTask{{Content: "Call mum", Priority: 2}}},
+		{Name: "Home", Tasks: []*ChecklistTask{{Content: "Pay rent", Priority: 4}}, Sections: []*ChecklistSection{
+			{Name: "Garden", Tasks: []*ChecklistTask{{Content: "Mow the lawn", Priority: 4}}},
+		}},
+	}}
+	if got := NewChecklist(projects, sections, tasks, nil); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected\n%s\nreceived\n%s", dumpChecklist(want), dumpChecklist(got))
+	}
+}
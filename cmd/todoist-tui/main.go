@@ -0,0 +1,217 @@
+// Command todoist-tui is a keyboard-driven terminal UI for Todoist. It shows
+// the project tree, and the sections and tasks of the selected project, from
+// a locally synced copy of the account.
+//
+// Tasks can be added (a), edited (e), completed (x), reordered (J and K) and
+// moved to another project (m). Changes show up right away and are sent to
+// Todoist in the background, in order; a change that fails is rolled back,
+// unless it may have been applied, in which case it is sent again before the
+// next sync.
+// The account is synced in the background every -interval, and on r.
+//
+// The API token is read from the -token flag or the TODOIST_API_TOKEN
+// environment variable. The synced state is saved on exit, so that the next
+// run starts with an incremental sync.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"time"
+
+	"github.com/ides15/todoist"
+)
+
+func main() {
+	token := flag.String("token", "", "API token; defaults to TODOIST_API_TOKEN")
+	statePath := flag.String("state", defaultStatePath(), "`file` the synced state is saved in; empty to not save it")
+	interval := flag.Duration("interval", 30*time.Second, "background sync interval")
+	apiVersion := flag.String("api-version", "", "Sync API `version`, v8 (the default) or v9")
+	flag.Parse()
+
+	if *token == "" {
+		*token = os.Getenv("TODOIST_API_TOKEN")
+	}
+	if *token == "" {
+		fmt.Fprintln(os.Stderr, "todoist-tui: no API token: set TODOIST_API_TOKEN or use -token")
+		os.Exit(2)
+	}
+
+	if err := start(*token, todoist.APIVersion(*apiVersion), *statePath, *interval); err != nil {
+		fmt.Fprintln(os.Stderr, "todoist-tui:", err)
+		os.Exit(1)
+	}
+}
+
+func defaultStatePath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(dir, "todoist", "tui-state.json")
+}
+
+func start(token string, apiVersion todoist.APIVersion, statePath string, interval time.Duration) error {
+	var opts []todoist.ClientOption
+	if apiVersion != "" {
+		opts = append(opts, todoist.WithAPIVersion(apiVersion))
+	}
+	client, err := todoist.NewClient(token, opts...)
+	if err != nil {
+		return err
+	}
+
+	store := loadState(statePath)
+
+	restore, err := makeRaw()
+	if err != nil {
+		return err
+	}
+	fmt.Print(enterScreen)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	err = run(ctx, newModel(client, store), os.Stdin, os.Stdout, terminalSize, interval)
+	stop()
+
+	fmt.Print(exitScreen)
+	restore()
+
+	if saveErr := saveState(statePath, store); err == nil {
+		err = saveErr
+	}
+
+	return err
+}
+
+// result is the result of an op sent by the worker.
+type result struct {
+	op  *op
+	err error
+}
+
+// run runs the event loop of the TUI until the user quits: it handles key
+// presses read from in, hands the queued ops to a worker sending them in
+// order, syncs every interval and redraws the screen after every event.
+// Quitting waits for the pending changes to be sent, unless ctrl+c is pressed
+// again.
+func run(ctx context.Context, m *model, in io.Reader, out io.Writer, size func() (int, int), interval time.Duration) error {
+	keys := make(chan string)
+	go readKeys(in, keys)
+
+	work := make(chan *op)
+	results := make(chan result)
+	stopped := make(chan struct{})
+	defer close(stopped)
+	go func() {
+		for {
+			select {
+			case o := <-work:
+				err := o.send(ctx)
+				select {
+				case results <- result{o, err}:
+				case <-stopped:
+					return
+				}
+			case <-stopped:
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	m.sync()
+
+	var waiting []*op
+	for {
+		waiting = append(waiting, m.takeQueue()...)
+		if m.quit && len(waiting) == 0 && len(m.pending) == 0 {
+			return nil
+		}
+
+		if err := draw(out, m.render(size())); err != nil {
+			return err
+		}
+
+		var next chan *op
+		var first *op
+		if len(waiting) > 0 {
+			next, first = work, waiting[0]
+		}
+		tick := ticker.C
+		if m.quit {
+			tick = nil
+		}
+
+		select {
+		case next <- first:
+			waiting = waiting[1:]
+		case key, ok := <-keys:
+			if !ok {
+				return nil
+			}
+			if m.quit && key == "ctrl+c" {
+				return nil
+			}
+			m.handleKey(key)
+			if m.quit && len(m.pending) > 0 {
+				m.status = "Sending changes… (ctrl+c to quit now)"
+			}
+		case r := <-results:
+			m.done(r.op, r.err)
+		case <-tick:
+			m.sync()
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// loadState returns the store saved at path, or an empty store if there is
+// none.
+func loadState(path string) *todoist.Store {
+	if path == "" {
+		return todoist.NewStore()
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return todoist.NewStore()
+	}
+	defer f.Close()
+
+	store, err := todoist.LoadStore(f)
+	if err != nil {
+		return todoist.NewStore()
+	}
+
+	return store
+}
+
+func saveState(path string, store *todoist.Store) error {
+	if path == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if err = store.Save(f); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
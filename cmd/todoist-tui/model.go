@@ -0,0 +1,564 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ides15/todoist"
+)
+
+type pane int
+
+const (
+	projectsPane pane = iota
+	tasksPane
+)
+
+type mode int
+
+const (
+	normalMode mode = iota
+	inputMode       // editing the text of a prompt
+	moveMode        // picking the project to move a task to
+)
+
+// op is a change made in the TUI, or a sync. Changes are applied to the
+// store with ApplyLocal right away, and pushed in the background; the store
+// rolls them back if they fail, and keeps them if they may have been applied,
+// in which case they are pushed again before the next sync.
+type op struct {
+	desc string
+
+	// The commands of a change, as applied to the store. nil for syncs.
+	commands []todoist.Command
+
+	// send pushes the change, or syncs the store.
+	send func(ctx context.Context) error
+}
+
+// model is the state of the TUI. It is only used from the goroutine running
+// the event loop; the ops it queues are sent by a single worker, in order, so
+// that commands using the temp IDs of earlier ones are sent after them.
+type model struct {
+	client *todoist.Client
+	store  *todoist.Store
+
+	pending []*op // queued or being sent
+	queue   []*op // not yet handed to the worker
+	unsent  []*op // failed, but still pending in the store
+	syncing bool
+
+	pane          pane
+	project       todoist.ProjectID // the project whose tasks are shown
+	projectCursor int
+	taskCursor    int
+
+	mode   mode
+	prompt string
+	input  []rune
+	submit func(text string)
+	moving todoist.TaskID // the task being moved in moveMode
+
+	status   string
+	lastSync time.Time
+	quit     bool
+}
+
+func newModel(client *todoist.Client, store *todoist.Store) *model {
+	return &model{client: client, store: store, pane: projectsPane}
+}
+
+// do applies the commands of a change to the store and queues them.
+func (m *model) do(desc string, commands ...todoist.Command) {
+	commands, err := m.store.ApplyLocal(commands...)
+	if err != nil {
+		m.status = fmt.Sprintf("%s failed: %v", desc, err)
+		return
+	}
+
+	m.push(desc, commands)
+}
+
+// push queues commands applied to the store, to be pushed.
+func (m *model) push(desc string, commands []todoist.Command) {
+	o := &op{desc: desc, commands: commands}
+	o.send = func(ctx context.Context) error {
+		syncErrs, err := m.store.Push(ctx, m.client, o.commands)
+		if err != nil {
+			return err
+		}
+		if len(syncErrs) > 0 {
+			return syncErrs[0]
+		}
+		return nil
+	}
+
+	m.pending = append(m.pending, o)
+	m.queue = append(m.queue, o)
+}
+
+// sync queues a sync, unless one is already queued or running.
+func (m *model) sync() {
+	if m.syncing {
+		return
+	}
+	m.syncing = true
+
+	for _, o := range m.unsent {
+		m.push(o.desc, o.commands)
+	}
+	m.unsent = nil
+
+	m.queue = append(m.queue, &op{desc: "sync", send: func(ctx context.Context) error {
+		_, err := m.store.Sync(ctx, m.client)
+		return err
+	}})
+}
+
+// takeQueue returns the queued ops, for the worker.
+func (m *model) takeQueue() []*op {
+	queue := m.queue
+	m.queue = nil
+
+	return queue
+}
+
+// done records the result of an op sent by the worker.
+func (m *model) done(o *op, err error) {
+	for i, p := range m.pending {
+		if p == o {
+			m.pending = append(m.pending[:i], m.pending[i+1:]...)
+			break
+		}
+	}
+
+	if o.commands == nil {
+		m.syncing = false
+		if err == nil {
+			m.lastSync = time.Now()
+		}
+	}
+
+	if err != nil {
+		m.status = fmt.Sprintf("%s failed: %v", o.desc, err)
+		if o.commands != nil && m.stillPending(o) {
+			m.unsent = append(m.unsent, o)
+			m.status += " (retrying at the next sync)"
+		}
+	}
+}
+
+// stillPending reports whether the commands of an op are still pending in
+// the store.
+func (m *model) stillPending(o *op) bool {
+	for _, c := range m.store.Pending() {
+		if c.UUID == o.commands[0].UUID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// state is the projects, sections and tasks of the store, with the pending
+// changes applied.
+type state struct {
+	projects []todoist.Project
+	sections []todoist.Section
+	tasks    []todoist.Task
+}
+
+func (m *model) state() *state {
+	return &state{
+		projects: m.store.Projects(),
+		sections: m.store.Sections(),
+		tasks:    m.store.Tasks(),
+	}
+}
+
+// task returns the task with the given ID.
+func (s *state) task(id todoist.TaskID) *todoist.Task {
+	for i := range s.tasks {
+		if s.tasks[i].ID == id {
+			return &s.tasks[i]
+		}
+	}
+
+	return nil
+}
+
+// siblings returns the tasks with the same project, section and parent as
+// task, in order.
+func (s *state) siblings(task todoist.Task) []todoist.Task {
+	var siblings []todoist.Task
+	for _, t := range s.tasks {
+		if t.ProjectID == task.ProjectID && equalSection(t.SectionID, task.SectionID) && equalParent(t.ParentID, task.ParentID) {
+			siblings = append(siblings, t)
+		}
+	}
+	sort.SliceStable(siblings, func(i, j int) bool { return siblings[i].ChildOrder < siblings[j].ChildOrder })
+
+	return siblings
+}
+
+func equalSection(a, b *todoist.SectionID) bool {
+	return (a == nil && b == nil) || (a != nil && b != nil && *a == *b)
+}
+
+func equalParent(a, b *todoist.TaskID) bool {
+	return (a == nil && b == nil) || (a != nil && b != nil && *a == *b)
+}
+
+// projectRow is a project in the projects pane.
+type projectRow struct {
+	project todoist.Project
+	depth   int
+}
+
+// taskRow is a row of the tasks pane: a section header or a task.
+type taskRow struct {
+	section *todoist.Section
+	task    *todoist.Task
+	depth   int
+}
+
+// view is what the TUI shows.
+type view struct {
+	projects []projectRow
+	tasks    []taskRow
+	project  todoist.Project
+}
+
+func (m *model) view() view {
+	s := m.state()
+
+	var v view
+	v.projects = projectTree(s.projects)
+
+	found := false
+	for _, row := range v.projects {
+		if row.project.ID == m.project {
+			v.project, found = row.project, true
+		}
+	}
+	if !found && len(v.projects) > 0 {
+		v.project = v.projects[0].project
+		m.project = v.project.ID
+	}
+
+	sections := map[todoist.SectionID]bool{}
+	for _, section := range s.sections {
+		sections[section.ID] = true
+	}
+
+	// Tasks without a section come first, then each section with its tasks.
+	children := map[string][]*todoist.Task{}
+	for i := range s.tasks {
+		t := &s.tasks[i]
+		if t.ProjectID != v.project.ID {
+			continue
+		}
+		key := ""
+		switch {
+		case t.ParentID != nil && s.task(*t.ParentID) != nil:
+			key = "task:" + t.ParentID.String()
+		case t.SectionID != nil && sections[*t.SectionID]:
+			key = "section:" + t.SectionID.String()
+		}
+		children[key] = append(children[key], t)
+	}
+	for _, tasks := range children {
+		sort.SliceStable(tasks, func(i, j int) bool { return tasks[i].ChildOrder < tasks[j].ChildOrder })
+	}
+
+	var walk func(key string, depth int)
+	walk = func(key string, depth int) {
+		for _, t := range children[key] {
+			v.tasks = append(v.tasks, taskRow{task: t, depth: depth})
+			walk("task:"+t.ID.String(), depth+1)
+		}
+	}
+	walk("", 0)
+	for i := range s.sections {
+		section := &s.sections[i]
+		if section.ProjectID != v.project.ID {
+			continue
+		}
+		v.tasks = append(v.tasks, taskRow{section: section})
+		walk("section:"+section.ID.String(), 1)
+	}
+
+	m.projectCursor = clamp(m.projectCursor, len(v.projects))
+	m.taskCursor = clamp(m.taskCursor, len(v.tasks))
+
+	return v
+}
+
+// projectTree orders projects depth-first, each followed by its sub-projects.
+func projectTree(projects []todoist.Project) []projectRow {
+	known := map[todoist.ProjectID]bool{}
+	for _, p := range projects {
+		known[p.ID] = true
+	}
+
+	children := map[todoist.ProjectID][]todoist.Project{}
+	var roots []todoist.Project
+	for _, p := range projects {
+		if p.ParentID == nil || !known[*p.ParentID] {
+			roots = append(roots, p)
+			continue
+		}
+		children[*p.ParentID] = append(children[*p.ParentID], p)
+	}
+
+	var rows []projectRow
+	var walk func([]todoist.Project, int)
+	walk = func(projects []todoist.Project, depth int) {
+		for _, p := range projects {
+			rows = append(rows, projectRow{project: p, depth: depth})
+			walk(children[p.ID], depth+1)
+		}
+	}
+	walk(roots, 0)
+
+	return rows
+}
+
+func clamp(cursor, n int) int {
+	if cursor >= n {
+		cursor = n - 1
+	}
+	if cursor < 0 {
+		cursor = 0
+	}
+
+	return cursor
+}
+
+// handleKey handles a key press, named as by readKeys.
+func (m *model) handleKey(key string) {
+	if key == "ctrl+c" {
+		m.quit = true
+		return
+	}
+
+	switch m.mode {
+	case inputMode:
+		m.handleInput(key)
+		return
+	case moveMode:
+		m.handleMove(key)
+		return
+	}
+
+	m.status = ""
+	v := m.view()
+
+	switch key {
+	case "q":
+		m.quit = true
+	case "tab", "left", "right", "h", "l":
+		if m.pane == projectsPane {
+			m.pane = tasksPane
+		} else {
+			m.pane = projectsPane
+		}
+	case "up", "k":
+		m.moveCursor(-1)
+	case "down", "j":
+		m.moveCursor(1)
+	case "enter":
+		if m.pane == projectsPane && len(v.projects) > 0 {
+			m.project = v.projects[m.projectCursor].project.ID
+			m.taskCursor = 0
+			m.pane = tasksPane
+		}
+	case "r":
+		m.sync()
+	case "a":
+		m.addTask(v)
+	case "e":
+		if task := m.selectedTask(v); task != nil {
+			m.editTask(*task)
+		}
+	case "x", " ":
+		if task := m.selectedTask(v); task != nil {
+			m.completeTask(*task)
+		}
+	case "K":
+		if task := m.selectedTask(v); task != nil {
+			m.reorderTask(*task, -1)
+		}
+	case "J":
+		if task := m.selectedTask(v); task != nil {
+			m.reorderTask(*task, 1)
+		}
+	case "m":
+		if task := m.selectedTask(v); task != nil {
+			m.mode = moveMode
+			m.moving = task.ID
+			m.pane = projectsPane
+			m.status = "Move to which project? (enter to move, esc to cancel)"
+		}
+	}
+}
+
+func (m *model) moveCursor(delta int) {
+	if m.pane == projectsPane {
+		m.projectCursor += delta
+	} else {
+		m.taskCursor += delta
+	}
+	m.view() // clamps the cursors
+}
+
+// selectedTask returns the task under the cursor of the tasks pane.
+func (m *model) selectedTask(v view) *todoist.Task {
+	if m.pane != tasksPane || m.taskCursor >= len(v.tasks) {
+		return nil
+	}
+
+	return v.tasks[m.taskCursor].task
+}
+
+// ask prompts for a line of text, which is passed to submit.
+func (m *model) ask(prompt, text string, submit func(string)) {
+	m.mode = inputMode
+	m.prompt = prompt
+	m.input = []rune(text)
+	m.submit = submit
+}
+
+func (m *model) handleInput(key string) {
+	switch key {
+	case "esc":
+		m.mode = normalMode
+	case "enter":
+		m.mode = normalMode
+		if text := strings.TrimSpace(string(m.input)); text != "" {
+			m.submit(text)
+		}
+	case "backspace":
+		if len(m.input) > 0 {
+			m.input = m.input[:len(m.input)-1]
+		}
+	default:
+		if r := []rune(key); len(r) == 1 {
+			m.input = append(m.input, r...)
+		}
+	}
+}
+
+func (m *model) handleMove(key string) {
+	switch key {
+	case "esc":
+		m.mode = normalMode
+		m.pane = tasksPane
+		m.status = ""
+	case "up", "k":
+		m.moveCursor(-1)
+	case "down", "j":
+		m.moveCursor(1)
+	case "enter":
+		v := m.view()
+		m.mode = normalMode
+		m.pane = tasksPane
+		m.status = ""
+		if len(v.projects) > 0 {
+			m.moveTask(m.moving, v.projects[m.projectCursor].project)
+		}
+	}
+}
+
+// addTask prompts for a new task, added after the task under the cursor, in
+// its section; or at the end of the section under the cursor.
+func (m *model) addTask(v view) {
+	projectID := v.project.ID
+	if projectID.IsZero() {
+		return
+	}
+
+	var sectionID *todoist.SectionID
+	if m.pane == tasksPane && m.taskCursor < len(v.tasks) {
+		row := v.tasks[m.taskCursor]
+		if row.section != nil {
+			sectionID = &row.section.ID
+		} else if row.task.ParentID == nil {
+			sectionID = row.task.SectionID
+		}
+	}
+
+	m.ask("Add task: ", "", func(content string) {
+		order := 0
+		for _, t := range m.state().siblings(todoist.Task{ProjectID: projectID, SectionID: sectionID}) {
+			if t.ChildOrder > order {
+				order = t.ChildOrder
+			}
+		}
+
+		m.do(fmt.Sprintf("adding %q", content), todoist.Command{
+			Type: "item_add",
+			Args: todoist.AddTask{Content: content, ProjectID: &projectID, SectionID: sectionID, ChildOrder: order + 1},
+		})
+	})
+}
+
+func (m *model) editTask(task todoist.Task) {
+	m.ask("Edit task: ", task.Content, func(content string) {
+		if content == task.Content {
+			return
+		}
+
+		m.do(fmt.Sprintf("editing %q", task.Content), todoist.Command{
+			Type: "item_update",
+			Args: todoist.UpdateTask{ID: task.ID, Content: content},
+		})
+	})
+}
+
+func (m *model) completeTask(task todoist.Task) {
+	m.do(fmt.Sprintf("completing %q", task.Content), todoist.Command{
+		Type: "item_complete",
+		Args: todoist.CompleteTask{ID: task.ID},
+	})
+}
+
+// reorderTask swaps a task with its previous (delta -1) or next (delta 1)
+// sibling. All the siblings are renumbered, as their orders may be equal.
+func (m *model) reorderTask(task todoist.Task, delta int) {
+	siblings := m.state().siblings(task)
+
+	i := 0
+	for i < len(siblings) && siblings[i].ID != task.ID {
+		i++
+	}
+	j := i + delta
+	if i == len(siblings) || j < 0 || j >= len(siblings) {
+		return
+	}
+	siblings[i], siblings[j] = siblings[j], siblings[i]
+
+	var reorder todoist.ReorderTasks
+	for k, t := range siblings {
+		reorder.Tasks = append(reorder.Tasks, todoist.ReorderedTask{ID: t.ID, ChildOrder: k + 1})
+	}
+	m.taskCursor += delta
+
+	m.do(fmt.Sprintf("reordering %q", task.Content), todoist.Command{Type: "item_reorder", Args: reorder})
+}
+
+// moveTask moves a task, with its sub-tasks, to the end of a project.
+func (m *model) moveTask(id todoist.TaskID, project todoist.Project) {
+	task, ok := m.store.Task(id)
+	if !ok || task.ProjectID == project.ID {
+		return
+	}
+
+	m.do(fmt.Sprintf("moving %q to %s", task.Content, project.Name), todoist.Command{
+		Type: "item_move",
+		Args: todoist.MoveTask{ID: task.ID, ProjectID: &project.ID},
+	})
+}
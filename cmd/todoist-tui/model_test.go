@@ -0,0 +1,250 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ides15/todoist"
+	"github.com/ides15/todoist/todoisttest"
+)
+
+// newTestModel returns a model synced with a fake server holding a Home
+// project with two tasks, and the inbox.
+func newTestModel(t *testing.T) (*model, *todoisttest.Server) {
+	t.Helper()
+
+	srv := todoisttest.NewServer()
+	t.Cleanup(srv.Close)
+
+	client, err := todoist.NewClient(srv.Token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.BaseURL, _ = url.Parse(srv.SyncURL())
+
+	ctx := context.Background()
+	commands := []todoist.Command{
+		{Type: "project_add", Args: map[string]interface{}{"name": "Home"}, UUID: "1", TempID: "home"},
+		{Type: "item_add", Args: map[string]interface{}{"content": "Mow the lawn", "project_id": todoist.NewTempID("home"), "child_order": 1}, UUID: "2", TempID: "mow"},
+		{Type: "item_add", Args: map[string]interface{}{"content": "Fix the fence", "project_id": todoist.NewTempID("home"), "child_order": 2}, UUID: "3", TempID: "fence"},
+	}
+	req, err := client.NewRequest("", nil, commands)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.Do(ctx, req, &todoist.CommandResponse{}); err != nil {
+		t.Fatal(err)
+	}
+
+	m := newModel(client, todoist.NewStore())
+	m.sync()
+	flush(t, m)
+
+	return m, srv
+}
+
+// flush sends the queued ops, as the worker does.
+func flush(t *testing.T, m *model) {
+	t.Helper()
+
+	for _, o := range m.takeQueue() {
+		m.done(o, o.send(context.Background()))
+	}
+}
+
+func keys(m *model, keys ...string) {
+	for _, key := range keys {
+		for _, k := range parseKeys([]byte(key)) {
+			m.handleKey(k)
+		}
+	}
+}
+
+// taskContents returns the contents of the tasks shown.
+func taskContents(m *model) []string {
+	var contents []string
+	for _, row := range m.view().tasks {
+		if row.task != nil {
+			contents = append(contents, row.task.Content)
+		}
+	}
+
+	return contents
+}
+
+func Test_Model(t *testing.T) {
+	m, _ := newTestModel(t)
+
+	// Open the Home project.
+	keys(m, "j", "\r")
+	if v := m.view(); v.project.Name != "Home" || m.pane != tasksPane {
+		t.Fatalf("expected the Home project to be open, received %s", v.project.Name)
+	}
+	if got := taskContents(m); !reflect.DeepEqual(got, []string{"Mow the lawn", "Fix the fence"}) {
+		t.Fatalf("unexpected tasks %q", got)
+	}
+
+	// Changes show up before they are sent.
+	keys(m, "a", "Paint the shed", "\r")
+	if got := taskContents(m); !reflect.DeepEqual(got, []string{"Mow the lawn", "Fix the fence", "Paint the shed"}) {
+		t.Fatalf("unexpected tasks %q", got)
+	}
+	if len(m.pending) != 1 || len(m.store.Pending()) != 1 || !m.view().tasks[2].task.ID.IsTemp() {
+		t.Fatalf("expected a pending task with a temp ID, received %+v", m.view().tasks[2].task)
+	}
+
+	// Ops on the new task are sent after it is added, with its temp ID.
+	keys(m, "j", "j", "e", "\x7f\x7f\x7f\x7fdoor", "\r")
+	keys(m, "K")
+	if got := taskContents(m); !reflect.DeepEqual(got, []string{"Mow the lawn", "Paint the door", "Fix the fence"}) {
+		t.Fatalf("unexpected tasks %q", got)
+	}
+	flush(t, m)
+	if len(m.pending) != 0 || m.status != "" {
+		t.Fatalf("expected the ops to be done, received %d pending and status %q", len(m.pending), m.status)
+	}
+	if got := taskContents(m); !reflect.DeepEqual(got, []string{"Mow the lawn", "Paint the door", "Fix the fence"}) {
+		t.Fatalf("unexpected synced tasks %q", got)
+	}
+	if m.view().tasks[1].task.ID.IsTemp() {
+		t.Error("expected the added task to have its real ID")
+	}
+
+	// Move the first task to the inbox.
+	keys(m, "k", "m", "k", "\r")
+	if got := taskContents(m); !reflect.DeepEqual(got, []string{"Paint the door", "Fix the fence"}) {
+		t.Fatalf("unexpected tasks %q", got)
+	}
+	keys(m, "x")
+	flush(t, m)
+	if got := taskContents(m); !reflect.DeepEqual(got, []string{"Fix the fence"}) {
+		t.Fatalf("unexpected tasks %q", got)
+	}
+
+	// A new sync shows the moved task in the inbox.
+	m.sync()
+	flush(t, m)
+	keys(m, "\t", "k", "\r")
+	if got := taskContents(m); !reflect.DeepEqual(got, []string{"Mow the lawn"}) {
+		t.Errorf("unexpected inbox tasks %q", got)
+	}
+}
+
+func Test_Model_Rollback(t *testing.T) {
+	m, srv := newTestModel(t)
+	keys(m, "j", "\r")
+
+	srv.InjectFault(todoisttest.Fault{Command: "item_update"})
+	keys(m, "e", " now", "\r", "x")
+	if got := taskContents(m); !reflect.DeepEqual(got, []string{"Fix the fence"}) {
+		t.Fatalf("unexpected tasks %q", got)
+	}
+	flush(t, m)
+
+	// The failed edit is rolled back, the completion is not.
+	if got := taskContents(m); !reflect.DeepEqual(got, []string{"Fix the fence"}) {
+		t.Errorf("unexpected tasks %q", got)
+	}
+	if !strings.Contains(m.status, `editing "Mow the lawn" failed`) {
+		t.Errorf("expected the failure in the status, received %q", m.status)
+	}
+
+	srv.ClearFaults()
+	srv.InjectFault(todoisttest.Fault{Command: "item_complete"})
+	keys(m, "x")
+	if got := taskContents(m); len(got) != 0 {
+		t.Fatalf("expected no tasks, received %q", got)
+	}
+	flush(t, m)
+	if got := taskContents(m); !reflect.DeepEqual(got, []string{"Fix the fence"}) {
+		t.Errorf("expected the completion to be rolled back, received %q", got)
+	}
+
+	// Changes that may have been applied are kept, and pushed again before
+	// the next sync.
+	srv.ClearFaults()
+	srv.InjectFault(todoisttest.Fault{Path: "sync", Times: 1})
+	keys(m, "e", " today", "\r")
+	flush(t, m)
+	if got := taskContents(m); !reflect.DeepEqual(got, []string{"Fix the fence today"}) || !strings.Contains(m.status, "retrying") {
+		t.Fatalf("expected the edit to be kept, received %q with status %q", got, m.status)
+	}
+	m.sync()
+	flush(t, m)
+	if got := taskContents(m); !reflect.DeepEqual(got, []string{"Fix the fence today"}) || len(m.store.Pending()) != 0 {
+		t.Errorf("expected the edit to be pushed, received %q", got)
+	}
+}
+
+func Test_Render(t *testing.T) {
+	m, _ := newTestModel(t)
+	keys(m, "j", "\r")
+
+	lines := m.render(60, 8)
+	if len(lines) != 8 {
+		t.Fatalf("expected 8 lines, received %d", len(lines))
+	}
+	screen := strings.Join(lines, "\n")
+	for _, want := range []string{" Projects", "Inbox", "• Home", "> [ ] Mow the lawn", "  [ ] Fix the fence", "a add"} {
+		if !strings.Contains(screen, want) {
+			t.Errorf("expected %q on the screen:\n%s", want, screen)
+		}
+	}
+
+	keys(m, "a", "Buy")
+	if last := lines[len(lines)-1]; !strings.HasPrefix(m.render(60, 8)[7], "Add task: Buy_") {
+		t.Errorf("expected the prompt, received %q", last)
+	}
+}
+
+func Test_ParseKeys(t *testing.T) {
+	got := parseKeys([]byte("a\x1b[A\x1b[B\x1b\r\t\x7f\x03é"))
+	want := []string{"a", "up", "down", "esc", "enter", "tab", "backspace", "ctrl+c", "é"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %q, received %q", want, got)
+	}
+}
+
+func Test_Run(t *testing.T) {
+	m, srv := newTestModel(t)
+
+	// Quitting waits for the added task to be sent.
+	r, w := io.Pipe()
+	go func() {
+		_, _ = io.WriteString(w, "j")
+		_, _ = io.WriteString(w, "\r")
+		_, _ = io.WriteString(w, "a")
+		_, _ = io.WriteString(w, "Rake leaves")
+		_, _ = io.WriteString(w, "\r")
+		_, _ = io.WriteString(w, "q")
+	}()
+
+	size := func() (int, int) { return 80, 24 }
+	done := make(chan error)
+	go func() { done <- run(context.Background(), m, r, io.Discard, size, time.Hour) }()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("run did not return")
+	}
+	w.Close()
+
+	var added bool
+	for _, req := range srv.Requests() {
+		for _, c := range req.Commands {
+			added = added || c == "item_add"
+		}
+	}
+	if !added || len(m.pending) != 0 {
+		t.Errorf("expected the task to be added before quitting, %d pending", len(m.pending))
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+const help = "tab switch  enter open  a add  e edit  x complete  J/K reorder  m move  r sync  q quit"
+
+// render draws the TUI as height lines of width columns.
+func (m *model) render(width, height int) []string {
+	v := m.view()
+
+	leftWidth := width / 3
+	if leftWidth > 30 {
+		leftWidth = 30
+	}
+	rightWidth := width - leftWidth - 1
+	body := height - 2
+	if body < 1 || rightWidth < 1 {
+		return []string{fit("Terminal too small", width)}
+	}
+
+	var left, right []string
+	for i, row := range v.projects {
+		marker := "  "
+		if i == m.projectCursor && (m.pane == projectsPane || m.mode == moveMode) {
+			marker = "> "
+		} else if row.project.ID == v.project.ID {
+			marker = "• "
+		}
+		left = append(left, marker+strings.Repeat("  ", row.depth)+row.project.Name)
+	}
+	for i, row := range v.tasks {
+		marker := "  "
+		if i == m.taskCursor && m.pane == tasksPane && m.mode != moveMode {
+			marker = "> "
+		}
+		if row.section != nil {
+			right = append(right, marker+"── "+row.section.Name+" ──")
+			continue
+		}
+
+		text := strings.Repeat("  ", row.depth) + "[ ] " + row.task.Content
+		if row.task.Priority > 1 {
+			text += fmt.Sprintf(" !%d", row.task.Priority)
+		}
+		if row.task.Due != nil {
+			due := row.task.Due.Date
+			if row.task.Due.String != "" {
+				due = row.task.Due.String
+			}
+			text += " (" + due + ")"
+		}
+		if row.task.ID.IsTemp() {
+			text += " …"
+		}
+		right = append(right, marker+text)
+	}
+
+	left = scroll(left, m.projectCursor, body-1)
+	right = scroll(right, m.taskCursor, body-1)
+
+	lines := []string{fit(" Projects", leftWidth) + "│" + fit(" "+v.project.Name, rightWidth)}
+	for i := 0; i < body-1; i++ {
+		var l, r string
+		if i < len(left) {
+			l = left[i]
+		}
+		if i < len(right) {
+			r = right[i]
+		}
+		lines = append(lines, fit(l, leftWidth)+"│"+fit(r, rightWidth))
+	}
+	lines = append(lines, strings.Repeat("─", leftWidth)+"┴"+strings.Repeat("─", rightWidth))
+
+	status := m.status
+	switch {
+	case m.mode == inputMode:
+		status = m.prompt + string(m.input) + "_"
+	case status == "":
+		status = help
+	}
+	if len(m.pending) > 0 && m.mode != inputMode {
+		status = fmt.Sprintf("[%d pending] %s", len(m.pending), status)
+	}
+	lines = append(lines, fit(status, width))
+
+	return lines
+}
+
+// scroll returns the window of n lines of rows that shows the cursor.
+func scroll(rows []string, cursor, n int) []string {
+	if n <= 0 {
+		return nil
+	}
+
+	start := 0
+	if cursor >= n {
+		start = cursor - n + 1
+	}
+	if start > len(rows) {
+		start = len(rows)
+	}
+	rows = rows[start:]
+	if len(rows) > n {
+		rows = rows[:n]
+	}
+
+	return rows
+}
+
+// fit truncates or pads s to width columns, counting a column per rune.
+func fit(s string, width int) string {
+	r := []rune(s)
+	if len(r) > width {
+		if width > 0 {
+			return string(r[:width-1]) + "…"
+		}
+		return ""
+	}
+
+	return s + strings.Repeat(" ", width-len(r))
+}
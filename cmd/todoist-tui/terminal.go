@@ -0,0 +1,150 @@
+package main
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+// ANSI escape sequences used to draw the screen.
+const (
+	enterScreen = "\x1b[?1049h\x1b[?25l" // switch to the alternate screen, hide the cursor
+	exitScreen  = "\x1b[?25h\x1b[?1049l" // show the cursor, switch back to the main screen
+	home        = "\x1b[H"
+	clearLine   = "\x1b[K"
+)
+
+// makeRaw puts the terminal of stdin in raw mode, without echo, and returns a
+// function restoring its previous mode. It relies on stty, so that no
+// platform-specific code is needed.
+func makeRaw() (func(), error) {
+	saved, err := stty("-g")
+	if err != nil {
+		return nil, err
+	}
+	if _, err = stty("raw", "-echo"); err != nil {
+		return nil, err
+	}
+
+	return func() { _, _ = stty(strings.TrimSpace(saved)) }, nil
+}
+
+// terminalSize returns the number of columns and rows of the terminal, or
+// 80x24 if they are unknown.
+func terminalSize() (int, int) {
+	out, err := stty("size")
+	if err == nil {
+		if fields := strings.Fields(out); len(fields) == 2 {
+			rows, err1 := strconv.Atoi(fields[0])
+			cols, err2 := strconv.Atoi(fields[1])
+			if err1 == nil && err2 == nil && rows > 0 && cols > 0 {
+				return cols, rows
+			}
+		}
+	}
+
+	return 80, 24
+}
+
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+
+	out, err := cmd.Output()
+	if err != nil {
+		return "", errors.Wrapf(err, "stty %s", strings.Join(args, " "))
+	}
+
+	return string(out), nil
+}
+
+// draw writes the lines of the screen, from its top left corner.
+func draw(w io.Writer, lines []string) error {
+	var b strings.Builder
+	b.WriteString(home)
+	for i, line := range lines {
+		if i > 0 {
+			b.WriteString("\r\n")
+		}
+		b.WriteString(line)
+		b.WriteString(clearLine)
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+// readKeys reads key presses from r and sends their names on keys, until r
+// fails, after which keys is closed.
+func readKeys(r io.Reader, keys chan<- string) {
+	defer close(keys)
+
+	buf := make([]byte, 256)
+	for {
+		n, err := r.Read(buf)
+		for _, key := range parseKeys(buf[:n]) {
+			keys <- key
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// parseKeys returns the names of the keys in a chunk of terminal input:
+// "up", "down", "left", "right", "enter", "tab", "backspace", "esc",
+// "ctrl+c", or the typed character.
+func parseKeys(b []byte) []string {
+	var keys []string
+	for len(b) > 0 {
+		switch c := b[0]; {
+		case c == 0x1b && len(b) >= 3 && (b[1] == '[' || b[1] == 'O'):
+			// A control sequence, ending with a byte in 0x40-0x7e.
+			end := 2
+			for end < len(b) && (b[end] < 0x40 || b[end] > 0x7e) {
+				end++
+			}
+			if end < len(b) {
+				switch b[end] {
+				case 'A':
+					keys = append(keys, "up")
+				case 'B':
+					keys = append(keys, "down")
+				case 'C':
+					keys = append(keys, "right")
+				case 'D':
+					keys = append(keys, "left")
+				}
+				end++
+			}
+			b = b[end:]
+			continue
+		case c == 0x1b:
+			keys = append(keys, "esc")
+		case c == 3:
+			keys = append(keys, "ctrl+c")
+		case c == '\r' || c == '\n':
+			keys = append(keys, "enter")
+		case c == '\t':
+			keys = append(keys, "tab")
+		case c == 127 || c == 8:
+			keys = append(keys, "backspace")
+		case c >= 0x20:
+			r, size := utf8.DecodeRune(b)
+			if r != utf8.RuneError {
+				keys = append(keys, string(r))
+			}
+			b = b[size:]
+			continue
+		}
+		b = b[1:]
+	}
+
+	return keys
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/ides15/todoist"
+	"github.com/pkg/errors"
+)
+
+// config is the configuration file of the command.
+type config struct {
+	// The API token, found in the Integrations tab of the Todoist user settings.
+	Token string `json:"token"`
+
+	// The version of the Sync API to use, "v8" (the default) or "v9".
+	APIVersion todoist.APIVersion `json:"api_version"`
+
+	// The default output format: "table" (the default), "json" or "csv".
+	Output string `json:"output"`
+
+	// The URL of the Sync API's sync endpoint, to use a proxy or a fake server.
+	SyncURL string `json:"sync_url"`
+}
+
+// loadConfig reads the configuration file at path. A missing file is an empty
+// configuration.
+func loadConfig(path string) (*config, error) {
+	cfg := &config{}
+	if path == "" {
+		return cfg, nil
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read configuration")
+	}
+
+	if err = json.Unmarshal(data, cfg); err != nil {
+		return nil, errors.Wrapf(err, "unable to read configuration %s", path)
+	}
+
+	return cfg, nil
+}
+
+// override applies the token and output format given on the command line or
+// in the environment, which take precedence over the configuration file's.
+func (cfg *config) override(flagToken, envToken, output string) {
+	switch {
+	case flagToken != "":
+		cfg.Token = flagToken
+	case envToken != "":
+		cfg.Token = envToken
+	}
+
+	if output != "" {
+		cfg.Output = output
+	}
+	if cfg.Output == "" {
+		cfg.Output = "table"
+	}
+}
+
+// newClient returns a client for the configuration.
+func (cfg *config) newClient() (*todoist.Client, error) {
+	var opts []todoist.ClientOption
+	if cfg.APIVersion != "" {
+		opts = append(opts, todoist.WithAPIVersion(cfg.APIVersion))
+	}
+
+	client, err := todoist.NewClient(cfg.Token, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.SyncURL != "" {
+		if client.BaseURL, err = url.Parse(cfg.SyncURL); err != nil {
+			return nil, errors.Wrap(err, "invalid sync_url")
+		}
+	}
+
+	return client, nil
+}
+
+// cache persists the store, and with it the sync token, between runs. Each
+// account and API version has its own file, named after a hash of the token,
+// so that switching tokens never mixes up accounts.
+type cache struct {
+	path string // empty if the cache is disabled
+}
+
+func newCache(dir string, cfg *config) *cache {
+	if dir == "" {
+		return &cache{}
+	}
+
+	sum := sha256.Sum256([]byte(string(cfg.APIVersion) + "\x00" + cfg.SyncURL + "\x00" + cfg.Token))
+
+	return &cache{path: filepath.Join(dir, "sync-"+hex.EncodeToString(sum[:8])+".json")}
+}
+
+// load returns the cached store. As the cache only saves requests, a missing
+// or unreadable cache file is an empty store, whose first sync is a full sync.
+func (c *cache) load() (*todoist.Store, error) {
+	if c.path == "" {
+		return todoist.NewStore(), nil
+	}
+
+	f, err := os.Open(c.path)
+	if os.IsNotExist(err) {
+		return todoist.NewStore(), nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read cache")
+	}
+	defer f.Close()
+
+	store, err := todoist.LoadStore(f)
+	if err != nil {
+		return todoist.NewStore(), nil
+	}
+
+	return store, nil
+}
+
+// save writes the store to the cache. The file is replaced atomically, so
+// that concurrent runs never read a partial file.
+func (c *cache) save(store *todoist.Store) error {
+	if c.path == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0700); err != nil {
+		return errors.Wrap(err, "unable to write cache")
+	}
+
+	f, err := ioutil.TempFile(filepath.Dir(c.path), ".sync-*")
+	if err != nil {
+		return errors.Wrap(err, "unable to write cache")
+	}
+	defer os.Remove(f.Name())
+
+	if err = store.Save(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return errors.Wrap(err, "unable to write cache")
+	}
+
+	return errors.Wrap(os.Rename(f.Name(), c.path), "unable to write cache")
+}
@@ -0,0 +1,248 @@
+// Command todoist manages the projects, sections and tasks of a Todoist
+// account from the command line.
+//
+// Usage:
+//
+//	todoist [flags] <projects|sections|tasks> <command> [arguments]
+//	todoist [flags] sync
+//
+// The commands are list, add, update, complete, move and delete; run
+// "todoist <resource> <command> -h" for their flags. Projects, sections and
+// tasks are given by ID, or by name (content, for tasks).
+//
+// The API token is read from the -token flag, the TODOIST_API_TOKEN
+// environment variable or the configuration file, in that order. The
+// configuration file is JSON:
+//
+//	{"token": "...", "api_version": "v9", "output": "table"}
+//
+// The synced projects, sections and tasks are cached with their sync token,
+// so that every run after the first only fetches what changed since the
+// previous one.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+
+	"github.com/ides15/todoist"
+	"github.com/pkg/errors"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	code := run(ctx, os.Args[1:], os.Stdout, os.Stderr)
+	stop()
+	os.Exit(code)
+}
+
+// app holds the state shared by the commands of a run.
+type app struct {
+	client *todoist.Client
+	store  *todoist.Store
+	output string
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// command is a command of a resource, run with its arguments.
+type command func(ctx context.Context, a *app, args []string) error
+
+var commands = map[string]map[string]command{
+	"projects": {
+		"list":     listProjects,
+		"add":      addProject,
+		"update":   updateProject,
+		"complete": completeProject,
+		"move":     moveProject,
+		"delete":   deleteProject,
+	},
+	"sections": {
+		"list":     listSections,
+		"add":      addSection,
+		"update":   updateSection,
+		"complete": completeSection,
+		"move":     moveSection,
+		"delete":   deleteSection,
+	},
+	"tasks": {
+		"list":     listTasks,
+		"add":      addTask,
+		"update":   updateTask,
+		"complete": completeTask,
+		"move":     moveTask,
+		"delete":   deleteTask,
+	},
+}
+
+const usage = `Usage:
+  todoist [flags] <projects|sections|tasks> <list|add|update|complete|move|delete> [arguments]
+  todoist [flags] sync
+
+Flags:
+`
+
+// run runs the command line args and returns the exit code: 0 on success, 1
+// on errors and 2 on usage errors.
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("todoist", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		fs.PrintDefaults()
+	}
+
+	configPath := fs.String("config", defaultPath(os.UserConfigDir, "config.json"), "configuration `file`")
+	cacheDir := fs.String("cache", defaultPath(os.UserCacheDir, ""), "`directory` of the sync cache; empty to disable it")
+	token := fs.String("token", "", "API token; overrides TODOIST_API_TOKEN and the configuration file")
+	output := fs.String("output", "", "output `format`: table, json or csv (default table)")
+	debug := fs.Bool("debug", false, "log API requests and responses")
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		fmt.Fprintln(stderr, "todoist:", err)
+		return 1
+	}
+	cfg.override(*token, os.Getenv("TODOIST_API_TOKEN"), *output)
+
+	if cfg.Output != "table" && cfg.Output != "json" && cfg.Output != "csv" {
+		fmt.Fprintf(stderr, "todoist: unknown output format %q\n", cfg.Output)
+		return 2
+	}
+
+	args = fs.Args()
+	var cmd command
+	switch {
+	case len(args) == 1 && args[0] == "sync":
+		cmd = syncStore
+	case len(args) >= 2 && commands[args[0]][args[1]] != nil:
+		cmd = commands[args[0]][args[1]]
+	default:
+		fs.Usage()
+		return 2
+	}
+
+	if cfg.Token == "" {
+		fmt.Fprintln(stderr, "todoist: no API token: set TODOIST_API_TOKEN or add a token to", *configPath)
+		return 1
+	}
+
+	client, err := cfg.newClient()
+	if err != nil {
+		fmt.Fprintln(stderr, "todoist:", err)
+		return 1
+	}
+	client.SetDebug(*debug)
+
+	c := newCache(*cacheDir, cfg)
+	store, err := c.load()
+	if err != nil {
+		fmt.Fprintln(stderr, "todoist:", err)
+		return 1
+	}
+
+	a := &app{client: client, store: store, output: cfg.Output, stdout: stdout, stderr: stderr}
+
+	var cmdArgs []string
+	if len(args) > 2 {
+		cmdArgs = args[2:]
+	}
+
+	err = cmd(ctx, a, cmdArgs)
+	if saveErr := c.save(store); saveErr != nil && err == nil {
+		err = saveErr
+	}
+
+	switch err.(type) {
+	case nil:
+		return 0
+	case flagsError:
+		return 2
+	case usageError:
+		fmt.Fprintln(stderr, "todoist:", err)
+		return 2
+	default:
+		fmt.Fprintln(stderr, "todoist:", err)
+		return 1
+	}
+}
+
+// defaultPath returns the path of name in the todoist directory of dir, or an
+// empty string if dir is unknown.
+func defaultPath(dir func() (string, error), name string) string {
+	d, err := dir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(d, "todoist", name)
+}
+
+// usageError is an error in the arguments of a command.
+type usageError string
+
+func (e usageError) Error() string {
+	return string(e)
+}
+
+// flagsError is an error parsing the flags of a command, which the flag
+// package has already reported.
+type flagsError struct {
+	err error
+}
+
+func (e flagsError) Error() string {
+	return e.err.Error()
+}
+
+// parse parses the flags of a command, followed by exactly nargs positional
+// arguments, which it returns. Once the arguments are valid, it syncs the
+// store, so that every command can look resources up by name and lists are
+// up to date; after the first run, the sync is incremental.
+func (a *app) parse(ctx context.Context, fs *flag.FlagSet, arguments string, args []string, nargs int) ([]string, error) {
+	fs.SetOutput(a.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(a.stderr, "Usage: todoist %s %s\n", fs.Name(), arguments)
+		fs.PrintDefaults()
+	}
+
+	// Allow the positional arguments before the flags, as in
+	// "todoist tasks update 123 -content ...".
+	var positional []string
+	for len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return nil, flagsError{err}
+	}
+	positional = append(positional, fs.Args()...)
+
+	if len(positional) != nargs {
+		fs.Usage()
+		return nil, flagsError{errors.New("wrong number of arguments")}
+	}
+
+	if _, err := a.store.Sync(ctx, a.client); err != nil {
+		return nil, err
+	}
+
+	return positional, nil
+}
+
+// syncStore syncs the store, without output.
+func syncStore(ctx context.Context, a *app, args []string) error {
+	_, err := a.parse(ctx, flag.NewFlagSet("sync", flag.ContinueOnError), "", args, 0)
+	return err
+}
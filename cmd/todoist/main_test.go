@@ -0,0 +1,172 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ides15/todoist"
+	"github.com/ides15/todoist/todoisttest"
+)
+
+// newTestRun returns a function running the command against a fake server,
+// with a configuration file pointing to it and a sync cache, and returning
+// the exit code and the output.
+func newTestRun(t *testing.T) (func(args ...string) (int, string, string), *todoisttest.Server) {
+	t.Helper()
+
+	srv := todoisttest.NewServer()
+	t.Cleanup(srv.Close)
+
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.json")
+	config := `{"sync_url": "` + srv.SyncURL() + `", "output": "csv"}`
+	if err := ioutil.WriteFile(configPath, []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("TODOIST_API_TOKEN", srv.Token)
+
+	return func(args ...string) (int, string, string) {
+		var stdout, stderr bytes.Buffer
+		args = append([]string{"-config", configPath, "-cache", filepath.Join(dir, "cache")}, args...)
+		code := run(context.Background(), args, &stdout, &stderr)
+		return code, stdout.String(), stderr.String()
+	}, srv
+}
+
+func readCSV(t *testing.T, s string) [][]string {
+	t.Helper()
+
+	records, err := csv.NewReader(strings.NewReader(s)).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV %q: %v", s, err)
+	}
+
+	return records
+}
+
+func Test_Run(t *testing.T) {
+	run, srv := newTestRun(t)
+
+	mustRun := func(args ...string) [][]string {
+		t.Helper()
+
+		code, stdout, stderr := run(args...)
+		if code != 0 {
+			t.Fatalf("%v: exit code %d: %s", args, code, stderr)
+		}
+		return readCSV(t, stdout)
+	}
+
+	mustRun("projects", "add", "Home", "-color", "berry_red")
+	mustRun("sections", "add", "Garden", "-project", "home")
+	records := mustRun("tasks", "add", "Mow the lawn", "-section", "Garden", "-priority", "4", "-due", "saturday")
+	if len(records) != 2 || !reflect.DeepEqual(records[1][1:], []string{"Mow the lawn", "Home", "Garden", "", "4", "saturday"}) {
+		t.Fatalf("unexpected output %q", records)
+	}
+	mow := records[1][0]
+	mustRun("tasks", "add", "Sharpen the blades", "-parent", mow)
+
+	records = mustRun("tasks", "list", "-project", "Home")
+	if len(records) != 3 || records[2][1] != "Sharpen the blades" || records[2][4] != "Mow the lawn" {
+		t.Fatalf("unexpected tasks %q", records)
+	}
+
+	records = mustRun("tasks", "update", mow, "-content", "Mow the grass")
+	if records[1][1] != "Mow the grass" {
+		t.Errorf("expected the updated task, received %q", records)
+	}
+
+	records = mustRun("tasks", "move", "Mow the grass", "-project", "Inbox")
+	if records[1][2] != "Inbox" || records[1][3] != "" {
+		t.Errorf("expected the task in the inbox, received %q", records)
+	}
+
+	// Completing a task completes its sub-tasks.
+	mustRun("tasks", "complete", "Mow the grass")
+	if records = mustRun("tasks", "list"); len(records) != 1 {
+		t.Errorf("expected no tasks, received %q", records)
+	}
+
+	mustRun("projects", "add", "Errands")
+	records = mustRun("projects", "move", "Errands", "-parent", "Home")
+	if records[1][2] != "Home" {
+		t.Errorf("expected a sub-project of Home, received %q", records)
+	}
+	records = mustRun("projects", "list")
+	var names []string
+	for _, r := range records[1:] {
+		names = append(names, r[1])
+	}
+	if !reflect.DeepEqual(names, []string{"Inbox", "Home", "Errands"}) {
+		t.Errorf("unexpected projects %q", names)
+	}
+
+	mustRun("sections", "delete", "Garden")
+	mustRun("projects", "complete", "Home")
+	if records = mustRun("projects", "list"); len(records) != 2 {
+		t.Errorf("expected the archived projects to be removed, received %q", records)
+	}
+
+	// Only the first run makes a full sync.
+	var syncs int
+	for _, r := range srv.Requests() {
+		if r.Path == "sync" && r.SyncToken == "*" {
+			syncs++
+		}
+	}
+	if syncs != 1 {
+		t.Errorf("expected 1 full sync, received %d", syncs)
+	}
+}
+
+func Test_Run_Output(t *testing.T) {
+	run, _ := newTestRun(t)
+
+	code, stdout, stderr := run("-output", "json", "tasks", "add", "Buy milk")
+	if code != 0 {
+		t.Fatalf("exit code %d: %s", code, stderr)
+	}
+	var tasks []todoist.Task
+	if err := json.Unmarshal([]byte(stdout), &tasks); err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 1 || tasks[0].Content != "Buy milk" {
+		t.Errorf("unexpected tasks %+v", tasks)
+	}
+
+	code, stdout, _ = run("-output", "table", "tasks", "list")
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if code != 0 || len(lines) != 2 || !strings.HasPrefix(lines[0], "ID ") || !strings.Contains(lines[1], "Buy milk") {
+		t.Errorf("unexpected table %q", stdout)
+	}
+}
+
+func Test_Run_Errors(t *testing.T) {
+	run, _ := newTestRun(t)
+
+	tests := []struct {
+		args []string
+		code int
+	}{
+		{[]string{}, 2},
+		{[]string{"tasks", "archive"}, 2},
+		{[]string{"-output", "xml", "tasks", "list"}, 2},
+		{[]string{"tasks", "add"}, 2},
+		{[]string{"tasks", "add", "Buy milk", "-priority", "5"}, 2},
+		{[]string{"tasks", "complete", "Unknown"}, 2},
+		{[]string{"tasks", "move", "Unknown", "-project", "Inbox", "-parent", "Other"}, 2},
+		{[]string{"-token", "wrong", "sync"}, 1},
+	}
+	for _, tt := range tests {
+		if code, _, _ := run(tt.args...); code != tt.code {
+			t.Errorf("%q: expected exit code %d, received %d", tt.args, tt.code, code)
+		}
+	}
+}
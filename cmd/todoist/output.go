@@ -0,0 +1,118 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ides15/todoist"
+	"github.com/pkg/errors"
+)
+
+// table is the output of a command: rows of columns for the table and CSV
+// formats, and the resources themselves for JSON.
+type table struct {
+	header []string
+	rows   [][]string
+	values interface{}
+}
+
+// write writes t in the output format of the run.
+func (a *app) write(t table) error {
+	switch a.output {
+	case "json":
+		enc := json.NewEncoder(a.stdout)
+		enc.SetIndent("", "  ")
+		return errors.Wrap(enc.Encode(t.values), "unable to write output")
+
+	case "csv":
+		w := csv.NewWriter(a.stdout)
+		_ = w.Write(t.header)
+		_ = w.WriteAll(t.rows)
+		return errors.Wrap(w.Error(), "unable to write output")
+
+	default:
+		w := tabwriter.NewWriter(a.stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, strings.Join(t.header, "\t"))
+		for _, row := range t.rows {
+			cells := make([]string, len(row))
+			for i, cell := range row {
+				cells[i] = strings.Join(strings.Fields(cell), " ")
+			}
+			fmt.Fprintln(w, strings.Join(cells, "\t"))
+		}
+		return errors.Wrap(w.Flush(), "unable to write output")
+	}
+}
+
+func (a *app) projectsTable(projects []todoist.Project) table {
+	t := table{header: []string{"ID", "NAME", "PARENT", "COLOR", "FAVORITE"}, values: projects}
+	for _, p := range projects {
+		t.rows = append(t.rows, []string{p.ID.String(), p.Name, a.projectName(p.ParentID), string(p.Color), yesNo(bool(p.IsFavorite))})
+	}
+
+	return t
+}
+
+func (a *app) sectionsTable(sections []todoist.Section) table {
+	t := table{header: []string{"ID", "NAME", "PROJECT"}, values: sections}
+	for _, s := range sections {
+		projectID := s.ProjectID
+		t.rows = append(t.rows, []string{s.ID.String(), s.Name, a.projectName(&projectID)})
+	}
+
+	return t
+}
+
+func (a *app) tasksTable(tasks []todoist.Task) table {
+	t := table{header: []string{"ID", "CONTENT", "PROJECT", "SECTION", "PARENT", "PRIORITY", "DUE"}, values: tasks}
+	for _, task := range tasks {
+		projectID := task.ProjectID
+
+		section := ""
+		if task.SectionID != nil {
+			if s, ok := a.store.Section(*task.SectionID); ok {
+				section = s.Name
+			}
+		}
+		parent := ""
+		if task.ParentID != nil {
+			if p, ok := a.store.Task(*task.ParentID); ok {
+				parent = p.Content
+			}
+		}
+		due := ""
+		if task.Due != nil {
+			due = task.Due.Date
+			if task.Due.String != "" {
+				due = task.Due.String
+			}
+		}
+
+		t.rows = append(t.rows, []string{task.ID.String(), task.Content, a.projectName(&projectID), section, parent, strconv.Itoa(task.Priority), due})
+	}
+
+	return t
+}
+
+// projectName returns the name of a project of the store, or an empty string
+// if id is nil or unknown.
+func (a *app) projectName(id *todoist.ProjectID) string {
+	if id == nil {
+		return ""
+	}
+	p, _ := a.store.Project(*id)
+
+	return p.Name
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+
+	return "no"
+}
@@ -0,0 +1,248 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/ides15/todoist"
+)
+
+// findProject returns the project of the store with the given ID or name.
+func (a *app) findProject(arg string) (todoist.Project, error) {
+	var matches []todoist.Project
+	for _, p := range a.store.Projects() {
+		if p.ID.String() == arg {
+			return p, nil
+		}
+		if strings.EqualFold(p.Name, arg) {
+			matches = append(matches, p)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return todoist.Project{}, usageError(fmt.Sprintf("no project %q", arg))
+	case 1:
+		return matches[0], nil
+	default:
+		return todoist.Project{}, usageError(fmt.Sprintf("%d projects are named %q, use an ID", len(matches), arg))
+	}
+}
+
+// inbox returns the user's inbox project.
+func (a *app) inbox() (todoist.Project, error) {
+	for _, p := range a.store.Projects() {
+		if p.InboxProject != nil && *p.InboxProject {
+			return p, nil
+		}
+	}
+
+	return todoist.Project{}, usageError("no inbox project")
+}
+
+// projectTree orders projects depth-first, each followed by its sub-projects.
+func projectTree(projects []todoist.Project) []todoist.Project {
+	children := map[todoist.ProjectID][]todoist.Project{}
+	known := map[todoist.ProjectID]bool{}
+	for _, p := range projects {
+		known[p.ID] = true
+	}
+
+	var roots []todoist.Project
+	for _, p := range projects {
+		if p.ParentID == nil || !known[*p.ParentID] {
+			roots = append(roots, p)
+			continue
+		}
+		children[*p.ParentID] = append(children[*p.ParentID], p)
+	}
+
+	tree := make([]todoist.Project, 0, len(projects))
+	var walk func([]todoist.Project)
+	walk = func(projects []todoist.Project) {
+		for _, p := range projects {
+			tree = append(tree, p)
+			walk(children[p.ID])
+		}
+	}
+	walk(roots)
+
+	return tree
+}
+
+// writeProject writes the project with the given ID, once a command response
+// has been applied to the store.
+func (a *app) writeProject(id todoist.ProjectID) error {
+	p, ok := a.store.Project(id)
+	if !ok {
+		return nil
+	}
+
+	return a.write(a.projectsTable([]todoist.Project{p}))
+}
+
+func listProjects(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("projects list", flag.ContinueOnError)
+	if _, err := a.parse(ctx, fs, "", args, 0); err != nil {
+		return err
+	}
+
+	return a.write(a.projectsTable(projectTree(a.store.Projects())))
+}
+
+func addProject(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("projects add", flag.ContinueOnError)
+	parent := fs.String("parent", "", "parent `project`")
+	color := fs.String("color", "", "`color` name, such as berry_red")
+	favorite := fs.Bool("favorite", false, "mark the project as a favorite")
+
+	args, err := a.parse(ctx, fs, "<name> [flags]", args, 1)
+	if err != nil {
+		return err
+	}
+
+	newProject := todoist.AddProject{Name: args[0], TempID: uuid.New().String()}
+	if *parent != "" {
+		p, err := a.findProject(*parent)
+		if err != nil {
+			return err
+		}
+		newProject.ParentID = &p.ID
+	}
+	if newProject.Color, err = colorID(*color); err != nil {
+		return err
+	}
+	if *favorite {
+		newProject.IsFavorite = 1
+	}
+
+	_, resp, err := a.client.Projects.Add(ctx, a.store.SyncToken(), newProject)
+	a.store.ApplyCommandResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	return a.writeProject(todoist.ProjectID{ID: resp.TempIDMapping[newProject.TempID]})
+}
+
+func updateProject(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("projects update", flag.ContinueOnError)
+	name := fs.String("name", "", "new `name`")
+	color := fs.String("color", "", "new `color` name, such as berry_red")
+	favorite := fs.Bool("favorite", false, "mark the project as a favorite")
+
+	args, err := a.parse(ctx, fs, "<project> [flags]", args, 1)
+	if err != nil {
+		return err
+	}
+
+	p, err := a.findProject(args[0])
+	if err != nil {
+		return err
+	}
+
+	change := todoist.UpdateProject{ID: p.ID, Name: *name}
+	if change.Color, err = colorID(*color); err != nil {
+		return err
+	}
+	if *favorite {
+		change.IsFavorite = 1
+	}
+	if change == (todoist.UpdateProject{ID: p.ID}) {
+		return usageError("nothing to update")
+	}
+
+	_, resp, err := a.client.Projects.Update(ctx, a.store.SyncToken(), change)
+	a.store.ApplyCommandResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	return a.writeProject(p.ID)
+}
+
+func completeProject(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("projects complete", flag.ContinueOnError)
+	args, err := a.parse(ctx, fs, "<project>", args, 1)
+	if err != nil {
+		return err
+	}
+
+	p, err := a.findProject(args[0])
+	if err != nil {
+		return err
+	}
+
+	// Completing a project archives it, with its sub-projects.
+	_, resp, err := a.client.Projects.Archive(ctx, a.store.SyncToken(), todoist.ArchiveProject{ID: p.ID})
+	a.store.ApplyCommandResponse(resp)
+
+	return err
+}
+
+func moveProject(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("projects move", flag.ContinueOnError)
+	parent := fs.String("parent", "", "new parent `project`; if empty, the project becomes a root project")
+
+	args, err := a.parse(ctx, fs, "<project> [flags]", args, 1)
+	if err != nil {
+		return err
+	}
+
+	p, err := a.findProject(args[0])
+	if err != nil {
+		return err
+	}
+
+	move := todoist.MoveProject{ID: p.ID}
+	if *parent != "" {
+		parentProject, err := a.findProject(*parent)
+		if err != nil {
+			return err
+		}
+		move.ParentID = &parentProject.ID
+	}
+
+	_, resp, err := a.client.Projects.Move(ctx, a.store.SyncToken(), move)
+	a.store.ApplyCommandResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	return a.writeProject(p.ID)
+}
+
+func deleteProject(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("projects delete", flag.ContinueOnError)
+	args, err := a.parse(ctx, fs, "<project>", args, 1)
+	if err != nil {
+		return err
+	}
+
+	p, err := a.findProject(args[0])
+	if err != nil {
+		return err
+	}
+
+	_, resp, err := a.client.Projects.Delete(ctx, a.store.SyncToken(), todoist.DeleteProject{ID: p.ID})
+	a.store.ApplyCommandResponse(resp)
+
+	return err
+}
+
+// colorID returns the v8 numeric ID of a color name, or 0 if name is empty.
+func colorID(name string) (int, error) {
+	if name == "" {
+		return 0, nil
+	}
+
+	id := todoist.Color(name).ID()
+	if id == 0 {
+		return 0, usageError(fmt.Sprintf("unknown color %q", name))
+	}
+
+	return id, nil
+}
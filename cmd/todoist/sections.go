@@ -0,0 +1,206 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/ides15/todoist"
+)
+
+// findSection returns the section of the store with the given ID or name. If
+// projectID is set, only the sections of that project are matched by name.
+func (a *app) findSection(arg string, projectID *todoist.ProjectID) (todoist.Section, error) {
+	var matches []todoist.Section
+	for _, s := range a.store.Sections() {
+		if s.ID.String() == arg {
+			return s, nil
+		}
+		if strings.EqualFold(s.Name, arg) && (projectID == nil || s.ProjectID == *projectID) {
+			matches = append(matches, s)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return todoist.Section{}, usageError(fmt.Sprintf("no section %q", arg))
+	case 1:
+		return matches[0], nil
+	default:
+		return todoist.Section{}, usageError(fmt.Sprintf("%d sections are named %q, use an ID or a project", len(matches), arg))
+	}
+}
+
+// optionalProject returns the ID of the project given by a -project flag, or
+// nil if the flag is empty.
+func (a *app) optionalProject(arg string) (*todoist.ProjectID, error) {
+	if arg == "" {
+		return nil, nil
+	}
+
+	p, err := a.findProject(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &p.ID, nil
+}
+
+// writeSection writes the section with the given ID, once a command response
+// has been applied to the store.
+func (a *app) writeSection(id todoist.SectionID) error {
+	s, ok := a.store.Section(id)
+	if !ok {
+		return nil
+	}
+
+	return a.write(a.sectionsTable([]todoist.Section{s}))
+}
+
+func listSections(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("sections list", flag.ContinueOnError)
+	project := fs.String("project", "", "only list the sections of this `project`")
+
+	if _, err := a.parse(ctx, fs, "[flags]", args, 0); err != nil {
+		return err
+	}
+
+	projectID, err := a.optionalProject(*project)
+	if err != nil {
+		return err
+	}
+
+	sections := []todoist.Section{}
+	for _, s := range a.store.Sections() {
+		if projectID == nil || s.ProjectID == *projectID {
+			sections = append(sections, s)
+		}
+	}
+
+	return a.write(a.sectionsTable(sections))
+}
+
+func addSection(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("sections add", flag.ContinueOnError)
+	project := fs.String("project", "", "`project` of the section (default the inbox)")
+
+	args, err := a.parse(ctx, fs, "<name> [flags]", args, 1)
+	if err != nil {
+		return err
+	}
+
+	var p todoist.Project
+	if *project != "" {
+		p, err = a.findProject(*project)
+	} else {
+		p, err = a.inbox()
+	}
+	if err != nil {
+		return err
+	}
+
+	newSection := todoist.AddSection{Name: args[0], ProjectID: p.ID, TempID: uuid.New().String()}
+
+	_, resp, err := a.client.Sections.Add(ctx, a.store.SyncToken(), newSection)
+	a.store.ApplyCommandResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	return a.writeSection(todoist.SectionID{ID: resp.TempIDMapping[newSection.TempID]})
+}
+
+func updateSection(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("sections update", flag.ContinueOnError)
+	name := fs.String("name", "", "new `name`")
+
+	args, err := a.parse(ctx, fs, "<section> [flags]", args, 1)
+	if err != nil {
+		return err
+	}
+
+	s, err := a.findSection(args[0], nil)
+	if err != nil {
+		return err
+	}
+	if *name == "" {
+		return usageError("nothing to update")
+	}
+
+	_, resp, err := a.client.Sections.Update(ctx, a.store.SyncToken(), todoist.UpdateSection{ID: s.ID, Name: *name, Collapsed: s.Collapsed})
+	a.store.ApplyCommandResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	return a.writeSection(s.ID)
+}
+
+func completeSection(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("sections complete", flag.ContinueOnError)
+	args, err := a.parse(ctx, fs, "<section>", args, 1)
+	if err != nil {
+		return err
+	}
+
+	s, err := a.findSection(args[0], nil)
+	if err != nil {
+		return err
+	}
+
+	// Completing a section archives it, completing its tasks.
+	_, resp, err := a.client.Sections.Archive(ctx, a.store.SyncToken(), todoist.ArchiveSection{ID: s.ID})
+	a.store.ApplyCommandResponse(resp)
+
+	return err
+}
+
+func moveSection(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("sections move", flag.ContinueOnError)
+	project := fs.String("project", "", "destination `project`")
+
+	args, err := a.parse(ctx, fs, "<section> -project <project>", args, 1)
+	if err != nil {
+		return err
+	}
+
+	s, err := a.findSection(args[0], nil)
+	if err != nil {
+		return err
+	}
+	if *project == "" {
+		return usageError("missing destination project")
+	}
+	p, err := a.findProject(*project)
+	if err != nil {
+		return err
+	}
+
+	_, resp, err := a.client.Sections.Move(ctx, a.store.SyncToken(), todoist.MoveSection{ID: s.ID, ProjectID: p.ID})
+	a.store.ApplyCommandResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	return a.writeSection(s.ID)
+}
+
+func deleteSection(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("sections delete", flag.ContinueOnError)
+	args, err := a.parse(ctx, fs, "<section>", args, 1)
+	if err != nil {
+		return err
+	}
+
+	s, err := a.findSection(args[0], nil)
+	if err != nil {
+		return err
+	}
+
+	_, resp, err := a.client.Sections.Delete(ctx, a.store.SyncToken(), todoist.DeleteSection{ID: s.ID})
+	a.store.ApplyCommandResponse(resp)
+
+	return err
+}
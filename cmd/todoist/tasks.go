@@ -0,0 +1,267 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/ides15/todoist"
+)
+
+// findTask returns the task of the store with the given ID or content.
+func (a *app) findTask(arg string) (todoist.Task, error) {
+	var matches []todoist.Task
+	for _, task := range a.store.Tasks() {
+		if task.ID.String() == arg {
+			return task, nil
+		}
+		if strings.EqualFold(task.Content, arg) {
+			matches = append(matches, task)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return todoist.Task{}, usageError(fmt.Sprintf("no task %q", arg))
+	case 1:
+		return matches[0], nil
+	default:
+		return todoist.Task{}, usageError(fmt.Sprintf("%d tasks are named %q, use an ID", len(matches), arg))
+	}
+}
+
+// writeTask writes the task with the given ID, once a command response has
+// been applied to the store.
+func (a *app) writeTask(id todoist.TaskID) error {
+	task, ok := a.store.Task(id)
+	if !ok {
+		return nil
+	}
+
+	return a.write(a.tasksTable([]todoist.Task{task}))
+}
+
+// checkPriority checks a -priority flag: 0 if unset, or 1 (natural) to 4
+// (very urgent).
+func checkPriority(priority int) error {
+	if priority < 0 || priority > 4 {
+		return usageError(fmt.Sprintf("invalid priority %d, expected 1 (natural) to 4 (very urgent)", priority))
+	}
+
+	return nil
+}
+
+func listTasks(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("tasks list", flag.ContinueOnError)
+	project := fs.String("project", "", "only list the tasks of this `project`")
+	section := fs.String("section", "", "only list the tasks of this `section`")
+
+	if _, err := a.parse(ctx, fs, "[flags]", args, 0); err != nil {
+		return err
+	}
+
+	projectID, err := a.optionalProject(*project)
+	if err != nil {
+		return err
+	}
+	var sectionID *todoist.SectionID
+	if *section != "" {
+		s, err := a.findSection(*section, projectID)
+		if err != nil {
+			return err
+		}
+		sectionID = &s.ID
+	}
+
+	tasks := []todoist.Task{}
+	for _, task := range a.store.Tasks() {
+		if projectID != nil && task.ProjectID != *projectID {
+			continue
+		}
+		if sectionID != nil && (task.SectionID == nil || *task.SectionID != *sectionID) {
+			continue
+		}
+		tasks = append(tasks, task)
+	}
+
+	return a.write(a.tasksTable(tasks))
+}
+
+func addTask(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("tasks add", flag.ContinueOnError)
+	description := fs.String("description", "", "`description` of the task")
+	project := fs.String("project", "", "`project` of the task (default the inbox)")
+	section := fs.String("section", "", "`section` of the task")
+	parent := fs.String("parent", "", "parent `task`")
+	priority := fs.Int("priority", 0, "`priority`, from 1 (natural) to 4 (very urgent)")
+	due := fs.String("due", "", "due date, such as \"tomorrow\" or \"every monday\"")
+
+	args, err := a.parse(ctx, fs, "<content> [flags]", args, 1)
+	if err != nil {
+		return err
+	}
+	if err = checkPriority(*priority); err != nil {
+		return err
+	}
+
+	newTask := todoist.AddTask{Content: args[0], Description: *description, Priority: *priority, TempID: uuid.New().String()}
+	if newTask.ProjectID, err = a.optionalProject(*project); err != nil {
+		return err
+	}
+	if *section != "" {
+		s, err := a.findSection(*section, newTask.ProjectID)
+		if err != nil {
+			return err
+		}
+		newTask.SectionID = &s.ID
+		newTask.ProjectID = &s.ProjectID
+	}
+	if *parent != "" {
+		p, err := a.findTask(*parent)
+		if err != nil {
+			return err
+		}
+		newTask.ParentID = &p.ID
+		newTask.ProjectID = &p.ProjectID
+		newTask.SectionID = p.SectionID
+	}
+	if *due != "" {
+		newTask.Due = &todoist.Due{String: *due}
+	}
+
+	_, resp, err := a.client.Tasks.Add(ctx, a.store.SyncToken(), newTask)
+	a.store.ApplyCommandResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	return a.writeTask(todoist.TaskID{ID: resp.TempIDMapping[newTask.TempID]})
+}
+
+func updateTask(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("tasks update", flag.ContinueOnError)
+	content := fs.String("content", "", "new `content`")
+	description := fs.String("description", "", "new `description`")
+	priority := fs.Int("priority", 0, "new `priority`, from 1 (natural) to 4 (very urgent)")
+	due := fs.String("due", "", "new due date, such as \"tomorrow\" or \"every monday\"")
+
+	args, err := a.parse(ctx, fs, "<task> [flags]", args, 1)
+	if err != nil {
+		return err
+	}
+	if err = checkPriority(*priority); err != nil {
+		return err
+	}
+
+	task, err := a.findTask(args[0])
+	if err != nil {
+		return err
+	}
+
+	change := todoist.UpdateTask{ID: task.ID, Content: *content, Description: *description, Priority: *priority}
+	if *due != "" {
+		change.Due = &todoist.Due{String: *due}
+	}
+	if *content == "" && *description == "" && *priority == 0 && *due == "" {
+		return usageError("nothing to update")
+	}
+
+	_, resp, err := a.client.Tasks.Update(ctx, a.store.SyncToken(), change)
+	a.store.ApplyCommandResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	return a.writeTask(task.ID)
+}
+
+func completeTask(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("tasks complete", flag.ContinueOnError)
+	args, err := a.parse(ctx, fs, "<task>", args, 1)
+	if err != nil {
+		return err
+	}
+
+	task, err := a.findTask(args[0])
+	if err != nil {
+		return err
+	}
+
+	_, resp, err := a.client.Tasks.Complete(ctx, a.store.SyncToken(), todoist.CompleteTask{ID: task.ID})
+	a.store.ApplyCommandResponse(resp)
+
+	return err
+}
+
+func moveTask(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("tasks move", flag.ContinueOnError)
+	project := fs.String("project", "", "destination `project`")
+	section := fs.String("section", "", "destination `section`")
+	parent := fs.String("parent", "", "destination parent `task`")
+
+	args, err := a.parse(ctx, fs, "<task> -project <project> | -section <section> | -parent <task>", args, 1)
+	if err != nil {
+		return err
+	}
+
+	task, err := a.findTask(args[0])
+	if err != nil {
+		return err
+	}
+
+	move := todoist.MoveTask{ID: task.ID}
+	switch {
+	case *parent != "" && *section == "" && *project == "":
+		p, err := a.findTask(*parent)
+		if err != nil {
+			return err
+		}
+		move.ParentID = &p.ID
+	case *section != "" && *parent == "":
+		projectID, err := a.optionalProject(*project)
+		if err != nil {
+			return err
+		}
+		s, err := a.findSection(*section, projectID)
+		if err != nil {
+			return err
+		}
+		move.SectionID = &s.ID
+	case *project != "" && *parent == "":
+		p, err := a.findProject(*project)
+		if err != nil {
+			return err
+		}
+		move.ProjectID = &p.ID
+	default:
+		return usageError("expected one of -project, -section and -parent")
+	}
+
+	_, resp, err := a.client.Tasks.Move(ctx, a.store.SyncToken(), move)
+	a.store.ApplyCommandResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	return a.writeTask(task.ID)
+}
+
+func deleteTask(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("tasks delete", flag.ContinueOnError)
+	args, err := a.parse(ctx, fs, "<task>", args, 1)
+	if err != nil {
+		return err
+	}
+
+	task, err := a.findTask(args[0])
+	if err != nil {
+		return err
+	}
+
+	_, resp, err := a.client.Tasks.Delete(ctx, a.store.SyncToken(), todoist.DeleteTask{ID: task.ID})
+	a.store.ApplyCommandResponse(resp)
+
+	return err
+}
@@ -0,0 +1,384 @@
+package todoist
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// Conflict is a field of a resource changed both by pending commands of a
+// Store and remotely, to different values. Values are JSON encoded, as in
+// the resource.
+type Conflict struct {
+	// The resource type of the resource: "projects", "sections" or "items".
+	ResourceType string
+
+	// The ID of the resource.
+	ID ID
+
+	// The JSON name of the field, such as "content".
+	Field string
+
+	// The value of the field as last synced, with the pending commands
+	// applied, and as changed remotely.
+	Base   json.RawMessage
+	Local  json.RawMessage
+	Remote json.RawMessage
+
+	// The value the conflict was resolved to.
+	Resolved json.RawMessage
+}
+
+// ConflictResolver returns the value a conflict is resolved to. Returning nil
+// keeps the local value.
+type ConflictResolver func(c Conflict) json.RawMessage
+
+// RemoteWins resolves conflicts to the remote value, dropping local changes.
+func RemoteWins(c Conflict) json.RawMessage {
+	return c.Remote
+}
+
+// LocalWins resolves conflicts to the local value, overwriting remote changes
+// once the pending commands are sent. It is the default resolver of a Store.
+func LocalWins(c Conflict) json.RawMessage {
+	return c.Local
+}
+
+// MergeDescription returns a resolver merging the local and remote changes
+// to task descriptions line by line, as diff3 does: lines changed on one side
+// only take that side's version, and lines added at the same place on both
+// sides are kept, the remote ones first. Descriptions whose same lines were
+// changed on both sides, and conflicts of other fields, are resolved by
+// fallback.
+func MergeDescription(fallback ConflictResolver) ConflictResolver {
+	return func(c Conflict) json.RawMessage {
+		if c.ResourceType != "items" || c.Field != "description" {
+			return fallback(c)
+		}
+
+		var base, local, remote string
+		if json.Unmarshal(c.Base, &base) != nil || json.Unmarshal(c.Local, &local) != nil || json.Unmarshal(c.Remote, &remote) != nil {
+			return fallback(c)
+		}
+
+		lines, ok := mergeLines(base, local, remote)
+		if !ok {
+			return fallback(c)
+		}
+
+		merged, err := json.Marshal(lines)
+		if err != nil {
+			return fallback(c)
+		}
+
+		return merged
+	}
+}
+
+// mergeLines merges the local and remote changes to base text. The lines of
+// base kept by both sides split the texts into chunks, which are merged one
+// by one. It returns false if a chunk was changed differently on both sides.
+func mergeLines(base, local, remote string) (string, bool) {
+	b := strings.Split(base, "\n")
+	l := strings.Split(local, "\n")
+	r := strings.Split(remote, "\n")
+	inLocal := matchLines(b, l)
+	inRemote := matchLines(b, r)
+
+	var merged []string
+	bi, li, ri := 0, 0, 0
+	for {
+		next := bi
+		for next < len(b) && (inLocal[next] < 0 || inRemote[next] < 0) {
+			next++
+		}
+
+		bEnd, lEnd, rEnd := len(b), len(l), len(r)
+		if next < len(b) {
+			bEnd, lEnd, rEnd = next, inLocal[next], inRemote[next]
+		}
+		chunk, ok := mergeChunk(b[bi:bEnd], l[li:lEnd], r[ri:rEnd])
+		if !ok {
+			return "", false
+		}
+		merged = append(merged, chunk...)
+
+		if next == len(b) {
+			break
+		}
+		merged = append(merged, b[next])
+		bi, li, ri = next+1, inLocal[next]+1, inRemote[next]+1
+	}
+
+	return strings.Join(merged, "\n"), true
+}
+
+// mergeChunk merges the local and remote versions of a chunk of base lines.
+func mergeChunk(base, local, remote []string) ([]string, bool) {
+	switch {
+	case equalLines(local, base):
+		return remote, true
+	case equalLines(remote, base), equalLines(local, remote):
+		return local, true
+	case len(base) == 0:
+		return append(append([]string(nil), remote...), local...), true
+	}
+
+	return nil, false
+}
+
+// matchLines returns, for each line of a, the index of the line of b it is
+// matched with in a longest common subsequence of the two, or -1.
+func matchLines(a, b []string) []int {
+	// lengths[i][j] is the length of the longest common subsequence of a[i:]
+	// and b[j:].
+	lengths := make([][]int, len(a)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			case lengths[i+1][j] >= lengths[i][j+1]:
+				lengths[i][j] = lengths[i+1][j]
+			default:
+				lengths[i][j] = lengths[i][j+1]
+			}
+		}
+	}
+
+	matches := make([]int, len(a))
+	i, j := 0, 0
+	for i < len(a) {
+		switch {
+		case j < len(b) && a[i] == b[j]:
+			matches[i] = j
+			i++
+			j++
+		case j == len(b) || lengths[i+1][j] >= lengths[i][j+1]:
+			matches[i] = -1
+			i++
+		default:
+			j++
+		}
+	}
+
+	return matches
+}
+
+func equalLines(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// conflictFields are the fields checked for conflicts, by resource type, and
+// the commands changing them.
+var conflictFields = map[string]struct {
+	fields   []string
+	commands []string
+}{
+	"projects": {
+		fields:   []string{"name", "color", "parent_id", "collapsed", "is_favorite"},
+		commands: []string{"project_update", "project_move"},
+	},
+	"sections": {
+		fields:   []string{"name", "project_id", "collapsed"},
+		commands: []string{"section_update", "section_move"},
+	},
+	"items": {
+		fields:   []string{"content", "description", "due", "priority", "labels", "collapsed", "responsible_uid"},
+		commands: []string{"item_update"},
+	},
+}
+
+// SetConflictResolver sets the resolver of the conflicts between pending
+// commands and the changes of later syncs. A nil resolver restores the
+// default, LocalWins.
+func (s *Store) SetConflictResolver(resolver ConflictResolver) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.resolver = resolver
+}
+
+// Conflicts returns the conflicts resolved when the last sync was applied,
+// in no particular order.
+func (s *Store) Conflicts() []Conflict {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return append([]Conflict(nil), s.conflicts...)
+}
+
+// resolveConflicts detects the conflicts between the pending commands and
+// the resources of a sync, before they are applied, and resolves them.
+// baseData holds the resources the sync is expected to change, and localData
+// the same with the pending commands applied, or nil if none is pending. The pending commands
+// are changed to set the resolved values.
+func (s *Store) resolveConflicts(baseData storeData, localData *storeData, projects []Project, sections []Section, tasks []Task) []Conflict {
+	if localData == nil {
+		return nil
+	}
+
+	var conflicts []Conflict
+	for _, remote := range projects {
+		base, inBase := baseData.projects[remote.ID]
+		local, inLocal := localData.projects[remote.ID]
+		if inBase && inLocal {
+			conflicts = append(conflicts, s.resolveConflict("projects", remote.ID.ID, base, local, remote)...)
+		}
+	}
+	for _, remote := range sections {
+		base, inBase := baseData.sections[remote.ID]
+		local, inLocal := localData.sections[remote.ID]
+		if inBase && inLocal {
+			conflicts = append(conflicts, s.resolveConflict("sections", remote.ID.ID, base, local, remote)...)
+		}
+	}
+	for _, remote := range tasks {
+		base, inBase := baseData.tasks[remote.ID]
+		local, inLocal := localData.tasks[remote.ID]
+		if inBase && inLocal {
+			conflicts = append(conflicts, s.resolveConflict("items", remote.ID.ID, base, local, remote)...)
+		}
+	}
+
+	return conflicts
+}
+
+// resolveConflict compares the fields of the three versions of a resource
+// and resolves the ones changed both locally and remotely.
+func (s *Store) resolveConflict(resourceType string, id ID, base, local, remote interface{}) []Conflict {
+	baseFields, err := jsonFields(base)
+	if err != nil {
+		return nil
+	}
+	localFields, err := jsonFields(local)
+	if err != nil {
+		return nil
+	}
+	remoteFields, err := jsonFields(remote)
+	if err != nil {
+		return nil
+	}
+
+	resolver := s.resolver
+	if resolver == nil {
+		resolver = LocalWins
+	}
+
+	var conflicts []Conflict
+	for _, field := range conflictFields[resourceType].fields {
+		b, l, r := baseFields[field], localFields[field], remoteFields[field]
+		if bytes.Equal(b, l) || bytes.Equal(b, r) || bytes.Equal(l, r) {
+			continue
+		}
+
+		c := Conflict{
+			ResourceType: resourceType,
+			ID:           id,
+			Field:        field,
+			Base:         b,
+			Local:        l,
+			Remote:       r,
+		}
+		c.Resolved = resolver(c)
+		if c.Resolved == nil {
+			c.Resolved = l
+		}
+		if !bytes.Equal(c.Resolved, l) {
+			s.setPendingField(conflictFields[resourceType].commands, id, field, c.Resolved)
+		}
+
+		conflicts = append(conflicts, c)
+	}
+
+	return conflicts
+}
+
+// setPendingField sets a field in the arguments of the pending commands of
+// the given types changing it for a resource.
+func (s *Store) setPendingField(commandTypes []string, id ID, field string, value json.RawMessage) {
+	for i, c := range s.pending {
+		if !containsString(commandTypes, c.Type) {
+			continue
+		}
+
+		b, err := json.Marshal(c.Args)
+		if err != nil {
+			continue
+		}
+
+		var args map[string]json.RawMessage
+		if err = json.Unmarshal(b, &args); err != nil {
+			continue
+		}
+
+		var target ID
+		if err = json.Unmarshal(args["id"], &target); err != nil || target.String() != id.String() {
+			continue
+		}
+		if _, ok := args[field]; !ok {
+			continue
+		}
+
+		args[field] = value
+		s.pending[i].Args = args
+	}
+}
+
+// pendingVersions returns commands with the ones still pending replaced by
+// their pending version, whose arguments may have been changed to resolve
+// conflicts.
+func (s *Store) pendingVersions(commands []Command) []Command {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	pending := make(map[string]Command, len(s.pending))
+	for _, c := range s.pending {
+		pending[c.UUID] = c
+	}
+
+	versions := make([]Command, len(commands))
+	for i, c := range commands {
+		if p, ok := pending[c.UUID]; ok {
+			c = p
+		}
+		versions[i] = c
+	}
+
+	return versions
+}
+
+// jsonFields returns the fields of the JSON encoding of v.
+func jsonFields(v interface{}) (map[string]json.RawMessage, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]json.RawMessage
+	err = json.Unmarshal(b, &fields)
+
+	return fields, err
+}
+
+func containsString(s []string, v string) bool {
+	for _, e := range s {
+		if e == v {
+			return true
+		}
+	}
+
+	return false
+}
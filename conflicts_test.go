@@ -0,0 +1,171 @@
+package todoist
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func Test_Store_Conflicts(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newFakeClient(t)
+
+	tasks, _, err := client.Tasks.Add(ctx, "", AddTask{Content: "Buy milk", Description: "2 liters\nskimmed\nfresh"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	milk := tasks[0].ID
+
+	store := NewStore()
+	if _, err = store.Sync(ctx, client); err != nil {
+		t.Fatal(err)
+	}
+	store.SetConflictResolver(MergeDescription(RemoteWins))
+
+	commands, err := store.ApplyLocal(Command{Type: "item_update", Args: UpdateTask{ID: milk, Content: "Buy oat milk", Description: "2 liters\nskimmed\nfresh\norganic"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = client.Tasks.Update(ctx, "", UpdateTask{ID: milk, Content: "Buy whole milk", Description: "2 liters\nfresh", Priority: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = store.Sync(ctx, client); err != nil {
+		t.Fatal(err)
+	}
+
+	conflicts := map[string]Conflict{}
+	for _, c := range store.Conflicts() {
+		conflicts[c.Field] = c
+	}
+	if len(conflicts) != 2 || string(conflicts["content"].Resolved) != `"Buy whole milk"` || string(conflicts["description"].Resolved) != `"2 liters\nfresh\norganic"` {
+		t.Fatalf("unexpected conflicts %+v", store.Conflicts())
+	}
+	if c := conflicts["content"]; c.ResourceType != "items" || c.ID != milk.ID || string(c.Base) != `"Buy milk"` || string(c.Local) != `"Buy oat milk"` {
+		t.Errorf("unexpected conflict %+v", c)
+	}
+
+	want := Task{Content: "Buy whole milk", Description: "2 liters\nfresh\norganic", Priority: 4}
+	task, _ := store.Task(milk)
+	if task.Content != want.Content || task.Description != want.Description || task.Priority != want.Priority {
+		t.Errorf("expected the resolved task, received %+v", task)
+	}
+
+	// The resolved values are sent.
+	if _, err = store.Push(ctx, client, commands); err != nil {
+		t.Fatal(err)
+	}
+	tasks, _, err = client.Tasks.List(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task := tasks[0]; task.Content != want.Content || task.Description != want.Description || task.Priority != want.Priority {
+		t.Errorf("expected the resolved task to be sent, received %+v", task)
+	}
+
+	// Callbacks choose any value, or keep the local one by returning nil.
+	store.SetConflictResolver(func(c Conflict) json.RawMessage {
+		if c.Field == "content" {
+			return json.RawMessage(`"Buy milk, any kind"`)
+		}
+		return nil
+	})
+	if _, err = store.ApplyLocal(
+		Command{Type: "item_update", Args: UpdateTask{ID: milk, Content: "Buy soy milk", Description: "1 liter"}},
+	); err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = client.Tasks.Update(ctx, "", UpdateTask{ID: milk, Content: "Buy goat milk", Description: "3 liters"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = store.Sync(ctx, client); err != nil {
+		t.Fatal(err)
+	}
+	if task, _ := store.Task(milk); task.Content != "Buy milk, any kind" || task.Description != "1 liter" || len(store.Conflicts()) != 2 {
+		t.Errorf("unexpected resolved task %+v, with conflicts %+v", task, store.Conflicts())
+	}
+
+	// Syncs without conflicting changes report no conflicts.
+	if _, err = store.Sync(ctx, client); err != nil {
+		t.Fatal(err)
+	}
+	if len(store.Conflicts()) != 0 {
+		t.Errorf("expected no conflicts, received %+v", store.Conflicts())
+	}
+}
+
+func Test_Store_Reconcile(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newFakeClient(t)
+
+	tasks, _, err := client.Tasks.Add(ctx, "", AddTask{Content: "Buy milk"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	milk := tasks[0].ID
+
+	store := NewStore()
+	if _, err = store.Sync(ctx, client); err != nil {
+		t.Fatal(err)
+	}
+	syncToken := store.SyncToken()
+
+	commands, err := store.ApplyLocal(Command{Type: "item_update", Args: UpdateTask{ID: milk, Priority: 4}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = store.ApplyLocal(Command{Type: "item_update", Args: UpdateTask{ID: milk, Content: "Buy oat milk"}}); err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = client.Tasks.Update(ctx, "", UpdateTask{ID: milk, Content: "Buy whole milk"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The response of a push is applied like a sync: the remote change
+	// conflicts with the command still pending, but not with the pushed one.
+	if _, err = store.Push(ctx, client, commands); err != nil {
+		t.Fatal(err)
+	}
+	if store.SyncToken() == syncToken {
+		t.Errorf("expected the sync token to be replaced, received %s", syncToken)
+	}
+	conflicts := store.Conflicts()
+	if len(conflicts) != 1 || conflicts[0].Field != "content" || string(conflicts[0].Local) != `"Buy oat milk"` || string(conflicts[0].Remote) != `"Buy whole milk"` {
+		t.Fatalf("unexpected conflicts %+v", conflicts)
+	}
+	if task, _ := store.Task(milk); task.Content != "Buy oat milk" || task.Priority != 4 || len(store.Pending()) != 1 {
+		t.Errorf("expected the pending update on top of the pushed one, received %+v", task)
+	}
+}
+
+func Test_mergeLines(t *testing.T) {
+	tests := []struct {
+		name                        string
+		base, local, remote, merged string
+		conflict                    bool
+	}{
+		{name: "both add", base: "a\nb", local: "a\nb\nc", remote: "a\nb\nd", merged: "a\nb\nd\nc"},
+		{name: "local removes", base: "a\nb\nc", local: "a\nc", remote: "a\nb\nc\nd", merged: "a\nc\nd"},
+		{name: "remote removes", base: "a\nb", local: "a\nb\nc", remote: "b", merged: "b\nc"},
+		{name: "same addition", base: "a", local: "a\nb", remote: "a\nb", merged: "a\nb"},
+		{name: "duplicate removed", base: "x\ny\nx\nw", local: "x\ny\nw", remote: "x\ny\nx\nw\nz", merged: "x\ny\nw\nz"},
+		{name: "duplicate added", base: "a\nb", local: "a\nb\na", remote: "c\na\nb", merged: "c\na\nb\na"},
+		{name: "both change", base: "a\nb", local: "a\nc", remote: "a\nd", conflict: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged, ok := mergeLines(tt.base, tt.local, tt.remote)
+			if ok == tt.conflict || merged != tt.merged {
+				t.Errorf("expected %q (conflict %t), received %q (conflict %t)", tt.merged, tt.conflict, merged, !ok)
+			}
+		})
+	}
+
+	// Conflicting changes are resolved by the fallback.
+	c := Conflict{ResourceType: "items", Field: "description", Base: json.RawMessage(`"a\nb"`), Local: json.RawMessage(`"a\nc"`), Remote: json.RawMessage(`"a\nd"`)}
+	if resolved := MergeDescription(RemoteWins)(c); string(resolved) != string(c.Remote) {
+		t.Errorf("expected the remote value, received %s", resolved)
+	}
+}
@@ -0,0 +1,175 @@
+package todoist
+
+import (
+	"reflect"
+)
+
+// differ turns the resources returned by syncs and commands into watch
+// events, by comparing them with the versions it has seen. Client.Watch and
+// Store.SyncChanges use one, so that they report changes the same way.
+//
+// A resource the differ has not seen is reported as created, and one that
+// differs from the version it has seen as updated; resources that did not
+// change are not reported. Deleted resources are reported as deleted, and
+// tasks that become checked as completed. Completed tasks are forgotten, like
+// deleted resources, so a task that is uncompleted is reported as created.
+type differ struct {
+	// removeArchived reports archived projects and sections as deleted, for
+	// feeds that only keep active resources, such as a Store.
+	removeArchived bool
+
+	// ignoreUnknownRemovals does not report the deletion or completion of
+	// resources the differ has not seen, for differs seeded with every
+	// resource there is.
+	ignoreUnknownRemovals bool
+
+	projects map[ProjectID]Project
+	sections map[SectionID]Section
+	tasks    map[TaskID]Task
+}
+
+// newDiffer returns a differ that has not seen any resource.
+func newDiffer() *differ {
+	return &differ{
+		projects: map[ProjectID]Project{},
+		sections: map[SectionID]Section{},
+		tasks:    map[TaskID]Task{},
+	}
+}
+
+// seed records resources as seen, without reporting them.
+func (d *differ) seed(projects []Project, sections []Section, tasks []Task) {
+	for _, p := range projects {
+		d.projects[p.ID] = p
+	}
+	for _, s := range sections {
+		d.sections[s.ID] = s
+	}
+	for _, t := range tasks {
+		d.tasks[t.ID] = t
+	}
+}
+
+// diff returns the events for the resources returned by a sync or command, in
+// the order they were returned, projects first, and records them as seen.
+//
+// A full sync returns every active resource, so the resources it does not
+// return are reported as deleted after the others.
+func (d *differ) diff(projects []Project, sections []Section, tasks []Task, fullSync bool) []WatchEvent {
+	var events []WatchEvent
+
+	seenProjects := map[ProjectID]bool{}
+	for i := range projects {
+		p := projects[i]
+		seenProjects[p.ID] = true
+		if t, ok := d.diffProject(p); ok {
+			events = append(events, WatchEvent{Type: t, Project: &p})
+		}
+	}
+
+	seenSections := map[SectionID]bool{}
+	for i := range sections {
+		s := sections[i]
+		seenSections[s.ID] = true
+		if t, ok := d.diffSection(s); ok {
+			events = append(events, WatchEvent{Type: t, Section: &s})
+		}
+	}
+
+	seenTasks := map[TaskID]bool{}
+	for i := range tasks {
+		task := tasks[i]
+		seenTasks[task.ID] = true
+		if t, ok := d.diffTask(task); ok {
+			events = append(events, WatchEvent{Type: t, Task: &task})
+		}
+	}
+
+	if !fullSync {
+		return events
+	}
+
+	for id, p := range d.projects {
+		if !seenProjects[id] {
+			p := p
+			delete(d.projects, id)
+			events = append(events, WatchEvent{Type: WatchDeleted, Project: &p})
+		}
+	}
+	for id, s := range d.sections {
+		if !seenSections[id] {
+			s := s
+			delete(d.sections, id)
+			events = append(events, WatchEvent{Type: WatchDeleted, Section: &s})
+		}
+	}
+	for id, t := range d.tasks {
+		if !seenTasks[id] {
+			t := t
+			delete(d.tasks, id)
+			events = append(events, WatchEvent{Type: WatchDeleted, Task: &t})
+		}
+	}
+
+	return events
+}
+
+func (d *differ) diffProject(p Project) (WatchEventType, bool) {
+	old, known := d.projects[p.ID]
+
+	switch {
+	case bool(p.IsDeleted) || (d.removeArchived && bool(p.IsArchived)):
+		delete(d.projects, p.ID)
+		return WatchDeleted, known || !d.ignoreUnknownRemovals
+	case known && reflect.DeepEqual(old, p):
+		return "", false
+	}
+
+	d.projects[p.ID] = p
+	if !known {
+		return WatchCreated, true
+	}
+
+	return WatchUpdated, true
+}
+
+func (d *differ) diffSection(s Section) (WatchEventType, bool) {
+	old, known := d.sections[s.ID]
+
+	switch {
+	case s.IsDeleted || (d.removeArchived && s.IsArchived):
+		delete(d.sections, s.ID)
+		return WatchDeleted, known || !d.ignoreUnknownRemovals
+	case known && reflect.DeepEqual(old, s):
+		return "", false
+	}
+
+	d.sections[s.ID] = s
+	if !known {
+		return WatchCreated, true
+	}
+
+	return WatchUpdated, true
+}
+
+func (d *differ) diffTask(t Task) (WatchEventType, bool) {
+	old, known := d.tasks[t.ID]
+
+	switch {
+	case bool(t.IsDeleted):
+		delete(d.tasks, t.ID)
+		return WatchDeleted, known || !d.ignoreUnknownRemovals
+	case bool(t.Checked):
+		delete(d.tasks, t.ID)
+		return WatchCompleted, known || !d.ignoreUnknownRemovals
+	case known && reflect.DeepEqual(old, t):
+		return "", false
+	}
+
+	d.tasks[t.ID] = t
+	if !known {
+		return WatchCreated, true
+	}
+
+	return WatchUpdated, true
+}
@@ -0,0 +1,82 @@
+package todoist
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_Differ(t *testing.T) {
+	home := Project{ID: ProjectID{NewID("1")}, Name: "Home"}
+	garden := Section{ID: SectionID{NewID("2")}, ProjectID: home.ID, Name: "Garden"}
+	mow := Task{ID: TaskID{NewID("3")}, ProjectID: home.ID, Content: "Mow the lawn"}
+	rake := Task{ID: TaskID{NewID("4")}, ProjectID: home.ID, Content: "Rake leaves"}
+
+	archived := home
+	archived.IsArchived = true
+	renamed := garden
+	renamed.Name = "Backyard"
+	completed := mow
+	completed.Checked = true
+	deleted := rake
+	deleted.IsDeleted = true
+	unknown := Task{ID: TaskID{NewID("5")}, ProjectID: home.ID, IsDeleted: true}
+
+	types := func(events []WatchEvent) []WatchEventType {
+		var types []WatchEventType
+		for _, e := range events {
+			types = append(types, e.Type)
+		}
+		return types
+	}
+
+	tests := []struct {
+		name                  string
+		removeArchived        bool
+		ignoreUnknownRemovals bool
+		projects              []Project
+		sections              []Section
+		tasks                 []Task
+		fullSync              bool
+		want                  []WatchEventType
+	}{
+		{name: "unchanged", projects: []Project{home}, sections: []Section{garden}, tasks: []Task{mow, rake}},
+		{name: "changes", sections: []Section{renamed}, tasks: []Task{completed, deleted, unknown}, want: []WatchEventType{WatchUpdated, WatchCompleted, WatchDeleted, WatchDeleted}},
+		{name: "unknown removals ignored", ignoreUnknownRemovals: true, tasks: []Task{deleted, unknown}, want: []WatchEventType{WatchDeleted}},
+		{name: "archived project updated", projects: []Project{archived}, want: []WatchEventType{WatchUpdated}},
+		{name: "archived project removed", removeArchived: true, projects: []Project{archived}, want: []WatchEventType{WatchDeleted}},
+		{name: "full sync", projects: []Project{home}, tasks: []Task{mow}, fullSync: true, want: []WatchEventType{WatchDeleted, WatchDeleted}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newDiffer()
+			d.removeArchived = tt.removeArchived
+			d.ignoreUnknownRemovals = tt.ignoreUnknownRemovals
+			d.seed([]Project{home}, []Section{garden}, []Task{mow, rake})
+
+			if got := types(d.diff(tt.projects, tt.sections, tt.tasks, tt.fullSync)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, received %v", tt.want, got)
+			}
+		})
+	}
+
+	// Seen changes are not reported again, and new resources are created.
+	d := newDiffer()
+	d.seed([]Project{home}, nil, []Task{mow, rake})
+	d.diff(nil, nil, []Task{rake}, false)
+	if got := types(d.diff(nil, []Section{garden}, []Task{rake}, false)); !reflect.DeepEqual(got, []WatchEventType{WatchCreated}) {
+		t.Errorf("expected a created section, received %v", got)
+	}
+
+	// Completed tasks are forgotten, so full syncs, which leave them out, do
+	// not report them, and uncompleting one creates it again.
+	d.diff(nil, nil, []Task{completed}, false)
+	if _, ok := d.tasks[mow.ID]; ok {
+		t.Errorf("expected the completed task to be forgotten")
+	}
+	if got := d.diff([]Project{home}, []Section{garden}, []Task{rake}, true); len(got) != 0 {
+		t.Errorf("expected no events, received %+v", got)
+	}
+	if got := types(d.diff(nil, nil, []Task{mow}, false)); !reflect.DeepEqual(got, []WatchEventType{WatchCreated}) {
+		t.Errorf("expected a created task, received %v", got)
+	}
+}
@@ -0,0 +1,81 @@
+package todoist
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Layouts of Due.Date: a full-day date, a floating date with a time (in the
+// user's current timezone), and a fixed date with a time (in UTC).
+const (
+	DueDateLayout         = "2006-01-02"
+	DueFloatingTimeLayout = "2006-01-02T15:04:05"
+	DueFixedTimeLayout    = "2006-01-02T15:04:05Z"
+)
+
+// Due is the due date of a task.
+//
+// When adding or updating a task, setting only String lets Todoist parse a
+// natural language due date, such as "every monday at 9am".
+//
+// Todoist API docs: https://developer.todoist.com/sync/v8/#due-dates
+type Due struct {
+	// Due date in the format of YYYY-MM-DD (RFC 3339). For recurring dates, the date of the current iteration. Dates with a time are in the format YYYY-MM-DDTHH:MM:SS, with a trailing Z if the time is fixed to a timezone.
+	Date string `json:"date,omitempty"`
+
+	// Always set to null for floating due dates. For dates with a fixed timezone, the timezone of the due date.
+	Timezone *string `json:"timezone,omitempty"`
+
+	// Human-readable representation of due date. String always represents the due object in user's timezone.
+	String string `json:"string,omitempty"`
+
+	// Lang which has to be used to parse the content of the string attribute. Used by clients and on the server side to properly process due dates when date object is not set, and when dealing with recurring tasks. Valid languages are: en, da, pl, zh, ko, de, pt, ja, it, fr, sv, ru, es, nl.
+	Lang string `json:"lang,omitempty"`
+
+	// Boolean flag which is set to true if the due object represents a recurring due date.
+	IsRecurring bool `json:"is_recurring,omitempty"`
+
+	// The due date and time in RFC3339 format in UTC, sent by the REST API for dates with a time.
+	Datetime string `json:"datetime,omitempty"`
+}
+
+// HasTime reports whether the due date has a time of day.
+func (d Due) HasTime() bool {
+	return strings.Contains(d.Date, "T") || d.Datetime != ""
+}
+
+// Time returns the due date as a time. Full-day and floating dates are
+// interpreted in loc (the user's timezone); fixed dates keep their instant.
+func (d Due) Time(loc *time.Location) (time.Time, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+
+	if d.Datetime != "" {
+		t, err := time.Parse(time.RFC3339, d.Datetime)
+		if err != nil {
+			return time.Time{}, errors.Wrapf(err, "invalid due datetime %q", d.Datetime)
+		}
+		return t, nil
+	}
+
+	var (
+		t   time.Time
+		err error
+	)
+	switch {
+	case strings.HasSuffix(d.Date, "Z"):
+		t, err = time.Parse(DueFixedTimeLayout, d.Date)
+	case strings.Contains(d.Date, "T"):
+		t, err = time.ParseInLocation(DueFloatingTimeLayout, d.Date, loc)
+	default:
+		t, err = time.ParseInLocation(DueDateLayout, d.Date, loc)
+	}
+	if err != nil {
+		return time.Time{}, errors.Wrapf(err, "invalid due date %q", d.Date)
+	}
+
+	return t, nil
+}
@@ -0,0 +1,44 @@
+package todoist
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_Due_Time(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Madrid")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	tests := []struct {
+		name    string
+		due     Due
+		want    time.Time
+		hasTime bool
+	}{
+		{name: "full day", due: Due{Date: "2016-12-01"}, want: time.Date(2016, 12, 1, 0, 0, 0, 0, loc)},
+		{name: "floating", due: Due{Date: "2016-12-01T12:00:00"}, want: time.Date(2016, 12, 1, 12, 0, 0, 0, loc), hasTime: true},
+		{name: "fixed", due: Due{Date: "2016-12-06T13:00:00Z"}, want: time.Date(2016, 12, 6, 13, 0, 0, 0, time.UTC), hasTime: true},
+		{name: "rest datetime", due: Due{Date: "2016-12-06", Datetime: "2016-12-06T13:00:00.000000Z"}, want: time.Date(2016, 12, 6, 13, 0, 0, 0, time.UTC), hasTime: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.due.Time(loc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("expected %s, received %s", tt.want, got)
+			}
+			if tt.due.HasTime() != tt.hasTime {
+				t.Errorf("expected HasTime %v", tt.hasTime)
+			}
+		})
+	}
+
+	if _, err = (Due{Date: "tomorrow"}).Time(loc); err == nil {
+		t.Error("expected an error for an invalid date")
+	}
+}
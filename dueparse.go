@@ -0,0 +1,913 @@
+package todoist
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DueSettings are the user settings due strings are interpreted with. They are
+// usually obtained from User.DueSettings.
+type DueSettings struct {
+	// The user's timezone, in which dates are computed. Defaults to UTC.
+	Location *time.Location
+
+	// The first day of the week, which "next monday" and weekly recurrences are relative to.
+	StartDay time.Weekday
+
+	// The day "next week" refers to.
+	NextWeek time.Weekday
+
+	// Whether dates such as 3/4 are read as the 3rd of April rather than March 4th.
+	DayFirst bool
+}
+
+// The times of day of "morning", "afternoon", "evening" (and "tonight") and
+// "night", as Todoist interprets them.
+var dueNamedClocks = map[string]dueClock{
+	"morning":   {9, 0},
+	"noon":      {12, 0},
+	"afternoon": {13, 0},
+	"evening":   {19, 0},
+	"night":     {22, 0},
+	"midnight":  {0, 0},
+}
+
+var dueWeekdayNames = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+var dueMonthNames = map[string]time.Month{
+	"january": time.January, "jan": time.January,
+	"february": time.February, "feb": time.February,
+	"march": time.March, "mar": time.March,
+	"april": time.April, "apr": time.April,
+	"may":  time.May,
+	"june": time.June, "jun": time.June,
+	"july": time.July, "jul": time.July,
+	"august": time.August, "aug": time.August,
+	"september": time.September, "sep": time.September, "sept": time.September,
+	"october": time.October, "oct": time.October,
+	"november": time.November, "nov": time.November,
+	"december": time.December, "dec": time.December,
+}
+
+var dueUnitFrequencies = map[string]Frequency{
+	"hour": Hourly, "hours": Hourly, "hr": Hourly, "hrs": Hourly,
+	"day": Daily, "days": Daily,
+	"week": Weekly, "weeks": Weekly,
+	"month": Monthly, "months": Monthly,
+	"year": Yearly, "years": Yearly,
+}
+
+// dueMaxInterval is the largest number of units in "every 3 days" or "for 3
+// weeks", which keeps the times computed from them from overflowing.
+const dueMaxInterval = 1000
+
+var (
+	dueClockPattern = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?(am|pm)?$`)
+	dueSlashPattern = regexp.MustCompile(`^(\d{1,2})/(\d{1,2})(?:/(\d{2}|\d{4}))?$`)
+)
+
+// ParseDueString interprets an English due string, such as "tomorrow at 5pm",
+// "in 3 days" or "every other tue at 9am starting next month", the way Todoist
+// would at the time now, without a request.
+//
+// The returned Due has its Date set to the due date, or to the first
+// occurrence for recurring due strings, in the format of a full-day or a
+// floating date. Recurring due strings also return their recurrence rule,
+// which is nil otherwise.
+//
+// Weekdays refer to the next such day ("monday" on a Monday is a week away),
+// "next monday" to the Monday of the next week, and dates without a year to
+// the next such date. Times without a date are today if still ahead, or
+// tomorrow. Recurring due dates with a time start after now.
+func ParseDueString(s string, now time.Time, settings DueSettings) (Due, *Recurrence, error) {
+	if settings.Location == nil {
+		settings.Location = time.UTC
+	}
+
+	p := &dueParser{
+		words:    dueWords(s),
+		now:      now.In(settings.Location),
+		settings: settings,
+	}
+	if len(p.words) == 0 {
+		return Due{}, nil, errors.New("empty due string")
+	}
+
+	due := Due{String: strings.TrimSpace(s), Lang: "en"}
+
+	r, err := p.parse()
+	if err != nil {
+		return Due{}, nil, errors.Wrapf(err, "unable to parse due string %q", s)
+	}
+
+	if r == nil {
+		if p.clock != nil {
+			due.Date = p.date.Format(DueFloatingTimeLayout)
+		} else {
+			due.Date = p.date.Format(DueDateLayout)
+		}
+		return due, nil, nil
+	}
+
+	due.IsRecurring = true
+	if r.HasTime {
+		due.Date = r.Start.Format(DueFloatingTimeLayout)
+	} else {
+		due.Date = r.Start.Format(DueDateLayout)
+	}
+
+	return due, r, nil
+}
+
+// dueWords splits a due string into lowercased words, dropping commas and
+// joining times written as "5 pm".
+func dueWords(s string) []string {
+	fields := strings.Fields(strings.ReplaceAll(strings.ToLower(s), ",", " "))
+
+	words := make([]string, 0, len(fields))
+	for i := 0; i < len(fields); i++ {
+		w := fields[i]
+		if i+1 < len(fields) && (fields[i+1] == "am" || fields[i+1] == "pm") && dueClockPattern.MatchString(w) {
+			w += fields[i+1]
+			i++
+		}
+		words = append(words, w)
+	}
+
+	return words
+}
+
+// dueClock is a time of day.
+type dueClock struct {
+	hour, minute int
+}
+
+// dueParser holds the state of ParseDueString.
+type dueParser struct {
+	words    []string
+	i        int
+	now      time.Time
+	settings DueSettings
+
+	// The date and time of a due date, or the time of the occurrences of a
+	// recurrence.
+	date    time.Time
+	hasDate bool
+	clock   *dueClock
+}
+
+func (p *dueParser) peek(offset int) string {
+	if p.i+offset >= len(p.words) {
+		return ""
+	}
+
+	return p.words[p.i+offset]
+}
+
+func (p *dueParser) accept(words ...string) bool {
+	for _, w := range words {
+		if p.peek(0) == w {
+			p.i++
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *dueParser) unexpected() error {
+	if p.i >= len(p.words) {
+		return errors.New("unexpected end")
+	}
+
+	return errors.Errorf("unexpected %q", p.words[p.i])
+}
+
+// today returns the start of the current day.
+func (p *dueParser) today() time.Time {
+	y, m, d := p.now.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, p.settings.Location)
+}
+
+// weekStart returns the first day of the week of t.
+func (p *dueParser) weekStart(t time.Time) time.Time {
+	return t.AddDate(0, 0, -daysUntil(p.settings.StartDay, t.Weekday()))
+}
+
+// daysUntil returns the number of days from one weekday to the next to.
+func daysUntil(from, to time.Weekday) int {
+	return (int(to) - int(from) + 7) % 7
+}
+
+func (p *dueParser) setClock(c dueClock) error {
+	if p.clock != nil {
+		return errors.New("more than one time")
+	}
+	p.clock = &c
+
+	return nil
+}
+
+// parse parses the words, returning the recurrence rule of recurring due
+// strings.
+func (p *dueParser) parse() (*Recurrence, error) {
+	r, err := p.recurrence()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		start, until time.Time
+		forN         int
+		forUnit      Frequency
+	)
+
+	for p.i < len(p.words) {
+		switch w := p.peek(0); {
+		case w == "at" || w == "@":
+			p.i++
+			c, ok := p.parseClock(true)
+			if !ok {
+				return nil, p.unexpected()
+			}
+			if err := p.setClock(c); err != nil {
+				return nil, err
+			}
+
+		case w == "on":
+			p.i++
+			if p.i >= len(p.words) {
+				return nil, p.unexpected()
+			}
+
+			// "every month on the 15th", "every 2 weeks on monday"
+			switch {
+			case r != nil && r.Frequency == Monthly && len(r.MonthDays) == 0:
+				p.accept("the")
+				if r.MonthDays = p.monthDays(); len(r.MonthDays) == 0 {
+					return nil, p.unexpected()
+				}
+			case r != nil && r.Frequency == Weekly && len(r.Weekdays) == 0 && isDueWeekday(p.peek(0)):
+				r.Weekdays = p.weekdays()
+			}
+
+		case r != nil && (w == "starting" || w == "starts" || w == "from"):
+			p.i++
+			p.accept("on")
+			d, c, ok := p.parseDate()
+			if !ok {
+				return nil, p.unexpected()
+			}
+			if c != nil {
+				if err := p.setClock(*c); err != nil {
+					return nil, err
+				}
+			}
+			start = d
+
+		case r != nil && (w == "until" || w == "till" || w == "ending" || w == "ends"):
+			p.i++
+			p.accept("on")
+			d, _, ok := p.parseDate()
+			if !ok {
+				return nil, p.unexpected()
+			}
+			until = d.AddDate(0, 0, 1).Add(-time.Nanosecond)
+
+		case r != nil && w == "for":
+			n, err := strconv.Atoi(p.peek(1))
+			if err != nil || n < 1 || n > dueMaxInterval || dueUnitFrequencies[p.peek(2)] == "" || dueUnitFrequencies[p.peek(2)] == Hourly {
+				p.i++
+				return nil, p.unexpected()
+			}
+			forN, forUnit = n, dueUnitFrequencies[p.peek(2)]
+			p.i += 3
+
+		default:
+			if c, ok := p.parseClock(false); ok {
+				if err := p.setClock(c); err != nil {
+					return nil, err
+				}
+				continue
+			}
+
+			if r == nil && !p.hasDate {
+				if d, c, ok := p.parseDate(); ok {
+					p.date, p.hasDate = d, true
+					if c != nil {
+						if err := p.setClock(*c); err != nil {
+							return nil, err
+						}
+					}
+					continue
+				}
+			}
+
+			return nil, p.unexpected()
+		}
+	}
+
+	if r == nil {
+		return nil, p.resolveDate()
+	}
+
+	startGiven := !start.IsZero()
+	if !startGiven {
+		start = p.today()
+	}
+
+	switch {
+	case r.Frequency == Hourly && p.clock != nil:
+		start = time.Date(start.Year(), start.Month(), start.Day(), p.clock.hour, p.clock.minute, 0, 0, start.Location())
+	case r.Frequency == Hourly && !startGiven:
+		start = p.now.Truncate(time.Minute)
+	}
+
+	if r.Frequency == Hourly {
+		r.HasTime, r.Hour, r.Minute = true, start.Hour(), start.Minute()
+	} else if p.clock != nil {
+		r.HasTime, r.Hour, r.Minute = true, p.clock.hour, p.clock.minute
+	}
+
+	// Rules counted from the completion date keep their days unset, which
+	// tells them apart from rules on given days.
+	switch {
+	case r.FromCompletion:
+	case r.Frequency == Weekly:
+		if len(r.Weekdays) == 0 {
+			r.Weekdays = []time.Weekday{start.Weekday()}
+		}
+	case r.Frequency == Monthly:
+		if len(r.MonthDays) == 0 {
+			r.MonthDays = []int{start.Day()}
+		}
+	case r.Frequency == Yearly:
+		if r.Month == 0 {
+			r.Month, r.MonthDays = start.Month(), []int{start.Day()}
+		}
+	}
+
+	r.Start = start
+	r.WeekStart = p.settings.StartDay
+
+	switch forUnit {
+	case Daily:
+		until = start.AddDate(0, 0, forN)
+	case Weekly:
+		until = start.AddDate(0, 0, 7*forN)
+	case Monthly:
+		until = start.AddDate(0, forN, 0)
+	case Yearly:
+		until = start.AddDate(forN, 0, 0)
+	}
+	if forUnit != "" {
+		until = time.Date(until.Year(), until.Month(), until.Day(), 0, 0, 0, 0, until.Location()).Add(-time.Nanosecond)
+	}
+	r.Until = until
+
+	// The first occurrence is on or after the start, and after now if it has
+	// a time. Periods are counted from it, so that "every other tue starting
+	// next month" starts on the first Tuesday of next month.
+	after := start.Add(-time.Nanosecond)
+	if r.HasTime && !startGiven && r.Frequency != Hourly {
+		after = p.now
+	}
+
+	anchor := *r
+	anchor.Interval = 1
+	first, ok := anchor.Next(after)
+	if !ok {
+		return nil, errors.New("no occurrences")
+	}
+	r.Start = first
+
+	return r, nil
+}
+
+// resolveDate completes the date of a due date that is not recurring.
+func (p *dueParser) resolveDate() error {
+	if !p.hasDate && p.clock == nil {
+		return p.unexpected()
+	}
+
+	if !p.hasDate {
+		p.date = p.today()
+		if !p.withClock(p.date).After(p.now) {
+			p.date = p.date.AddDate(0, 0, 1)
+		}
+	}
+	p.date = p.withClock(p.date)
+
+	return nil
+}
+
+func (p *dueParser) withClock(date time.Time) time.Time {
+	if p.clock == nil {
+		return date
+	}
+
+	return time.Date(date.Year(), date.Month(), date.Day(), p.clock.hour, p.clock.minute, 0, 0, date.Location())
+}
+
+// recurrence parses the start of a recurring due string, such as "every other
+// day" or "every mon, fri", and returns nil if the due string is not recurring.
+func (p *dueParser) recurrence() (*Recurrence, error) {
+	r := &Recurrence{Interval: 1}
+
+	switch p.peek(0) {
+	case "daily":
+		r.Frequency = Daily
+	case "weekly":
+		r.Frequency = Weekly
+	case "monthly":
+		r.Frequency = Monthly
+	case "yearly", "annually":
+		r.Frequency = Yearly
+	case "hourly":
+		r.Frequency = Hourly
+	case "every", "ev":
+	case "every!", "ev!", "after":
+		r.FromCompletion = true
+	default:
+		return nil, nil
+	}
+	p.i++
+
+	if r.Frequency != "" {
+		return r, nil
+	}
+
+	if p.accept("other") {
+		r.Interval = 2
+	}
+
+	if !p.pattern(r) {
+		return nil, p.unexpected()
+	}
+
+	return r, nil
+}
+
+// pattern parses what follows "every", such as "3 weeks", "weekday", "mon and
+// thu", "1st, 15th", "last day" or "jan 5".
+func (p *dueParser) pattern(r *Recurrence) bool {
+	w, next := p.peek(0), p.peek(1)
+
+	switch {
+	case dueNumberPattern.MatchString(w) && dueUnitFrequencies[next] != "":
+		n, err := strconv.Atoi(w)
+		if err != nil || n < 1 || n > dueMaxInterval {
+			return false
+		}
+		r.Interval *= n
+		r.Frequency = dueUnitFrequencies[next]
+		p.i += 2
+
+	case dueUnitFrequencies[w] != "":
+		r.Frequency = dueUnitFrequencies[w]
+		p.i++
+
+	case w == "weekday" || w == "weekdays" || w == "workday" || w == "workdays":
+		r.Frequency = Weekly
+		r.Weekdays = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+		p.i++
+
+	case w == "weekend" || w == "weekends":
+		r.Frequency = Weekly
+		r.Weekdays = []time.Weekday{time.Saturday, time.Sunday}
+		p.i++
+
+	case w == "quarter":
+		r.Frequency = Monthly
+		r.Interval *= 3
+		p.i++
+
+	case isDueWeekday(w):
+		r.Frequency = Weekly
+		r.Weekdays = p.weekdays()
+
+	case w == "last" && next == "day", dueOrdinalPattern.MatchString(w):
+		r.Frequency = Monthly
+		r.MonthDays = p.monthDays()
+
+		if len(r.MonthDays) == 1 && r.MonthDays[0] > 0 {
+			n := 0
+			if p.peek(0) == "of" {
+				n = 1
+			}
+			if month, ok := dueMonthNames[p.peek(n)]; ok {
+				r.Frequency, r.Month = Yearly, month
+				p.i += n + 1
+			}
+		}
+
+	case dueMonthNames[w] != 0 && dueDayOfMonth(next) > 0:
+		r.Frequency, r.Month = Yearly, dueMonthNames[w]
+		r.MonthDays = []int{dueDayOfMonth(next)}
+		p.i += 2
+
+	case dueNumberPattern.MatchString(w) && dueMonthNames[next] != 0:
+		r.Frequency, r.Month = Yearly, dueMonthNames[next]
+		r.MonthDays = []int{dueDayOfMonth(w)}
+		p.i += 2
+
+	default:
+		c, ok := p.parseClock(false)
+		if !ok {
+			return false
+		}
+		r.Frequency = Daily
+		p.clock = &c
+	}
+
+	// "every feb 30" never occurs.
+	if r.Month != 0 && len(r.MonthDays) == 1 && clampDay(2000, r.Month, r.MonthDays[0]) != r.MonthDays[0] {
+		return false
+	}
+
+	return true
+}
+
+// weekdays parses a list of weekdays, such as "mon and thu".
+func (p *dueParser) weekdays() []time.Weekday {
+	var weekdays []time.Weekday
+	for isDueWeekday(p.peek(0)) {
+		weekdays = append(weekdays, dueWeekday(p.peek(0)))
+		p.i++
+		if (p.peek(0) == "and" || p.peek(0) == "&") && isDueWeekday(p.peek(1)) {
+			p.i++
+		}
+	}
+
+	return weekdays
+}
+
+// monthDays parses a list of days of the month, such as "1st, 15th" or "15th
+// and last day", optionally followed by "of the month".
+func (p *dueParser) monthDays() []int {
+	var days []int
+	for {
+		if p.peek(0) == "last" && p.peek(1) == "day" {
+			days = append(days, -1)
+			p.i += 2
+		} else if day, ok := dueOrdinal(p.peek(0)); ok {
+			days = append(days, day)
+			p.i++
+		} else {
+			break
+		}
+
+		if (p.peek(0) == "and" || p.peek(0) == "&") && (dueOrdinalPattern.MatchString(p.peek(1)) || p.peek(1) == "last") {
+			p.i++
+		}
+	}
+
+	// "of the month", "of every month"
+	if len(days) > 0 && p.peek(0) == "of" {
+		save := p.i
+		p.i++
+		p.accept("the", "every", "each")
+		if !p.accept("month") {
+			p.i = save
+		}
+	}
+
+	return days
+}
+
+// parseClock parses a time of day, such as "5pm", "17:30", "noon" or
+// "evening". Bare hours such as "5" are only accepted after "at".
+func (p *dueParser) parseClock(afterAt bool) (dueClock, bool) {
+	w := p.peek(0)
+	if c, ok := dueNamedClocks[w]; ok {
+		p.i++
+		return c, true
+	}
+
+	m := dueClockPattern.FindStringSubmatch(w)
+	if m == nil || (m[2] == "" && m[3] == "" && !afterAt) {
+		return dueClock{}, false
+	}
+
+	hour, _ := strconv.Atoi(m[1])
+	minute := 0
+	if m[2] != "" {
+		minute, _ = strconv.Atoi(m[2])
+	}
+
+	if m[3] != "" {
+		if hour < 1 || hour > 12 {
+			return dueClock{}, false
+		}
+		hour %= 12
+		if m[3] == "pm" {
+			hour += 12
+		}
+	}
+	if hour > 23 || minute > 59 {
+		return dueClock{}, false
+	}
+
+	p.i++
+
+	return dueClock{hour, minute}, true
+}
+
+// parseDate parses a date, such as "tomorrow", "next week", "fri", "in 3
+// days", "jan 5th" or "2022-01-05". Some dates, such as "tonight" and "in 2
+// hours", also have a time of day.
+func (p *dueParser) parseDate() (time.Time, *dueClock, bool) {
+	today := p.today()
+	w, next := p.peek(0), p.peek(1)
+
+	switch {
+	case w == "today" || w == "tod":
+		p.i++
+		return today, nil, true
+
+	case w == "tomorrow" || w == "tom" || w == "tmr":
+		p.i++
+		return today.AddDate(0, 0, 1), nil, true
+
+	case w == "yesterday":
+		p.i++
+		return today.AddDate(0, 0, -1), nil, true
+
+	case w == "tonight":
+		p.i++
+		c := dueNamedClocks["evening"]
+		return today, &c, true
+
+	case w == "next" || w == "this":
+		if d, ok := p.period(w == "next", next); ok {
+			p.i += 2
+			return d, nil, true
+		}
+
+	case w == "weekend":
+		p.i++
+		return today.AddDate(0, 0, daysUntil(today.Weekday(), time.Saturday)%6), nil, true
+
+	case isDueWeekday(w):
+		p.i++
+		days := daysUntil(today.Weekday(), dueWeekday(w))
+		if days == 0 {
+			days = 7
+		}
+		return today.AddDate(0, 0, days), nil, true
+
+	case w == "in":
+		return p.in()
+
+	case w == "end" && next == "of":
+		save := p.i
+		p.i += 2
+		p.accept("the")
+		if p.accept("month") {
+			return time.Date(today.Year(), today.Month()+1, 0, 0, 0, 0, 0, today.Location()), nil, true
+		}
+		p.i = save
+	}
+
+	if d, ok := p.absoluteDate(); ok {
+		return d, nil, true
+	}
+
+	return time.Time{}, nil, false
+}
+
+// period returns the date of "next week", "this friday" or similar.
+func (p *dueParser) period(next bool, w string) (time.Time, bool) {
+	today := p.today()
+	week := p.weekStart(today)
+	if next {
+		week = week.AddDate(0, 0, 7)
+	}
+
+	switch {
+	case isDueWeekday(w) && next:
+		return week.AddDate(0, 0, daysUntil(p.settings.StartDay, dueWeekday(w))), true
+	case isDueWeekday(w):
+		return today.AddDate(0, 0, daysUntil(today.Weekday(), dueWeekday(w))), true
+	case w == "weekend" && next:
+		return week.AddDate(0, 0, daysUntil(p.settings.StartDay, time.Saturday)), true
+	case w == "weekend":
+		return today.AddDate(0, 0, daysUntil(today.Weekday(), time.Saturday)%6), true
+	case w == "week" && next:
+		return week.AddDate(0, 0, daysUntil(p.settings.StartDay, p.settings.NextWeek)), true
+	case w == "month" && next:
+		return time.Date(today.Year(), today.Month()+1, 1, 0, 0, 0, 0, today.Location()), true
+	case w == "year" && next:
+		return time.Date(today.Year()+1, time.January, 1, 0, 0, 0, 0, today.Location()), true
+	}
+
+	return time.Time{}, false
+}
+
+// in parses "in 3 days", "in a week" or "in 2 hours".
+func (p *dueParser) in() (time.Time, *dueClock, bool) {
+	n := 1
+	if w := p.peek(1); w != "a" && w != "an" {
+		var err error
+		if n, err = strconv.Atoi(w); err != nil {
+			return time.Time{}, nil, false
+		}
+	}
+
+	today := p.today()
+
+	var d time.Time
+	switch p.peek(2) {
+	case "day", "days":
+		d = today.AddDate(0, 0, n)
+	case "week", "weeks":
+		d = today.AddDate(0, 0, 7*n)
+	case "month", "months":
+		d = today.AddDate(0, n, 0)
+	case "year", "years":
+		d = today.AddDate(n, 0, 0)
+	case "hour", "hours", "hr", "hrs", "minute", "minutes", "min", "mins":
+		unit := time.Minute
+		if strings.HasPrefix(p.peek(2), "h") {
+			unit = time.Hour
+		}
+		t := p.now.Add(time.Duration(n) * unit).Truncate(time.Minute)
+		p.i += 3
+		y, m, day := t.Date()
+		return time.Date(y, m, day, 0, 0, 0, 0, t.Location()), &dueClock{t.Hour(), t.Minute()}, true
+	default:
+		return time.Time{}, nil, false
+	}
+
+	p.i += 3
+
+	return d, nil, true
+}
+
+// absoluteDate parses "2022-01-05", "1/5", "1/5/22", "jan 5th", "5 jan 2022",
+// "5th of jan" or "the 5th".
+func (p *dueParser) absoluteDate() (time.Time, bool) {
+	today := p.today()
+	loc := today.Location()
+	w, next := p.peek(0), p.peek(1)
+
+	if t, err := time.ParseInLocation(DueDateLayout, w, loc); err == nil {
+		p.i++
+		return t, true
+	}
+
+	if m := dueSlashPattern.FindStringSubmatch(w); m != nil {
+		first, _ := strconv.Atoi(m[1])
+		second, _ := strconv.Atoi(m[2])
+		month, day := first, second
+		if p.settings.DayFirst {
+			month, day = second, first
+		}
+
+		year := 0
+		if m[3] != "" {
+			year, _ = strconv.Atoi(m[3])
+			if year < 100 {
+				year += 2000
+			}
+		}
+
+		d, ok := p.monthDay(time.Month(month), day, year)
+		if ok {
+			p.i++
+		}
+		return d, ok
+	}
+
+	var (
+		month time.Month
+		day   int
+		n     int
+	)
+	switch {
+	case dueMonthNames[w] != 0 && dueDayOfMonth(next) > 0:
+		month, day, n = dueMonthNames[w], dueDayOfMonth(next), 2
+	case dueDayOfMonth(w) > 0 && dueMonthNames[next] != 0:
+		month, day, n = dueMonthNames[next], dueDayOfMonth(w), 2
+	case dueOrdinalPattern.MatchString(w) && next == "of" && dueMonthNames[p.peek(2)] != 0:
+		month, day, n = dueMonthNames[p.peek(2)], dueDayOfMonth(w), 3
+	case w == "the" && dueOrdinalPattern.MatchString(next):
+		day, n = dueDayOfMonth(next), 2
+	case dueOrdinalPattern.MatchString(w):
+		day, n = dueDayOfMonth(w), 1
+	default:
+		return time.Time{}, false
+	}
+
+	if month == 0 {
+		// The next such day of a month.
+		for i := 0; i < 12; i++ {
+			first := time.Date(today.Year(), today.Month()+time.Month(i), 1, 0, 0, 0, 0, loc)
+			d := time.Date(first.Year(), first.Month(), day, 0, 0, 0, 0, loc)
+			if d.Month() == first.Month() && !d.Before(today) {
+				p.i += n
+				return d, true
+			}
+		}
+		return time.Time{}, false
+	}
+
+	year := 0
+	if dueYearPattern.MatchString(p.peek(n)) {
+		year, _ = strconv.Atoi(p.peek(n))
+		n++
+	}
+
+	d, ok := p.monthDay(month, day, year)
+	if ok {
+		p.i += n
+	}
+
+	return d, ok
+}
+
+// monthDay returns the given date, or the next such date if year is 0.
+func (p *dueParser) monthDay(month time.Month, day, year int) (time.Time, bool) {
+	today := p.today()
+
+	y := year
+	if y == 0 {
+		y = today.Year()
+	}
+
+	for i := 0; i < 8; i++ {
+		d := time.Date(y+i, month, day, 0, 0, 0, 0, today.Location())
+		valid := d.Month() == month && d.Day() == day
+		switch {
+		case year != 0:
+			return d, valid
+		case valid && !d.Before(today):
+			return d, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+func isDueWeekday(w string) bool {
+	_, ok := dueWeekdayNames[w]
+	if !ok && strings.HasSuffix(w, "s") {
+		_, ok = dueWeekdayNames[strings.TrimSuffix(w, "s")]
+	}
+
+	return ok
+}
+
+// dueWeekday returns the weekday of a name such as "mon" or "mondays".
+func dueWeekday(w string) time.Weekday {
+	if wd, ok := dueWeekdayNames[w]; ok {
+		return wd
+	}
+
+	return dueWeekdayNames[strings.TrimSuffix(w, "s")]
+}
+
+// dueOrdinal returns the day of an ordinal such as "15th".
+func dueOrdinal(w string) (int, bool) {
+	if !dueOrdinalPattern.MatchString(w) {
+		return 0, false
+	}
+
+	day, _ := strconv.Atoi(w[:len(w)-2])
+	if day < 1 || day > 31 {
+		return 0, false
+	}
+
+	return day, true
+}
+
+// dueDayOfMonth returns the day of "15" or "15th", or 0 if w is not a day of
+// the month.
+func dueDayOfMonth(w string) int {
+	if day, ok := dueOrdinal(w); ok {
+		return day
+	}
+
+	if !dueNumberPattern.MatchString(w) || len(w) > 2 {
+		return 0
+	}
+
+	day, _ := strconv.Atoi(w)
+	if day < 1 || day > 31 {
+		return 0
+	}
+
+	return day
+}
@@ -0,0 +1,313 @@
+package todoist
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+var (
+	// Wednesday, March 9 2022, 10:30 in the user's timezone.
+	dueTestLocation = time.FixedZone("CET", 60*60)
+	dueTestNow      = time.Date(2022, time.March, 9, 10, 30, 0, 0, dueTestLocation)
+	dueTestSettings = DueSettings{Location: dueTestLocation, StartDay: time.Monday, NextWeek: time.Monday}
+)
+
+func Test_ParseDueString(t *testing.T) {
+	tests := []struct {
+		s    string
+		want string
+	}{
+		{s: "today", want: "2022-03-09"},
+		{s: "Today", want: "2022-03-09"},
+		{s: "tod", want: "2022-03-09"},
+		{s: "tomorrow", want: "2022-03-10"},
+		{s: "tom", want: "2022-03-10"},
+		{s: "yesterday", want: "2022-03-08"},
+		{s: "tonight", want: "2022-03-09T19:00:00"},
+
+		// Times
+		{s: "tomorrow at 5pm", want: "2022-03-10T17:00:00"},
+		{s: "5pm tomorrow", want: "2022-03-10T17:00:00"},
+		{s: "tomorrow 17:30", want: "2022-03-10T17:30:00"},
+		{s: "tomorrow morning", want: "2022-03-10T09:00:00"},
+		{s: "today at 9am", want: "2022-03-09T09:00:00"},
+		{s: "at 5pm", want: "2022-03-09T17:00:00"},
+		{s: "5 pm", want: "2022-03-09T17:00:00"},
+		{s: "at 15", want: "2022-03-09T15:00:00"},
+		{s: "9am", want: "2022-03-10T09:00:00"},
+		{s: "12am", want: "2022-03-10T00:00:00"},
+		{s: "noon", want: "2022-03-09T12:00:00"},
+		{s: "evening", want: "2022-03-09T19:00:00"},
+
+		// Weekdays
+		{s: "friday", want: "2022-03-11"},
+		{s: "on monday", want: "2022-03-14"},
+		{s: "wed", want: "2022-03-16"},
+		{s: "fri at 9:15am", want: "2022-03-11T09:15:00"},
+		{s: "this friday", want: "2022-03-11"},
+		{s: "this wed", want: "2022-03-09"},
+		{s: "next monday", want: "2022-03-14"},
+		{s: "next friday", want: "2022-03-18"},
+		{s: "next sunday", want: "2022-03-20"},
+
+		// Periods
+		{s: "next week", want: "2022-03-14"},
+		{s: "this weekend", want: "2022-03-12"},
+		{s: "weekend", want: "2022-03-12"},
+		{s: "next weekend", want: "2022-03-19"},
+		{s: "next month", want: "2022-04-01"},
+		{s: "next year", want: "2023-01-01"},
+		{s: "end of month", want: "2022-03-31"},
+		{s: "end of the month", want: "2022-03-31"},
+
+		// Relative
+		{s: "in 3 days", want: "2022-03-12"},
+		{s: "in a week", want: "2022-03-16"},
+		{s: "in 2 weeks", want: "2022-03-23"},
+		{s: "in 1 month", want: "2022-04-09"},
+		{s: "in 2 years", want: "2024-03-09"},
+		{s: "in 2 hours", want: "2022-03-09T12:30:00"},
+		{s: "in 45 minutes", want: "2022-03-09T11:15:00"},
+		{s: "in 20 hours", want: "2022-03-10T06:30:00"},
+
+		// Dates
+		{s: "march 20", want: "2022-03-20"},
+		{s: "20 march", want: "2022-03-20"},
+		{s: "mar 20th", want: "2022-03-20"},
+		{s: "jan 5", want: "2023-01-05"},
+		{s: "5th of jan", want: "2023-01-05"},
+		{s: "jan 5th 2024", want: "2024-01-05"},
+		{s: "feb 29", want: "2024-02-29"},
+		{s: "2022-12-25", want: "2022-12-25"},
+		{s: "3/4", want: "2023-03-04"},
+		{s: "3/4/2022", want: "2022-03-04"},
+		{s: "12/31/22", want: "2022-12-31"},
+		{s: "the 15th", want: "2022-03-15"},
+		{s: "9th", want: "2022-03-09"},
+		{s: "5th", want: "2022-04-05"},
+		{s: "31st", want: "2022-03-31"},
+		{s: "dec 24 at 8pm", want: "2022-12-24T20:00:00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			due, r, err := ParseDueString(tt.s, dueTestNow, dueTestSettings)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if r != nil {
+				t.Errorf("expected no recurrence, received %+v", r)
+			}
+
+			want := Due{Date: tt.want, String: tt.s, Lang: "en"}
+			if due != want {
+				t.Errorf("expected %+v, received %+v", want, due)
+			}
+		})
+	}
+}
+
+func Test_ParseDueString_Recurring(t *testing.T) {
+	weekdays := []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+
+	tests := []struct {
+		s     string
+		want  string
+		rule  Recurrence
+		until string
+	}{
+		{s: "every day", want: "2022-03-09", rule: Recurrence{Frequency: Daily, Interval: 1}},
+		{s: "daily", want: "2022-03-09", rule: Recurrence{Frequency: Daily, Interval: 1}},
+		{s: "ev day", want: "2022-03-09", rule: Recurrence{Frequency: Daily, Interval: 1}},
+		{s: "every other day", want: "2022-03-09", rule: Recurrence{Frequency: Daily, Interval: 2}},
+		{s: "every 3 days", want: "2022-03-09", rule: Recurrence{Frequency: Daily, Interval: 3}},
+		{s: "every day at 9am", want: "2022-03-10T09:00:00", rule: Recurrence{Frequency: Daily, Interval: 1, HasTime: true, Hour: 9}},
+		{s: "every day at 5:45pm", want: "2022-03-09T17:45:00", rule: Recurrence{Frequency: Daily, Interval: 1, HasTime: true, Hour: 17, Minute: 45}},
+		{s: "every morning", want: "2022-03-10T09:00:00", rule: Recurrence{Frequency: Daily, Interval: 1, HasTime: true, Hour: 9}},
+		{s: "every evening", want: "2022-03-09T19:00:00", rule: Recurrence{Frequency: Daily, Interval: 1, HasTime: true, Hour: 19}},
+		{s: "every hour", want: "2022-03-09T10:30:00", rule: Recurrence{Frequency: Hourly, Interval: 1, HasTime: true, Hour: 10, Minute: 30}},
+		{s: "every 4 hours", want: "2022-03-09T10:30:00", rule: Recurrence{Frequency: Hourly, Interval: 4, HasTime: true, Hour: 10, Minute: 30}},
+
+		{s: "every weekday", want: "2022-03-09", rule: Recurrence{Frequency: Weekly, Interval: 1, Weekdays: weekdays}},
+		{s: "every workday at 9am", want: "2022-03-10T09:00:00", rule: Recurrence{Frequency: Weekly, Interval: 1, Weekdays: weekdays, HasTime: true, Hour: 9}},
+		{s: "every weekend", want: "2022-03-12", rule: Recurrence{Frequency: Weekly, Interval: 1, Weekdays: []time.Weekday{time.Saturday, time.Sunday}}},
+		{s: "every mon", want: "2022-03-14", rule: Recurrence{Frequency: Weekly, Interval: 1, Weekdays: []time.Weekday{time.Monday}}},
+		{s: "every mondays", want: "2022-03-14", rule: Recurrence{Frequency: Weekly, Interval: 1, Weekdays: []time.Weekday{time.Monday}}},
+		{s: "every monday, wednesday and friday", want: "2022-03-09", rule: Recurrence{Frequency: Weekly, Interval: 1, Weekdays: []time.Weekday{time.Monday, time.Wednesday, time.Friday}}},
+		{s: "every tue, thu at 8pm", want: "2022-03-10T20:00:00", rule: Recurrence{Frequency: Weekly, Interval: 1, Weekdays: []time.Weekday{time.Tuesday, time.Thursday}, HasTime: true, Hour: 20}},
+		{s: "every week", want: "2022-03-09", rule: Recurrence{Frequency: Weekly, Interval: 1, Weekdays: []time.Weekday{time.Wednesday}}},
+		{s: "weekly", want: "2022-03-09", rule: Recurrence{Frequency: Weekly, Interval: 1, Weekdays: []time.Weekday{time.Wednesday}}},
+		{s: "every other week", want: "2022-03-09", rule: Recurrence{Frequency: Weekly, Interval: 2, Weekdays: []time.Weekday{time.Wednesday}}},
+		{s: "every 2 weeks", want: "2022-03-09", rule: Recurrence{Frequency: Weekly, Interval: 2, Weekdays: []time.Weekday{time.Wednesday}}},
+		{s: "every 2 weeks on monday", want: "2022-03-14", rule: Recurrence{Frequency: Weekly, Interval: 2, Weekdays: []time.Weekday{time.Monday}}},
+		{s: "every week on mon and thu", want: "2022-03-10", rule: Recurrence{Frequency: Weekly, Interval: 1, Weekdays: []time.Weekday{time.Monday, time.Thursday}}},
+		{s: "every other tue at 9am starting next month", want: "2022-04-05T09:00:00", rule: Recurrence{Frequency: Weekly, Interval: 2, Weekdays: []time.Weekday{time.Tuesday}, HasTime: true, Hour: 9}},
+
+		{s: "every month", want: "2022-03-09", rule: Recurrence{Frequency: Monthly, Interval: 1, MonthDays: []int{9}}},
+		{s: "monthly", want: "2022-03-09", rule: Recurrence{Frequency: Monthly, Interval: 1, MonthDays: []int{9}}},
+		{s: "every 1st", want: "2022-04-01", rule: Recurrence{Frequency: Monthly, Interval: 1, MonthDays: []int{1}}},
+		{s: "every 1st of the month", want: "2022-04-01", rule: Recurrence{Frequency: Monthly, Interval: 1, MonthDays: []int{1}}},
+		{s: "every month on the 15th", want: "2022-03-15", rule: Recurrence{Frequency: Monthly, Interval: 1, MonthDays: []int{15}}},
+		{s: "every 2 months on the last day", want: "2022-03-31", rule: Recurrence{Frequency: Monthly, Interval: 2, MonthDays: []int{-1}}},
+		{s: "every 1st and 15th", want: "2022-03-15", rule: Recurrence{Frequency: Monthly, Interval: 1, MonthDays: []int{1, 15}}},
+		{s: "every 15th, last day", want: "2022-03-15", rule: Recurrence{Frequency: Monthly, Interval: 1, MonthDays: []int{15, -1}}},
+		{s: "every last day", want: "2022-03-31", rule: Recurrence{Frequency: Monthly, Interval: 1, MonthDays: []int{-1}}},
+		{s: "every 9th at noon", want: "2022-03-09T12:00:00", rule: Recurrence{Frequency: Monthly, Interval: 1, MonthDays: []int{9}, HasTime: true, Hour: 12}},
+		{s: "every 3 months", want: "2022-03-09", rule: Recurrence{Frequency: Monthly, Interval: 3, MonthDays: []int{9}}},
+		{s: "every quarter", want: "2022-03-09", rule: Recurrence{Frequency: Monthly, Interval: 3, MonthDays: []int{9}}},
+
+		{s: "every year", want: "2022-03-09", rule: Recurrence{Frequency: Yearly, Interval: 1, Month: time.March, MonthDays: []int{9}}},
+		{s: "every jan 5", want: "2023-01-05", rule: Recurrence{Frequency: Yearly, Interval: 1, Month: time.January, MonthDays: []int{5}}},
+		{s: "every 5 jan", want: "2023-01-05", rule: Recurrence{Frequency: Yearly, Interval: 1, Month: time.January, MonthDays: []int{5}}},
+		{s: "every 20th of march", want: "2022-03-20", rule: Recurrence{Frequency: Yearly, Interval: 1, Month: time.March, MonthDays: []int{20}}},
+
+		{s: "every day starting tomorrow", want: "2022-03-10", rule: Recurrence{Frequency: Daily, Interval: 1}},
+		{s: "every mon starting 2022-04-01", want: "2022-04-04", rule: Recurrence{Frequency: Weekly, Interval: 1, Weekdays: []time.Weekday{time.Monday}}},
+		{s: "every day at 9am starting today", want: "2022-03-09T09:00:00", rule: Recurrence{Frequency: Daily, Interval: 1, HasTime: true, Hour: 9}},
+		{s: "every day until mar 20", want: "2022-03-09", rule: Recurrence{Frequency: Daily, Interval: 1}, until: "2022-03-20T23:59:59"},
+		{s: "every week ending 2022-06-30", want: "2022-03-09", rule: Recurrence{Frequency: Weekly, Interval: 1, Weekdays: []time.Weekday{time.Wednesday}}, until: "2022-06-30T23:59:59"},
+		{s: "every day for 3 days", want: "2022-03-09", rule: Recurrence{Frequency: Daily, Interval: 1}, until: "2022-03-11T23:59:59"},
+		{s: "every mon for 2 weeks starting next week", want: "2022-03-14", rule: Recurrence{Frequency: Weekly, Interval: 1, Weekdays: []time.Weekday{time.Monday}}, until: "2022-03-27T23:59:59"},
+
+		{s: "every! 3 days", want: "2022-03-09", rule: Recurrence{Frequency: Daily, Interval: 3, FromCompletion: true}},
+		{s: "after 2 weeks", want: "2022-03-09", rule: Recurrence{Frequency: Weekly, Interval: 2, FromCompletion: true}},
+		{s: "every! mon", want: "2022-03-14", rule: Recurrence{Frequency: Weekly, Interval: 1, Weekdays: []time.Weekday{time.Monday}, FromCompletion: true}},
+		{s: "every! week on monday", want: "2022-03-14", rule: Recurrence{Frequency: Weekly, Interval: 1, Weekdays: []time.Weekday{time.Monday}, FromCompletion: true}},
+		{s: "every! month on the 15th", want: "2022-03-15", rule: Recurrence{Frequency: Monthly, Interval: 1, MonthDays: []int{15}, FromCompletion: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			due, r, err := ParseDueString(tt.s, dueTestNow, dueTestSettings)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			want := Due{Date: tt.want, String: tt.s, Lang: "en", IsRecurring: true}
+			if due != want {
+				t.Errorf("expected %+v, received %+v", want, due)
+			}
+			if r == nil {
+				t.Fatal("expected a recurrence")
+			}
+
+			start, err := due.Time(dueTestLocation)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !r.Start.Equal(start) {
+				t.Errorf("expected the rule to start at %s, received %s", start, r.Start)
+			}
+
+			until := ""
+			if !r.Until.IsZero() {
+				until = r.Until.Format(DueFloatingTimeLayout)
+			}
+			if until != tt.until {
+				t.Errorf("expected the rule to end at %q, received %q", tt.until, until)
+			}
+
+			got := *r
+			got.Start, got.Until = time.Time{}, time.Time{}
+			tt.rule.WeekStart = time.Monday
+			if !reflect.DeepEqual(got, tt.rule) {
+				t.Errorf("expected rule %+v, received %+v", tt.rule, got)
+			}
+		})
+	}
+}
+
+func Test_ParseDueString_Settings(t *testing.T) {
+	tests := []struct {
+		name     string
+		s        string
+		now      time.Time
+		settings DueSettings
+		want     string
+	}{
+		{name: "week starting on sunday", s: "next sunday", now: dueTestNow, settings: DueSettings{Location: dueTestLocation, StartDay: time.Sunday, NextWeek: time.Monday}, want: "2022-03-13"},
+		{name: "next week on friday", s: "next week", now: dueTestNow, settings: DueSettings{Location: dueTestLocation, StartDay: time.Monday, NextWeek: time.Friday}, want: "2022-03-18"},
+		{name: "next weekend with week starting on saturday", s: "next weekend", now: dueTestNow, settings: DueSettings{Location: dueTestLocation, StartDay: time.Saturday}, want: "2022-03-12"},
+		{name: "day first", s: "3/4", now: dueTestNow, settings: DueSettings{Location: dueTestLocation, DayFirst: true}, want: "2022-04-03"},
+		{name: "user timezone", s: "today", now: time.Date(2022, time.March, 9, 23, 30, 0, 0, time.UTC), settings: DueSettings{Location: time.FixedZone("EEST", 3*60*60)}, want: "2022-03-10"},
+		{name: "default timezone", s: "today", now: time.Date(2022, time.March, 9, 23, 30, 0, 0, dueTestLocation), settings: DueSettings{}, want: "2022-03-09"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			due, _, err := ParseDueString(tt.s, tt.now, tt.settings)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if due.Date != tt.want {
+				t.Errorf("expected %q, received %q", tt.want, due.Date)
+			}
+		})
+	}
+}
+
+func Test_ParseDueString_Errors(t *testing.T) {
+	tests := []string{
+		"",
+		"  ",
+		"every",
+		"every other",
+		"someday",
+		"tomorrow tomorrow",
+		"tomorrow at 5pm at 6pm",
+		"at 25",
+		"13pm",
+		"feb 30 2022",
+		"2/30/2022",
+		"in 3 parsecs",
+		"on",
+		"starting tomorrow",
+		"every day until",
+		"every day starting",
+		"every day for 3 hours",
+		"every day until yesterday",
+		"every week on",
+		"every month on",
+		"every feb 30",
+		"every 31st of april",
+		"every 99999999999 days",
+		"every 1001 hours",
+		"every day for 99999999999 days",
+	}
+
+	for _, s := range tests {
+		t.Run(s, func(t *testing.T) {
+			if due, r, err := ParseDueString(s, dueTestNow, dueTestSettings); err == nil {
+				t.Errorf("expected an error, received %+v and %+v", due, r)
+			}
+		})
+	}
+}
+
+func Test_User_DueSettings(t *testing.T) {
+	user := User{
+		StartDay:   7,
+		DateFormat: 0,
+		TZInfo:     TZInfo{Timezone: "Mars/Olympus_Mons", GMTString: "+05:30", Hours: 5, Minutes: 30},
+	}
+
+	settings := user.DueSettings()
+	if settings.StartDay != time.Sunday || settings.NextWeek != time.Sunday || !settings.DayFirst {
+		t.Errorf("unexpected settings %+v", settings)
+	}
+
+	// Unknown timezones fall back to the offset from GMT.
+	if _, offset := dueTestNow.In(settings.Location).Zone(); offset != 5*60*60+30*60 {
+		t.Errorf("expected an offset of 5:30, received %d", offset)
+	}
+
+	user = User{StartDay: 1, NextWeek: 5, DateFormat: 1, TZInfo: TZInfo{Hours: -3, Minutes: 30}}
+	settings = user.DueSettings()
+	if settings.StartDay != time.Monday || settings.NextWeek != time.Friday || settings.DayFirst {
+		t.Errorf("unexpected settings %+v", settings)
+	}
+	if _, offset := dueTestNow.In(settings.Location).Zone(); offset != -(3*60*60 + 30*60) {
+		t.Errorf("expected an offset of -3:30, received %d", offset)
+	}
+}
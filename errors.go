@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
+	"strings"
 
 	"github.com/pkg/errors"
 )
@@ -99,6 +101,39 @@ type SyncError struct {
 	ID string `json:"-"` // original command UUID
 }
 
+// newSyncError builds the SyncError of a command from its non "ok"
+// sync_status value.
+func newSyncError(cmdID string, cmdResult interface{}) (SyncError, error) {
+	// Serialize the command result back into an "unmarshallable" string
+	cmdResultBytes, _ := json.Marshal(cmdResult)
+
+	var syncErr SyncError
+	if err := json.Unmarshal(cmdResultBytes, &syncErr); err != nil {
+		return SyncError{}, err
+	}
+
+	syncErr.ID = cmdID
+
+	return syncErr, nil
+}
+
+// decodeBaseError builds a BaseError from an error response body. The Sync API
+// responds with a JSON error object, while the REST API responds with a plain
+// text message, which is used as the error message as is.
+func decodeBaseError(r *http.Response, body []byte) BaseError {
+	var baseError BaseError
+	if err := json.Unmarshal(body, &baseError); err != nil {
+		baseError = BaseError{Message: strings.TrimSpace(string(body))}
+	}
+
+	baseError.Response = r
+	if baseError.HTTPCode == 0 {
+		baseError.HTTPCode = r.StatusCode
+	}
+
+	return baseError
+}
+
 // checkResponseForErrors checks the API response for an error, and returns it if
 // present. A response is considered an error if it has a status code not equal
 // to 200 OK, or it has values in the `sync_status` field that are not equal
@@ -123,6 +158,17 @@ func checkResponseForErrors(r *http.Response, v interface{}) error {
 	// partially correct. If the response is a CommandResponse, there might be errors
 	// in the sync_status field, so we still need to check that field for any errors.
 	case http.StatusOK:
+		if len(body) == 0 {
+			return nil
+		}
+
+		// Raw responses (such as exported files) are not JSON, and are
+		// written to v by Do as is.
+		if _, ok := v.(io.Writer); ok {
+			r.Body = ioutil.NopCloser(bytes.NewBuffer(body))
+			return nil
+		}
+
 		if err = json.Unmarshal(body, &v); err != nil {
 			// TODO: handle this nicer
 			return err
@@ -131,23 +177,18 @@ func checkResponseForErrors(r *http.Response, v interface{}) error {
 		r.Body = ioutil.NopCloser(bytes.NewBuffer(body))
 
 		switch vType := v.(type) {
-		case CommandResponse:
+		case *CommandResponse:
 			cr := vType
 
 			// Range through each of the sync_status values, and map
 			// each non "ok" value to a SyncError struct
 			for cmdID, cmdResult := range cr.SyncStatus {
 				if cmdResult != "ok" {
-					// Serialize the command result back into an "unmarshallable" string
-					cmdResultBytes, _ := json.Marshal(cmdResult)
-
-					var syncErr SyncError
-					if err = json.Unmarshal(cmdResultBytes, &syncErr); err != nil {
+					syncErr, err := newSyncError(cmdID, cmdResult)
+					if err != nil {
 						return err
 					}
 
-					syncErr.ID = cmdID
-
 					return syncErr
 				}
 			}
@@ -158,68 +199,38 @@ func checkResponseForErrors(r *http.Response, v interface{}) error {
 			return nil
 		}
 
+	// The request was processed successfully, and there is no content to return
+	// (used by the REST API for closing, reopening and deleting resources).
+	case http.StatusNoContent:
+		return nil
+
 	// The request was incorrect.
 	case http.StatusBadRequest:
-		var badRequestError BadRequestError
-		if err := json.Unmarshal(body, &badRequestError); err != nil {
-			return err
-		}
-
-		return badRequestError
+		return BadRequestError{decodeBaseError(r, body)}
 
 	// Authentication is required, and has failed, or has not yet been provided.
 	case http.StatusUnauthorized:
-		var unauthorizedError UnauthorizedError
-		if err := json.Unmarshal(body, &unauthorizedError); err != nil {
-			return err
-		}
-
-		return unauthorizedError
+		return UnauthorizedError{decodeBaseError(r, body)}
 
 	// The request was valid, but for something that is forbidden.
 	case http.StatusForbidden:
-		var forbiddenError ForbiddenError
-		if err := json.Unmarshal(body, &forbiddenError); err != nil {
-			return err
-		}
-
-		return forbiddenError
+		return ForbiddenError{decodeBaseError(r, body)}
 
 	// The requested resource could not be found.
 	case http.StatusNotFound:
-		var notFoundError NotFoundError
-		if err := json.Unmarshal(body, &notFoundError); err != nil {
-			return err
-		}
-
-		return notFoundError
+		return NotFoundError{decodeBaseError(r, body)}
 
 	// The user has sent too many requests in a given amount of time.
 	case http.StatusTooManyRequests:
-		var tooManyRequestsError TooManyRequestsError
-		if err := json.Unmarshal(body, &tooManyRequestsError); err != nil {
-			return err
-		}
-
-		return tooManyRequestsError
+		return TooManyRequestsError{decodeBaseError(r, body)}
 
 	// The request failed due to a server error.
 	case http.StatusInternalServerError:
-		var internalServerError InternalServerError
-		if err := json.Unmarshal(body, &internalServerError); err != nil {
-			return err
-		}
-
-		return internalServerError
+		return InternalServerError{decodeBaseError(r, body)}
 
 	// The server is currently unable to handle the request.
 	case http.StatusServiceUnavailable:
-		var serviceUnavailableError ServiceUnavailableError
-		if err := json.Unmarshal(body, &serviceUnavailableError); err != nil {
-			return err
-		}
-
-		return serviceUnavailableError
+		return ServiceUnavailableError{decodeBaseError(r, body)}
 
 	default:
 		unknownError := BaseError{
@@ -0,0 +1,27 @@
+package todoist
+
+// Filter represents a Todoist filter, a saved query over the user's tasks.
+//
+// Todoist API docs: https://developer.todoist.com/sync/v8/#filters
+type Filter struct {
+	// The ID of the filter.
+	ID FilterID `json:"id"`
+
+	// The name of the filter.
+	Name string `json:"name"`
+
+	// The query to search for, such as "today | overdue". Examples of searches can be found in the Todoist help page.
+	Query string `json:"query"`
+
+	// The color of the filter icon. Refer to the name column in the Colors guide for more info.
+	Color Color `json:"color"`
+
+	// Filter's order in the filter list (where the smallest value should place the filter at the top).
+	ItemOrder int `json:"item_order"`
+
+	// Whether the filter is marked as deleted.
+	IsDeleted Bool `json:"is_deleted"`
+
+	// Whether the filter is a favorite.
+	IsFavorite Bool `json:"is_favorite"`
+}
@@ -0,0 +1,447 @@
+package todoist
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ICalComponent is the kind of calendar component tasks are exported as.
+type ICalComponent string
+
+const (
+	// ICalTodo exports tasks as to-dos (VTODO), due at their due date.
+	ICalTodo ICalComponent = "VTODO"
+
+	// ICalEvent exports tasks as events (VEVENT), which more calendar
+	// applications show. Full-day due dates become all-day events.
+	ICalEvent ICalComponent = "VEVENT"
+)
+
+const (
+	defaultICalDomain = "todoist.com"
+	icalProductID     = "-//ides15//todoist-go//EN"
+	icalDateLayout    = "20060102"
+	icalFloatLayout   = "20060102T150405"
+	icalUTCLayout     = "20060102T150405Z"
+	icalLineLength    = 75
+)
+
+// ICalOptions configures the export of tasks to iCalendar.
+type ICalOptions struct {
+	// The kind of component to export tasks as. Defaults to ICalTodo.
+	Component ICalComponent
+
+	// The name of the calendar, shown by calendar applications (X-WR-CALNAME).
+	Name string
+
+	// The user settings recurring due strings are interpreted with, usually from User.DueSettings.
+	Settings DueSettings
+
+	// The projects of the tasks, whose names are exported as categories.
+	Projects []Project
+
+	// The domain of the UIDs, which are derived from the task IDs. Defaults to "todoist.com".
+	Domain string
+
+	// The time of the export (DTSTAMP). Defaults to the current time.
+	Now time.Time
+}
+
+// WriteICalendar writes the tasks with a due date as an RFC 5545 calendar.
+// Deleted tasks are skipped, as are completed tasks when exporting events.
+//
+// Each task keeps the same UID across exports, so subscribed calendars update
+// entries rather than duplicating them. Recurring tasks have an RRULE from
+// their due string when it can be interpreted (see Task.Recurrence); "every!"
+// rules, which depend on when the task is completed, only export their next
+// occurrence.
+func WriteICalendar(w io.Writer, tasks []Task, opts ICalOptions) error {
+	if opts.Component == "" {
+		opts.Component = ICalTodo
+	}
+	if opts.Component != ICalTodo && opts.Component != ICalEvent {
+		return errors.Errorf("unknown iCalendar component %q", opts.Component)
+	}
+	if opts.Domain == "" {
+		opts.Domain = defaultICalDomain
+	}
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	if opts.Settings.Location == nil {
+		opts.Settings.Location = time.UTC
+	}
+
+	projects := make(map[string]string, len(opts.Projects))
+	for _, p := range opts.Projects {
+		projects[p.ID.String()] = p.Name
+	}
+
+	iw := &icalWriter{w: bufio.NewWriter(w)}
+	iw.line("BEGIN", "VCALENDAR")
+	iw.line("VERSION", "2.0")
+	iw.line("PRODID", icalProductID)
+	iw.line("CALSCALE", "GREGORIAN")
+	iw.line("METHOD", "PUBLISH")
+	if opts.Name != "" {
+		iw.line("X-WR-CALNAME", icalText(opts.Name))
+	}
+
+	for i := range tasks {
+		t := &tasks[i]
+		if t.Due == nil || bool(t.IsDeleted) || (bool(t.Checked) && opts.Component == ICalEvent) {
+			continue
+		}
+
+		if err := iw.task(t, projects[t.ProjectID.String()], opts); err != nil {
+			return err
+		}
+	}
+
+	iw.line("END", "VCALENDAR")
+	if iw.err != nil {
+		return iw.err
+	}
+
+	return iw.w.Flush()
+}
+
+// icalWriter writes content lines, keeping the first error.
+type icalWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+// line writes a content line, folding it at 75 octets without splitting UTF-8
+// sequences.
+func (iw *icalWriter) line(name, value string) {
+	if iw.err != nil {
+		return
+	}
+
+	s := name + ":" + value
+
+	// Continuation lines start with a space, which counts towards the length.
+	limit := icalLineLength
+	for len(s) > limit {
+		n := limit
+		for n > 0 && s[n]&0xC0 == 0x80 {
+			n--
+		}
+		if _, iw.err = iw.w.WriteString(s[:n] + "\r\n "); iw.err != nil {
+			return
+		}
+		s = s[n:]
+		limit = icalLineLength - 1
+	}
+
+	_, iw.err = iw.w.WriteString(s + "\r\n")
+}
+
+func (iw *icalWriter) task(t *Task, project string, opts ICalOptions) error {
+	loc := opts.Settings.Location
+	if t.Due.Timezone != nil {
+		if l, err := time.LoadLocation(*t.Due.Timezone); err == nil {
+			loc = l
+		}
+	}
+
+	due, err := t.Due.Time(loc)
+	if err != nil {
+		return errors.Wrapf(err, "task %s", t.ID)
+	}
+
+	// Full-day dates are DATE values, floating dates local times without a
+	// timezone, and dates fixed to a timezone UTC times.
+	var (
+		param  string
+		layout string
+	)
+	switch {
+	case !t.Due.HasTime():
+		param, layout = ";VALUE=DATE", icalDateLayout
+	case t.Due.Timezone != nil || t.Due.Datetime != "" || strings.HasSuffix(t.Due.Date, "Z"):
+		due, layout = due.UTC(), icalUTCLayout
+	default:
+		layout = icalFloatLayout
+	}
+
+	iw.line("BEGIN", string(opts.Component))
+	iw.line("UID", fmt.Sprintf("task-%s@%s", t.ID, opts.Domain))
+	iw.line("DTSTAMP", opts.Now.UTC().Format(icalUTCLayout))
+	iw.line("SUMMARY", icalText(t.Content))
+	if t.Description != "" {
+		iw.line("DESCRIPTION", icalText(t.Description))
+	}
+	if project != "" {
+		iw.line("CATEGORIES", icalText(project))
+	}
+	if p := icalPriority(t.Priority); p != 0 {
+		iw.line("PRIORITY", strconv.Itoa(p))
+	}
+	iw.line("URL", "https://todoist.com/showTask?id="+t.ID.String())
+
+	// RFC 5545 requires the DUE of a to-do to be later than its DTSTART, so
+	// to-dos only have a DUE.
+	if opts.Component == ICalEvent {
+		iw.line("DTSTART"+param, due.Format(layout))
+	}
+	if opts.Component == ICalTodo {
+		iw.line("DUE"+param, due.Format(layout))
+		if bool(t.Checked) {
+			iw.line("STATUS", "COMPLETED")
+		} else {
+			iw.line("STATUS", "NEEDS-ACTION")
+		}
+	}
+
+	if t.Due.IsRecurring {
+		if r, err := t.Recurrence(opts.Settings); err == nil && r != nil && !r.FromCompletion {
+			iw.line("RRULE", r.rrule(layout))
+		}
+	}
+
+	iw.line("END", string(opts.Component))
+
+	return nil
+}
+
+// icalPriority converts a task priority (4 for p1, down to 1 for p4) to an
+// iCalendar priority (1 for the highest, or 0 for none).
+func icalPriority(priority int) int {
+	switch priority {
+	case 4:
+		return 1
+	case 3:
+		return 5
+	case 2:
+		return 9
+	default:
+		return 0
+	}
+}
+
+var icalTextEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+
+// icalText escapes a TEXT value.
+func icalText(s string) string {
+	return icalTextEscaper.Replace(s)
+}
+
+var icalWeekdays = [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// RRule returns the rule as an RFC 5545 RRULE value, such as
+// "FREQ=WEEKLY;INTERVAL=2;BYDAY=TU;WKST=MO". Days past the end of a month are
+// expressed with BYSETPOS, so that they fall on the last day of shorter months.
+func (r Recurrence) RRule() string {
+	layout := icalDateLayout
+	if r.HasTime {
+		layout = icalFloatLayout
+	}
+
+	return r.rrule(layout)
+}
+
+// rrule returns the RRULE value, with UNTIL in the layout of DTSTART.
+func (r Recurrence) rrule(layout string) string {
+	parts := []string{"FREQ=" + strings.ToUpper(string(r.Frequency))}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+
+	switch r.Frequency {
+	case Weekly:
+		days := make([]string, 0, len(r.Weekdays))
+		for _, wd := range r.Weekdays {
+			days = append(days, icalWeekdays[wd])
+		}
+		if len(days) != 0 {
+			parts = append(parts, "BYDAY="+strings.Join(days, ","))
+		}
+
+	case Monthly:
+		parts = append(parts, icalMonthDays(r.MonthDays)...)
+
+	case Yearly:
+		if r.Month != 0 {
+			parts = append(parts, "BYMONTH="+strconv.Itoa(int(r.Month)))
+		}
+		parts = append(parts, icalMonthDays(r.MonthDays[:icalMin(len(r.MonthDays), 1)])...)
+	}
+
+	if !r.Until.IsZero() {
+		until := r.Until
+		if layout == icalUTCLayout {
+			until = until.UTC()
+		}
+		parts = append(parts, "UNTIL="+until.Format(layout))
+	}
+
+	if r.Frequency == Weekly {
+		parts = append(parts, "WKST="+icalWeekdays[r.WeekStart])
+	}
+
+	return strings.Join(parts, ";")
+}
+
+// icalMonthDays returns the BYMONTHDAY parts for days of the month. A single
+// day past the 28th falls on the last day of months that are too short, which
+// RFC 5545 only expresses with BYSETPOS.
+//
+// In a list of days, the 31st always falls on the last day of the month, and
+// is exported as -1. The 29th and 30th cannot be expressed in a list, and
+// skip the months that are too short, where Recurrence.Next falls on their
+// last day instead.
+func icalMonthDays(days []int) []string {
+	if len(days) == 0 {
+		return nil
+	}
+
+	if len(days) == 1 && days[0] > 28 {
+		candidates := make([]string, 0, 4)
+		for d := 28; d <= days[0]; d++ {
+			candidates = append(candidates, strconv.Itoa(d))
+		}
+		return []string{"BYMONTHDAY=" + strings.Join(candidates, ","), "BYSETPOS=-1"}
+	}
+
+	s := make([]string, 0, len(days))
+	seen := map[int]bool{}
+	for _, d := range days {
+		if d == 31 {
+			d = -1
+		}
+		if seen[d] {
+			continue
+		}
+		seen[d] = true
+		s = append(s, strconv.Itoa(d))
+	}
+
+	return []string{"BYMONTHDAY=" + strings.Join(s, ",")}
+}
+
+func icalMin(a, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
+
+// ICalHandler is an http.Handler serving the user's tasks with a due date as
+// an iCalendar feed that calendar applications can subscribe to.
+type ICalHandler struct {
+	client *Client
+	opts   ICalOptions
+
+	// The tasks and projects, kept up to date with incremental syncs, and
+	// the user as last synced.
+	mu    sync.Mutex
+	store *Store
+	user  *User
+}
+
+// NewICalHandler returns a handler that syncs the tasks, projects and user
+// settings with client on each request and serves them as a calendar. The
+// first request does a full sync, and later ones only fetch what changed.
+// Options.Projects is ignored, and Options.Settings is taken from the user
+// unless it has a Location.
+func NewICalHandler(client *Client, opts ICalOptions) *ICalHandler {
+	return &ICalHandler{client: client, opts: opts, store: NewStore()}
+}
+
+// ServeHTTP implements http.Handler. Responses carry an ETag, so that
+// subscribed calendars polling an unchanged feed receive 304 Not Modified.
+func (h *ICalHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	tasks, projects, user, err := h.sync(r.Context())
+	if err != nil {
+		h.client.Logf("ical: %v\n", err)
+		http.Error(w, "unable to sync tasks", http.StatusBadGateway)
+		return
+	}
+
+	opts := h.opts
+	opts.Projects = projects
+	if opts.Settings.Location == nil && user != nil {
+		opts.Settings = user.DueSettings()
+	}
+
+	// The ETag is computed with a fixed DTSTAMP, so that it only changes with
+	// the tasks.
+	now := opts.Now
+	opts.Now = time.Unix(0, 0)
+
+	var buf bytes.Buffer
+	if err = WriteICalendar(&buf, tasks, opts); err != nil {
+		h.client.Logf("ical: %v\n", err)
+		http.Error(w, "unable to export tasks", http.StatusInternalServerError)
+		return
+	}
+
+	sum := sha256.Sum256(buf.Bytes())
+	etag := `W/"` + hex.EncodeToString(sum[:16]) + `"`
+
+	w.Header().Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	buf.Reset()
+	opts.Now = now
+	if err = WriteICalendar(&buf, tasks, opts); err != nil {
+		http.Error(w, "unable to export tasks", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="todoist.ics"`)
+	if r.Method == http.MethodHead {
+		return
+	}
+
+	_, _ = w.Write(buf.Bytes())
+}
+
+// sync fetches the changes since the previous request, and returns the
+// tasks, projects and user.
+func (h *ICalHandler) sync(ctx context.Context) ([]Task, []Project, *User, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	req, err := h.client.NewRequest(h.store.SyncToken(), []string{"projects", "items", "user"}, nil)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var readResponse ReadResponse
+	if _, err = h.client.Do(ctx, req, &readResponse); err != nil {
+		return nil, nil, nil, err
+	}
+
+	h.store.Apply(readResponse)
+	if readResponse.User != nil {
+		h.user = readResponse.User
+	}
+
+	return h.store.Tasks(), h.store.Projects(), h.user, nil
+}
@@ -0,0 +1,268 @@
+package todoist
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ides15/todoist/todoisttest"
+)
+
+func Test_WriteICalendar(t *testing.T) {
+	utc := "UTC"
+	home := ProjectID{NewID("10")}
+	tasks := []Task{
+		{ID: TaskID{NewID("1")}, ProjectID: home, Content: "Pay rent", Description: "Bank transfer, ref; 42", Priority: 4, Due: &Due{Date: "2022-04-01", String: "every 1st", Lang: "en", IsRecurring: true}},
+		{ID: TaskID{NewID("2")}, ProjectID: home, Content: "Standup", Priority: 1, Due: &Due{Date: "2022-03-10T09:00:00", String: "every weekday at 9am", Lang: "en", IsRecurring: true}},
+		{ID: TaskID{NewID("3")}, Content: "Flight", Priority: 3, Due: &Due{Date: "2022-03-12T08:30:00Z", Timezone: &utc, String: "mar 12 at 9:30"}},
+		{ID: TaskID{NewID("4")}, Content: "No due date"},
+		{ID: TaskID{NewID("5")}, Content: "Deleted", IsDeleted: true, Due: &Due{Date: "2022-03-10"}},
+		{ID: TaskID{NewID("6")}, Content: "Done", Checked: true, Due: &Due{Date: "2022-03-08"}},
+		{ID: TaskID{NewID("7")}, Content: "Water plants", Due: &Due{Date: "2022-03-09", String: "every! 3 days", Lang: "en", IsRecurring: true}},
+	}
+
+	opts := ICalOptions{
+		Name:     "Todoist",
+		Settings: dueTestSettings,
+		Projects: []Project{{ID: home, Name: "Home"}},
+		Now:      dueTestNow,
+	}
+
+	var buf bytes.Buffer
+	if err := WriteICalendar(&buf, tasks, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	want := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//ides15//todoist-go//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		"X-WR-CALNAME:Todoist",
+		"BEGIN:VTODO",
+		"UID:task-1@todoist.com",
+		"DTSTAMP:20220309T093000Z",
+		"SUMMARY:Pay rent",
+		`DESCRIPTION:Bank transfer\, ref\; 42`,
+		"CATEGORIES:Home",
+		"PRIORITY:1",
+		"URL:https://todoist.com/showTask?id=1",
+		"DUE;VALUE=DATE:20220401",
+		"STATUS:NEEDS-ACTION",
+		"RRULE:FREQ=MONTHLY;BYMONTHDAY=1",
+		"END:VTODO",
+		"BEGIN:VTODO",
+		"UID:task-2@todoist.com",
+		"DTSTAMP:20220309T093000Z",
+		"SUMMARY:Standup",
+		"CATEGORIES:Home",
+		"URL:https://todoist.com/showTask?id=2",
+		"DUE:20220310T090000",
+		"STATUS:NEEDS-ACTION",
+		"RRULE:FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR;WKST=MO",
+		"END:VTODO",
+		"BEGIN:VTODO",
+		"UID:task-3@todoist.com",
+		"DTSTAMP:20220309T093000Z",
+		"SUMMARY:Flight",
+		"PRIORITY:5",
+		"URL:https://todoist.com/showTask?id=3",
+		"DUE:20220312T083000Z",
+		"STATUS:NEEDS-ACTION",
+		"END:VTODO",
+		"BEGIN:VTODO",
+		"UID:task-6@todoist.com",
+		"DTSTAMP:20220309T093000Z",
+		"SUMMARY:Done",
+		"URL:https://todoist.com/showTask?id=6",
+		"DUE;VALUE=DATE:20220308",
+		"STATUS:COMPLETED",
+		"END:VTODO",
+		"BEGIN:VTODO",
+		"UID:task-7@todoist.com",
+		"DTSTAMP:20220309T093000Z",
+		"SUMMARY:Water plants",
+		"URL:https://todoist.com/showTask?id=7",
+		"DUE;VALUE=DATE:20220309",
+		"STATUS:NEEDS-ACTION",
+		"END:VTODO",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n")
+
+	if got := buf.String(); got != want {
+		t.Errorf("unexpected calendar:\n%s\nexpected:\n%s", got, want)
+	}
+
+	// Events leave out completed tasks and the DUE and STATUS properties.
+	buf.Reset()
+	opts.Component = ICalEvent
+	if err := WriteICalendar(&buf, tasks, opts); err != nil {
+		t.Fatal(err)
+	}
+	got := buf.String()
+	if strings.Count(got, "BEGIN:VEVENT") != 4 || strings.Contains(got, "task-6@") || strings.Contains(got, "DUE") || strings.Contains(got, "STATUS") {
+		t.Errorf("unexpected events:\n%s", got)
+	}
+
+	opts.Component = "VJOURNAL"
+	if err := WriteICalendar(&buf, tasks, opts); err == nil {
+		t.Error("expected an error for an unknown component")
+	}
+}
+
+func Test_WriteICalendar_Folding(t *testing.T) {
+	content := strings.Repeat("Überprüfen der Dokumentation, ", 10)
+	tasks := []Task{{ID: TaskID{NewID("1")}, Content: content, Due: &Due{Date: "2022-03-09"}}}
+
+	var buf bytes.Buffer
+	if err := WriteICalendar(&buf, tasks, ICalOptions{Now: dueTestNow}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("expected lines of at most 75 octets, received %d: %q", len(line), line)
+		}
+	}
+
+	unfolded := strings.ReplaceAll(buf.String(), "\r\n ", "")
+	if !strings.Contains(unfolded, "SUMMARY:"+icalText(content)+"\r\n") {
+		t.Errorf("expected the summary to unfold to the content, received:\n%s", unfolded)
+	}
+}
+
+func Test_ICalText(t *testing.T) {
+	if got, want := icalText("a\\b;c,d\r\ne\nf\rg"), `a\\b\;c\,d\ne\nf\ng`; got != want {
+		t.Errorf("expected %q, received %q", want, got)
+	}
+}
+
+func Test_Recurrence_RRule(t *testing.T) {
+	until := time.Date(2022, time.June, 30, 23, 59, 59, 0, dueTestLocation)
+
+	tests := []struct {
+		name string
+		rule Recurrence
+		want string
+	}{
+		{name: "daily", rule: Recurrence{Frequency: Daily, Interval: 1}, want: "FREQ=DAILY"},
+		{name: "interval", rule: Recurrence{Frequency: Daily, Interval: 3}, want: "FREQ=DAILY;INTERVAL=3"},
+		{name: "hourly", rule: Recurrence{Frequency: Hourly, Interval: 2, HasTime: true}, want: "FREQ=HOURLY;INTERVAL=2"},
+		{name: "weekly", rule: Recurrence{Frequency: Weekly, Interval: 2, Weekdays: []time.Weekday{time.Tuesday}, WeekStart: time.Monday}, want: "FREQ=WEEKLY;INTERVAL=2;BYDAY=TU;WKST=MO"},
+		{name: "week starting on sunday", rule: Recurrence{Frequency: Weekly, Interval: 1, Weekdays: []time.Weekday{time.Sunday, time.Saturday}}, want: "FREQ=WEEKLY;BYDAY=SU,SA;WKST=SU"},
+		{name: "monthly", rule: Recurrence{Frequency: Monthly, Interval: 1, MonthDays: []int{1, 15}}, want: "FREQ=MONTHLY;BYMONTHDAY=1,15"},
+		{name: "last day", rule: Recurrence{Frequency: Monthly, Interval: 1, MonthDays: []int{-1}}, want: "FREQ=MONTHLY;BYMONTHDAY=-1"},
+		{name: "15th and 31st", rule: Recurrence{Frequency: Monthly, Interval: 1, MonthDays: []int{15, 31}}, want: "FREQ=MONTHLY;BYMONTHDAY=15,-1"},
+		{name: "31st and last day", rule: Recurrence{Frequency: Monthly, Interval: 1, MonthDays: []int{1, 31, -1}}, want: "FREQ=MONTHLY;BYMONTHDAY=1,-1"},
+		{name: "31st", rule: Recurrence{Frequency: Monthly, Interval: 1, MonthDays: []int{31}}, want: "FREQ=MONTHLY;BYMONTHDAY=28,29,30,31;BYSETPOS=-1"},
+		{name: "yearly", rule: Recurrence{Frequency: Yearly, Interval: 1, Month: time.January, MonthDays: []int{5}}, want: "FREQ=YEARLY;BYMONTH=1;BYMONTHDAY=5"},
+		{name: "leap day", rule: Recurrence{Frequency: Yearly, Interval: 1, Month: time.February, MonthDays: []int{29}}, want: "FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=28,29;BYSETPOS=-1"},
+		{name: "until", rule: Recurrence{Frequency: Daily, Interval: 1, Until: until}, want: "FREQ=DAILY;UNTIL=20220630"},
+		{name: "until with time", rule: Recurrence{Frequency: Daily, Interval: 1, HasTime: true, Hour: 9, Until: until}, want: "FREQ=DAILY;UNTIL=20220630T235959"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule.RRule(); got != tt.want {
+				t.Errorf("expected %q, received %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_ICalHandler(t *testing.T) {
+	client, _ := newFakeClient(t)
+	ctx := context.Background()
+
+	projects, _, err := client.Projects.Add(ctx, "", AddProject{Name: "Work"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var work Project
+	for _, p := range projects {
+		if p.Name == "Work" {
+			work = p
+		}
+	}
+
+	if _, _, err = client.Tasks.Add(ctx, "", AddTask{Content: "Review PRs", ProjectID: &work.ID, Due: &Due{Date: "2022-03-10", String: "every weekday", Lang: "en", IsRecurring: true}}); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewServer(NewICalHandler(client, ICalOptions{Name: "Work", Component: ICalEvent, Settings: dueTestSettings}))
+	t.Cleanup(srv.Close)
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var body bytes.Buffer
+	_, _ = body.ReadFrom(resp.Body)
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/calendar; charset=utf-8" {
+		t.Fatalf("unexpected response %d %v", resp.StatusCode, resp.Header)
+	}
+	for _, want := range []string{"BEGIN:VEVENT", "SUMMARY:Review PRs", "CATEGORIES:Work", "DTSTART;VALUE=DATE:20220310", "RRULE:FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR;WKST=MO"} {
+		if !strings.Contains(body.String(), want+"\r\n") {
+			t.Errorf("expected the feed to contain %q, received:\n%s", want, body.String())
+		}
+	}
+
+	etag := resp.Header.Get("ETag")
+	if etag == "" {
+		t.Fatal("expected an ETag")
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+	req.Header.Set("If-None-Match", etag)
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotModified {
+		t.Errorf("expected 304 for an unchanged feed, received %d", resp.StatusCode)
+	}
+
+	// Later requests only fetch what changed.
+	rec := todoisttest.NewRecorder(nil)
+	client.SetHTTPClient(&http.Client{Transport: rec})
+
+	if _, _, err = client.Tasks.Add(ctx, "", AddTask{Content: "Ship release", ProjectID: &work.ID, Due: &Due{Date: "2022-03-11"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err = http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body.Reset()
+	_, _ = body.ReadFrom(resp.Body)
+	resp.Body.Close()
+
+	for _, want := range []string{"SUMMARY:Review PRs", "SUMMARY:Ship release"} {
+		if !strings.Contains(body.String(), want+"\r\n") {
+			t.Errorf("expected the feed to contain %q, received:\n%s", want, body.String())
+		}
+	}
+	interactions := rec.Cassette().Interactions
+	if last := interactions[len(interactions)-1].Request.Form.Get("sync_token"); last == "*" {
+		t.Error("expected an incremental sync for a later request")
+	}
+
+	resp, err = http.Post(srv.URL, "text/plain", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405 for POST, received %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,309 @@
+package todoist
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// ID identifies a Todoist resource. An ID holds either a real ID assigned by
+// Todoist, or the temp ID of a resource created by a command, which Todoist
+// maps to a real ID in the command response's TempIDMapping.
+//
+// Temp IDs can be used in place of real IDs anywhere a command accepts an ID.
+// Within a single request Todoist resolves them itself; across requests the
+// Client resolves them from the TempIDMapping of earlier responses, so an ID
+// created with NewTempID keeps working after the resource has been created.
+//
+// The zero ID is marshalled as null.
+type ID struct {
+	id   string
+	temp bool
+}
+
+// NewID returns an ID holding a real Todoist ID.
+func NewID(id string) ID {
+	return ID{id: id}
+}
+
+// NewIntID returns an ID holding a real, numeric Todoist ID.
+func NewIntID(id int) ID {
+	return ID{id: strconv.Itoa(id)}
+}
+
+// NewTempID returns an ID holding a temp ID. The temp ID should match the
+// TempID of the command creating the resource.
+func NewTempID(tempID string) ID {
+	return ID{id: tempID, temp: true}
+}
+
+// String returns the real or temp ID.
+func (id ID) String() string {
+	return id.id
+}
+
+// IsTemp reports whether id holds a temp ID.
+func (id ID) IsTemp() bool {
+	return id.temp
+}
+
+// IsZero reports whether id is the zero ID.
+func (id ID) IsZero() bool {
+	return id.id == ""
+}
+
+// Int returns a real, numeric ID as an int. It returns an error for temp IDs
+// and for IDs that are not numeric.
+func (id ID) Int() (int, error) {
+	if id.temp {
+		return 0, errors.Errorf("cannot convert temp id %q to an int", id.id)
+	}
+
+	return strconv.Atoi(id.id)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (id ID) MarshalJSON() ([]byte, error) {
+	if id.IsZero() {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(id.id)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts IDs encoded as JSON
+// numbers or strings.
+func (id *ID) UnmarshalJSON(b []byte) error {
+	*id = ID{}
+
+	if bytes.Equal(b, []byte("null")) {
+		return nil
+	}
+
+	var n json.Number
+	if err := json.Unmarshal(b, &n); err == nil {
+		id.id = n.String()
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return errors.Wrap(err, "unable to unmarshal id")
+	}
+	id.id = s
+
+	return nil
+}
+
+// resolveTempID replaces a temp ID with the real ID it is mapped to, if any.
+func (id *ID) resolveTempID(mapping map[string]ID) {
+	if !id.temp {
+		return
+	}
+
+	if resolved, ok := mapping[id.id]; ok {
+		*id = resolved
+	}
+}
+
+// ProjectID identifies a project.
+type ProjectID struct{ ID }
+
+// SectionID identifies a section.
+type SectionID struct{ ID }
+
+// TaskID identifies a task.
+type TaskID struct{ ID }
+
+// LabelID identifies a label.
+type LabelID struct{ ID }
+
+// UserID identifies a user.
+type UserID struct{ ID }
+
+// CommentID identifies a comment (a note, in the Sync API).
+type CommentID struct{ ID }
+
+// FilterID identifies a filter.
+type FilterID struct{ ID }
+
+// ReminderID identifies a reminder.
+type ReminderID struct{ ID }
+
+// tempIDResolver is implemented by pointers to ID and to the typed IDs
+// embedding it.
+type tempIDResolver interface {
+	resolveTempID(mapping map[string]ID)
+}
+
+// tempIDKeep is the number of temp IDs a client or store remembers the real
+// IDs of.
+const tempIDKeep = 1024
+
+// tempIDMap holds the real IDs of the temp IDs of resources created by
+// commands, keeping only the tempIDKeep most recently recorded ones.
+type tempIDMap struct {
+	ids   map[string]ID
+	order []string // The temp IDs in the order they were recorded.
+}
+
+func (m *tempIDMap) record(mapping map[string]ID) {
+	if m.ids == nil {
+		m.ids = map[string]ID{}
+	}
+	for tempID, id := range mapping {
+		if _, ok := m.ids[tempID]; !ok {
+			m.order = append(m.order, tempID)
+		}
+		m.ids[tempID] = id
+	}
+
+	if n := len(m.order) - tempIDKeep; n > 0 {
+		for _, tempID := range m.order[:n] {
+			delete(m.ids, tempID)
+		}
+		m.order = append([]string(nil), m.order[n:]...)
+	}
+}
+
+func (m *tempIDMap) forget(tempID string) {
+	if _, ok := m.ids[tempID]; !ok {
+		return
+	}
+
+	delete(m.ids, tempID)
+	for i, t := range m.order {
+		if t == tempID {
+			m.order = append(m.order[:i], m.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// recordTempIDs remembers the real IDs of resources created by commands, so
+// that temp IDs referring to them can be resolved in later requests. Only the
+// most recently recorded temp IDs are kept.
+func (c *Client) recordTempIDs(mapping map[string]ID) {
+	if len(mapping) == 0 {
+		return
+	}
+
+	c.tempIDsMu.Lock()
+	defer c.tempIDsMu.Unlock()
+
+	c.tempIDs.record(mapping)
+}
+
+// forgetTempIDs forgets the real IDs of temp IDs that are defined again by
+// new commands, so that references to them are left for Todoist to resolve
+// within the request rather than resolved to the resources created earlier.
+func (c *Client) forgetTempIDs(commands []Command) {
+	c.tempIDsMu.Lock()
+	defer c.tempIDsMu.Unlock()
+
+	for _, cmd := range commands {
+		if cmd.TempID != "" {
+			c.tempIDs.forget(cmd.TempID)
+		}
+	}
+}
+
+// ResolveID returns the real ID a temp ID has been mapped to by an earlier
+// response. Real IDs, and temp IDs that have not been mapped yet, are returned
+// unchanged.
+func (c *Client) ResolveID(id ID) ID {
+	c.tempIDsMu.Lock()
+	defer c.tempIDsMu.Unlock()
+
+	id.resolveTempID(c.tempIDs.ids)
+	return id
+}
+
+// resolveTempIDs returns a copy of v with every known temp ID replaced by its
+// real ID. v itself is left untouched.
+func (c *Client) resolveTempIDs(v interface{}) interface{} {
+	c.tempIDsMu.Lock()
+	defer c.tempIDsMu.Unlock()
+
+	if v == nil || len(c.tempIDs.ids) == 0 {
+		return v
+	}
+
+	cp := reflect.New(reflect.TypeOf(v)).Elem()
+	cp.Set(reflect.ValueOf(v))
+	resolveValue(cp, c.tempIDs.ids)
+
+	return cp.Interface()
+}
+
+var tempIDResolverType = reflect.TypeOf((*tempIDResolver)(nil)).Elem()
+
+// resolveValue resolves the temp IDs held by the settable value v. Values
+// reachable through pointers, slices and maps are copied before being
+// modified, so that they are not shared with the caller.
+func resolveValue(v reflect.Value, mapping map[string]ID) {
+	if v.Addr().Type().Implements(tempIDResolverType) {
+		v.Addr().Interface().(tempIDResolver).resolveTempID(mapping)
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return
+		}
+		cp := reflect.New(v.Elem().Type())
+		cp.Elem().Set(v.Elem())
+		resolveValue(cp.Elem(), mapping)
+		v.Set(cp)
+
+	case reflect.Interface:
+		if v.IsNil() {
+			return
+		}
+		cp := reflect.New(v.Elem().Type()).Elem()
+		cp.Set(v.Elem())
+		resolveValue(cp, mapping)
+		v.Set(cp)
+
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if f := v.Field(i); f.CanSet() {
+				resolveValue(f, mapping)
+			}
+		}
+
+	case reflect.Slice:
+		if v.IsNil() {
+			return
+		}
+		cp := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		reflect.Copy(cp, v)
+		for i := 0; i < cp.Len(); i++ {
+			resolveValue(cp.Index(i), mapping)
+		}
+		v.Set(cp)
+
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			resolveValue(v.Index(i), mapping)
+		}
+
+	case reflect.Map:
+		if v.IsNil() {
+			return
+		}
+		cp := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			elem := reflect.New(v.Type().Elem()).Elem()
+			elem.Set(iter.Value())
+			resolveValue(elem, mapping)
+			cp.SetMapIndex(iter.Key(), elem)
+		}
+		v.Set(cp)
+	}
+}
@@ -0,0 +1,167 @@
+package todoist
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func Test_ID_JSON(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want ID
+	}{
+		{name: "number", json: `2203306141`, want: NewIntID(2203306141)},
+		{name: "string", json: `"6Jf8VQXxpwv56VQ7"`, want: NewID("6Jf8VQXxpwv56VQ7")},
+		{name: "null", json: `null`, want: ID{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var id ID
+			if err := json.Unmarshal([]byte(tt.json), &id); err != nil {
+				t.Fatal(err)
+			}
+			if id != tt.want {
+				t.Errorf("expected %#v, received %#v", tt.want, id)
+			}
+		})
+	}
+
+	b, err := json.Marshal(struct {
+		Project ProjectID  `json:"project_id"`
+		Parent  *ProjectID `json:"parent_id,omitempty"`
+		Section SectionID  `json:"section_id"`
+		Labels  []LabelID  `json:"labels"`
+	}{
+		Project: ProjectID{ID: NewTempID("temp")},
+		Labels:  []LabelID{{ID: NewIntID(1)}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"project_id":"temp","section_id":null,"labels":["1"]}`; string(b) != want {
+		t.Errorf("expected %s, received %s", want, b)
+	}
+
+	if _, err = NewTempID("temp").Int(); err == nil {
+		t.Error("expected an error converting a temp id to an int")
+	}
+	if n, err := NewIntID(42).Int(); err != nil || n != 42 {
+		t.Errorf("expected 42, received %d (%v)", n, err)
+	}
+}
+
+func Test_ResolveTempIDs(t *testing.T) {
+	c, _ := NewClient("12345")
+	c.recordTempIDs(map[string]ID{"project": NewIntID(100), "task": NewIntID(200)})
+
+	parent := TaskID{ID: NewTempID("task")}
+	addTask := AddTask{
+		Content:   "Task",
+		ProjectID: &ProjectID{ID: NewTempID("project")},
+		ParentID:  &parent,
+		SectionID: &SectionID{ID: NewTempID("unknown")},
+	}
+
+	resolved := c.resolveTempIDs(addTask).(AddTask)
+
+	if *resolved.ProjectID != (ProjectID{ID: NewIntID(100)}) {
+		t.Errorf("expected project id to resolve to 100, received %#v", resolved.ProjectID)
+	}
+	if *resolved.ParentID != (TaskID{ID: NewIntID(200)}) {
+		t.Errorf("expected parent id to resolve to 200, received %#v", resolved.ParentID)
+	}
+	if !resolved.SectionID.IsTemp() {
+		t.Errorf("expected unknown temp id to be left alone, received %#v", resolved.SectionID)
+	}
+	if !parent.IsTemp() || !addTask.ProjectID.IsTemp() {
+		t.Error("expected the original value not to be modified")
+	}
+
+	cmds := map[string]interface{}{"id": ProjectID{ID: NewTempID("project")}}
+	if got := c.resolveTempIDs(cmds).(map[string]interface{})["id"]; got != (ProjectID{ID: NewIntID(100)}) {
+		t.Errorf("expected temp id in map to resolve to 100, received %#v", got)
+	}
+
+	if id := c.ResolveID(NewTempID("project")); id != NewIntID(100) {
+		t.Errorf("expected ResolveID to return 100, received %#v", id)
+	}
+}
+
+func Test_TempIDsAcrossRequests(t *testing.T) {
+	client, _ := newFakeClient(t)
+	ctx := context.Background()
+
+	_, _, err := client.Projects.Add(ctx, "", AddProject{Name: "Temp ID project", TempID: "tempIDProject"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	projectID := ProjectID{ID: NewTempID("tempIDProject")}
+	sections, _, err := client.Sections.Add(ctx, "", AddSection{Name: "Temp ID section", ProjectID: projectID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sections) == 0 || sections[len(sections)-1].ProjectID.IsTemp() {
+		t.Fatalf("expected the section to be added to the resolved project, received %+v", sections)
+	}
+
+	if _, _, err = client.Projects.Delete(ctx, "", DeleteProject{ID: projectID}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_TempIDsReused(t *testing.T) {
+	client, _ := newFakeClient(t)
+	ctx := context.Background()
+
+	_, first, err := client.Projects.Add(ctx, "", AddProject{Name: "First", TempID: "reused"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A batch defining the temp ID again refers to the resource it creates.
+	req, err := client.NewRequest("", []string{"sections"}, []Command{
+		{Type: "project_add", Args: AddProject{Name: "Second"}, UUID: uuid.New().String(), TempID: "reused"},
+		{Type: "section_add", Args: AddSection{Name: "Section", ProjectID: ProjectID{ID: NewTempID("reused")}}, UUID: uuid.New().String(), TempID: "section"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var second CommandResponse
+	if _, err = client.Do(ctx, req, &second); err != nil {
+		t.Fatal(err)
+	}
+
+	if second.TempIDMapping["reused"] == first.TempIDMapping["reused"] {
+		t.Fatalf("expected a new project, received %s twice", second.TempIDMapping["reused"])
+	}
+	if len(second.Sections) != 1 || second.Sections[0].ProjectID.ID != second.TempIDMapping["reused"] {
+		t.Errorf("expected the section to be added to the second project, received %+v", second.Sections)
+	}
+	if id := client.ResolveID(NewTempID("reused")); id != second.TempIDMapping["reused"] {
+		t.Errorf("expected the temp ID to resolve to the second project, received %s", id)
+	}
+}
+
+func Test_TempIDsBounded(t *testing.T) {
+	c, _ := NewClient("12345")
+
+	for i := 0; i < tempIDKeep+10; i++ {
+		c.recordTempIDs(map[string]ID{strconv.Itoa(i): NewIntID(i)})
+	}
+
+	if len(c.tempIDs.ids) != tempIDKeep || len(c.tempIDs.order) != tempIDKeep {
+		t.Errorf("expected %d remembered temp IDs, received %d", tempIDKeep, len(c.tempIDs.ids))
+	}
+	if id := c.ResolveID(NewTempID("0")); !id.IsTemp() {
+		t.Errorf("expected the oldest temp ID to be forgotten, received %s", id)
+	}
+}
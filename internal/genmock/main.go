@@ -0,0 +1,365 @@
+// Command genmock generates mock implementations of the interfaces declared in
+// a Go source file of the todoist package. Every generated mock records its
+// calls and delegates to an optional function field per method, returning zero
+// values when the field is nil.
+//
+// It is run through go generate from the repository root:
+//
+//	go run ./internal/genmock -source api.go -out todoistmock/mocks.go
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"io/ioutil"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var (
+	source     = flag.String("source", "api.go", "Go source file declaring the interfaces")
+	out        = flag.String("out", "todoistmock/mocks.go", "output file")
+	pkgName    = flag.String("pkg", "todoistmock", "package name of the generated file")
+	importPath = flag.String("import", "github.com/ides15/todoist", "import path of the package declaring the interfaces")
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("genmock: ")
+	flag.Parse()
+
+	code, err := generate(*source, *pkgName, *importPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err = ioutil.WriteFile(*out, code, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// generator holds the state of a single generation run.
+type generator struct {
+	fset     *token.FileSet
+	srcPkg   string            // name of the package declaring the interfaces
+	imports  map[string]string // import name -> path, as declared in the source
+	used     map[string]bool   // import paths used by the generated code
+	buf      bytes.Buffer
+	exprBuf  bytes.Buffer
+	firstErr error
+}
+
+func generate(path, pkg, importPath string) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &generator{
+		fset:    fset,
+		srcPkg:  file.Name.Name,
+		imports: map[string]string{},
+		used:    map[string]bool{"sync": true, importPath: true},
+	}
+
+	for _, imp := range file.Imports {
+		p, _ := strconv.Unquote(imp.Path.Value)
+		name := p[strings.LastIndex(p, "/")+1:]
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		g.imports[name] = p
+	}
+	g.imports[g.srcPkg] = importPath
+
+	var body bytes.Buffer
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+
+		for _, spec := range gen.Specs {
+			ts := spec.(*ast.TypeSpec)
+			iface, ok := ts.Type.(*ast.InterfaceType)
+			if !ok || !ts.Name.IsExported() {
+				continue
+			}
+
+			if err = g.mock(&body, ts.Name.Name, iface); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	paths := make([]string, 0, len(g.used))
+	for p := range g.used {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	fmt.Fprintf(&g.buf, "// Code generated by genmock from %s. DO NOT EDIT.\n\n", path)
+	fmt.Fprintf(&g.buf, "package %s\n\nimport (\n", pkg)
+	for _, std := range []bool{true, false} {
+		for _, p := range paths {
+			if isStd(p) == std {
+				fmt.Fprintf(&g.buf, "\t%q\n", p)
+			}
+		}
+		if std {
+			fmt.Fprintf(&g.buf, "\n")
+		}
+	}
+	fmt.Fprintf(&g.buf, ")\n\n")
+	g.buf.Write(body.Bytes())
+
+	if g.firstErr != nil {
+		return nil, g.firstErr
+	}
+
+	code, err := format.Source(g.buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %v\n%s", err, g.buf.Bytes())
+	}
+
+	return code, nil
+}
+
+// param is a parameter or result of an interface method.
+type param struct {
+	name     string
+	typ      string
+	variadic bool
+}
+
+func (g *generator) mock(w *bytes.Buffer, name string, iface *ast.InterfaceType) error {
+	fmt.Fprintf(w, "// %s is a mock implementation of %s.%s.\n", name, g.srcPkg, name)
+	fmt.Fprintf(w, "type %s struct {\n\tmu sync.Mutex\n\n", name)
+
+	type method struct {
+		name    string
+		params  []param
+		results []param
+	}
+
+	var methods []method
+	for _, field := range iface.Methods.List {
+		ft, ok := field.Type.(*ast.FuncType)
+		if !ok || len(field.Names) == 0 {
+			return fmt.Errorf("%s: embedded interfaces are not supported", name)
+		}
+
+		m := method{name: field.Names[0].Name}
+		m.params = g.params(ft.Params, "p")
+		if ft.Results != nil {
+			m.results = g.params(ft.Results, "r")
+		}
+		for i := range m.results {
+			m.results[i].name = fmt.Sprintf("r%d", i)
+		}
+		methods = append(methods, m)
+	}
+
+	for _, m := range methods {
+		fmt.Fprintf(w, "\t// %sFunc, if set, is called by %s.\n", m.name, m.name)
+		fmt.Fprintf(w, "\t%sFunc func(%s) %s\n", m.name, signature(m.params), results(m.results))
+		fmt.Fprintf(w, "\t// %sCalls records the arguments of every call to %s.\n", m.name, m.name)
+		fmt.Fprintf(w, "\t%sCalls []%s%sCall\n\n", m.name, name, m.name)
+	}
+	fmt.Fprintf(w, "}\n\n")
+
+	for _, m := range methods {
+		call := name + m.name + "Call"
+
+		fmt.Fprintf(w, "// %s records the arguments of a call to %s.%s.\n", call, name, m.name)
+		fmt.Fprintf(w, "type %s struct {\n", call)
+		for _, p := range m.params {
+			typ := p.typ
+			if p.variadic {
+				typ = "[]" + strings.TrimPrefix(typ, "...")
+			}
+			fmt.Fprintf(w, "\t%s %s\n", exportedName(p.name), typ)
+		}
+		fmt.Fprintf(w, "}\n\n")
+
+		var args, fields []string
+		for _, p := range m.params {
+			arg := p.name
+			if p.variadic {
+				arg += "..."
+			}
+			args = append(args, arg)
+			fields = append(fields, fmt.Sprintf("%s: %s", exportedName(p.name), p.name))
+		}
+
+		fmt.Fprintf(w, "// %s implements %s.%s.\n", m.name, g.srcPkg, name)
+		fmt.Fprintf(w, "func (m *%s) %s(%s) %s {\n", name, m.name, signature(m.params), results(m.results))
+		fmt.Fprintf(w, "\tm.mu.Lock()\n")
+		fmt.Fprintf(w, "\tm.%sCalls = append(m.%sCalls, %s{%s})\n", m.name, m.name, call, strings.Join(fields, ", "))
+		fmt.Fprintf(w, "\tfn := m.%sFunc\n", m.name)
+		fmt.Fprintf(w, "\tm.mu.Unlock()\n\n")
+
+		ret := ""
+		if len(m.results) > 0 {
+			ret = "return "
+		}
+		fmt.Fprintf(w, "\tif fn != nil {\n\t\t%sfn(%s)\n", ret, strings.Join(args, ", "))
+		if ret == "" {
+			fmt.Fprintf(w, "\t\treturn\n")
+		}
+		fmt.Fprintf(w, "\t}\n\n")
+
+		if len(m.results) > 0 {
+			var names []string
+			for _, r := range m.results {
+				fmt.Fprintf(w, "\tvar %s %s\n", r.name, r.typ)
+				names = append(names, r.name)
+			}
+			fmt.Fprintf(w, "\treturn %s\n", strings.Join(names, ", "))
+		}
+		fmt.Fprintf(w, "}\n\n")
+	}
+
+	return nil
+}
+
+func (g *generator) params(fields *ast.FieldList, prefix string) []param {
+	var ps []param
+	for _, field := range fields.List {
+		_, variadic := field.Type.(*ast.Ellipsis)
+		typ := g.typeString(field.Type)
+
+		if len(field.Names) == 0 {
+			ps = append(ps, param{name: fmt.Sprintf("%s%d", prefix, len(ps)), typ: typ, variadic: variadic})
+			continue
+		}
+		for _, n := range field.Names {
+			name := n.Name
+			if name == "m" || name == "fn" || name == "_" {
+				name = fmt.Sprintf("%s%d", prefix, len(ps))
+			}
+			ps = append(ps, param{name: name, typ: typ, variadic: variadic})
+		}
+	}
+
+	return ps
+}
+
+// typeString prints a type expression, qualifying identifiers declared in the
+// source package and recording the imports it uses.
+func (g *generator) typeString(expr ast.Expr) string {
+	g.exprBuf.Reset()
+	if err := printer.Fprint(&g.exprBuf, token.NewFileSet(), g.qualify(expr)); err != nil && g.firstErr == nil {
+		g.firstErr = err
+	}
+
+	// go/printer spreads empty interface literals over two lines.
+	return strings.ReplaceAll(g.exprBuf.String(), "interface {\n}", "interface{}")
+}
+
+func (g *generator) qualify(expr ast.Expr) ast.Expr {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		if e.IsExported() {
+			g.used[g.imports[g.srcPkg]] = true
+			return &ast.SelectorExpr{X: ast.NewIdent(g.srcPkg), Sel: ast.NewIdent(e.Name)}
+		}
+		return ast.NewIdent(e.Name)
+	case *ast.SelectorExpr:
+		pkg := e.X.(*ast.Ident).Name
+		p, ok := g.imports[pkg]
+		if !ok && g.firstErr == nil {
+			g.firstErr = fmt.Errorf("unknown package %s", pkg)
+		}
+		g.used[p] = true
+		return &ast.SelectorExpr{X: ast.NewIdent(pkg), Sel: ast.NewIdent(e.Sel.Name)}
+	case *ast.StarExpr:
+		return &ast.StarExpr{X: g.qualify(e.X)}
+	case *ast.ArrayType:
+		return &ast.ArrayType{Len: e.Len, Elt: g.qualify(e.Elt)}
+	case *ast.MapType:
+		return &ast.MapType{Key: g.qualify(e.Key), Value: g.qualify(e.Value)}
+	case *ast.ChanType:
+		return &ast.ChanType{Dir: e.Dir, Value: g.qualify(e.Value)}
+	case *ast.Ellipsis:
+		return &ast.Ellipsis{Elt: g.qualify(e.Elt)}
+	case *ast.InterfaceType:
+		if len(e.Methods.List) > 0 && g.firstErr == nil {
+			g.firstErr = fmt.Errorf("non-empty interface literals are not supported")
+		}
+		return &ast.InterfaceType{Methods: &ast.FieldList{}}
+	case *ast.FuncType:
+		return &ast.FuncType{Params: g.qualifyFields(e.Params), Results: g.qualifyFields(e.Results)}
+	default:
+		if g.firstErr == nil {
+			g.firstErr = fmt.Errorf("unsupported type expression %T", expr)
+		}
+		return expr
+	}
+}
+
+func (g *generator) qualifyFields(fields *ast.FieldList) *ast.FieldList {
+	if fields == nil {
+		return nil
+	}
+
+	out := &ast.FieldList{}
+	for _, f := range fields.List {
+		out.List = append(out.List, &ast.Field{Names: f.Names, Type: g.qualify(f.Type)})
+	}
+
+	return out
+}
+
+// isStd reports whether p is the import path of a standard library package.
+func isStd(p string) bool {
+	return !strings.Contains(strings.SplitN(p, "/", 2)[0], ".")
+}
+
+func signature(ps []param) string {
+	var parts []string
+	for _, p := range ps {
+		parts = append(parts, p.name+" "+p.typ)
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+func results(rs []param) string {
+	switch len(rs) {
+	case 0:
+		return ""
+	case 1:
+		return rs[0].typ
+	}
+
+	var parts []string
+	for _, r := range rs {
+		parts = append(parts, r.typ)
+	}
+
+	return "(" + strings.Join(parts, ", ") + ")"
+}
+
+// exportedName returns name with its first letter upper-cased, keeping common
+// initialisms such as ID intact.
+func exportedName(name string) string {
+	if name == "" {
+		return name
+	}
+
+	r := []rune(name)
+	r[0] = unicode.ToUpper(r[0])
+
+	return string(r)
+}
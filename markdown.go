@@ -0,0 +1,225 @@
+package todoist
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	markdownHeadingPattern  = regexp.MustCompile(`^(#{1,6})\s+(.*?)(?:\s+#+)?\s*$`)
+	markdownItemPattern     = regexp.MustCompile(`^[-*+]\s+(?:\[([ xX])\]\s+)?(.*)$`)
+	markdownLabelPattern    = regexp.MustCompile(`(?:^|\s+)@([^\s@()]+)$`)
+	markdownPriorityPattern = regexp.MustCompile(`(?:^|\s+)\(p([1-4])\)$`)
+	markdownDuePattern      = regexp.MustCompile(`(?:^|\s+)\(due: ([^()]+)\)$`)
+)
+
+// ReadMarkdown reads a checklist from GitHub-flavored Markdown:
+//
+//	# Home
+//
+//	- [ ] Pay rent (due: every 1st) (p1) @bills
+//	  Bank transfer
+//	  - [x] Find the IBAN
+//
+//	## Garden
+//
+//	- [ ] Mow the lawn
+//
+// Top-level headings start a project and lower-level headings a section of it;
+// list items before the first heading belong to the Inbox. List items, with or
+// without a task list checkbox, are tasks, nested by their indentation (tabs
+// count as four spaces). Indented lines below a task that are not list items
+// form its description. A task's due string, priority and labels are read from
+// the "(due: ...)", "(p1)" to "(p4)" and "@label" annotations at the end of
+// its line. Other lines, and fenced code blocks, are ignored.
+func ReadMarkdown(r io.Reader) (*Checklist, error) {
+	c := &Checklist{}
+
+	var (
+		project *ChecklistProject
+		section *ChecklistSection
+		fenced  bool
+	)
+	type level struct {
+		indent int
+		task   *ChecklistTask
+	}
+	var stack []level
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(strings.ReplaceAll(scanner.Text(), "\t", "    "), " \r")
+		text := strings.TrimLeft(line, " ")
+		indent := len(line) - len(text)
+
+		if strings.HasPrefix(text, "```") || strings.HasPrefix(text, "~~~") {
+			fenced = !fenced
+			continue
+		}
+		if fenced || text == "" {
+			continue
+		}
+
+		if m := markdownHeadingPattern.FindStringSubmatch(text); m != nil && indent == 0 {
+			if len(m[1]) == 1 {
+				project, section = c.project(m[2]), nil
+			} else {
+				if project == nil {
+					project = c.project("")
+				}
+				section = project.section(m[2])
+			}
+			stack = nil
+			continue
+		}
+
+		for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+			stack = stack[:len(stack)-1]
+		}
+
+		m := markdownItemPattern.FindStringSubmatch(text)
+		if m == nil {
+			// Description lines are indented below their task.
+			if len(stack) > 0 && indent > 0 {
+				task := stack[len(stack)-1].task
+				if task.Description != "" {
+					task.Description += "\n"
+				}
+				task.Description += text
+			}
+			continue
+		}
+
+		task := &ChecklistTask{Checked: m[1] == "x" || m[1] == "X"}
+		task.Content, task.Due, task.Priority, task.Labels = parseMarkdownAnnotations(m[2])
+		if task.Content == "" {
+			return nil, errors.Errorf("task without content: %q", line)
+		}
+
+		switch {
+		case len(stack) > 0:
+			parent := stack[len(stack)-1].task
+			parent.Children = append(parent.Children, task)
+		case section != nil:
+			section.Tasks = append(section.Tasks, task)
+		default:
+			if project == nil {
+				project = c.project("")
+			}
+			project.Tasks = append(project.Tasks, task)
+		}
+		stack = append(stack, level{indent: indent, task: task})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "unable to read markdown")
+	}
+
+	return c, nil
+}
+
+// parseMarkdownAnnotations splits the due string, priority and labels off the
+// end of a task's line.
+func parseMarkdownAnnotations(text string) (content, due string, priority int, labels []string) {
+	content = strings.TrimSpace(text)
+	for {
+		if m := markdownLabelPattern.FindStringSubmatch(content); m != nil {
+			labels = append([]string{m[1]}, labels...)
+			content = strings.TrimSuffix(content, m[0])
+		} else if m := markdownPriorityPattern.FindStringSubmatch(content); m != nil {
+			priority = int(m[1][0] - '0')
+			content = strings.TrimSuffix(content, m[0])
+		} else if m := markdownDuePattern.FindStringSubmatch(content); m != nil {
+			due = strings.TrimSpace(m[1])
+			content = strings.TrimSuffix(content, m[0])
+		} else {
+			return content, due, priority, labels
+		}
+	}
+}
+
+// WriteMarkdown writes the checklist as GitHub-flavored Markdown, in the format
+// read by ReadMarkdown: a heading for each project and section, and a task
+// list item for each task, with sub-tasks indented by two spaces. The Inbox
+// comes first, without a heading. The p4 priority, which is the default, is
+// left out.
+func (c *Checklist) WriteMarkdown(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	var writeTasks func(tasks []*ChecklistTask, indent string)
+	writeTasks = func(tasks []*ChecklistTask, indent string) {
+		for _, task := range tasks {
+			check := " "
+			if task.Checked {
+				check = "x"
+			}
+			fmt.Fprintf(bw, "%s- [%s] %s", indent, check, task.Content)
+
+			due := task.Due
+			if due == "" {
+				due = task.DueDate
+			}
+			if due != "" {
+				fmt.Fprintf(bw, " (due: %s)", due)
+			}
+			if task.Priority >= 1 && task.Priority <= 3 {
+				fmt.Fprintf(bw, " (p%d)", task.Priority)
+			}
+			for _, label := range task.Labels {
+				fmt.Fprintf(bw, " @%s", label)
+			}
+			fmt.Fprintln(bw)
+
+			if task.Description != "" {
+				for _, line := range strings.Split(task.Description, "\n") {
+					fmt.Fprintf(bw, "%s  %s\n", indent, line)
+				}
+			}
+
+			writeTasks(task.Children, indent+"  ")
+		}
+	}
+
+	var projects []*ChecklistProject
+	for _, p := range c.Projects {
+		if p.Name == "" {
+			projects = append([]*ChecklistProject{p}, projects...)
+		} else {
+			projects = append(projects, p)
+		}
+	}
+
+	first := true
+	block := func() {
+		if !first {
+			fmt.Fprintln(bw)
+		}
+		first = false
+	}
+
+	for _, p := range projects {
+		if p.Name != "" {
+			block()
+			fmt.Fprintf(bw, "# %s\n", p.Name)
+		}
+		if len(p.Tasks) > 0 {
+			block()
+			writeTasks(p.Tasks, "")
+		}
+
+		for _, s := range p.Sections {
+			block()
+			fmt.Fprintf(bw, "## %s\n", s.Name)
+			if len(s.Tasks) > 0 {
+				block()
+				writeTasks(s.Tasks, "")
+			}
+		}
+	}
+
+	return bw.Flush()
+}
@@ -0,0 +1,136 @@
+package todoist
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testMarkdown = `Tasks for the week.
+
+- [ ] Call mum (p2)
+* Plain list item
+
+# Home
+
+- [ ] Pay rent (due: every 1st) (p1) @bills @monthly
+  Bank transfer
+  Reference on the invoice
+  - [x] Find the IBAN
+` + "\t- [ ] Ask the landlord @waiting" + `
+
+- [ ] Water plants (due: every! 3 days)
+
+` + "```" + `
+- [ ] Not a task
+` + "```" + `
+
+## Garden ##
+
+- [X] Mow the lawn (due: 2022-03-12)
+
+# Work
+`
+
+// testChecklist is the checklist of testMarkdown.
+func testChecklist() *Checklist {
+	return &Checklist{Projects: []*ChecklistProject{
+		{Name: "", Tasks: []*ChecklistTask{
+			{Content: "Call mum", Priority: 2},
+			{Content: "Plain list item"},
+		}},
+		{Name: "Home", Tasks: []*ChecklistTask{
+			{Content: "Pay rent", Description: "Bank transfer\nReference on the invoice", Due: "every 1st", Priority: 1, Labels: []string{"bills", "monthly"}, Children: []*ChecklistTask{
+				{Content: "Find the IBAN", Checked: true, Children: []*ChecklistTask{
+					{Content: "Ask the landlord", Labels: []string{"waiting"}},
+				}},
+			}},
+			{Content: "Water plants", Due: "every! 3 days"},
+		}, Sections: []*ChecklistSection{
+			{Name: "Garden", Tasks: []*ChecklistTask{
+				{Content: "Mow the lawn", Checked: true, Due: "2022-03-12"},
+			}},
+		}},
+		{Name: "Work"},
+	}}
+}
+
+func Test_ReadMarkdown(t *testing.T) {
+	got, err := ReadMarkdown(strings.NewReader(testMarkdown))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := testChecklist(); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected\n%s\nreceived\n%s", dumpChecklist(want), dumpChecklist(got))
+	}
+
+	if _, err = ReadMarkdown(strings.NewReader("- [ ] (p1) @bills\n")); err == nil {
+		t.Error("expected an error for a task without content")
+	}
+}
+
+func Test_Checklist_WriteMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testChecklist().WriteMarkdown(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `- [ ] Call mum (p2)
+- [ ] Plain list item
+
+# Home
+
+- [ ] Pay rent (due: every 1st) (p1) @bills @monthly
+  Bank transfer
+  Reference on the invoice
+  - [x] Find the IBAN
+    - [ ] Ask the landlord @waiting
+- [ ] Water plants (due: every! 3 days)
+
+## Garden
+
+- [x] Mow the lawn (due: 2022-03-12)
+
+# Work
+`
+	if got := buf.String(); got != want {
+		t.Errorf("expected\n%s\nreceived\n%s", want, got)
+	}
+
+	again, err := ReadMarkdown(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(again, testChecklist()) {
+		t.Errorf("expected the written markdown to read back the same checklist, received\n%s", dumpChecklist(again))
+	}
+}
+
+// dumpChecklist formats a checklist for test failure messages.
+func dumpChecklist(c *Checklist) string {
+	var b strings.Builder
+
+	var dumpTasks func(tasks []*ChecklistTask, indent string)
+	dumpTasks = func(tasks []*ChecklistTask, indent string) {
+		for _, task := range tasks {
+			fields := *task
+			fields.Children = nil
+			fmt.Fprintf(&b, "%s%+v\n", indent, fields)
+			dumpTasks(task.Children, indent+"  ")
+		}
+	}
+
+	for _, p := range c.Projects {
+		fmt.Fprintf(&b, "project %q\n", p.Name)
+		dumpTasks(p.Tasks, "  ")
+		for _, s := range p.Sections {
+			fmt.Fprintf(&b, "  section %q\n", s.Name)
+			dumpTasks(s.Tasks, "    ")
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,291 @@
+package todoist
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultAuthURL   = "https://todoist.com/oauth/authorize"
+	defaultTokenURL  = "https://todoist.com/oauth/access_token"
+	defaultRevokeURL = "https://api.todoist.com/sync/v8/access_tokens/revoke"
+)
+
+// Scope is a permission requested from the user during the OAuth flow.
+//
+// Todoist API docs: https://developer.todoist.com/guides/#authorization
+type Scope string
+
+const (
+	// ScopeTaskAdd grants permission to add new tasks (the application cannot read or modify any existing data).
+	ScopeTaskAdd Scope = "task:add"
+
+	// ScopeDataRead grants read-only access to application data, including tasks, projects, labels, and filters.
+	ScopeDataRead Scope = "data:read"
+
+	// ScopeDataReadWrite grants read and write access to application data, including tasks, projects, labels, and filters. This scope includes task:add and data:read scopes.
+	ScopeDataReadWrite Scope = "data:read_write"
+
+	// ScopeDataDelete grants permission to delete application data, including tasks, labels, and filters.
+	ScopeDataDelete Scope = "data:delete"
+
+	// ScopeProjectDelete grants permission to delete projects.
+	ScopeProjectDelete Scope = "project:delete"
+)
+
+// Token is an OAuth access token, used in place of a personal API token.
+type Token struct {
+	// The access token.
+	AccessToken string `json:"access_token"`
+
+	// The type of the token, always "Bearer".
+	TokenType string `json:"token_type"`
+}
+
+// A TokenSource supplies the token a Client authenticates with. It is called
+// for every request, so implementations can refresh or rotate tokens; they
+// must be safe for concurrent use.
+type TokenSource interface {
+	Token() (*Token, error)
+}
+
+// StaticTokenSource returns a TokenSource that always returns the same token.
+func StaticTokenSource(token *Token) TokenSource {
+	return staticTokenSource{token}
+}
+
+type staticTokenSource struct {
+	token *Token
+}
+
+func (s staticTokenSource) Token() (*Token, error) {
+	return s.token, nil
+}
+
+// WithTokenSource makes the client obtain its token from ts for every request,
+// instead of using APIToken. NewClient accepts an empty apiToken when a token
+// source is set.
+func WithTokenSource(ts TokenSource) ClientOption {
+	return func(c *Client) error {
+		if ts == nil {
+			return errors.New("token source cannot be nil")
+		}
+
+		c.tokenSource = ts
+		return nil
+	}
+}
+
+// token returns the token to authenticate the next request with.
+func (c *Client) token() (string, error) {
+	if c.tokenSource == nil {
+		return c.APIToken, nil
+	}
+
+	token, err := c.tokenSource.Token()
+	if err != nil {
+		return "", errors.Wrap(err, "unable to get token from token source")
+	}
+	if token == nil || token.AccessToken == "" {
+		return "", errors.New("token source returned an empty token")
+	}
+
+	return token.AccessToken, nil
+}
+
+// OAuthConfig describes a Todoist OAuth application, and implements the
+// authorization code flow: send the user to AuthCodeURL, check the redirect
+// with ParseCallback, then trade the code for a token with Exchange.
+//
+// Todoist API docs: https://developer.todoist.com/guides/#oauth
+type OAuthConfig struct {
+	// The unique Client ID of the Todoist application, from the App Management Console.
+	ClientID string
+
+	// The unique Client Secret of the Todoist application, from the App Management Console.
+	ClientSecret string
+
+	// The permissions requested from the user.
+	Scopes []Scope
+
+	// Endpoints of the authorization server. They default to Todoist's, and
+	// can be pointed at a local stand-in server in tests.
+	AuthURL   string
+	TokenURL  string
+	RevokeURL string
+
+	// HTTP client used for token requests. Defaults to http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+// NewState returns a random value for the state parameter of the authorize
+// URL, which protects against cross-site request forgery.
+func NewState() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.Wrap(err, "unable to generate state")
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+// AuthCodeURL returns the URL of the authorization page to send the user to.
+// state is echoed back to the redirect URL and must be checked there.
+func (o *OAuthConfig) AuthCodeURL(state string) string {
+	scopes := make([]string, len(o.Scopes))
+	for i, scope := range o.Scopes {
+		scopes[i] = string(scope)
+	}
+
+	q := url.Values{}
+	q.Set("client_id", o.ClientID)
+	q.Set("scope", strings.Join(scopes, ","))
+	q.Set("state", state)
+
+	authURL := o.AuthURL
+	if authURL == "" {
+		authURL = defaultAuthURL
+	}
+
+	sep := "?"
+	if strings.Contains(authURL, "?") {
+		sep = "&"
+	}
+
+	return authURL + sep + q.Encode()
+}
+
+// ParseCallback returns the authorization code from the request the user is
+// redirected with, after checking that it carries the expected state, which
+// must not be empty. It returns an error if the user denied access.
+func (o *OAuthConfig) ParseCallback(r *http.Request, state string) (string, error) {
+	if state == "" {
+		return "", errors.New("expected authorization state is empty")
+	}
+
+	q := r.URL.Query()
+
+	if e := q.Get("error"); e != "" {
+		return "", errors.Errorf("authorization failed: %s", e)
+	}
+	if q.Get("state") != state {
+		return "", errors.New("authorization state mismatch")
+	}
+
+	code := q.Get("code")
+	if code == "" {
+		return "", errors.New("authorization code missing from callback")
+	}
+
+	return code, nil
+}
+
+// Exchange trades an authorization code for an access token.
+func (o *OAuthConfig) Exchange(ctx context.Context, code string) (*Token, error) {
+	form := url.Values{}
+	form.Set("client_id", o.ClientID)
+	form.Set("client_secret", o.ClientSecret)
+	form.Set("code", code)
+
+	tokenURL := o.TokenURL
+	if tokenURL == "" {
+		tokenURL = defaultTokenURL
+	}
+
+	var token Token
+	if err := o.post(ctx, tokenURL, form, &token); err != nil {
+		return nil, err
+	}
+	if token.AccessToken == "" {
+		return nil, errors.New("token response did not contain an access token")
+	}
+
+	return &token, nil
+}
+
+// Revoke invalidates an access token.
+func (o *OAuthConfig) Revoke(ctx context.Context, accessToken string) error {
+	form := url.Values{}
+	form.Set("client_id", o.ClientID)
+	form.Set("client_secret", o.ClientSecret)
+	form.Set("access_token", accessToken)
+
+	revokeURL := o.RevokeURL
+	if revokeURL == "" {
+		revokeURL = defaultRevokeURL
+	}
+
+	return o.post(ctx, revokeURL, form, nil)
+}
+
+// NewReuseTokenSource returns a TokenSource for a token obtained through
+// Exchange. Todoist access tokens do not expire, so the token is returned as
+// is until it is replaced with SetToken.
+func NewReuseTokenSource(token *Token) *ReuseTokenSource {
+	return &ReuseTokenSource{token: token}
+}
+
+// ReuseTokenSource is a TokenSource whose token can be replaced, for example
+// after the user authorizes the application again.
+type ReuseTokenSource struct {
+	mu    sync.Mutex
+	token *Token
+}
+
+// Token implements TokenSource.
+func (s *ReuseTokenSource) Token() (*Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token == nil {
+		return nil, errors.New("no token")
+	}
+
+	return s.token, nil
+}
+
+// SetToken replaces the token returned by the source.
+func (s *ReuseTokenSource) SetToken(token *Token) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.token = token
+}
+
+// post sends a form to an authorization server endpoint, decoding the JSON
+// response into v if it is not nil.
+func (o *OAuthConfig) post(ctx context.Context, endpoint string, form url.Values, v interface{}) error {
+	req, err := http.NewRequest(http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("User-Agent", userAgent)
+
+	client := o.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		return err
+	}
+	defer resp.Body.Close()
+
+	return checkResponseForErrors(resp, v)
+}
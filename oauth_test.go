@@ -0,0 +1,219 @@
+package todoist
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+)
+
+// authServer is a local stand-in for the Todoist authorization server.
+type authServer struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	codes   map[string]string // authorization code -> access token
+	tokens  map[string]bool   // valid access tokens
+	revoked []string
+}
+
+const (
+	testClientID     = "client-id"
+	testClientSecret = "client-secret"
+)
+
+func newAuthServer(t *testing.T) *authServer {
+	t.Helper()
+
+	s := &authServer{
+		codes:  map[string]string{"good-code": "access-token"},
+		tokens: map[string]bool{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/oauth/access_token", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if r.FormValue("client_id") != testClientID || r.FormValue("client_secret") != testClientSecret {
+			http.Error(w, `{"error": "invalid_client"}`, http.StatusUnauthorized)
+			return
+		}
+
+		token, ok := s.codes[r.FormValue("code")]
+		if !ok {
+			http.Error(w, `{"error": "bad_authorization_code"}`, http.StatusBadRequest)
+			return
+		}
+		delete(s.codes, r.FormValue("code"))
+		s.tokens[token] = true
+
+		_, _ = w.Write([]byte(`{"access_token": "` + token + `", "token_type": "Bearer"}`))
+	})
+	mux.HandleFunc("/access_tokens/revoke", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		token := r.FormValue("access_token")
+		delete(s.tokens, token)
+		s.revoked = append(s.revoked, token)
+	})
+	mux.HandleFunc("/sync", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if !s.tokens[r.FormValue("token")] {
+			http.Error(w, `{"error": "Invalid token", "error_tag": "AUTH_INVALID_TOKEN", "http_code": 401}`, http.StatusUnauthorized)
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"full_sync": true, "sync_token": "token", "projects": []}`))
+	})
+
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func (s *authServer) config() *OAuthConfig {
+	return &OAuthConfig{
+		ClientID:     testClientID,
+		ClientSecret: testClientSecret,
+		Scopes:       []Scope{ScopeDataReadWrite, ScopeDataDelete},
+		AuthURL:      s.URL + "/oauth/authorize",
+		TokenURL:     s.URL + "/oauth/access_token",
+		RevokeURL:    s.URL + "/access_tokens/revoke",
+	}
+}
+
+func Test_OAuth_AuthCodeURL(t *testing.T) {
+	config := &OAuthConfig{ClientID: testClientID, Scopes: []Scope{ScopeDataRead, ScopeTaskAdd}}
+
+	u, err := url.Parse(config.AuthCodeURL("xyz"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Scheme+"://"+u.Host+u.Path != defaultAuthURL {
+		t.Errorf("expected the Todoist authorize URL, received %s", u)
+	}
+
+	q := u.Query()
+	if q.Get("client_id") != testClientID || q.Get("scope") != "data:read,task:add" || q.Get("state") != "xyz" {
+		t.Errorf("unexpected query %v", q)
+	}
+
+	state1, err := NewState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	state2, _ := NewState()
+	if state1 == "" || state1 == state2 {
+		t.Errorf("expected distinct random states, received %q and %q", state1, state2)
+	}
+}
+
+func Test_OAuth_ParseCallback(t *testing.T) {
+	config := &OAuthConfig{}
+
+	tests := []struct {
+		name    string
+		query   string
+		want    string
+		wantErr bool
+	}{
+		{name: "ok", query: "code=abc&state=xyz", want: "abc"},
+		{name: "state mismatch", query: "code=abc&state=other", wantErr: true},
+		{name: "access denied", query: "error=access_denied&state=xyz", wantErr: true},
+		{name: "missing code", query: "state=xyz", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/callback?"+tt.query, nil)
+
+			code, err := config.ParseCallback(r, "xyz")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error: %v, received %v", tt.wantErr, err)
+			}
+			if code != tt.want {
+				t.Errorf("expected code %q, received %q", tt.want, code)
+			}
+		})
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/callback?code=abc&state=", nil)
+	if _, err := config.ParseCallback(r, ""); err == nil {
+		t.Error("expected an error for an empty expected state")
+	}
+}
+
+func Test_OAuth_Flow(t *testing.T) {
+	srv := newAuthServer(t)
+	config := srv.config()
+	ctx := context.Background()
+
+	_, err := config.Exchange(ctx, "bad-code")
+	var badRequest BadRequestError
+	if !errors.As(err, &badRequest) || badRequest.Message != "bad_authorization_code" {
+		t.Fatalf("expected a BadRequestError for a bad code, received %v", err)
+	}
+
+	token, err := config.Exchange(ctx, "good-code")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.AccessToken != "access-token" || token.TokenType != "Bearer" {
+		t.Errorf("unexpected token %+v", token)
+	}
+
+	ts := NewReuseTokenSource(token)
+	client, err := NewClient("", WithTokenSource(ts))
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.BaseURL, _ = url.Parse(srv.URL + "/sync")
+
+	if _, _, err = client.Projects.List(ctx, ""); err != nil {
+		t.Fatalf("expected the client to authenticate with the exchanged token, received %v", err)
+	}
+
+	if err = config.Revoke(ctx, token.AccessToken); err != nil {
+		t.Fatal(err)
+	}
+	if len(srv.revoked) != 1 || srv.revoked[0] != "access-token" {
+		t.Errorf("expected the token to be revoked, received %v", srv.revoked)
+	}
+
+	_, _, err = client.Projects.List(ctx, "")
+	var unauthorized UnauthorizedError
+	if !errors.As(err, &unauthorized) {
+		t.Fatalf("expected an UnauthorizedError after revoking, received %v", err)
+	}
+
+	srv.mu.Lock()
+	srv.tokens["new-token"] = true
+	srv.mu.Unlock()
+	ts.SetToken(&Token{AccessToken: "new-token", TokenType: "Bearer"})
+
+	if _, _, err = client.Projects.List(ctx, ""); err != nil {
+		t.Fatalf("expected the client to pick up the new token, received %v", err)
+	}
+}
+
+func Test_NewClient_TokenRequired(t *testing.T) {
+	if _, err := NewClient(""); err == nil {
+		t.Error("expected an error without an API token or token source")
+	}
+
+	client, err := NewClient("", WithTokenSource(StaticTokenSource(&Token{})))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.NewRequest("", nil, nil); err == nil {
+		t.Error("expected an error for an empty token")
+	}
+}
@@ -0,0 +1,683 @@
+package todoist
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// ApplyLocal applies commands to the store before they are sent, so that
+// their changes show right away. The commands are copied, given a UUID and a
+// temp ID where they have none, and returned to be sent, usually with Push.
+// Resources added by the commands get their temp ID until the commands are
+// reconciled.
+//
+// The commands stay pending until Reconcile or Rollback. Later syncs keep
+// them applied on top of the synced resources, skipping the ones that no
+// longer apply, such as an update of a task deleted remotely.
+//
+// The store supports the add, update, move, reorder, archive, delete and
+// complete commands of projects, sections and tasks. If a command is not
+// supported or does not apply, an error is returned and none of the commands
+// are applied.
+func (s *Store) ApplyLocal(commands ...Command) ([]Command, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	commands = append([]Command(nil), commands...)
+	for i := range commands {
+		if commands[i].UUID == "" {
+			commands[i].UUID = uuid.New().String()
+		}
+		if commands[i].TempID == "" {
+			commands[i].TempID = uuid.New().String()
+		}
+	}
+
+	local := s.current().clone()
+	for _, c := range commands {
+		if err := s.applyCommand(local, c); err != nil {
+			return nil, errors.Wrapf(err, "unable to apply %s command locally", c.Type)
+		}
+	}
+
+	s.pending = append(s.pending, commands...)
+	s.local = &local
+
+	return commands, nil
+}
+
+// Reconcile applies the response to pending commands, which must be the
+// response of a request made with the store's sync token and resource types,
+// as Push makes. The response is applied like a sync: conflicts between the
+// changes it returns and the commands still pending are resolved, and the
+// store's sync token is replaced by the response's. The temp IDs of added
+// resources are replaced by their real IDs.
+//
+// Commands that succeeded are no longer pending, and the ones that failed
+// are rolled back: their changes are undone and their errors are returned, in
+// the order the commands were applied. Commands without a sync status in the
+// response stay pending.
+func (s *Store) Reconcile(commandResponse CommandResponse) []SyncError {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tempIDs.record(commandResponse.TempIDMapping)
+
+	var failed []SyncError
+	var succeeded, pending []Command
+	for _, c := range s.pending {
+		status, ok := commandResponse.SyncStatus[c.UUID]
+		switch {
+		case !ok:
+			pending = append(pending, c)
+		case status == "ok":
+			succeeded = append(succeeded, c)
+		default:
+			syncErr, err := newSyncError(c.UUID, status)
+			if err != nil {
+				syncErr = SyncError{ID: c.UUID}
+			}
+			failed = append(failed, syncErr)
+		}
+	}
+	s.pending = pending
+
+	// The response returns the changes of the commands that succeeded, which
+	// are not conflicts with the commands still pending, so they are applied
+	// to the base the response is compared with.
+	base := s.data.clone()
+	for _, c := range succeeded {
+		_ = s.applyCommand(base, c)
+	}
+	var local *storeData
+	if len(pending) > 0 {
+		l := base.clone()
+		for _, c := range pending {
+			_ = s.applyCommand(l, c)
+		}
+		local = &l
+	}
+	s.conflicts = s.resolveConflicts(base, local, commandResponse.Projects, commandResponse.Sections, commandResponse.Tasks)
+
+	if commandResponse.FullSync {
+		s.data = newStoreData()
+	}
+
+	s.data.apply(commandResponse.Projects, commandResponse.Sections, commandResponse.Tasks)
+
+	if commandResponse.SyncToken != "" {
+		s.syncToken = commandResponse.SyncToken
+	}
+
+	s.replay()
+
+	return failed
+}
+
+// Rollback undoes the changes of pending commands, such as the ones of a
+// request that could not be sent.
+func (s *Store) Rollback(commands ...Command) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rolledBack := map[string]bool{}
+	for _, c := range commands {
+		rolledBack[c.UUID] = true
+	}
+
+	pending := s.pending[:0]
+	for _, c := range s.pending {
+		if !rolledBack[c.UUID] {
+			pending = append(pending, c)
+		}
+	}
+	s.pending = pending
+
+	s.replay()
+}
+
+// Pending returns the commands applied with ApplyLocal that have not been
+// reconciled or rolled back yet, in order.
+func (s *Store) Pending() []Command {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return append([]Command(nil), s.pending...)
+}
+
+// Push sends commands returned by ApplyLocal and reconciles the store with
+// the response. The errors of the commands that failed are returned, and
+// those commands are rolled back.
+//
+// If the request fails, the error is returned. The commands are rolled back
+// only if they were certainly not applied: when the request could not be
+// built, or Todoist rejected it with a 4xx status. Otherwise, such as after a
+// network error or a 5xx status, they may have been applied, so they stay
+// pending; pushing them again is safe, as Todoist ignores commands whose UUID
+// it has already seen.
+//
+// Pending commands are sent as changed by conflict resolution.
+func (s *Store) Push(ctx context.Context, client *Client, commands []Command) ([]SyncError, error) {
+	client.Logln("---------- Store.Push")
+
+	commands = s.pendingVersions(commands)
+
+	req, err := client.NewRequest(s.SyncToken(), storeResourceTypes, commands)
+	if err != nil {
+		s.Rollback(commands...)
+		return nil, err
+	}
+
+	var commandResponse CommandResponse
+	resp, err := client.Do(ctx, req, &commandResponse)
+	if err != nil {
+		if _, ok := err.(SyncError); !ok {
+			if resp != nil && resp.StatusCode >= 400 && resp.StatusCode < 500 {
+				s.Rollback(commands...)
+			}
+			return nil, err
+		}
+	}
+
+	return s.Reconcile(commandResponse), nil
+}
+
+// resolveID returns the real ID of the temp ID of a reconciled command.
+// Other IDs are returned unchanged.
+func (s *Store) resolveID(id ID) ID {
+	if !id.IsTemp() {
+		return id
+	}
+
+	if real, ok := s.tempIDs.ids[id.String()]; ok {
+		return real
+	}
+
+	return id
+}
+
+// replay applies the pending commands to a copy of the synced resources.
+// Commands that no longer apply are skipped, but stay pending.
+func (s *Store) replay() {
+	if len(s.pending) == 0 {
+		s.local = nil
+		return
+	}
+
+	local := s.data.clone()
+	for _, c := range s.pending {
+		_ = s.applyCommand(local, c)
+	}
+	s.local = &local
+}
+
+// applyCommand applies a command to d, as Todoist would.
+func (s *Store) applyCommand(d storeData, c Command) error {
+	args, err := json.Marshal(c.Args)
+	if err != nil {
+		return err
+	}
+
+	a := localApplier{data: d, tempIDs: s.tempIDs.ids}
+
+	switch c.Type {
+	case "project_add":
+		return a.addProject(c, args)
+	case "project_update", "project_move":
+		return a.updateProject(args)
+	case "project_archive", "project_delete":
+		id, err := a.commandID(args)
+		if err != nil {
+			return err
+		}
+		if _, ok := d.projects[ProjectID{id}]; !ok {
+			return errors.Errorf("project %s not found", id)
+		}
+		d.removeProject(ProjectID{id})
+	case "project_reorder":
+		return a.reorder(args, "projects", func(id ID, order int) bool {
+			p, ok := d.projects[ProjectID{id}]
+			if ok {
+				p.ChildOrder = order
+				d.projects[p.ID] = p
+			}
+			return ok
+		})
+	case "section_add":
+		return a.addSection(c, args)
+	case "section_update", "section_move":
+		return a.updateSection(args)
+	case "section_archive", "section_delete":
+		id, err := a.commandID(args)
+		if err != nil {
+			return err
+		}
+		if _, ok := d.sections[SectionID{id}]; !ok {
+			return errors.Errorf("section %s not found", id)
+		}
+		d.removeSection(SectionID{id})
+	case "section_reorder":
+		return a.reorder(args, "sections", func(id ID, order int) bool {
+			section, ok := d.sections[SectionID{id}]
+			if ok {
+				section.SectionOrder = order
+				d.sections[section.ID] = section
+			}
+			return ok
+		})
+	case "item_add":
+		return a.addTask(c, args)
+	case "item_update":
+		return a.updateTask(args)
+	case "item_move":
+		return a.moveTask(args)
+	case "item_close", "item_complete", "item_delete":
+		id, err := a.commandID(args)
+		if err != nil {
+			return err
+		}
+		if _, ok := d.tasks[TaskID{id}]; !ok {
+			return errors.Errorf("task %s not found", id)
+		}
+		d.removeTask(TaskID{id})
+	case "item_reorder":
+		return a.reorder(args, "items", func(id ID, order int) bool {
+			task, ok := d.tasks[TaskID{id}]
+			if ok {
+				task.ChildOrder = order
+				d.tasks[task.ID] = task
+			}
+			return ok
+		})
+	default:
+		return errors.Errorf("unsupported command type %q", c.Type)
+	}
+
+	return nil
+}
+
+// localApplier applies commands to the resources of a store. The IDs in
+// command arguments lose their temp flag when decoded, so they are matched
+// against the resources added locally and the temp IDs of reconciled
+// commands.
+type localApplier struct {
+	data    storeData
+	tempIDs map[string]ID
+}
+
+// id returns the ID of the store's resources an ID decoded from command
+// arguments refers to.
+func (a localApplier) id(id ID) ID {
+	if id.IsZero() {
+		return id
+	}
+
+	if real, ok := a.tempIDs[id.String()]; ok {
+		return real
+	}
+
+	temp := NewTempID(id.String())
+	_, isProject := a.data.projects[ProjectID{temp}]
+	_, isSection := a.data.sections[SectionID{temp}]
+	_, isTask := a.data.tasks[TaskID{temp}]
+	if isProject || isSection || isTask {
+		return temp
+	}
+
+	return id
+}
+
+func (a localApplier) projectID(id *ProjectID) *ProjectID {
+	if id == nil {
+		return nil
+	}
+
+	return &ProjectID{a.id(id.ID)}
+}
+
+func (a localApplier) sectionID(id *SectionID) *SectionID {
+	if id == nil {
+		return nil
+	}
+
+	return &SectionID{a.id(id.ID)}
+}
+
+func (a localApplier) taskID(id *TaskID) *TaskID {
+	if id == nil {
+		return nil
+	}
+
+	return &TaskID{a.id(id.ID)}
+}
+
+// commandID returns the ID given in command arguments.
+func (a localApplier) commandID(args []byte) (ID, error) {
+	var v struct {
+		ID ID `json:"id"`
+	}
+	if err := json.Unmarshal(args, &v); err != nil {
+		return ID{}, err
+	}
+	if v.ID.IsZero() {
+		return ID{}, errors.New("missing id")
+	}
+
+	return a.id(v.ID), nil
+}
+
+func (a localApplier) addProject(c Command, args []byte) error {
+	var p Project
+	if err := json.Unmarshal(args, &p); err != nil {
+		return err
+	}
+
+	p.ID = ProjectID{NewTempID(c.TempID)}
+	p.ParentID = a.projectID(p.ParentID)
+	if p.ParentID != nil {
+		if _, ok := a.data.projects[*p.ParentID]; !ok {
+			return errors.Errorf("project %s not found", p.ParentID)
+		}
+	}
+	a.data.projects[p.ID] = p
+
+	return nil
+}
+
+func (a localApplier) updateProject(args []byte) error {
+	id, err := a.commandID(args)
+	if err != nil {
+		return err
+	}
+
+	p, ok := a.data.projects[ProjectID{id}]
+	if !ok {
+		return errors.Errorf("project %s not found", id)
+	}
+	if err = overlay(&p, args); err != nil {
+		return err
+	}
+
+	p.ID = ProjectID{id}
+	p.ParentID = a.projectID(p.ParentID)
+	a.data.projects[p.ID] = p
+
+	return nil
+}
+
+func (a localApplier) addSection(c Command, args []byte) error {
+	var section Section
+	if err := json.Unmarshal(args, &section); err != nil {
+		return err
+	}
+
+	section.ID = SectionID{NewTempID(c.TempID)}
+	section.ProjectID = ProjectID{a.id(section.ProjectID.ID)}
+	if _, ok := a.data.projects[section.ProjectID]; !ok {
+		return errors.Errorf("project %s not found", section.ProjectID)
+	}
+	a.data.sections[section.ID] = section
+
+	return nil
+}
+
+func (a localApplier) updateSection(args []byte) error {
+	id, err := a.commandID(args)
+	if err != nil {
+		return err
+	}
+
+	section, ok := a.data.sections[SectionID{id}]
+	if !ok {
+		return errors.Errorf("section %s not found", id)
+	}
+	if err = overlay(&section, args); err != nil {
+		return err
+	}
+
+	section.ID = SectionID{id}
+	section.ProjectID = ProjectID{a.id(section.ProjectID.ID)}
+	if _, ok := a.data.projects[section.ProjectID]; !ok {
+		return errors.Errorf("project %s not found", section.ProjectID)
+	}
+	a.data.sections[section.ID] = section
+
+	// A moved section takes its tasks along.
+	for taskID, task := range a.data.tasks {
+		if task.SectionID != nil && *task.SectionID == section.ID {
+			task.ProjectID = section.ProjectID
+			a.data.tasks[taskID] = task
+		}
+	}
+
+	return nil
+}
+
+func (a localApplier) addTask(c Command, args []byte) error {
+	var task Task
+	if err := json.Unmarshal(args, &task); err != nil {
+		return err
+	}
+
+	task.ID = TaskID{NewTempID(c.TempID)}
+	if task.Priority == 0 {
+		task.Priority = 1
+	}
+	task.ProjectID = ProjectID{a.id(task.ProjectID.ID)}
+	task.SectionID = a.sectionID(task.SectionID)
+	task.ParentID = a.taskID(task.ParentID)
+
+	// Todoist adds tasks to the project of their parent or section, or else
+	// to the inbox.
+	switch {
+	case task.ParentID != nil:
+		parent, ok := a.data.tasks[*task.ParentID]
+		if !ok {
+			return errors.Errorf("task %s not found", task.ParentID)
+		}
+		task.ProjectID = parent.ProjectID
+		task.SectionID = parent.SectionID
+	case task.SectionID != nil:
+		section, ok := a.data.sections[*task.SectionID]
+		if !ok {
+			return errors.Errorf("section %s not found", task.SectionID)
+		}
+		task.ProjectID = section.ProjectID
+	case task.ProjectID.IsZero():
+		for _, p := range a.data.projects {
+			if p.InboxProject != nil && *p.InboxProject {
+				task.ProjectID = p.ID
+			}
+		}
+	}
+	if _, ok := a.data.projects[task.ProjectID]; !ok {
+		return errors.Errorf("project %s not found", task.ProjectID)
+	}
+	a.data.tasks[task.ID] = task
+
+	return nil
+}
+
+func (a localApplier) updateTask(args []byte) error {
+	id, err := a.commandID(args)
+	if err != nil {
+		return err
+	}
+
+	task, ok := a.data.tasks[TaskID{id}]
+	if !ok {
+		return errors.Errorf("task %s not found", id)
+	}
+	if err = overlay(&task, args); err != nil {
+		return err
+	}
+
+	task.ID = TaskID{id}
+	task.ProjectID = ProjectID{a.id(task.ProjectID.ID)}
+	task.SectionID = a.sectionID(task.SectionID)
+	task.ParentID = a.taskID(task.ParentID)
+	a.data.tasks[task.ID] = task
+
+	return nil
+}
+
+func (a localApplier) moveTask(args []byte) error {
+	var move MoveTask
+	if err := json.Unmarshal(args, &move); err != nil {
+		return err
+	}
+
+	id := TaskID{a.id(move.ID.ID)}
+	task, ok := a.data.tasks[id]
+	if !ok {
+		return errors.Errorf("task %s not found", id)
+	}
+
+	switch {
+	case move.ParentID != nil:
+		parentID := a.taskID(move.ParentID)
+		parent, ok := a.data.tasks[*parentID]
+		if !ok {
+			return errors.Errorf("task %s not found", parentID)
+		}
+		task.ParentID = parentID
+		task.ProjectID = parent.ProjectID
+		task.SectionID = parent.SectionID
+	case move.SectionID != nil:
+		sectionID := a.sectionID(move.SectionID)
+		section, ok := a.data.sections[*sectionID]
+		if !ok {
+			return errors.Errorf("section %s not found", sectionID)
+		}
+		task.ParentID = nil
+		task.ProjectID = section.ProjectID
+		task.SectionID = sectionID
+	case move.ProjectID != nil:
+		projectID := a.projectID(move.ProjectID)
+		if _, ok := a.data.projects[*projectID]; !ok {
+			return errors.Errorf("project %s not found", projectID)
+		}
+		task.ParentID = nil
+		task.ProjectID = *projectID
+		task.SectionID = nil
+	default:
+		return errors.New("missing destination")
+	}
+
+	a.data.tasks[id] = task
+	a.data.moveSubtasks(id, task.ProjectID, task.SectionID)
+
+	return nil
+}
+
+// reorder sets the order of the resources listed under key in reorder
+// command arguments. set reports whether the resource exists.
+func (a localApplier) reorder(args []byte, key string, set func(id ID, order int) bool) error {
+	var v map[string][]struct {
+		ID           ID  `json:"id"`
+		ChildOrder   int `json:"child_order"`
+		SectionOrder int `json:"section_order"`
+	}
+	if err := json.Unmarshal(args, &v); err != nil {
+		return err
+	}
+
+	for _, r := range v[key] {
+		order := r.ChildOrder
+		if key == "sections" {
+			order = r.SectionOrder
+		}
+		if id := a.id(r.ID); !set(id, order) {
+			return errors.Errorf("%s %s not found", key, id)
+		}
+	}
+
+	return nil
+}
+
+// overlay sets the fields of v given in the JSON object args, except its ID.
+func overlay(v interface{}, args []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(args, &fields); err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	var current map[string]json.RawMessage
+	if err = json.Unmarshal(b, &current); err != nil {
+		return err
+	}
+	for field, value := range fields {
+		if field != "id" {
+			current[field] = value
+		}
+	}
+
+	if b, err = json.Marshal(current); err != nil {
+		return err
+	}
+
+	return json.Unmarshal(b, v)
+}
+
+// removeProject removes a project with its subprojects, sections and tasks.
+func (d storeData) removeProject(id ProjectID) {
+	delete(d.projects, id)
+
+	for childID, p := range d.projects {
+		if p.ParentID != nil && *p.ParentID == id {
+			d.removeProject(childID)
+		}
+	}
+	for sectionID, section := range d.sections {
+		if section.ProjectID == id {
+			delete(d.sections, sectionID)
+		}
+	}
+	for taskID, task := range d.tasks {
+		if task.ProjectID == id {
+			delete(d.tasks, taskID)
+		}
+	}
+}
+
+// removeSection removes a section with its tasks.
+func (d storeData) removeSection(id SectionID) {
+	delete(d.sections, id)
+
+	for taskID, task := range d.tasks {
+		if task.SectionID != nil && *task.SectionID == id {
+			delete(d.tasks, taskID)
+		}
+	}
+}
+
+// removeTask removes a task with its subtasks.
+func (d storeData) removeTask(id TaskID) {
+	delete(d.tasks, id)
+
+	for taskID, task := range d.tasks {
+		if task.ParentID != nil && *task.ParentID == id {
+			d.removeTask(taskID)
+		}
+	}
+}
+
+// moveSubtasks moves the subtasks of a task to its project and section.
+func (d storeData) moveSubtasks(id TaskID, projectID ProjectID, sectionID *SectionID) {
+	for taskID, task := range d.tasks {
+		if task.ParentID != nil && *task.ParentID == id {
+			task.ProjectID = projectID
+			task.SectionID = sectionID
+			d.tasks[taskID] = task
+			d.moveSubtasks(taskID, projectID, sectionID)
+		}
+	}
+}
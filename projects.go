@@ -2,12 +2,10 @@ package todoist
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
-	"io"
-	"io/ioutil"
 	"net/url"
 	"strconv"
-	"strings"
 
 	"github.com/google/uuid"
 )
@@ -21,7 +19,7 @@ type ProjectsService service
 // Project represents a Todoist project.
 type Project struct {
 	// The ID of the project.
-	ID int `json:"id"`
+	ID ProjectID `json:"id"`
 
 	// The legacy ID of the project.
 	// (only shown for objects created before 1 April 2017)
@@ -30,11 +28,11 @@ type Project struct {
 	// The name of the project.
 	Name string `json:"name"`
 
-	// A numeric ID representing the color of the project icon. Refer to the id column in the Colors guide for more info.
-	Color int `json:"color"`
+	// The color of the project icon. Refer to the name column in the Colors guide for more info.
+	Color Color `json:"color"`
 
 	// The ID of the parent project. Set to null for root projects.
-	ParentID *int `json:"parent_id"`
+	ParentID *ProjectID `json:"parent_id"`
 
 	// The legacy ID of the parent project. Set to null for root projects.
 	// (only shown for objects created before 1 April 2017)
@@ -43,23 +41,26 @@ type Project struct {
 	// The order of the project. Defines the position of the project among all the projects with the same parent_id
 	ChildOrder int `json:"child_order"`
 
-	// Whether the project's sub-projects are collapsed (where 1 is true and 0 is false).
-	Collapsed int `json:"collapsed"`
+	// Whether the project's sub-projects are collapsed.
+	Collapsed Bool `json:"collapsed"`
 
 	// Whether the project is shared (a true or false value).
 	Shared bool `json:"shared"`
 
-	// Whether the project is marked as deleted (where 1 is true and 0 is false).
-	IsDeleted int `json:"is_deleted"`
+	// Whether the project is marked as deleted.
+	IsDeleted Bool `json:"is_deleted"`
 
-	// Whether the project is marked as archived (where 1 is true and 0 is false).
-	IsArchived int `json:"is_archived"`
+	// Whether the project is marked as archived.
+	IsArchived Bool `json:"is_archived"`
 
-	// Whether the project is a favorite (where 1 is true and 0 is false).
-	IsFavorite int `json:"is_favorite"`
+	// Whether the project is a favorite.
+	IsFavorite Bool `json:"is_favorite"`
 
 	// Identifier to find the match between different copies of shared projects. When you share a project, its copy has a different ID for your collaborators. To find a project in a different account that matches yours, you can use the "sync_id" attribute. For non-shared projects the attribute is set to null.
-	SyncID *int `json:"sync_id"`
+	SyncID *ID `json:"sync_id"`
+
+	// The mode in which to render tasks in this project, "list" or "board" (v9 only).
+	ViewStyle string `json:"view_style,omitempty"`
 
 	// Whether the project is Inbox (true or otherwise this property is not sent).
 	InboxProject *bool `json:"inbox_project"`
@@ -68,6 +69,41 @@ type Project struct {
 	TeamInbox *bool `json:"team_inbox"`
 }
 
+// UnmarshalJSON implements json.Unmarshaler, accepting the field names of
+// both the Sync and REST APIs.
+func (p *Project) UnmarshalJSON(data []byte) error {
+	type project Project
+
+	var v struct {
+		project
+
+		// REST API field names.
+		Order          *int  `json:"order"`
+		IsShared       *bool `json:"is_shared"`
+		IsInboxProject bool  `json:"is_inbox_project"`
+		IsTeamInbox    bool  `json:"is_team_inbox"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	*p = Project(v.project)
+	if v.Order != nil {
+		p.ChildOrder = *v.Order
+	}
+	if v.IsShared != nil {
+		p.Shared = *v.IsShared
+	}
+	if v.IsInboxProject {
+		p.InboxProject = &v.IsInboxProject
+	}
+	if v.IsTeamInbox {
+		p.TeamInbox = &v.IsTeamInbox
+	}
+
+	return nil
+}
+
 // List the projects for a user.
 func (s *ProjectsService) List(ctx context.Context, syncToken string) ([]Project, ReadResponse, error) {
 	s.client.Logln("---------- Projects.List")
@@ -93,8 +129,8 @@ type AddProject struct {
 	// A numeric ID representing the color of the project icon. Refer to the id column in the Colors guide for more info.
 	Color int `json:"color,omitempty"`
 
-	// The ID of the parent project. Set to null for root projects
-	ParentID int `json:"parent_id,omitempty"`
+	// The ID of the parent project (could be temp id). Set to null for root projects
+	ParentID *ProjectID `json:"parent_id,omitempty"`
 
 	// The order of the project. Defines the position of the project among all the projects with the same parent_id
 	ChildOrder int `json:"child_order,omitempty"`
@@ -140,7 +176,7 @@ func (s *ProjectsService) Add(ctx context.Context, syncToken string, addProject
 
 type UpdateProject struct {
 	// The ID of the project (could be temp id).
-	ID string `json:"id"`
+	ID ProjectID `json:"id"`
 
 	// The name of the project (a string value).
 	Name string `json:"name,omitempty"`
@@ -192,10 +228,10 @@ func (s *ProjectsService) Update(ctx context.Context, syncToken string, updatePr
 
 type MoveProject struct {
 	// The ID of the project (could be temp id).
-	ID string `json:"id"`
+	ID ProjectID `json:"id"`
 
 	// The ID of the parent project (could be temp id). If set to null, the project will be moved to the root
-	ParentID string `json:"parent_id"`
+	ParentID *ProjectID `json:"parent_id"`
 
 	TempID string `json:"-"`
 }
@@ -235,7 +271,7 @@ func (s *ProjectsService) Move(ctx context.Context, syncToken string, moveProjec
 
 type DeleteProject struct {
 	// ID of the project to delete (could be a temp id).
-	ID string `json:"id"`
+	ID ProjectID `json:"id"`
 
 	TempID string `json:"-"`
 }
@@ -275,7 +311,7 @@ func (s *ProjectsService) Delete(ctx context.Context, syncToken string, deletePr
 
 type ArchiveProject struct {
 	// ID of the project to archive (could be a temp id).
-	ID string `json:"id"`
+	ID ProjectID `json:"id"`
 
 	TempID string `json:"-"`
 }
@@ -315,7 +351,7 @@ func (s *ProjectsService) Archive(ctx context.Context, syncToken string, archive
 
 type UnarchiveProject struct {
 	// ID of the project to unarchive (could be a temp id).
-	ID string `json:"id"`
+	ID ProjectID `json:"id"`
 
 	TempID string `json:"-"`
 }
@@ -357,8 +393,8 @@ func (s *ProjectsService) Unarchive(ctx context.Context, syncToken string, unarc
 }
 
 type ReorderedProject struct {
-	// ID of the project to order.
-	ID string `json:"id"`
+	// ID of the project to order (could be a temp id).
+	ID ProjectID `json:"id"`
 
 	// The new order.
 	ChildOrder int `json:"child_order"`
@@ -413,52 +449,20 @@ type ProjectInfo struct {
 // including all the notes. It's especially important because on initial load
 // we return no more than the last 10 notes. If a client requires more, they
 // can be downloaded using this endpoint. It returns a JSON object with the
-// project, and optionally the notes attributes.
-func (s *ProjectsService) GetProjectInfo(ctx context.Context, syncToken string, ID string, allData bool) (ProjectInfo, error) {
+// project, and optionally the notes attributes. The endpoint doesn't take a
+// sync token, so syncToken is ignored.
+func (s *ProjectsService) GetProjectInfo(ctx context.Context, syncToken string, ID ProjectID, allData bool) (ProjectInfo, error) {
 	s.client.Logln("---------- Projects.GetProjectInfo")
 
-	s.client.SetDebug(false)
-	req, err := s.client.NewRequest(syncToken, []string{}, nil)
-	if err != nil {
-		return ProjectInfo{}, err
-	}
-	s.client.SetDebug(true)
-
-	// Update the URL
-	req.URL, _ = url.Parse(defaultBaseURL + "/projects/get")
-
-	// Parse the request body
-	body, err := ioutil.ReadAll(req.Body)
-	if err != nil {
-		return ProjectInfo{}, err
-	}
+	form := url.Values{}
+	form.Set("project_id", s.client.ResolveID(ID.ID).String())
+	form.Set("all_data", strconv.FormatBool(allData))
 
-	form, err := url.ParseQuery(string(body))
+	req, err := s.client.newEndpointRequest("projects/get", form)
 	if err != nil {
 		return ProjectInfo{}, err
 	}
 
-	// Remove the "commands" form field since we don't use it in this request
-	form.Del("commands")
-
-	// Add GetProjectInfo-specific fields
-	form.Add("project_id", ID)
-	form.Add("all_data", strconv.FormatBool(allData))
-
-	for k := range form {
-		s.client.Logf("%-15s %-30s\n", k, form.Get(k))
-	}
-	s.client.Logln()
-
-	bodyReader := strings.NewReader(form.Encode())
-
-	// Set the updated content-length header or else http/2 will complain about
-	// request body being larger than the content length
-	req.ContentLength = int64(bodyReader.Len())
-
-	// Add encoded form back to the original request body
-	req.Body = io.NopCloser(bodyReader)
-
 	var projectInfoResponse ProjectInfo
 	_, err = s.client.Do(ctx, req, &projectInfoResponse)
 	if err != nil {
@@ -469,57 +473,25 @@ func (s *ProjectsService) GetProjectInfo(ctx context.Context, syncToken string,
 }
 
 type ProjectData struct {
-	Project  Project       `json:"project"`
-	Notes    []interface{} `json:"project_notes"` // TODO use the actual notes struct
-	Sections []interface{} `json:"sections"`      // TODO use the actual sections struct
-	Items    []interface{} `json:"items"`         // TODO use the actual items struct
+	Project  Project   `json:"project"`
+	Notes    []Comment `json:"project_notes"`
+	Sections []Section `json:"sections"`
+	Items    []Task    `json:"items"`
 }
 
 // Gets a JSON object with the project, its notes, sections and any uncompleted items.
-func (s *ProjectsService) GetProjectData(ctx context.Context, syncToken string, projectID string) (ProjectData, error) {
+// syncToken is ignored, as with GetProjectInfo.
+func (s *ProjectsService) GetProjectData(ctx context.Context, syncToken string, projectID ProjectID) (ProjectData, error) {
 	s.client.Logln("---------- Projects.GetProjectData")
 
-	s.client.SetDebug(false)
-	req, err := s.client.NewRequest(syncToken, []string{}, nil)
-	if err != nil {
-		return ProjectData{}, err
-	}
-	s.client.SetDebug(true)
-
-	// Update the URL
-	req.URL, _ = url.Parse(defaultBaseURL + "/projects/get_data")
-
-	// Parse the request body
-	body, err := ioutil.ReadAll(req.Body)
-	if err != nil {
-		return ProjectData{}, err
-	}
+	form := url.Values{}
+	form.Set("project_id", s.client.ResolveID(projectID.ID).String())
 
-	form, err := url.ParseQuery(string(body))
+	req, err := s.client.newEndpointRequest("projects/get_data", form)
 	if err != nil {
 		return ProjectData{}, err
 	}
 
-	// Remove the "commands" form field since we don't use it in this request
-	form.Del("commands")
-
-	// Add GetProjectData-specific fields
-	form.Add("project_id", projectID)
-
-	for k := range form {
-		s.client.Logf("%-15s %-30s\n", k, form.Get(k))
-	}
-	s.client.Logln()
-
-	bodyReader := strings.NewReader(form.Encode())
-
-	// Set the updated content-length header or else http/2 will complain about
-	// request body being larger than the content length
-	req.ContentLength = int64(bodyReader.Len())
-
-	// Add encoded form back to the original request body
-	req.Body = io.NopCloser(bodyReader)
-
 	var projectDataResponse ProjectData
 	_, err = s.client.Do(ctx, req, &projectDataResponse)
 	if err != nil {
@@ -541,53 +513,21 @@ type Pagination struct {
 //
 // Purposefully leaving `pagination` as a pointer so the caller can optionally pass in
 // pagination details. If pagination details are not provided, they are not added to the request.
+// syncToken is ignored, as with GetProjectInfo.
 func (s *ProjectsService) GetArchivedProjects(ctx context.Context, syncToken string, pagination *Pagination) ([]Project, error) {
 	s.client.Logln("---------- Projects.GetArchivedProjects")
 
-	s.client.SetDebug(false)
-	req, err := s.client.NewRequest(syncToken, []string{}, nil)
-	if err != nil {
-		return []Project{}, err
-	}
-	s.client.SetDebug(true)
-
-	// Update the URL
-	req.URL, _ = url.Parse(defaultBaseURL + "/projects/get_archived")
-
-	// Parse the request body
-	body, err := ioutil.ReadAll(req.Body)
-	if err != nil {
-		return []Project{}, err
+	form := url.Values{}
+	if pagination != nil {
+		form.Set("limit", fmt.Sprint(pagination.Limit))
+		form.Set("offset", fmt.Sprint(pagination.Offset))
 	}
 
-	form, err := url.ParseQuery(string(body))
+	req, err := s.client.newEndpointRequest("projects/get_archived", form)
 	if err != nil {
 		return []Project{}, err
 	}
 
-	// Remove the "commands" form field since we don't use it in this request
-	form.Del("commands")
-
-	// Add GetProjectData-specific fields
-	if pagination != nil {
-		form.Add("limit", fmt.Sprint(pagination.Limit))
-		form.Add("offset", fmt.Sprint(pagination.Offset))
-	}
-
-	for k := range form {
-		s.client.Logf("%-15s %-30s\n", k, form.Get(k))
-	}
-	s.client.Logln()
-
-	bodyReader := strings.NewReader(form.Encode())
-
-	// Set the updated content-length header or else http/2 will complain about
-	// request body being larger than the content length
-	req.ContentLength = int64(bodyReader.Len())
-
-	// Add encoded form back to the original request body
-	req.Body = io.NopCloser(bodyReader)
-
 	var archivedProjectsResponse []Project
 	_, err = s.client.Do(ctx, req, &archivedProjectsResponse)
 	if err != nil {
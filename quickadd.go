@@ -0,0 +1,285 @@
+package todoist
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// QuickAddPreview is Quick Add text parsed offline by ParseQuickAdd, showing
+// what a task would look like before it is submitted.
+type QuickAddPreview struct {
+	// The task content, with the Quick Add markers and due date removed.
+	Content string
+
+	// The name of the project, from "#Project".
+	Project string
+
+	// The name of the section, from "/Section".
+	Section string
+
+	// The names of the labels, from "@label".
+	Labels []string
+
+	// The priority of the task as sent to the API (4 for p1, down to 1 for p4), or 0 if not set.
+	Priority int
+
+	// The due date phrase, such as "every 1st" or "tomorrow at 5pm".
+	DueString string
+}
+
+var priorityPattern = regexp.MustCompile(`^(?i)p([1-4])$`)
+
+// ParseQuickAdd parses Quick Add text, such as "Pay rent every 1st #Home
+// @bills p1", the way QuickAdd would: "#project", "/section" and "@label"
+// markers, priorities p1 to p4, and an English due date phrase.
+//
+// Due date phrases are recognized but not resolved to dates; the phrase is
+// kept as the due string for Todoist to parse.
+func ParseQuickAdd(text string) QuickAddPreview {
+	var p QuickAddPreview
+
+	var words []string
+	for _, word := range strings.Fields(text) {
+		switch {
+		case len(word) > 1 && word[0] == '#':
+			p.Project = word[1:]
+		case len(word) > 1 && word[0] == '/':
+			p.Section = word[1:]
+		case len(word) > 1 && word[0] == '@':
+			p.Labels = append(p.Labels, word[1:])
+		case priorityPattern.MatchString(word):
+			p.Priority = 5 - int(word[1]-'0')
+		default:
+			words = append(words, word)
+		}
+	}
+
+	for i := range words {
+		if n := dueLength(words[i:]); n > 0 {
+			p.DueString = strings.TrimRight(strings.Join(words[i:i+n], " "), ",")
+			words = append(words[:i:i], words[i+n:]...)
+			break
+		}
+	}
+
+	p.Content = strings.Join(words, " ")
+
+	return p
+}
+
+// AddTask fills an AddTask from the preview, resolving the project, section
+// and label names against the given resources (for example from a full sync).
+// Names are matched case-insensitively, and an error is returned for names
+// that cannot be resolved.
+//
+// The section is looked up in the task's project, or in any project if no
+// project is set. If labels is nil, label names are used as IDs as is, as the
+// v9 API refers to labels by name.
+func (p QuickAddPreview) AddTask(projects []Project, sections []Section, labels []Label) (AddTask, error) {
+	addTask := AddTask{
+		Content:  p.Content,
+		Priority: p.Priority,
+	}
+
+	if p.DueString != "" {
+		addTask.Due = &Due{String: p.DueString, Lang: "en"}
+	}
+
+	if p.Project != "" {
+		for i := range projects {
+			if strings.EqualFold(projects[i].Name, p.Project) && !bool(projects[i].IsDeleted) {
+				addTask.ProjectID = &projects[i].ID
+				break
+			}
+		}
+		if addTask.ProjectID == nil {
+			return AddTask{}, errors.Errorf("unknown project %q", p.Project)
+		}
+	}
+
+	if p.Section != "" {
+		for i := range sections {
+			s := &sections[i]
+			if !strings.EqualFold(s.Name, p.Section) || s.IsDeleted {
+				continue
+			}
+			if addTask.ProjectID != nil && s.ProjectID != *addTask.ProjectID {
+				continue
+			}
+
+			addTask.SectionID = &s.ID
+			if addTask.ProjectID == nil {
+				addTask.ProjectID = &s.ProjectID
+			}
+			break
+		}
+		if addTask.SectionID == nil {
+			return AddTask{}, errors.Errorf("unknown section %q", p.Section)
+		}
+	}
+
+	for _, name := range p.Labels {
+		if labels == nil {
+			addTask.Labels = append(addTask.Labels, LabelID{NewID(name)})
+			continue
+		}
+
+		found := false
+		for _, l := range labels {
+			if strings.EqualFold(l.Name, name) && !bool(l.IsDeleted) {
+				addTask.Labels = append(addTask.Labels, l.ID)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return AddTask{}, errors.Errorf("unknown label %q", name)
+		}
+	}
+
+	return addTask, nil
+}
+
+var (
+	dueRelativeDays = wordSet("today", "tod", "tomorrow", "tom", "tonight", "yesterday")
+	dueWeekdays     = wordSet("monday", "mon", "tuesday", "tue", "tues", "wednesday", "wed", "thursday", "thu", "thur", "thurs", "friday", "fri", "saturday", "sat", "sunday", "sun")
+	dueMonths       = wordSet("january", "jan", "february", "feb", "march", "mar", "april", "apr", "may", "june", "jun", "july", "jul", "august", "aug", "september", "sep", "sept", "october", "oct", "november", "nov", "december", "dec")
+	dueUnits        = wordSet("day", "days", "week", "weeks", "month", "months", "year", "years", "hour", "hours", "hr", "hrs", "minute", "minutes", "min", "mins")
+	dueRecurring    = wordSet("weekday", "weekdays", "workday", "workdays", "weekend", "quarter")
+	dueDayParts     = wordSet("morning", "afternoon", "evening", "night")
+	dueConnectors   = wordSet("on", "at", "from", "starting", "until", "ending", "and", "for")
+	dueNextPeriods  = wordSet("week", "month", "year", "weekend")
+
+	dueTimePattern    = regexp.MustCompile(`^(\d{1,2}(:\d{2})?(am|pm)|\d{1,2}:\d{2}|noon|midnight)$`)
+	dueDatePattern    = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2}|\d{1,2}/\d{1,2}(/\d{2,4})?)$`)
+	dueOrdinalPattern = regexp.MustCompile(`^\d{1,2}(st|nd|rd|th)$`)
+	dueNumberPattern  = regexp.MustCompile(`^\d+$`)
+	dueYearPattern    = regexp.MustCompile(`^\d{4}$`)
+)
+
+func wordSet(words ...string) map[string]bool {
+	m := make(map[string]bool, len(words))
+	for _, w := range words {
+		m[w] = true
+	}
+
+	return m
+}
+
+// dueMatcher recognizes due date phrases in a list of words.
+type dueMatcher struct {
+	words     []string // Lowercased words, without trailing commas.
+	i         int      // Index of the next word.
+	recurring bool     // Whether the phrase started with "every".
+	atoms     int      // Number of date or time parts matched.
+}
+
+// dueLength returns the number of words at the start of words that form a
+// due date phrase, or 0 if words does not start with one.
+func dueLength(words []string) int {
+	m := dueMatcher{words: make([]string, len(words))}
+	for i, w := range words {
+		m.words[i] = strings.TrimRight(strings.ToLower(w), ",")
+	}
+
+	if m.accept("every", "every!", "ev", "after") {
+		m.recurring = true
+		m.accept("other")
+	}
+
+	for {
+		save := m.i
+
+		connector := dueConnectors[m.peek(0)]
+		if connector {
+			m.i++
+		}
+
+		if !m.atom(connector) {
+			m.i = save
+			break
+		}
+		m.atoms++
+	}
+
+	if m.atoms == 0 {
+		return 0
+	}
+
+	return m.i
+}
+
+func (m *dueMatcher) peek(offset int) string {
+	if m.i+offset >= len(m.words) {
+		return ""
+	}
+
+	return m.words[m.i+offset]
+}
+
+func (m *dueMatcher) accept(words ...string) bool {
+	for _, w := range words {
+		if m.peek(0) == w {
+			m.i++
+			return true
+		}
+	}
+
+	return false
+}
+
+// atom matches a single date or time part, such as "tomorrow", "next monday",
+// "in 3 days", "jan 5th" or "5pm". afterConnector reports whether it follows
+// a connector such as "on", which allows parts that are ambiguous on their own.
+func (m *dueMatcher) atom(afterConnector bool) bool {
+	w, next := m.peek(0), m.peek(1)
+	ambiguous := m.recurring || m.atoms > 0 || afterConnector
+
+	switch {
+	case w == "":
+		return false
+
+	case dueRelativeDays[w], dueWeekdays[w], dueTimePattern.MatchString(w), dueDatePattern.MatchString(w):
+		m.i++
+
+	case (w == "next" || w == "this") && (dueWeekdays[next] || dueNextPeriods[next]):
+		m.i += 2
+
+	case w == "in" && (dueNumberPattern.MatchString(next) || next == "a" || next == "an") && dueUnits[m.peek(2)]:
+		m.i += 3
+
+	case dueNumberPattern.MatchString(w) && (next == "am" || next == "pm"):
+		m.i += 2
+
+	case (dueNumberPattern.MatchString(w) || dueOrdinalPattern.MatchString(w)) && dueMonths[next]:
+		m.i += 2
+		m.year()
+
+	case dueMonths[w] && (dueNumberPattern.MatchString(next) || dueOrdinalPattern.MatchString(next)):
+		m.i += 2
+		m.year()
+
+	case m.recurring && dueNumberPattern.MatchString(w) && (dueUnits[next] || dueRecurring[next]):
+		m.i += 2
+
+	case ambiguous && (dueOrdinalPattern.MatchString(w) || dueMonths[w] || dueDayParts[w]):
+		m.i++
+
+	case m.recurring && (dueUnits[w] || dueRecurring[w] || dueWeekdays[strings.TrimSuffix(w, "s")]):
+		m.i++
+
+	default:
+		return false
+	}
+
+	return true
+}
+
+// year consumes an optional year after a month and day.
+func (m *dueMatcher) year() {
+	if dueYearPattern.MatchString(m.peek(0)) {
+		m.i++
+	}
+}
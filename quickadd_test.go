@@ -0,0 +1,165 @@
+package todoist
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func Test_ParseQuickAdd(t *testing.T) {
+	tests := []struct {
+		text string
+		want QuickAddPreview
+	}{
+		{
+			text: "Pay rent every 1st #Home @bills p1",
+			want: QuickAddPreview{Content: "Pay rent", Project: "Home", Labels: []string{"bills"}, Priority: 4, DueString: "every 1st"},
+		},
+		{
+			text: "Call mom tomorrow at 5pm",
+			want: QuickAddPreview{Content: "Call mom", DueString: "tomorrow at 5pm"},
+		},
+		{
+			text: "Submit report on Jan 5th 2022 /Reports #Work p2",
+			want: QuickAddPreview{Content: "Submit report", Project: "Work", Section: "Reports", Priority: 3, DueString: "on Jan 5th 2022"},
+		},
+		{
+			text: "Water plants every other week starting mon",
+			want: QuickAddPreview{Content: "Water plants", DueString: "every other week starting mon"},
+		},
+		{
+			text: "Stand-up every weekday at 9:30am @work @meetings",
+			want: QuickAddPreview{Content: "Stand-up", Labels: []string{"work", "meetings"}, DueString: "every weekday at 9:30am"},
+		},
+		{
+			text: "Renew passport in 3 weeks P4",
+			want: QuickAddPreview{Content: "Renew passport", Priority: 1, DueString: "in 3 weeks"},
+		},
+		{
+			text: "Gym every mon, wed, fri at 7am",
+			want: QuickAddPreview{Content: "Gym", DueString: "every mon, wed, fri at 7am"},
+		},
+		{
+			text: "Dentist next tuesday 10am",
+			want: QuickAddPreview{Content: "Dentist", DueString: "next tuesday 10am"},
+		},
+		{
+			text: "File taxes 2022-04-15",
+			want: QuickAddPreview{Content: "File taxes", DueString: "2022-04-15"},
+		},
+		{
+			text: "Read 3 books",
+			want: QuickAddPreview{Content: "Read 3 books"},
+		},
+		{
+			text: "Work on project at home",
+			want: QuickAddPreview{Content: "Work on project at home"},
+		},
+		{
+			text: "Review every detail #",
+			want: QuickAddPreview{Content: "Review every detail #"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			if got := ParseQuickAdd(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %+v, received %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_QuickAddPreview_AddTask(t *testing.T) {
+	home := Project{ID: ProjectID{NewIntID(1)}, Name: "Home"}
+	work := Project{ID: ProjectID{NewIntID(2)}, Name: "Work"}
+	homeChores := Section{ID: SectionID{NewIntID(10)}, ProjectID: home.ID, Name: "Chores"}
+	workChores := Section{ID: SectionID{NewIntID(11)}, ProjectID: work.ID, Name: "Chores"}
+	bills := Label{ID: LabelID{NewIntID(100)}, Name: "Bills"}
+
+	projects := []Project{home, work}
+	sections := []Section{homeChores, workChores}
+	labels := []Label{bills}
+
+	addTask, err := ParseQuickAdd("Pay rent every 1st #work /chores @bills p1").AddTask(projects, sections, labels)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := AddTask{
+		Content:   "Pay rent",
+		ProjectID: &work.ID,
+		SectionID: &workChores.ID,
+		Labels:    []LabelID{bills.ID},
+		Priority:  4,
+		Due:       &Due{String: "every 1st", Lang: "en"},
+	}
+	if !reflect.DeepEqual(addTask, want) {
+		t.Errorf("expected %+v, received %+v", want, addTask)
+	}
+
+	addTask, err = ParseQuickAdd("Sweep /Chores @errands").AddTask(projects, sections, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *addTask.ProjectID != home.ID || *addTask.SectionID != homeChores.ID {
+		t.Errorf("expected the section's project to be used, received %v %v", addTask.ProjectID, addTask.SectionID)
+	}
+	if len(addTask.Labels) != 1 || addTask.Labels[0].String() != "errands" {
+		t.Errorf("expected label names as is without labels, received %v", addTask.Labels)
+	}
+
+	for _, text := range []string{"Task #Garden", "Task #Home /Errands", "Task @unknown"} {
+		if _, err = ParseQuickAdd(text).AddTask(projects, sections, labels); err == nil {
+			t.Errorf("expected an error for %q", text)
+		}
+	}
+}
+
+func Test_QuickAdd(t *testing.T) {
+	var form url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/sync/v8/quick/add" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Error(err)
+		}
+		form = r.PostForm
+
+		_, _ = w.Write([]byte(`{
+			"id": 2995104339,
+			"project_id": 2203306141,
+			"content": "Pay rent",
+			"priority": 4,
+			"labels": [2156154810],
+			"due": {"date": "2022-02-01", "timezone": null, "string": "every 1st", "lang": "en", "is_recurring": true}
+		}`))
+	}))
+	defer srv.Close()
+
+	client, err := NewClient("quick-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.BaseURL, _ = url.Parse(srv.URL + "/sync/v8/sync")
+
+	task, err := client.Tasks.QuickAdd(context.Background(), "Pay rent every 1st #Home @bills p1", &QuickAddOptions{Note: "Bank transfer", AutoReminder: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if form.Get("token") != "quick-token" || form.Get("text") != "Pay rent every 1st #Home @bills p1" || form.Get("note") != "Bank transfer" || form.Get("auto_reminder") != "true" {
+		t.Errorf("unexpected form %v", form)
+	}
+	if form.Has("reminder") {
+		t.Error("expected no reminder to be sent")
+	}
+
+	if task.Content != "Pay rent" || task.Priority != 4 || task.Due == nil || !task.Due.IsRecurring || task.Due.String != "every 1st" {
+		t.Errorf("unexpected task %+v", task)
+	}
+}
@@ -0,0 +1,416 @@
+package todoist
+
+import (
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Frequency is the unit of repetition of a Recurrence.
+type Frequency string
+
+const (
+	Hourly  Frequency = "hourly"
+	Daily   Frequency = "daily"
+	Weekly  Frequency = "weekly"
+	Monthly Frequency = "monthly"
+	Yearly  Frequency = "yearly"
+)
+
+// maxRecurrencePeriods bounds the number of periods searched for an
+// occurrence, so that rules which never match (such as "every 30th" of
+// February only) cannot loop forever.
+const maxRecurrencePeriods = 10000
+
+// Recurrence is a recurrence rule, such as the one described by the due string
+// "every other tue at 9am starting next month". Rules are usually obtained
+// from ParseDueString.
+type Recurrence struct {
+	// The unit of repetition.
+	Frequency Frequency
+
+	// The number of Frequency units between periods (2 for "every other week").
+	Interval int
+
+	// The days of the week of weekly rules. Defaults to the weekday of Start.
+	Weekdays []time.Weekday
+
+	// The days of the month of monthly rules, where -1 is the last day. Days past the end of a month fall on its last day. Defaults to the day of Start.
+	MonthDays []int
+
+	// The month of yearly rules, which fall on the first of MonthDays. Defaults to the month of Start.
+	Month time.Month
+
+	// Whether occurrences have a time of day, given by Hour and Minute.
+	HasTime bool
+	Hour    int
+	Minute  int
+
+	// The first occurrence. Periods are counted from it.
+	Start time.Time
+
+	// The last possible occurrence, or the zero time if the rule does not end.
+	Until time.Time
+
+	// Whether occurrences follow the completion date rather than the previous occurrence ("every!" and "after" rules).
+	FromCompletion bool
+
+	// The first day of the week, used to count weeks for weekly rules.
+	WeekStart time.Weekday
+}
+
+// location returns the location occurrences are computed in.
+func (r Recurrence) location() *time.Location {
+	if r.Start.IsZero() {
+		return time.UTC
+	}
+
+	return r.Start.Location()
+}
+
+// at returns the occurrence on the given date.
+func (r Recurrence) at(year int, month time.Month, day int) time.Time {
+	hour, minute := 0, 0
+	if r.HasTime {
+		hour, minute = r.Hour, r.Minute
+	}
+
+	return time.Date(year, month, day, hour, minute, 0, 0, r.location())
+}
+
+// Next returns the first occurrence strictly after the given time, and false
+// if there is none.
+func (r Recurrence) Next(after time.Time) (time.Time, bool) {
+	interval := r.Interval
+	if interval < 1 {
+		interval = 1
+	}
+
+	start := r.Start.In(r.location())
+	after = after.In(r.location())
+
+	var candidates func(period int) []time.Time
+	switch r.Frequency {
+	case Hourly:
+		candidates = func(period int) []time.Time {
+			return []time.Time{start.Add(time.Duration(period*interval) * time.Hour)}
+		}
+
+	case Daily:
+		candidates = func(period int) []time.Time {
+			return []time.Time{r.at(start.Year(), start.Month(), start.Day()+period*interval)}
+		}
+
+	case Weekly:
+		weekdays := r.Weekdays
+		if len(weekdays) == 0 {
+			weekdays = []time.Weekday{start.Weekday()}
+		}
+		offsets := make([]int, len(weekdays))
+		for i, wd := range weekdays {
+			offsets[i] = (int(wd) - int(r.WeekStart) + 7) % 7
+		}
+		sort.Ints(offsets)
+
+		weekOffset := (int(start.Weekday()) - int(r.WeekStart) + 7) % 7
+		candidates = func(period int) []time.Time {
+			out := make([]time.Time, len(offsets))
+			for i, offset := range offsets {
+				out[i] = r.at(start.Year(), start.Month(), start.Day()-weekOffset+period*interval*7+offset)
+			}
+			return out
+		}
+
+	case Monthly:
+		days := r.MonthDays
+		if len(days) == 0 {
+			days = []int{start.Day()}
+		}
+		candidates = func(period int) []time.Time {
+			first := time.Date(start.Year(), start.Month()+time.Month(period*interval), 1, 0, 0, 0, 0, r.location())
+			out := make([]time.Time, 0, len(days))
+			for _, day := range days {
+				out = append(out, r.at(first.Year(), first.Month(), clampDay(first.Year(), first.Month(), day)))
+			}
+			sort.Slice(out, func(i, j int) bool { return out[i].Before(out[j]) })
+			return out
+		}
+
+	case Yearly:
+		month, day := r.Month, start.Day()
+		if month == 0 {
+			month = start.Month()
+		}
+		if len(r.MonthDays) != 0 {
+			day = r.MonthDays[0]
+		}
+		candidates = func(period int) []time.Time {
+			year := start.Year() + period*interval
+			return []time.Time{r.at(year, month, clampDay(year, month, day))}
+		}
+
+	default:
+		return time.Time{}, false
+	}
+
+	// Skip the periods that end before after.
+	period := 0
+	if skip := r.periodsBefore(start, after, interval); skip > 0 {
+		period = skip
+	}
+
+	for ; period < maxRecurrencePeriods; period++ {
+		for _, t := range candidates(period) {
+			if t.Before(start) || !t.After(after) {
+				continue
+			}
+			if !r.Until.IsZero() && t.After(r.Until) {
+				return time.Time{}, false
+			}
+			return t, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// periodsBefore returns a number of periods that can safely be skipped when
+// looking for the first occurrence after the given time.
+func (r Recurrence) periodsBefore(start, after time.Time, interval int) int {
+	if !after.After(start) {
+		return 0
+	}
+
+	var n int
+	switch r.Frequency {
+	case Hourly:
+		n = int(after.Sub(start)/time.Hour) / interval
+	case Daily:
+		n = int(after.Sub(start)/(24*time.Hour)) / interval
+	case Weekly:
+		n = int(after.Sub(start)/(7*24*time.Hour)) / interval
+	case Monthly:
+		n = ((after.Year()-start.Year())*12 + int(after.Month()) - int(start.Month())) / interval
+	case Yearly:
+		n = (after.Year() - start.Year()) / interval
+	}
+
+	// Step back a period to stay clear of daylight saving time shifts and
+	// periods that straddle after.
+	return n - 1
+}
+
+// clampDay returns day as a day of the given month, where -1 is the last day
+// and days past the end of the month fall on the last day.
+func clampDay(year int, month time.Month, day int) int {
+	last := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	if day < 0 || day > last {
+		return last
+	}
+
+	return day
+}
+
+// Occurrences returns the first n occurrences, starting with Start.
+func (r Recurrence) Occurrences(n int) []time.Time {
+	var out []time.Time
+
+	t, ok := r.Start, !r.Start.IsZero()
+	for ok && len(out) < n {
+		out = append(out, t)
+		t, ok = r.Next(t)
+	}
+
+	return out
+}
+
+// Between returns the occurrences from start (inclusive) to end (exclusive).
+func (r Recurrence) Between(start, end time.Time) []time.Time {
+	var out []time.Time
+
+	t, ok := r.Next(start.Add(-time.Nanosecond))
+	for ok && t.Before(end) {
+		out = append(out, t)
+		t, ok = r.Next(t)
+	}
+
+	return out
+}
+
+// NextAfterCompletion returns the occurrence that follows completing the
+// current occurrence at the given time, and false if the rule has ended.
+//
+// Rules with FromCompletion count from the completion date: "every! 3 days"
+// is due 3 days after it is completed. Other rules continue from current,
+// skipping the occurrences that are already past when it is completed.
+func (r Recurrence) NextAfterCompletion(current, completed time.Time) (time.Time, bool) {
+	loc := r.location()
+	completed = completed.In(loc)
+
+	if r.FromCompletion {
+		return r.afterCompletion(completed)
+	}
+
+	after := current
+	if !r.HasTime {
+		// Full-day occurrences are past once their day has started.
+		y, m, d := completed.Date()
+		completed = time.Date(y, m, d, 0, 0, 0, 0, loc).Add(-time.Nanosecond)
+	}
+	if completed.After(after) {
+		after = completed
+	}
+
+	return r.Next(after)
+}
+
+// afterCompletion returns the next occurrence of a FromCompletion rule.
+func (r Recurrence) afterCompletion(completed time.Time) (time.Time, bool) {
+	interval := r.Interval
+	if interval < 1 {
+		interval = 1
+	}
+
+	day := r.at(completed.Year(), completed.Month(), completed.Day())
+
+	var next time.Time
+	switch {
+	case r.Frequency == Hourly:
+		next = completed.Add(time.Duration(interval) * time.Hour).Truncate(time.Minute)
+	case r.Frequency == Daily:
+		next = day.AddDate(0, 0, interval)
+	case r.Frequency == Weekly && len(r.Weekdays) == 0:
+		next = day.AddDate(0, 0, 7*interval)
+	case r.Frequency == Monthly && len(r.MonthDays) == 0:
+		first := time.Date(day.Year(), day.Month()+time.Month(interval), 1, 0, 0, 0, 0, day.Location())
+		next = r.at(first.Year(), first.Month(), clampDay(first.Year(), first.Month(), day.Day()))
+	case r.Frequency == Yearly && r.Month == 0:
+		year := day.Year() + interval
+		next = r.at(year, day.Month(), clampDay(year, day.Month(), day.Day()))
+	default:
+		// Rules on given days, such as "every! mon", follow the completion
+		// date like any other rule.
+		anchored := r
+		anchored.Start = day
+		return anchored.Next(day.Add(-time.Nanosecond).Add(24 * time.Hour))
+	}
+
+	if !r.Until.IsZero() && next.After(r.Until) {
+		return time.Time{}, false
+	}
+
+	return next, true
+}
+
+// Recurrence returns the recurrence rule of a task with a recurring due date,
+// interpreting its due string with the user's settings, with the current due
+// date as the first occurrence. It returns nil for tasks without a recurring
+// due date.
+func (t Task) Recurrence(settings DueSettings) (*Recurrence, error) {
+	if t.Due == nil || !t.Due.IsRecurring {
+		return nil, nil
+	}
+	if t.Due.Lang != "" && t.Due.Lang != "en" {
+		return nil, errors.Errorf("unable to interpret due strings in language %q", t.Due.Lang)
+	}
+
+	if settings.Location == nil {
+		settings.Location = time.UTC
+	}
+	if t.Due.Timezone != nil {
+		if loc, err := time.LoadLocation(*t.Due.Timezone); err == nil {
+			settings.Location = loc
+		}
+	}
+
+	current, err := t.Due.Time(settings.Location)
+	if err != nil {
+		return nil, err
+	}
+	current = current.In(settings.Location)
+
+	y, m, d := current.Date()
+	_, r, err := ParseDueString(t.Due.String, time.Date(y, m, d, 0, 0, 0, 0, settings.Location), settings)
+	if err != nil {
+		return nil, err
+	}
+	if r == nil {
+		return nil, errors.Errorf("due string %q is not recurring", t.Due.String)
+	}
+
+	r.Start = current
+	r.HasTime = t.Due.HasTime()
+	if r.HasTime {
+		r.Hour, r.Minute = current.Hour(), current.Minute()
+	}
+
+	return r, nil
+}
+
+// Occurrences returns the next n occurrences of the task, starting with its
+// current due date. For "every!" rules, each occurrence is assumed to be
+// completed when due. Tasks with a due date that is not recurring have a
+// single occurrence, and tasks without a due date none.
+func (t Task) Occurrences(n int, settings DueSettings) ([]time.Time, error) {
+	if t.Due == nil || n <= 0 {
+		return nil, nil
+	}
+
+	r, err := t.Recurrence(settings)
+	if err != nil || r != nil {
+		if r == nil {
+			return nil, err
+		}
+		return r.Occurrences(n), nil
+	}
+
+	due, err := t.dueTime(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	return []time.Time{due}, nil
+}
+
+// OccurrencesBetween returns the occurrences of the task from start
+// (inclusive) to end (exclusive), such as the days shown by a calendar view.
+// Occurrences before the current due date are not included.
+func (t Task) OccurrencesBetween(start, end time.Time, settings DueSettings) ([]time.Time, error) {
+	if t.Due == nil {
+		return nil, nil
+	}
+
+	r, err := t.Recurrence(settings)
+	if err != nil || r != nil {
+		if r == nil {
+			return nil, err
+		}
+		return r.Between(start, end), nil
+	}
+
+	due, err := t.dueTime(settings)
+	if err != nil {
+		return nil, err
+	}
+	if due.Before(start) || !due.Before(end) {
+		return nil, nil
+	}
+
+	return []time.Time{due}, nil
+}
+
+// dueTime returns the due date of a task in the user's timezone.
+func (t Task) dueTime(settings DueSettings) (time.Time, error) {
+	loc := settings.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	due, err := t.Due.Time(loc)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return due.In(loc), nil
+}
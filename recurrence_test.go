@@ -0,0 +1,191 @@
+package todoist
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func recurringTask(s, date string) Task {
+	return Task{Content: "Recurring", Due: &Due{Date: date, String: s, Lang: "en", IsRecurring: true}}
+}
+
+func formatOccurrences(times []time.Time) []string {
+	out := make([]string, len(times))
+	for i, t := range times {
+		if t.Hour() == 0 && t.Minute() == 0 {
+			out[i] = t.Format(DueDateLayout)
+		} else {
+			out[i] = t.Format(DueFloatingTimeLayout)
+		}
+	}
+
+	return out
+}
+
+func Test_Task_Occurrences(t *testing.T) {
+	tests := []struct {
+		s, date string
+		n       int
+		want    []string
+	}{
+		{s: "every day", date: "2022-03-09", n: 3, want: []string{"2022-03-09", "2022-03-10", "2022-03-11"}},
+		{s: "every other week", date: "2022-03-09", n: 3, want: []string{"2022-03-09", "2022-03-23", "2022-04-06"}},
+		{s: "every weekday", date: "2022-03-11", n: 3, want: []string{"2022-03-11", "2022-03-14", "2022-03-15"}},
+		{s: "every mon, fri", date: "2022-03-11", n: 4, want: []string{"2022-03-11", "2022-03-14", "2022-03-18", "2022-03-21"}},
+		{s: "every other tue at 9am starting next month", date: "2022-04-05T09:00:00", n: 3, want: []string{"2022-04-05T09:00:00", "2022-04-19T09:00:00", "2022-05-03T09:00:00"}},
+		{s: "every 31st", date: "2022-03-31", n: 4, want: []string{"2022-03-31", "2022-04-30", "2022-05-31", "2022-06-30"}},
+		{s: "every last day", date: "2022-02-28", n: 3, want: []string{"2022-02-28", "2022-03-31", "2022-04-30"}},
+		{s: "every 1st, 15th", date: "2022-03-15", n: 3, want: []string{"2022-03-15", "2022-04-01", "2022-04-15"}},
+		{s: "every month on the 15th", date: "2022-03-15", n: 3, want: []string{"2022-03-15", "2022-04-15", "2022-05-15"}},
+		{s: "every 2 weeks on monday", date: "2022-03-14", n: 3, want: []string{"2022-03-14", "2022-03-28", "2022-04-11"}},
+		{s: "every 3 months", date: "2022-01-31", n: 3, want: []string{"2022-01-31", "2022-04-30", "2022-07-31"}},
+		{s: "every year", date: "2024-02-29", n: 3, want: []string{"2024-02-29", "2025-02-28", "2026-02-28"}},
+		{s: "every day at 9am", date: "2022-03-10T09:00:00", n: 2, want: []string{"2022-03-10T09:00:00", "2022-03-11T09:00:00"}},
+		{s: "every 2 hours", date: "2022-03-09T10:00:00", n: 3, want: []string{"2022-03-09T10:00:00", "2022-03-09T12:00:00", "2022-03-09T14:00:00"}},
+		{s: "every day until mar 11", date: "2022-03-09", n: 5, want: []string{"2022-03-09", "2022-03-10", "2022-03-11"}},
+		{s: "every day ending 2022-03-10", date: "2022-03-09", n: 5, want: []string{"2022-03-09", "2022-03-10"}},
+		{s: "every! 3 days", date: "2022-03-09", n: 3, want: []string{"2022-03-09", "2022-03-12", "2022-03-15"}},
+		{s: "every day", date: "2022-03-09", n: 0, want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			got, err := recurringTask(tt.s, tt.date).Occurrences(tt.n, dueTestSettings)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s := formatOccurrences(got); !reflect.DeepEqual(s, tt.want) {
+				t.Errorf("expected %v, received %v", tt.want, s)
+			}
+		})
+	}
+}
+
+func Test_Task_Occurrences_FixedTimezone(t *testing.T) {
+	utc := "UTC"
+	task := Task{Due: &Due{Date: "2022-03-09T08:00:00Z", Timezone: &utc, String: "every day at 8am", Lang: "en", IsRecurring: true}}
+
+	got, err := task.Occurrences(2, dueTestSettings)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []time.Time{
+		time.Date(2022, time.March, 9, 8, 0, 0, 0, time.UTC),
+		time.Date(2022, time.March, 10, 8, 0, 0, 0, time.UTC),
+	}
+	if len(got) != len(want) || !got[0].Equal(want[0]) || !got[1].Equal(want[1]) {
+		t.Errorf("expected %v, received %v", want, got)
+	}
+}
+
+func Test_Task_OccurrencesBetween(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2022, time.March, d, 0, 0, 0, 0, dueTestLocation)
+	}
+
+	tests := []struct {
+		name       string
+		task       Task
+		start, end time.Time
+		want       []string
+	}{
+		{name: "window", task: recurringTask("every mon, fri", "2022-03-11"), start: day(1), end: day(22), want: []string{"2022-03-11", "2022-03-14", "2022-03-18", "2022-03-21"}},
+		{name: "end is exclusive", task: recurringTask("every mon, fri", "2022-03-11"), start: day(11), end: day(18), want: []string{"2022-03-11", "2022-03-14"}},
+		{name: "window after due date", task: recurringTask("every other day", "2022-03-09"), start: day(14), end: day(20), want: []string{"2022-03-15", "2022-03-17", "2022-03-19"}},
+		{name: "monthly on a day", task: recurringTask("every month on the 15th", "2022-03-15"), start: day(1), end: day(31), want: []string{"2022-03-15"}},
+		{name: "ended", task: recurringTask("every day until mar 10", "2022-03-09"), start: day(12), end: day(20), want: []string{}},
+		{name: "not recurring", task: Task{Due: &Due{Date: "2022-03-10"}}, start: day(1), end: day(31), want: []string{"2022-03-10"}},
+		{name: "not recurring outside", task: Task{Due: &Due{Date: "2022-03-10"}}, start: day(11), end: day(31), want: []string{}},
+		{name: "no due date", task: Task{}, start: day(1), end: day(31), want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.task.OccurrencesBetween(tt.start, tt.end, dueTestSettings)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s := formatOccurrences(got); !reflect.DeepEqual(s, tt.want) {
+				t.Errorf("expected %v, received %v", tt.want, s)
+			}
+		})
+	}
+}
+
+func Test_Task_Recurrence_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		task Task
+	}{
+		{name: "language", task: Task{Due: &Due{Date: "2022-03-09", String: "jeden Tag", Lang: "de", IsRecurring: true}}},
+		{name: "due string", task: recurringTask("every blue moon", "2022-03-09")},
+		{name: "not recurring", task: recurringTask("tomorrow", "2022-03-09")},
+		{name: "date", task: recurringTask("every day", "March 9")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.task.Occurrences(3, dueTestSettings); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+
+	if r, err := (Task{Due: &Due{Date: "2022-03-09"}}).Recurrence(dueTestSettings); r != nil || err != nil {
+		t.Errorf("expected no recurrence for a due date that is not recurring, received %+v, %v", r, err)
+	}
+}
+
+func Test_Recurrence_NextAfterCompletion(t *testing.T) {
+	at := func(d, h, m int) time.Time {
+		return time.Date(2022, time.March, d, h, m, 0, 0, dueTestLocation)
+	}
+
+	tests := []struct {
+		name      string
+		s, date   string
+		completed time.Time
+		want      string
+	}{
+		{name: "on time", s: "every day", date: "2022-03-09", completed: at(9, 15, 0), want: "2022-03-10"},
+		{name: "early", s: "every day", date: "2022-03-09", completed: at(8, 15, 0), want: "2022-03-10"},
+		{name: "overdue", s: "every day", date: "2022-03-01", completed: at(9, 15, 0), want: "2022-03-09"},
+		{name: "overdue weekly", s: "every mon", date: "2022-02-21", completed: at(9, 15, 0), want: "2022-03-14"},
+		{name: "with time", s: "every day at 9am", date: "2022-03-09T09:00:00", completed: at(9, 10, 0), want: "2022-03-10T09:00:00"},
+		{name: "with time overdue", s: "every day at 9am", date: "2022-03-07T09:00:00", completed: at(9, 8, 0), want: "2022-03-09T09:00:00"},
+		{name: "every!", s: "every! 3 days", date: "2022-03-09", completed: at(10, 15, 0), want: "2022-03-13"},
+		{name: "after", s: "after 2 weeks", date: "2022-03-09", completed: at(10, 15, 0), want: "2022-03-24"},
+		{name: "every! with time", s: "every! day at 9am", date: "2022-03-09T09:00:00", completed: at(10, 15, 0), want: "2022-03-11T09:00:00"},
+		{name: "every! hours", s: "every! 3 hours", date: "2022-03-09T09:00:00", completed: at(9, 10, 17), want: "2022-03-09T13:17:00"},
+		{name: "every! month", s: "every! month", date: "2022-01-31", completed: time.Date(2022, time.January, 31, 12, 0, 0, 0, dueTestLocation), want: "2022-02-28"},
+		{name: "every! weekday", s: "every! mon", date: "2022-03-14", completed: at(14, 12, 0), want: "2022-03-21"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := recurringTask(tt.s, tt.date)
+			r, err := task.Recurrence(dueTestSettings)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			next, ok := r.NextAfterCompletion(r.Start, tt.completed)
+			if !ok {
+				t.Fatal("expected a next occurrence")
+			}
+			if got := formatOccurrences([]time.Time{next})[0]; got != tt.want {
+				t.Errorf("expected %s, received %s", tt.want, got)
+			}
+		})
+	}
+
+	r, err := recurringTask("every day until mar 10", "2022-03-10").Recurrence(dueTestSettings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next, ok := r.NextAfterCompletion(r.Start, at(10, 12, 0)); ok {
+		t.Errorf("expected the rule to have ended, received %s", next)
+	}
+}
@@ -0,0 +1,69 @@
+package todoist
+
+import "encoding/json"
+
+// Reminder represents a Todoist reminder of a task.
+//
+// Todoist API docs: https://developer.todoist.com/sync/v8/#reminders
+type Reminder struct {
+	// The ID of the reminder.
+	ID ReminderID `json:"id"`
+
+	// The user to notify (or null if the user is the owner of the task).
+	NotifyUID *UserID `json:"notify_uid"`
+
+	// The task the reminder is about.
+	TaskID TaskID `json:"item_id"`
+
+	// How to send the reminder: "email", "mobile" (SMS) or "push" (mobile push notifications).
+	Service string `json:"service"`
+
+	// The type of the reminder: "relative" for a time-based reminder specified in minutes from the due date, "absolute" for a time-based reminder with a specific time and date in the future, and "location" for a location-based reminder.
+	Type string `json:"type"`
+
+	// The due date of the reminder, for absolute reminders.
+	Due *Due `json:"due"`
+
+	// The number of minutes before the task's due date when a relative reminder is triggered. Sent as minute_offset by the v9 API.
+	MinuteOffset int `json:"mm_offset"`
+
+	// An alias name for the location, for location reminders.
+	Name string `json:"name,omitempty"`
+
+	// The location latitude, for location reminders.
+	LocLat string `json:"loc_lat,omitempty"`
+
+	// The location longitude, for location reminders.
+	LocLong string `json:"loc_long,omitempty"`
+
+	// What should trigger a location reminder: "on_enter" for entering the location, or "on_leave" for leaving it.
+	LocTrigger string `json:"loc_trigger,omitempty"`
+
+	// The radius around the location that is still considered as part of the location (in meters).
+	Radius int `json:"radius,omitempty"`
+
+	// Whether the reminder is marked as deleted.
+	IsDeleted Bool `json:"is_deleted"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting the field names of
+// both the v8 and v9 Sync APIs.
+func (r *Reminder) UnmarshalJSON(data []byte) error {
+	type reminder Reminder
+
+	var v struct {
+		reminder
+
+		MinuteOffset *int `json:"minute_offset"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	*r = Reminder(v.reminder)
+	if v.MinuteOffset != nil {
+		r.MinuteOffset = *v.MinuteOffset
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package todoist
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// RESTService groups the services used for talking to the Todoist REST API.
+//
+// The REST API is simpler than the Sync API for some operations, such as
+// getting a single task, filtering active tasks by a query, or working with
+// comments. It shares the client's authentication, error types, retry policy
+// and logging, and returns the same Task, Project and Section structs as the
+// Sync API services.
+//
+// Todoist API docs: https://developer.todoist.com/rest/v2/
+type RESTService struct {
+	Tasks    *RESTTasksService
+	Projects *RESTProjectsService
+	Sections *RESTSectionsService
+	Labels   *RESTLabelsService
+	Comments *RESTCommentsService
+}
+
+// NewRESTRequest creates a REST API request for the given path, relative to the
+// client's RESTBaseURL. If specified, query is encoded into the URL, and the
+// value pointed to by body is JSON encoded and included as the request body.
+//
+// Temp IDs of resources created through the Sync API are replaced with their
+// real IDs in body, as the REST API does not know about temp IDs.
+func (c *Client) NewRESTRequest(method, path string, query url.Values, body interface{}) (*http.Request, error) {
+	u := c.RESTBaseURL.ResolveReference(&url.URL{Path: path, RawQuery: query.Encode()})
+
+	var r io.Reader
+	if body != nil {
+		b, err := json.Marshal(c.resolveTempIDs(body))
+		if err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("unable to serialize request body: %v", body))
+		}
+
+		c.Logf("%-15s %-30s\n", "body", b)
+		r = bytes.NewReader(b)
+	}
+
+	c.Logf("%-15s %-30s\n", method, u)
+	c.Logln()
+
+	req, err := http.NewRequest(method, u.String(), r)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := c.token()
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if method == http.MethodPost {
+		// Lets Todoist discard duplicates of a request that is retried.
+		req.Header.Set("X-Request-Id", uuid.New().String())
+	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+
+	return req, nil
+}
+
+// doREST creates and sends a REST API request, decoding the response into v.
+func (c *Client) doREST(ctx context.Context, method, path string, query url.Values, body, v interface{}) error {
+	req, err := c.NewRESTRequest(method, path, query, body)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.Do(ctx, req, v)
+	return err
+}
+
+// restPath joins a REST API collection and the ID of one of its resources,
+// such as "tasks/2995104339".
+func (c *Client) restPath(collection string, id ID) string {
+	return collection + "/" + c.ResolveID(id).String()
+}
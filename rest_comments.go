@@ -0,0 +1,176 @@
+package todoist
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+)
+
+// RESTCommentsService handles communication with the comment related
+// methods of the Todoist REST API.
+//
+// Todoist API docs: https://developer.todoist.com/rest/v2/#comments
+type RESTCommentsService service
+
+// FileAttachment is a file attached to a comment.
+//
+// Todoist API docs: https://developer.todoist.com/sync/v8/#file-attachments
+type FileAttachment struct {
+	// The name of the file.
+	FileName string `json:"file_name"`
+
+	// The size of the file in bytes.
+	FileSize int `json:"file_size,omitempty"`
+
+	// MIME type (for example text/plain or image/png).
+	FileType string `json:"file_type"`
+
+	// The URL where the file is located. Note that Todoist doesn't cache the remote content on their servers and stream or expose files directly from third party resources. In particular this means that you should avoid providing links to non-encrypted (plain HTTP) resources, as exposing this files in Todoist may issue a browser warning.
+	FileURL string `json:"file_url"`
+
+	// The type of the attachment, such as "file", "image", "video" or "audio".
+	ResourceType string `json:"resource_type,omitempty"`
+
+	// Upload completion state (either "pending" or "completed").
+	UploadState string `json:"upload_state,omitempty"`
+}
+
+type Comment struct {
+	// The ID of the comment.
+	ID CommentID `json:"id"`
+
+	// The ID of the task the comment belongs to, for task comments. Sent as item_id by the Sync API.
+	TaskID *TaskID `json:"task_id"`
+
+	// The ID of the project the comment belongs to, for project comments.
+	ProjectID *ProjectID `json:"project_id"`
+
+	// The date when the comment was posted.
+	PostedAt string `json:"posted_at"`
+
+	// The content of the comment. This value may contain markdown-formatted text and hyperlinks.
+	Content string `json:"content"`
+
+	// A file attached to the comment (or null if there is none). Sent as file_attachment by the Sync API.
+	Attachment *FileAttachment `json:"attachment"`
+
+	// Whether the comment is marked as deleted (Sync API only).
+	IsDeleted Bool `json:"is_deleted"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting the field names of
+// both the Sync (notes) and REST APIs.
+func (c *Comment) UnmarshalJSON(data []byte) error {
+	type comment Comment
+
+	var v struct {
+		comment
+
+		ItemID         *TaskID         `json:"item_id"`
+		Posted         *string         `json:"posted"`
+		FileAttachment *FileAttachment `json:"file_attachment"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	*c = Comment(v.comment)
+	if v.ItemID != nil {
+		c.TaskID = v.ItemID
+	}
+	if v.Posted != nil {
+		c.PostedAt = *v.Posted
+	}
+	if v.FileAttachment != nil {
+		c.Attachment = v.FileAttachment
+	}
+
+	return nil
+}
+
+// CommentFilter selects the comments returned by RESTCommentsService.List.
+// Exactly one of TaskID and ProjectID must be set.
+type CommentFilter struct {
+	// List the comments of a task.
+	TaskID *TaskID
+
+	// List the comments of a project.
+	ProjectID *ProjectID
+}
+
+// List the comments of a task or a project.
+func (s *RESTCommentsService) List(ctx context.Context, filter CommentFilter) ([]Comment, error) {
+	s.client.Logln("---------- REST.Comments.List")
+
+	q := url.Values{}
+	if filter.TaskID != nil {
+		q.Set("task_id", s.client.ResolveID(filter.TaskID.ID).String())
+	}
+	if filter.ProjectID != nil {
+		q.Set("project_id", s.client.ResolveID(filter.ProjectID.ID).String())
+	}
+
+	var comments []Comment
+	if err := s.client.doREST(ctx, http.MethodGet, "comments", q, nil, &comments); err != nil {
+		return nil, err
+	}
+
+	return comments, nil
+}
+
+// Get a comment.
+func (s *RESTCommentsService) Get(ctx context.Context, id CommentID) (Comment, error) {
+	s.client.Logln("---------- REST.Comments.Get")
+
+	var comment Comment
+	err := s.client.doREST(ctx, http.MethodGet, s.client.restPath("comments", id.ID), nil, nil, &comment)
+
+	return comment, err
+}
+
+type CreateCommentParams struct {
+	// The ID of the task to comment on. Either TaskID or ProjectID must be set.
+	TaskID *TaskID `json:"task_id,omitempty"`
+
+	// The ID of the project to comment on. Either TaskID or ProjectID must be set.
+	ProjectID *ProjectID `json:"project_id,omitempty"`
+
+	// The content of the comment. This value may contain markdown-formatted text and hyperlinks.
+	Content string `json:"content"`
+
+	// A file to attach to the comment.
+	Attachment *FileAttachment `json:"attachment,omitempty"`
+}
+
+// Create a new comment on a task or a project.
+func (s *RESTCommentsService) Create(ctx context.Context, createComment CreateCommentParams) (Comment, error) {
+	s.client.Logln("---------- REST.Comments.Create")
+
+	var comment Comment
+	err := s.client.doREST(ctx, http.MethodPost, "comments", nil, createComment, &comment)
+
+	return comment, err
+}
+
+type UpdateCommentParams struct {
+	// The new content of the comment.
+	Content string `json:"content"`
+}
+
+// Update an existing comment, returning the updated comment.
+func (s *RESTCommentsService) Update(ctx context.Context, id CommentID, updateComment UpdateCommentParams) (Comment, error) {
+	s.client.Logln("---------- REST.Comments.Update")
+
+	var comment Comment
+	err := s.client.doREST(ctx, http.MethodPost, s.client.restPath("comments", id.ID), nil, updateComment, &comment)
+
+	return comment, err
+}
+
+// Delete a comment.
+func (s *RESTCommentsService) Delete(ctx context.Context, id CommentID) error {
+	s.client.Logln("---------- REST.Comments.Delete")
+
+	return s.client.doREST(ctx, http.MethodDelete, s.client.restPath("comments", id.ID), nil, nil, nil)
+}
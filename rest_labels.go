@@ -0,0 +1,132 @@
+package todoist
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// RESTLabelsService handles communication with the label related
+// methods of the Todoist REST API.
+//
+// Todoist API docs: https://developer.todoist.com/rest/v2/#labels
+type RESTLabelsService service
+
+type Label struct {
+	// The ID of the label.
+	ID LabelID `json:"id"`
+
+	// The name of the label.
+	Name string `json:"name"`
+
+	// The color of the label icon. Refer to the name column in the Colors guide for more info.
+	Color Color `json:"color"`
+
+	// Label's order in the label list (a number, where the smallest value should place the label at the top). Sent as item_order by the Sync API.
+	Order int `json:"order"`
+
+	// Whether the label is a favorite.
+	IsFavorite Bool `json:"is_favorite"`
+
+	// Whether the label is marked as deleted (Sync API only).
+	IsDeleted Bool `json:"is_deleted"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting the field names of
+// both the Sync and REST APIs.
+func (l *Label) UnmarshalJSON(data []byte) error {
+	type label Label
+
+	var v struct {
+		label
+
+		ItemOrder *int `json:"item_order"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	*l = Label(v.label)
+	if v.ItemOrder != nil {
+		l.Order = *v.ItemOrder
+	}
+
+	return nil
+}
+
+// List the personal labels of the user.
+func (s *RESTLabelsService) List(ctx context.Context) ([]Label, error) {
+	s.client.Logln("---------- REST.Labels.List")
+
+	var labels []Label
+	if err := s.client.doREST(ctx, http.MethodGet, "labels", nil, nil, &labels); err != nil {
+		return nil, err
+	}
+
+	return labels, nil
+}
+
+// Get a personal label.
+func (s *RESTLabelsService) Get(ctx context.Context, id LabelID) (Label, error) {
+	s.client.Logln("---------- REST.Labels.Get")
+
+	var label Label
+	err := s.client.doREST(ctx, http.MethodGet, s.client.restPath("labels", id.ID), nil, nil, &label)
+
+	return label, err
+}
+
+type CreateLabelParams struct {
+	// The name of the label.
+	Name string `json:"name"`
+
+	// Label's order in the label list.
+	Order int `json:"order,omitempty"`
+
+	// The color of the label icon. Refer to the name column in the Colors guide for more info.
+	Color Color `json:"color,omitempty"`
+
+	// Whether the label is a favorite.
+	IsFavorite bool `json:"is_favorite,omitempty"`
+}
+
+// Create a new personal label.
+func (s *RESTLabelsService) Create(ctx context.Context, createLabel CreateLabelParams) (Label, error) {
+	s.client.Logln("---------- REST.Labels.Create")
+
+	var label Label
+	err := s.client.doREST(ctx, http.MethodPost, "labels", nil, createLabel, &label)
+
+	return label, err
+}
+
+type UpdateLabelParams struct {
+	// The name of the label.
+	Name string `json:"name,omitempty"`
+
+	// Label's order in the label list.
+	Order int `json:"order,omitempty"`
+
+	// The color of the label icon. Refer to the name column in the Colors guide for more info.
+	Color Color `json:"color,omitempty"`
+
+	// Whether the label is a favorite.
+	IsFavorite *bool `json:"is_favorite,omitempty"`
+}
+
+// Update an existing personal label, returning the updated label.
+func (s *RESTLabelsService) Update(ctx context.Context, id LabelID, updateLabel UpdateLabelParams) (Label, error) {
+	s.client.Logln("---------- REST.Labels.Update")
+
+	var label Label
+	err := s.client.doREST(ctx, http.MethodPost, s.client.restPath("labels", id.ID), nil, updateLabel, &label)
+
+	return label, err
+}
+
+// Delete a personal label. The label is removed from all tasks.
+func (s *RESTLabelsService) Delete(ctx context.Context, id LabelID) error {
+	s.client.Logln("---------- REST.Labels.Delete")
+
+	return s.client.doREST(ctx, http.MethodDelete, s.client.restPath("labels", id.ID), nil, nil, nil)
+}
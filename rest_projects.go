@@ -0,0 +1,92 @@
+package todoist
+
+import (
+	"context"
+	"net/http"
+)
+
+// RESTProjectsService handles communication with the project related
+// methods of the Todoist REST API.
+//
+// Todoist API docs: https://developer.todoist.com/rest/v2/#projects
+type RESTProjectsService service
+
+// List all projects of the user.
+func (s *RESTProjectsService) List(ctx context.Context) ([]Project, error) {
+	s.client.Logln("---------- REST.Projects.List")
+
+	var projects []Project
+	if err := s.client.doREST(ctx, http.MethodGet, "projects", nil, nil, &projects); err != nil {
+		return nil, err
+	}
+
+	return projects, nil
+}
+
+// Get a project.
+func (s *RESTProjectsService) Get(ctx context.Context, id ProjectID) (Project, error) {
+	s.client.Logln("---------- REST.Projects.Get")
+
+	var project Project
+	err := s.client.doREST(ctx, http.MethodGet, s.client.restPath("projects", id.ID), nil, nil, &project)
+
+	return project, err
+}
+
+type CreateProjectParams struct {
+	// The name of the project.
+	Name string `json:"name"`
+
+	// The ID of the parent project.
+	ParentID *ProjectID `json:"parent_id,omitempty"`
+
+	// The color of the project icon. Refer to the name column in the Colors guide for more info.
+	Color Color `json:"color,omitempty"`
+
+	// Whether the project is a favorite.
+	IsFavorite bool `json:"is_favorite,omitempty"`
+
+	// The mode in which to render tasks in this project, "list" or "board".
+	ViewStyle string `json:"view_style,omitempty"`
+}
+
+// Create a new project.
+func (s *RESTProjectsService) Create(ctx context.Context, createProject CreateProjectParams) (Project, error) {
+	s.client.Logln("---------- REST.Projects.Create")
+
+	var project Project
+	err := s.client.doREST(ctx, http.MethodPost, "projects", nil, createProject, &project)
+
+	return project, err
+}
+
+type UpdateProjectParams struct {
+	// The name of the project.
+	Name string `json:"name,omitempty"`
+
+	// The color of the project icon. Refer to the name column in the Colors guide for more info.
+	Color Color `json:"color,omitempty"`
+
+	// Whether the project is a favorite.
+	IsFavorite *bool `json:"is_favorite,omitempty"`
+
+	// The mode in which to render tasks in this project, "list" or "board".
+	ViewStyle string `json:"view_style,omitempty"`
+}
+
+// Update an existing project, returning the updated project.
+func (s *RESTProjectsService) Update(ctx context.Context, id ProjectID, updateProject UpdateProjectParams) (Project, error) {
+	s.client.Logln("---------- REST.Projects.Update")
+
+	var project Project
+	err := s.client.doREST(ctx, http.MethodPost, s.client.restPath("projects", id.ID), nil, updateProject, &project)
+
+	return project, err
+}
+
+// Delete a project and all of its sections and tasks.
+func (s *RESTProjectsService) Delete(ctx context.Context, id ProjectID) error {
+	s.client.Logln("---------- REST.Projects.Delete")
+
+	return s.client.doREST(ctx, http.MethodDelete, s.client.restPath("projects", id.ID), nil, nil, nil)
+}
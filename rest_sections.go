@@ -0,0 +1,83 @@
+package todoist
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// RESTSectionsService handles communication with the section related
+// methods of the Todoist REST API.
+//
+// Todoist API docs: https://developer.todoist.com/rest/v2/#sections
+type RESTSectionsService service
+
+// List the sections of the user, or of a single project if projectID is set.
+func (s *RESTSectionsService) List(ctx context.Context, projectID *ProjectID) ([]Section, error) {
+	s.client.Logln("---------- REST.Sections.List")
+
+	q := url.Values{}
+	if projectID != nil {
+		q.Set("project_id", s.client.ResolveID(projectID.ID).String())
+	}
+
+	var sections []Section
+	if err := s.client.doREST(ctx, http.MethodGet, "sections", q, nil, &sections); err != nil {
+		return nil, err
+	}
+
+	return sections, nil
+}
+
+// Get a section.
+func (s *RESTSectionsService) Get(ctx context.Context, id SectionID) (Section, error) {
+	s.client.Logln("---------- REST.Sections.Get")
+
+	var section Section
+	err := s.client.doREST(ctx, http.MethodGet, s.client.restPath("sections", id.ID), nil, nil, &section)
+
+	return section, err
+}
+
+type CreateSectionParams struct {
+	// The name of the section.
+	Name string `json:"name"`
+
+	// The ID of the project the section belongs to.
+	ProjectID ProjectID `json:"project_id"`
+
+	// The order of the section among all the sections of the project.
+	Order int `json:"order,omitempty"`
+}
+
+// Create a new section.
+func (s *RESTSectionsService) Create(ctx context.Context, createSection CreateSectionParams) (Section, error) {
+	s.client.Logln("---------- REST.Sections.Create")
+
+	var section Section
+	err := s.client.doREST(ctx, http.MethodPost, "sections", nil, createSection, &section)
+
+	return section, err
+}
+
+type UpdateSectionParams struct {
+	// The name of the section.
+	Name string `json:"name"`
+}
+
+// Update an existing section, returning the updated section.
+func (s *RESTSectionsService) Update(ctx context.Context, id SectionID, updateSection UpdateSectionParams) (Section, error) {
+	s.client.Logln("---------- REST.Sections.Update")
+
+	var section Section
+	err := s.client.doREST(ctx, http.MethodPost, s.client.restPath("sections", id.ID), nil, updateSection, &section)
+
+	return section, err
+}
+
+// Delete a section and all of its tasks.
+func (s *RESTSectionsService) Delete(ctx context.Context, id SectionID) error {
+	s.client.Logln("---------- REST.Sections.Delete")
+
+	return s.client.doREST(ctx, http.MethodDelete, s.client.restPath("sections", id.ID), nil, nil, nil)
+}
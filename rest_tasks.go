@@ -0,0 +1,201 @@
+package todoist
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// RESTTasksService handles communication with the task related
+// methods of the Todoist REST API.
+//
+// Todoist API docs: https://developer.todoist.com/rest/v2/#tasks
+type RESTTasksService service
+
+// TaskFilter narrows down the active tasks returned by RESTTasksService.List.
+// Filter takes precedence over the other fields, and IDs over all of them.
+type TaskFilter struct {
+	// Filter tasks by project ID.
+	ProjectID *ProjectID
+
+	// Filter tasks by section ID.
+	SectionID *SectionID
+
+	// Filter tasks by label name.
+	Label string
+
+	// Filter by any supported filter query, such as "today | overdue".
+	Filter string
+
+	// IETF language tag defining what language the filter is written in, if it differs from the default English.
+	Lang string
+
+	// A list of the task IDs to retrieve.
+	IDs []TaskID
+}
+
+func (f *TaskFilter) values(c *Client) url.Values {
+	q := url.Values{}
+	if f == nil {
+		return q
+	}
+
+	if f.ProjectID != nil {
+		q.Set("project_id", c.ResolveID(f.ProjectID.ID).String())
+	}
+	if f.SectionID != nil {
+		q.Set("section_id", c.ResolveID(f.SectionID.ID).String())
+	}
+	if f.Label != "" {
+		q.Set("label", f.Label)
+	}
+	if f.Filter != "" {
+		q.Set("filter", f.Filter)
+	}
+	if f.Lang != "" {
+		q.Set("lang", f.Lang)
+	}
+	if len(f.IDs) != 0 {
+		ids := make([]string, len(f.IDs))
+		for i, id := range f.IDs {
+			ids[i] = c.ResolveID(id.ID).String()
+		}
+		q.Set("ids", strings.Join(ids, ","))
+	}
+
+	return q
+}
+
+// List the active tasks of the user, optionally narrowed down by filter.
+func (s *RESTTasksService) List(ctx context.Context, filter *TaskFilter) ([]Task, error) {
+	s.client.Logln("---------- REST.Tasks.List")
+
+	var tasks []Task
+	if err := s.client.doREST(ctx, http.MethodGet, "tasks", filter.values(s.client), nil, &tasks); err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// Get an active task.
+func (s *RESTTasksService) Get(ctx context.Context, id TaskID) (Task, error) {
+	s.client.Logln("---------- REST.Tasks.Get")
+
+	var task Task
+	err := s.client.doREST(ctx, http.MethodGet, s.client.restPath("tasks", id.ID), nil, nil, &task)
+
+	return task, err
+}
+
+type CreateTaskParams struct {
+	// The text of the task. This value may contain markdown-formatted text and hyperlinks.
+	Content string `json:"content"`
+
+	// A description for the task. This value may contain markdown-formatted text and hyperlinks.
+	Description string `json:"description,omitempty"`
+
+	// The ID of the project to add the task to. By default the task is added to the user's Inbox project.
+	ProjectID *ProjectID `json:"project_id,omitempty"`
+
+	// The ID of the section to put the task into.
+	SectionID *SectionID `json:"section_id,omitempty"`
+
+	// The ID of the parent task.
+	ParentID *TaskID `json:"parent_id,omitempty"`
+
+	// Non-zero integer value used by clients to sort tasks under the same parent.
+	Order int `json:"order,omitempty"`
+
+	// The task's labels (a list of label names).
+	Labels []string `json:"labels,omitempty"`
+
+	// The priority of the task (a number between 1 and 4, 4 for very urgent and 1 for natural).
+	Priority int `json:"priority,omitempty"`
+
+	// Human defined task due date (ex.: "next Monday", "Tomorrow"). Only one of the due fields can be used.
+	DueString string `json:"due_string,omitempty"`
+
+	// Specific date in YYYY-MM-DD format relative to the user's timezone.
+	DueDate string `json:"due_date,omitempty"`
+
+	// Specific date and time in RFC3339 format in UTC.
+	DueDatetime string `json:"due_datetime,omitempty"`
+
+	// 2-letter code specifying the language in case DueString is not written in English.
+	DueLang string `json:"due_lang,omitempty"`
+
+	// The responsible user ID (only applies to shared tasks).
+	AssigneeID *UserID `json:"assignee_id,omitempty"`
+}
+
+// Create a new task.
+func (s *RESTTasksService) Create(ctx context.Context, createTask CreateTaskParams) (Task, error) {
+	s.client.Logln("---------- REST.Tasks.Create")
+
+	var task Task
+	err := s.client.doREST(ctx, http.MethodPost, "tasks", nil, createTask, &task)
+
+	return task, err
+}
+
+type UpdateTaskParams struct {
+	// The text of the task.
+	Content *string `json:"content,omitempty"`
+
+	// A description for the task.
+	Description *string `json:"description,omitempty"`
+
+	// The task's labels (a list of label names). An empty, non-nil list removes all labels.
+	Labels *[]string `json:"labels,omitempty"`
+
+	// The priority of the task (a number between 1 and 4, 4 for very urgent and 1 for natural).
+	Priority int `json:"priority,omitempty"`
+
+	// Human defined task due date (ex.: "next Monday", "Tomorrow"), or "no date" to remove the due date.
+	DueString string `json:"due_string,omitempty"`
+
+	// Specific date in YYYY-MM-DD format relative to the user's timezone.
+	DueDate string `json:"due_date,omitempty"`
+
+	// Specific date and time in RFC3339 format in UTC.
+	DueDatetime string `json:"due_datetime,omitempty"`
+
+	// 2-letter code specifying the language in case DueString is not written in English.
+	DueLang string `json:"due_lang,omitempty"`
+
+	// The responsible user ID.
+	AssigneeID *UserID `json:"assignee_id,omitempty"`
+}
+
+// Update an existing task, returning the updated task.
+func (s *RESTTasksService) Update(ctx context.Context, id TaskID, updateTask UpdateTaskParams) (Task, error) {
+	s.client.Logln("---------- REST.Tasks.Update")
+
+	var task Task
+	err := s.client.doREST(ctx, http.MethodPost, s.client.restPath("tasks", id.ID), nil, updateTask, &task)
+
+	return task, err
+}
+
+// Close (complete) a task.
+func (s *RESTTasksService) Close(ctx context.Context, id TaskID) error {
+	s.client.Logln("---------- REST.Tasks.Close")
+
+	return s.client.doREST(ctx, http.MethodPost, s.client.restPath("tasks", id.ID)+"/close", nil, nil, nil)
+}
+
+// Reopen (uncomplete) a task.
+func (s *RESTTasksService) Reopen(ctx context.Context, id TaskID) error {
+	s.client.Logln("---------- REST.Tasks.Reopen")
+
+	return s.client.doREST(ctx, http.MethodPost, s.client.restPath("tasks", id.ID)+"/reopen", nil, nil, nil)
+}
+
+// Delete a task.
+func (s *RESTTasksService) Delete(ctx context.Context, id TaskID) error {
+	s.client.Logln("---------- REST.Tasks.Delete")
+
+	return s.client.doREST(ctx, http.MethodDelete, s.client.restPath("tasks", id.ID), nil, nil, nil)
+}
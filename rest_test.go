@@ -0,0 +1,279 @@
+package todoist
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ides15/todoist/todoisttest"
+)
+
+// newRESTClient returns a client whose REST requests are served by handler.
+func newRESTClient(t *testing.T, handler http.Handler) *Client {
+	t.Helper()
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	client, err := NewClient("rest-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.RESTBaseURL, _ = url.Parse(srv.URL + "/rest/v2/")
+
+	return client
+}
+
+func Test_REST_Tasks(t *testing.T) {
+	type request struct {
+		method, path, query string
+		body                map[string]interface{}
+	}
+	var requests []request
+
+	client := newRESTClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer rest-token" {
+			t.Errorf("expected bearer authorization, received %q", got)
+		}
+		if r.Method == http.MethodPost && r.Header.Get("X-Request-Id") == "" {
+			t.Errorf("expected an X-Request-Id header for %s %s", r.Method, r.URL.Path)
+		}
+
+		req := request{method: r.Method, path: r.URL.Path, query: r.URL.RawQuery}
+		if r.ContentLength > 0 {
+			if err := json.NewDecoder(r.Body).Decode(&req.body); err != nil {
+				t.Error(err)
+			}
+		}
+		requests = append(requests, req)
+
+		task := `{"id": "2995104339", "project_id": "2203306141", "content": "Buy milk", "labels": ["errands"], "is_completed": false, "order": 3, "created_at": "2019-12-11T22:36:50.000000Z", "creator_id": "2671355", "assignee_id": null}`
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/rest/v2/tasks":
+			_, _ = w.Write([]byte("[" + task + "]"))
+		case r.Method == http.MethodDelete, strings.HasSuffix(r.URL.Path, "/close"), strings.HasSuffix(r.URL.Path, "/reopen"):
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			_, _ = w.Write([]byte(task))
+		}
+	}))
+
+	ctx := context.Background()
+	taskID := TaskID{NewID("2995104339")}
+	projectID := ProjectID{NewID("2203306141")}
+
+	tasks, err := client.REST.Tasks.List(ctx, &TaskFilter{ProjectID: &projectID, Filter: "today | overdue", IDs: []TaskID{taskID}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 1 {
+		t.Fatalf("expected 1 task, received %d", len(tasks))
+	}
+
+	task := tasks[0]
+	if task.ID != taskID || task.ProjectID != projectID || task.ChildOrder != 3 || task.DateAdded != "2019-12-11T22:36:50.000000Z" {
+		t.Errorf("unexpected task %+v", task)
+	}
+	if task.AddedByUID == nil || task.AddedByUID.String() != "2671355" {
+		t.Errorf("expected creator_id to be decoded into AddedByUID, received %v", task.AddedByUID)
+	}
+
+	if _, err = client.REST.Tasks.Get(ctx, taskID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.REST.Tasks.Create(ctx, CreateTaskParams{Content: "Buy milk", ProjectID: &projectID, DueString: "tomorrow"}); err != nil {
+		t.Fatal(err)
+	}
+	content := "Buy oat milk"
+	if _, err = client.REST.Tasks.Update(ctx, taskID, UpdateTaskParams{Content: &content}); err != nil {
+		t.Fatal(err)
+	}
+	if err = client.REST.Tasks.Close(ctx, taskID); err != nil {
+		t.Fatal(err)
+	}
+	if err = client.REST.Tasks.Reopen(ctx, taskID); err != nil {
+		t.Fatal(err)
+	}
+	if err = client.REST.Tasks.Delete(ctx, taskID); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []request{
+		{method: http.MethodGet, path: "/rest/v2/tasks", query: "filter=today+%7C+overdue&ids=2995104339&project_id=2203306141"},
+		{method: http.MethodGet, path: "/rest/v2/tasks/2995104339"},
+		{method: http.MethodPost, path: "/rest/v2/tasks", body: map[string]interface{}{"content": "Buy milk", "project_id": "2203306141", "due_string": "tomorrow"}},
+		{method: http.MethodPost, path: "/rest/v2/tasks/2995104339", body: map[string]interface{}{"content": "Buy oat milk"}},
+		{method: http.MethodPost, path: "/rest/v2/tasks/2995104339/close"},
+		{method: http.MethodPost, path: "/rest/v2/tasks/2995104339/reopen"},
+		{method: http.MethodDelete, path: "/rest/v2/tasks/2995104339"},
+	}
+	if len(requests) != len(want) {
+		t.Fatalf("expected %d requests, received %d", len(want), len(requests))
+	}
+	for i, w := range want {
+		got := requests[i]
+		if got.method != w.method || got.path != w.path || got.query != w.query {
+			t.Errorf("request %d: expected %s %s?%s, received %s %s?%s", i, w.method, w.path, w.query, got.method, got.path, got.query)
+		}
+		if w.body != nil {
+			gotBody, _ := json.Marshal(got.body)
+			wantBody, _ := json.Marshal(w.body)
+			if string(gotBody) != string(wantBody) {
+				t.Errorf("request %d: expected body %s, received %s", i, wantBody, gotBody)
+			}
+		}
+	}
+}
+
+func Test_REST_Resources(t *testing.T) {
+	responses := map[string]string{
+		"/rest/v2/projects/2203306141": `{"id": "2203306141", "name": "Shopping List", "color": "charcoal", "parent_id": null, "order": 1, "is_shared": true, "is_favorite": false, "is_inbox_project": false, "view_style": "list"}`,
+		"/rest/v2/sections/7025":       `{"id": "7025", "project_id": "2203306141", "order": 2, "name": "Groceries"}`,
+		"/rest/v2/labels/2156154810":   `{"id": "2156154810", "name": "Food", "color": "charcoal", "order": 1, "is_favorite": true}`,
+		"/rest/v2/comments/2992679862": `{"id": "2992679862", "task_id": "2995104339", "project_id": null, "posted_at": "2016-09-22T07:00:00.000000Z", "content": "Need one bottle of milk", "attachment": {"file_name": "File.pdf", "file_type": "application/pdf", "file_url": "https://cdn-domain.tld/path/to/file.pdf", "resource_type": "file"}}`,
+	}
+
+	client := newRESTClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(responses[r.URL.Path]))
+	}))
+	ctx := context.Background()
+
+	project, err := client.REST.Projects.Get(ctx, ProjectID{NewID("2203306141")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if project.Color != "charcoal" || !project.Shared || project.ChildOrder != 1 || project.InboxProject != nil {
+		t.Errorf("unexpected project %+v", project)
+	}
+
+	section, err := client.REST.Sections.Get(ctx, SectionID{NewID("7025")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if section.ProjectID != project.ID || section.SectionOrder != 2 {
+		t.Errorf("unexpected section %+v", section)
+	}
+
+	label, err := client.REST.Labels.Get(ctx, LabelID{NewID("2156154810")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if label.Name != "Food" || label.Order != 1 || !label.IsFavorite {
+		t.Errorf("unexpected label %+v", label)
+	}
+
+	comment, err := client.REST.Comments.Get(ctx, CommentID{NewID("2992679862")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if comment.TaskID == nil || comment.TaskID.String() != "2995104339" || comment.Attachment == nil || comment.Attachment.FileName != "File.pdf" {
+		t.Errorf("unexpected comment %+v", comment)
+	}
+}
+
+func Test_REST_Errors(t *testing.T) {
+	client := newRESTClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Task not found", http.StatusNotFound)
+	}))
+
+	_, err := client.REST.Tasks.Get(context.Background(), TaskID{NewID("1")})
+
+	var notFound NotFoundError
+	if !errors.As(err, &notFound) {
+		t.Fatalf("expected a NotFoundError, received %v", err)
+	}
+	if notFound.Message != "Task not found" || notFound.HTTPCode != http.StatusNotFound {
+		t.Errorf("unexpected error %+v", notFound.BaseError)
+	}
+}
+
+func Test_Retry(t *testing.T) {
+	var attempts int
+	client := newRESTClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			http.Error(w, "Service Unavailable", http.StatusServiceUnavailable)
+			return
+		}
+
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body["name"] != "Retried" {
+			t.Errorf("expected the request body to be resent, received %v (%v)", body, err)
+		}
+		_, _ = w.Write([]byte(`{"id": "1", "name": "Retried"}`))
+	}))
+	client.SetRetryPolicy(RetryPolicy{MaxRetries: 2, MinWait: time.Millisecond})
+
+	label, err := client.REST.Labels.Create(context.Background(), CreateLabelParams{Name: "Retried"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attempts != 3 || label.Name != "Retried" {
+		t.Errorf("expected the label after 3 attempts, received %+v after %d", label, attempts)
+	}
+
+	attempts = -10
+	client.SetRetryPolicy(RetryPolicy{MaxRetries: 1, MinWait: time.Millisecond})
+
+	_, err = client.REST.Labels.Create(context.Background(), CreateLabelParams{Name: "Retried"})
+	var unavailable ServiceUnavailableError
+	if !errors.As(err, &unavailable) {
+		t.Errorf("expected a ServiceUnavailableError once retries are exhausted, received %v", err)
+	}
+}
+
+func Test_Retry_Sync(t *testing.T) {
+	client, srv := newFakeClient(t)
+	client.SetRetryPolicy(RetryPolicy{MaxRetries: 1, MaxWait: time.Millisecond})
+
+	srv.RateLimit(1)
+
+	if _, _, err := client.Projects.List(context.Background(), ""); err != nil {
+		t.Fatalf("expected the rate limited request to be retried, received %v", err)
+	}
+
+	// Endpoint requests have their body replaced after NewRequest, which must
+	// be the body that is resent.
+	inbox := inboxProjectID(t, client)
+	srv.InjectFault(todoisttest.Fault{Path: "projects/get", StatusCode: http.StatusServiceUnavailable, Times: 1})
+
+	info, err := client.Projects.GetProjectInfo(context.Background(), "", inbox, false)
+	if err != nil {
+		t.Fatalf("expected the failed request to be retried, received %v", err)
+	}
+	if info.Project.ID != inbox {
+		t.Errorf("expected the inbox project, received %+v", info.Project)
+	}
+}
+
+func Test_RetryPolicy_Backoff(t *testing.T) {
+	p := RetryPolicy{MinWait: time.Second, MaxWait: 5 * time.Second}
+
+	tests := []struct {
+		name  string
+		retry int
+		resp  *http.Response
+		err   error
+		want  time.Duration
+	}{
+		{name: "first retry", retry: 0, want: time.Second},
+		{name: "exponential", retry: 2, want: 4 * time.Second},
+		{name: "capped", retry: 5, want: 5 * time.Second},
+		{name: "retry-after header", retry: 0, resp: &http.Response{Header: http.Header{"Retry-After": []string{"3"}}}, want: 3 * time.Second},
+		{name: "retry_after extra", retry: 0, err: TooManyRequestsError{BaseError{ErrorExtra: map[string]interface{}{"retry_after": float64(2)}}}, want: 2 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := p.backoff(tt.retry, tt.resp, tt.err); got != tt.want {
+				t.Errorf("expected %s, received %s", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package todoist
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultRetryMinWait = time.Second
+	defaultRetryMaxWait = 30 * time.Second
+)
+
+// RetryPolicy controls how the client retries requests that failed because of
+// a rate limit (429) or a transient server error (500, 502, 503 or 504). It
+// applies to both Sync and REST API requests.
+//
+// Retrying is safe for Sync API commands, which Todoist deduplicates by their
+// UUID, and for REST API requests, which are sent with an X-Request-Id header.
+type RetryPolicy struct {
+	// Number of retries after the first attempt. Zero (the default) disables retrying.
+	MaxRetries int
+
+	// Wait before the first retry, doubled for each following retry. Defaults to one second.
+	MinWait time.Duration
+
+	// Upper bound of the wait between retries, including waits requested by the
+	// API through the Retry-After header. Defaults to 30 seconds.
+	MaxWait time.Duration
+}
+
+// WithRetryPolicy sets the policy used to retry failed requests.
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(c *Client) error {
+		c.SetRetryPolicy(policy)
+		return nil
+	}
+}
+
+// SetRetryPolicy sets the policy used to retry failed requests.
+func (c *Client) SetRetryPolicy(policy RetryPolicy) {
+	c.retry = policy
+}
+
+// shouldRetry reports whether a request that received resp can be retried.
+func shouldRetry(resp *http.Response) bool {
+	if resp == nil {
+		return false
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// backoff returns how long to wait before the given retry (starting at 0). A
+// wait requested by the API, through the Retry-After header or the retry_after
+// field of a rate limit error, takes precedence over exponential backoff.
+func (p RetryPolicy) backoff(retry int, resp *http.Response, err error) time.Duration {
+	minWait, maxWait := p.MinWait, p.MaxWait
+	if minWait <= 0 {
+		minWait = defaultRetryMinWait
+	}
+	if maxWait <= 0 {
+		maxWait = defaultRetryMaxWait
+	}
+
+	wait := retryAfter(resp, err)
+	if wait == 0 {
+		wait = minWait << uint(retry)
+	}
+
+	if wait > maxWait || wait < 0 {
+		wait = maxWait
+	}
+
+	return wait
+}
+
+// retryAfter returns the wait requested by the API, or 0 if there is none.
+func retryAfter(resp *http.Response, err error) time.Duration {
+	if resp != nil {
+		if seconds, convErr := strconv.Atoi(resp.Header.Get("Retry-After")); convErr == nil && seconds > 0 {
+			return time.Duration(seconds) * time.Second
+		}
+	}
+
+	if e, ok := err.(TooManyRequestsError); ok {
+		if seconds, ok := e.ErrorExtra["retry_after"].(float64); ok && seconds > 0 {
+			return time.Duration(seconds) * time.Second
+		}
+	}
+
+	return 0
+}
@@ -2,6 +2,7 @@ package todoist
 
 import (
 	"context"
+	"encoding/json"
 
 	"github.com/google/uuid"
 )
@@ -15,13 +16,13 @@ type SectionsService service
 // Section represents a Todoist section.
 type Section struct {
 	// The ID of the section.
-	ID int `json:"id"`
+	ID SectionID `json:"id"`
 
 	// The name of the section.
 	Name string `json:"name"`
 
 	// Project that the section resides in.
-	ProjectID int `json:"project_id"`
+	ProjectID ProjectID `json:"project_id"`
 
 	// Legacy project ID for the project that the section resides in.
 	// (only shown for objects created before 1 April 2017)
@@ -34,7 +35,7 @@ type Section struct {
 	Collapsed bool `json:"collapsed"`
 
 	// A special ID for shared sections (a number or null if not set). Used internally and can be ignored.
-	SyncID *int `json:"sync_id"`
+	SyncID *ID `json:"sync_id"`
 
 	// Whether the section is marked as deleted (a true or false value).
 	IsDeleted bool `json:"is_deleted"`
@@ -42,13 +43,45 @@ type Section struct {
 	// Whether the section is marked as archived (a true or false value).
 	IsArchived bool `json:"is_archived"`
 
-	// The date when the section was archived (or null if not archived).
+	// The date when the section was archived (or null if not archived). Sent as archived_at by the v9 API.
 	DateArchived *string `json:"date_archived"`
 
-	// The date when the section was created.
+	// The date when the section was created. Sent as added_at by the v9 API.
 	DateAdded string `json:"date_added"`
 }
 
+// UnmarshalJSON implements json.Unmarshaler, accepting the field names of
+// the v8 and v9 Sync APIs and of the REST API.
+func (s *Section) UnmarshalJSON(data []byte) error {
+	type section Section
+
+	var v struct {
+		section
+
+		AddedAt    *string `json:"added_at"`
+		ArchivedAt *string `json:"archived_at"`
+
+		// REST API field names.
+		Order *int `json:"order"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	*s = Section(v.section)
+	if v.AddedAt != nil {
+		s.DateAdded = *v.AddedAt
+	}
+	if v.ArchivedAt != nil {
+		s.DateArchived = v.ArchivedAt
+	}
+	if v.Order != nil {
+		s.SectionOrder = *v.Order
+	}
+
+	return nil
+}
+
 func (s *SectionsService) List(ctx context.Context, syncToken string) ([]Section, ReadResponse, error) {
 	s.client.Logln("---------- Sections.List")
 
@@ -70,8 +103,8 @@ type AddSection struct {
 	// The name of the section.
 	Name string `json:"name"`
 
-	// The ID of the parent project.
-	ProjectID int `json:"project_id"`
+	// The ID of the parent project (could be a temp id).
+	ProjectID ProjectID `json:"project_id"`
 
 	// The order of the section. Defines the position of the section among all the sections in the project.
 	SectionOrder int `json:"section_order,omitempty"`
@@ -113,8 +146,8 @@ func (s *SectionsService) Add(ctx context.Context, syncToken string, addSection
 }
 
 type UpdateSection struct {
-	// The ID of the section.
-	ID string `json:"id"`
+	// The ID of the section (could be a temp id).
+	ID SectionID `json:"id"`
 
 	// The name of the section.
 	Name string `json:"name,omitempty"`
@@ -159,11 +192,11 @@ func (s *SectionsService) Update(ctx context.Context, syncToken string, updateSe
 }
 
 type MoveSection struct {
-	// The ID of the section.
-	ID string `json:"id"`
+	// The ID of the section (could be a temp id).
+	ID SectionID `json:"id"`
 
-	// ID of the destination project.
-	ProjectID string `json:"project_id,omitempty"`
+	// ID of the destination project (could be a temp id).
+	ProjectID ProjectID `json:"project_id"`
 
 	TempID string `json:"-"`
 }
@@ -202,8 +235,8 @@ func (s *SectionsService) Move(ctx context.Context, syncToken string, moveSectio
 }
 
 type ReorderedSection struct {
-	// ID of the section to update.
-	ID string `json:"id"`
+	// ID of the section to update (could be a temp id).
+	ID SectionID `json:"id"`
 
 	// The new order.
 	SectionOrder int `json:"section_order"`
@@ -250,8 +283,8 @@ func (s *SectionsService) Reorder(ctx context.Context, syncToken string, reorder
 }
 
 type DeleteSection struct {
-	// ID of the section to delete.
-	ID string `json:"id"`
+	// ID of the section to delete (could be a temp id).
+	ID SectionID `json:"id"`
 
 	TempID string `json:"-"`
 }
@@ -290,8 +323,8 @@ func (s *SectionsService) Delete(ctx context.Context, syncToken string, deleteSe
 }
 
 type ArchiveSection struct {
-	// Section ID to archive.
-	ID string `json:"id"`
+	// Section ID to archive (could be a temp id).
+	ID SectionID `json:"id"`
 
 	TempID string `json:"-"`
 }
@@ -330,8 +363,8 @@ func (s *SectionsService) Archive(ctx context.Context, syncToken string, archive
 }
 
 type UnarchiveSection struct {
-	// Section ID to unarchive
-	ID string `json:"id"`
+	// Section ID to unarchive (could be a temp id).
+	ID SectionID `json:"id"`
 
 	TempID string `json:"-"`
 }
@@ -0,0 +1,608 @@
+package todoist
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// SnapshotVersion is the version of the snapshot format written by
+// Snapshot.Write. ReadSnapshot reads snapshots up to this version.
+const SnapshotVersion = 1
+
+// snapshotPageSize is the number of archived projects read per request, the
+// maximum allowed by the API.
+const snapshotPageSize = 500
+
+// restoreBatchSize is the number of commands sent per request by Restore, the
+// maximum allowed by the API.
+const restoreBatchSize = 100
+
+// Snapshot is a backup of a whole account: the resources returned by a full
+// sync, plus the archived projects with their sections, uncompleted tasks and
+// comments. Snapshots are written and read as versioned JSON archives, and
+// can be restored into an empty account with Client.Restore.
+type Snapshot struct {
+	// The version of the snapshot format (SnapshotVersion when taken).
+	Version int `json:"version"`
+
+	// When the snapshot was taken.
+	CreatedAt time.Time `json:"created_at"`
+
+	// The Sync API version the snapshot was taken with. In v9 snapshots, task labels are label names rather than IDs.
+	APIVersion APIVersion `json:"api_version"`
+
+	Projects     []Project  `json:"projects"`
+	Sections     []Section  `json:"sections"`
+	Tasks        []Task     `json:"items"`
+	Notes        []Comment  `json:"notes"`
+	ProjectNotes []Comment  `json:"project_notes"`
+	Labels       []Label    `json:"labels"`
+	Filters      []Filter   `json:"filters"`
+	Reminders    []Reminder `json:"reminders"`
+}
+
+// Snapshot takes a snapshot of the account: a full sync of all resources,
+// followed by the data of every archived project. The task comments of
+// archived projects are not part of the snapshot, as the API only returns
+// their project comments.
+func (c *Client) Snapshot(ctx context.Context) (*Snapshot, error) {
+	c.Logln("---------- Snapshot")
+
+	req, err := c.NewRequest("", []string{"all"}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var readResponse ReadResponse
+	if _, err = c.Do(ctx, req, &readResponse); err != nil {
+		return nil, err
+	}
+
+	s := &Snapshot{
+		Version:      SnapshotVersion,
+		CreatedAt:    time.Now().UTC(),
+		APIVersion:   c.version,
+		Projects:     readResponse.Projects,
+		Sections:     readResponse.Sections,
+		Tasks:        readResponse.Tasks,
+		Notes:        readResponse.Notes,
+		ProjectNotes: readResponse.ProjectNotes,
+		Labels:       readResponse.Labels,
+		Filters:      readResponse.Filters,
+		Reminders:    readResponse.Reminders,
+	}
+
+	projects := map[ProjectID]bool{}
+	for _, p := range s.Projects {
+		projects[p.ID] = true
+	}
+	sections := map[SectionID]bool{}
+	for _, section := range s.Sections {
+		sections[section.ID] = true
+	}
+	tasks := map[TaskID]bool{}
+	for _, task := range s.Tasks {
+		tasks[task.ID] = true
+	}
+	notes := map[CommentID]bool{}
+	for _, note := range s.ProjectNotes {
+		notes[note.ID] = true
+	}
+
+	for offset := 0; ; offset += snapshotPageSize {
+		archived, err := c.Projects.GetArchivedProjects(ctx, "", &Pagination{Limit: snapshotPageSize, Offset: offset})
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to list archived projects")
+		}
+
+		for _, p := range archived {
+			if projects[p.ID] {
+				continue
+			}
+			projects[p.ID] = true
+
+			data, err := c.Projects.GetProjectData(ctx, "", p.ID)
+			if err != nil {
+				return nil, errors.Wrapf(err, "unable to get the data of archived project %q", p.Name)
+			}
+
+			s.Projects = append(s.Projects, p)
+			for _, section := range data.Sections {
+				if !sections[section.ID] {
+					sections[section.ID] = true
+					s.Sections = append(s.Sections, section)
+				}
+			}
+			for _, task := range data.Items {
+				if !tasks[task.ID] {
+					tasks[task.ID] = true
+					s.Tasks = append(s.Tasks, task)
+				}
+			}
+			for _, note := range data.Notes {
+				if !notes[note.ID] {
+					notes[note.ID] = true
+					s.ProjectNotes = append(s.ProjectNotes, note)
+				}
+			}
+		}
+
+		if len(archived) < snapshotPageSize {
+			break
+		}
+	}
+
+	return s, nil
+}
+
+// ReadSnapshot reads a snapshot written by Snapshot.Write.
+func ReadSnapshot(r io.Reader) (*Snapshot, error) {
+	var s Snapshot
+	if err := json.NewDecoder(r).Decode(&s); err != nil {
+		return nil, errors.Wrap(err, "unable to read snapshot")
+	}
+
+	if s.Version < 1 || s.Version > SnapshotVersion {
+		return nil, errors.Errorf("unsupported snapshot version %d", s.Version)
+	}
+
+	return &s, nil
+}
+
+// Write writes the snapshot as indented JSON.
+func (s *Snapshot) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return errors.Wrap(enc.Encode(s), "unable to write snapshot")
+}
+
+// RestoreReport maps the IDs of a snapshot's resources to the IDs of the
+// resources restored from them. Resources that were not restored, such as
+// deleted ones, are missing from the maps.
+//
+// The IDs returned by Snapshot.Commands are temp IDs; Client.Restore resolves
+// them to the real IDs of the restored resources.
+type RestoreReport struct {
+	Projects  map[ProjectID]ProjectID
+	Sections  map[SectionID]SectionID
+	Tasks     map[TaskID]TaskID
+	Comments  map[CommentID]CommentID
+	Labels    map[LabelID]LabelID
+	Filters   map[FilterID]FilterID
+	Reminders map[ReminderID]ReminderID
+}
+
+// snapshotLabel holds the arguments of a label_add command.
+type snapshotLabel struct {
+	Name       string `json:"name"`
+	Color      int    `json:"color,omitempty"`
+	ItemOrder  int    `json:"item_order,omitempty"`
+	IsFavorite int    `json:"is_favorite,omitempty"`
+}
+
+// snapshotFilter holds the arguments of a filter_add command.
+type snapshotFilter struct {
+	Name       string `json:"name"`
+	Query      string `json:"query"`
+	Color      int    `json:"color,omitempty"`
+	ItemOrder  int    `json:"item_order,omitempty"`
+	IsFavorite int    `json:"is_favorite,omitempty"`
+}
+
+// snapshotNote holds the arguments of a note_add (with TaskID set) or
+// project_note_add command.
+type snapshotNote struct {
+	TaskID         *TaskID         `json:"item_id,omitempty"`
+	ProjectID      *ProjectID      `json:"project_id,omitempty"`
+	Content        string          `json:"content"`
+	FileAttachment *FileAttachment `json:"file_attachment,omitempty"`
+}
+
+// snapshotReminder holds the arguments of a reminder_add command.
+type snapshotReminder struct {
+	TaskID       TaskID `json:"item_id"`
+	Service      string `json:"service,omitempty"`
+	Type         string `json:"type,omitempty"`
+	Due          *Due   `json:"due,omitempty"`
+	MinuteOffset int    `json:"minute_offset,omitempty"`
+	Name         string `json:"name,omitempty"`
+	LocLat       string `json:"loc_lat,omitempty"`
+	LocLong      string `json:"loc_long,omitempty"`
+	LocTrigger   string `json:"loc_trigger,omitempty"`
+	Radius       int    `json:"radius,omitempty"`
+}
+
+// Commands returns the commands that restore the snapshot into an empty
+// account whose Inbox project is inbox, along with a report mapping the
+// snapshot's IDs to the temp IDs of the commands. The snapshot's Inbox is
+// restored into inbox, and version is the API version the commands are sent
+// with, which decides whether task labels are sent as IDs or names.
+//
+// Parents are added before their children, and tasks are completed and
+// projects and sections archived after everything else has been added.
+// Deleted resources are skipped, and so are the resources belonging to them.
+// Task assignments are not restored, as the collaborators may not exist.
+func (s *Snapshot) Commands(inbox ProjectID, version APIVersion) ([]Command, *RestoreReport, error) {
+	var batch commandBatch
+
+	report := &RestoreReport{
+		Projects:  map[ProjectID]ProjectID{},
+		Sections:  map[SectionID]SectionID{},
+		Tasks:     map[TaskID]TaskID{},
+		Comments:  map[CommentID]CommentID{},
+		Labels:    map[LabelID]LabelID{},
+		Filters:   map[FilterID]FilterID{},
+		Reminders: map[ReminderID]ReminderID{},
+	}
+
+	// Task labels are label IDs in v8 snapshots and label names in v9 ones,
+	// so labels are looked up by both.
+	labelIDs := map[string]LabelID{}
+	labelNames := map[string]string{}
+	for _, l := range s.Labels {
+		if l.IsDeleted {
+			continue
+		}
+		if l.Name == "" {
+			return nil, nil, errors.Errorf("label %s without a name", l.ID)
+		}
+
+		id := LabelID{NewTempID(batch.add("label_add", snapshotLabel{
+			Name:       l.Name,
+			Color:      l.Color.ID(),
+			ItemOrder:  l.Order,
+			IsFavorite: l.IsFavorite.Int(),
+		}))}
+		report.Labels[l.ID] = id
+		for _, key := range []string{l.ID.String(), l.Name} {
+			labelIDs[key] = id
+			labelNames[key] = l.Name
+		}
+	}
+
+	for _, f := range s.Filters {
+		if f.IsDeleted {
+			continue
+		}
+		report.Filters[f.ID] = FilterID{NewTempID(batch.add("filter_add", snapshotFilter{
+			Name:       f.Name,
+			Query:      f.Query,
+			Color:      f.Color.ID(),
+			ItemOrder:  f.ItemOrder,
+			IsFavorite: f.IsFavorite.Int(),
+		}))}
+	}
+
+	projectIDs := make([]string, len(s.Projects))
+	projectParents := make([]string, len(s.Projects))
+	projectOrders := make([]int, len(s.Projects))
+	for i, p := range s.Projects {
+		projectIDs[i] = p.ID.String()
+		if p.ParentID != nil {
+			projectParents[i] = p.ParentID.String()
+		}
+		projectOrders[i] = p.ChildOrder
+	}
+
+	var archivedProjects []ProjectID
+	for _, i := range topologicalOrder(projectIDs, projectParents, projectOrders) {
+		p := s.Projects[i]
+		if p.IsDeleted {
+			continue
+		}
+		if p.InboxProject != nil && *p.InboxProject {
+			report.Projects[p.ID] = inbox
+			continue
+		}
+
+		addProject := AddProject{
+			Name:       p.Name,
+			Color:      p.Color.ID(),
+			ChildOrder: p.ChildOrder,
+			IsFavorite: p.IsFavorite.Int(),
+		}
+		var parentArchived bool
+		if p.ParentID != nil {
+			if parentID, ok := report.Projects[*p.ParentID]; ok {
+				addProject.ParentID = &parentID
+				parentArchived = isArchived(s.Projects, *p.ParentID)
+			}
+		}
+
+		id := ProjectID{NewTempID(batch.add("project_add", addProject))}
+		report.Projects[p.ID] = id
+
+		// Archiving a project archives its sub-projects too.
+		if bool(p.IsArchived) && !parentArchived {
+			archivedProjects = append(archivedProjects, id)
+		}
+	}
+
+	sections := append([]Section(nil), s.Sections...)
+	sort.SliceStable(sections, func(i, j int) bool { return sections[i].SectionOrder < sections[j].SectionOrder })
+
+	var archivedSections []SectionID
+	for _, section := range sections {
+		projectID, ok := report.Projects[section.ProjectID]
+		if section.IsDeleted || !ok {
+			continue
+		}
+
+		id := SectionID{NewTempID(batch.add("section_add", AddSection{
+			Name:         section.Name,
+			ProjectID:    projectID,
+			SectionOrder: section.SectionOrder,
+		}))}
+		report.Sections[section.ID] = id
+
+		if section.IsArchived {
+			archivedSections = append(archivedSections, id)
+		}
+	}
+
+	taskIDs := make([]string, len(s.Tasks))
+	taskParents := make([]string, len(s.Tasks))
+	taskOrders := make([]int, len(s.Tasks))
+	for i, task := range s.Tasks {
+		taskIDs[i] = task.ID.String()
+		if task.ParentID != nil {
+			taskParents[i] = task.ParentID.String()
+		}
+		taskOrders[i] = task.ChildOrder
+	}
+
+	var checkedTasks []TaskID
+	for _, i := range topologicalOrder(taskIDs, taskParents, taskOrders) {
+		task := s.Tasks[i]
+		projectID, ok := report.Projects[task.ProjectID]
+		if bool(task.IsDeleted) || !ok {
+			continue
+		}
+
+		addTask := AddTask{
+			Content:     task.Content,
+			Description: task.Description,
+			ProjectID:   &projectID,
+			Due:         task.Due,
+			Priority:    task.Priority,
+			ChildOrder:  task.ChildOrder,
+			Collapsed:   task.Collapsed.Int(),
+		}
+		if task.SectionID != nil {
+			if sectionID, ok := report.Sections[*task.SectionID]; ok {
+				addTask.SectionID = &sectionID
+			}
+		}
+		if task.ParentID != nil {
+			if parentID, ok := report.Tasks[*task.ParentID]; ok {
+				addTask.ParentID = &parentID
+			}
+		}
+		for _, label := range task.Labels {
+			id, ok := labelIDs[label.String()]
+			switch {
+			case !ok && version == APIVersionV9:
+				addTask.Labels = append(addTask.Labels, label)
+			case !ok:
+				// An unknown label ID cannot be restored.
+			case version == APIVersionV9:
+				addTask.Labels = append(addTask.Labels, LabelID{NewID(labelNames[label.String()])})
+			default:
+				addTask.Labels = append(addTask.Labels, id)
+			}
+		}
+
+		id := TaskID{NewTempID(batch.add("item_add", addTask))}
+		report.Tasks[task.ID] = id
+
+		if task.Checked {
+			checkedTasks = append(checkedTasks, id)
+		}
+	}
+
+	for _, note := range s.Notes {
+		if note.IsDeleted || note.TaskID == nil {
+			continue
+		}
+		taskID, ok := report.Tasks[*note.TaskID]
+		if !ok {
+			continue
+		}
+		report.Comments[note.ID] = CommentID{NewTempID(batch.add("note_add", snapshotNote{
+			TaskID:         &taskID,
+			Content:        note.Content,
+			FileAttachment: note.Attachment,
+		}))}
+	}
+
+	for _, note := range s.ProjectNotes {
+		if note.IsDeleted || note.ProjectID == nil {
+			continue
+		}
+		projectID, ok := report.Projects[*note.ProjectID]
+		if !ok {
+			continue
+		}
+		report.Comments[note.ID] = CommentID{NewTempID(batch.add("project_note_add", snapshotNote{
+			ProjectID:      &projectID,
+			Content:        note.Content,
+			FileAttachment: note.Attachment,
+		}))}
+	}
+
+	for _, r := range s.Reminders {
+		taskID, ok := report.Tasks[r.TaskID]
+		if bool(r.IsDeleted) || !ok {
+			continue
+		}
+		report.Reminders[r.ID] = ReminderID{NewTempID(batch.add("reminder_add", snapshotReminder{
+			TaskID:       taskID,
+			Service:      r.Service,
+			Type:         r.Type,
+			Due:          r.Due,
+			MinuteOffset: r.MinuteOffset,
+			Name:         r.Name,
+			LocLat:       r.LocLat,
+			LocLong:      r.LocLong,
+			LocTrigger:   r.LocTrigger,
+			Radius:       r.Radius,
+		}))}
+	}
+
+	// Sub-tasks are completed before their parents, which would complete
+	// them too.
+	for i := len(checkedTasks) - 1; i >= 0; i-- {
+		batch.add("item_complete", checklistItem{ID: checkedTasks[i]})
+	}
+	for _, id := range archivedSections {
+		batch.add("section_archive", ArchiveSection{ID: id})
+	}
+	for _, id := range archivedProjects {
+		batch.add("project_archive", ArchiveProject{ID: id})
+	}
+
+	return batch.commands, report, nil
+}
+
+// Restore restores a snapshot into the client's account, which must be
+// empty: no projects other than the Inbox, and no tasks, labels or filters.
+// Commands are sent in batches, and the returned report maps the snapshot's
+// IDs to the real IDs of the restored resources. If a batch fails, the
+// report of the resources restored so far is returned with the error.
+func (c *Client) Restore(ctx context.Context, snapshot *Snapshot) (*RestoreReport, error) {
+	c.Logln("---------- Restore")
+
+	req, err := c.NewRequest("", []string{"projects", "items", "labels", "filters"}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var readResponse ReadResponse
+	if _, err = c.Do(ctx, req, &readResponse); err != nil {
+		return nil, err
+	}
+
+	var inbox *ProjectID
+	for _, p := range readResponse.Projects {
+		if p.IsDeleted || p.IsArchived {
+			continue
+		}
+		if p.InboxProject != nil && *p.InboxProject {
+			id := p.ID
+			inbox = &id
+			continue
+		}
+		return nil, errors.Errorf("account is not empty: it has project %q", p.Name)
+	}
+	if inbox == nil {
+		return nil, errors.New("account has no Inbox project")
+	}
+	for _, task := range readResponse.Tasks {
+		if !task.IsDeleted {
+			return nil, errors.Errorf("account is not empty: it has task %q", task.Content)
+		}
+	}
+	for _, l := range readResponse.Labels {
+		if !l.IsDeleted {
+			return nil, errors.Errorf("account is not empty: it has label %q", l.Name)
+		}
+	}
+	for _, f := range readResponse.Filters {
+		if !f.IsDeleted {
+			return nil, errors.Errorf("account is not empty: it has filter %q", f.Name)
+		}
+	}
+
+	commands, report, err := snapshot.Commands(*inbox, c.version)
+	if err != nil {
+		return nil, err
+	}
+
+	for start := 0; start < len(commands); start += restoreBatchSize {
+		end := start + restoreBatchSize
+		if end > len(commands) {
+			end = len(commands)
+		}
+
+		req, err = c.NewRequest("", []string{}, commands[start:end])
+		if err != nil {
+			return c.resolveReport(report), err
+		}
+
+		var commandResponse CommandResponse
+		if _, err = c.Do(ctx, req, &commandResponse); err != nil {
+			return c.resolveReport(report), errors.Wrapf(err, "unable to restore commands %d to %d", start+1, end)
+		}
+	}
+
+	return c.resolveReport(report), nil
+}
+
+// resolveReport returns a copy of report with the temp IDs of the restored
+// resources resolved to their real IDs.
+func (c *Client) resolveReport(report *RestoreReport) *RestoreReport {
+	resolved := c.resolveTempIDs(*report).(RestoreReport)
+	return &resolved
+}
+
+// isArchived reports whether the project with the given ID is archived.
+func isArchived(projects []Project, id ProjectID) bool {
+	for _, p := range projects {
+		if p.ID == id {
+			return bool(p.IsArchived)
+		}
+	}
+
+	return false
+}
+
+// topologicalOrder returns the indexes of resources with the given IDs and
+// parent IDs ("" for none), ordered so that parents come before their
+// children, and siblings by their order. Resources whose parent is unknown are
+// treated as roots, as are resources in a parent cycle.
+func topologicalOrder(ids, parents []string, orders []int) []int {
+	known := map[string]bool{}
+	for _, id := range ids {
+		known[id] = true
+	}
+
+	children := map[string][]int{}
+	var roots []int
+	for i, parent := range parents {
+		if parent == "" || parent == ids[i] || !known[parent] {
+			roots = append(roots, i)
+		} else {
+			children[parent] = append(children[parent], i)
+		}
+	}
+
+	out := make([]int, 0, len(ids))
+	visited := make([]bool, len(ids))
+	var visit func(level []int)
+	visit = func(level []int) {
+		sort.SliceStable(level, func(a, b int) bool { return orders[level[a]] < orders[level[b]] })
+		for _, i := range level {
+			if visited[i] {
+				continue
+			}
+			visited[i] = true
+			out = append(out, i)
+			visit(children[ids[i]])
+		}
+	}
+	visit(roots)
+
+	for i := range ids {
+		if !visited[i] {
+			visit([]int{i})
+		}
+	}
+
+	return out
+}
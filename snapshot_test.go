@@ -0,0 +1,314 @@
+package todoist
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func Test_Snapshot_Restore(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newFakeClient(t)
+	inbox := inboxProjectID(t, client)
+
+	// Populate the account with every kind of resource.
+	var commands []Command
+	add := func(commandType, tempID string, args map[string]interface{}) {
+		commands = append(commands, Command{Type: commandType, Args: args, UUID: tempID + "-uuid", TempID: tempID})
+	}
+	temp := func(tempID string) ID { return NewTempID(tempID) }
+
+	add("label_add", "bills", map[string]interface{}{"name": "bills", "color": 31})
+	add("filter_add", "urgent", map[string]interface{}{"name": "Urgent", "query": "p1 & today", "is_favorite": 1})
+	add("project_add", "home", map[string]interface{}{"name": "Home", "child_order": 1})
+	add("project_add", "garden", map[string]interface{}{"name": "Garden", "parent_id": temp("home")})
+	add("project_add", "old", map[string]interface{}{"name": "Old", "child_order": 2})
+	add("project_add", "older", map[string]interface{}{"name": "Older", "parent_id": temp("old")})
+	add("section_add", "front", map[string]interface{}{"name": "Front", "project_id": temp("garden")})
+	add("section_add", "done", map[string]interface{}{"name": "Done", "project_id": temp("home")})
+	add("item_add", "call", map[string]interface{}{"content": "Call mum", "project_id": inbox})
+	add("item_add", "rent", map[string]interface{}{"content": "Pay rent", "project_id": temp("home"), "priority": 4, "labels": []ID{temp("bills")}, "due": &Due{Date: "2022-04-01", String: "every 1st", IsRecurring: true}})
+	add("item_add", "iban", map[string]interface{}{"content": "Find the IBAN", "project_id": temp("home"), "parent_id": temp("rent")})
+	add("item_add", "mow", map[string]interface{}{"content": "Mow the lawn", "project_id": temp("garden"), "section_id": temp("front")})
+	add("item_add", "box", map[string]interface{}{"content": "Sort the box", "project_id": temp("older")})
+	add("note_add", "note", map[string]interface{}{"item_id": temp("rent"), "content": "Bank transfer"})
+	add("project_note_add", "pnote", map[string]interface{}{"project_id": temp("old"), "content": "Kept for the records"})
+	add("reminder_add", "remind", map[string]interface{}{"item_id": temp("rent"), "minute_offset": 30})
+	add("section_archive", "", map[string]interface{}{"id": temp("done")})
+	add("project_archive", "", map[string]interface{}{"id": temp("old")})
+
+	req, err := client.NewRequest("", nil, commands)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.Do(ctx, req, &CommandResponse{}); err != nil {
+		t.Fatal(err)
+	}
+
+	snapshot, err := client.Snapshot(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snapshot.Version != SnapshotVersion || snapshot.APIVersion != APIVersionV8 || snapshot.CreatedAt.IsZero() {
+		t.Errorf("unexpected snapshot header %d %s %s", snapshot.Version, snapshot.APIVersion, snapshot.CreatedAt)
+	}
+	if len(snapshot.Projects) != 5 || len(snapshot.Tasks) != 5 || len(snapshot.Notes) != 1 || len(snapshot.ProjectNotes) != 1 ||
+		len(snapshot.Labels) != 1 || len(snapshot.Filters) != 1 || len(snapshot.Reminders) != 1 {
+		t.Fatalf("expected the archived projects to be part of the snapshot, received %d projects, %d tasks, %d notes, %d project notes, %d labels, %d filters and %d reminders",
+			len(snapshot.Projects), len(snapshot.Tasks), len(snapshot.Notes), len(snapshot.ProjectNotes), len(snapshot.Labels), len(snapshot.Filters), len(snapshot.Reminders))
+	}
+
+	var buf bytes.Buffer
+	if err = snapshot.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	read, err := ReadSnapshot(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(read, snapshot) {
+		t.Errorf("expected the written snapshot to read back the same, received %+v", read)
+	}
+
+	// Restore into another account.
+	target, _ := newFakeClient(t)
+	report, err := target.Restore(ctx, read)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	restored, err := target.Snapshot(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := describeSnapshot(restored), describeSnapshot(snapshot); got != want {
+		t.Errorf("expected the restored account\n%s\nreceived\n%s", want, got)
+	}
+
+	for old, id := range report.Projects {
+		if id.IsTemp() {
+			t.Errorf("project %s: expected a real ID, received temp ID %s", old, id)
+		}
+	}
+	for _, task := range snapshot.Tasks {
+		id, ok := report.Tasks[task.ID]
+		if !ok || id.IsTemp() {
+			t.Errorf("task %q: expected a real ID in the report, received %v", task.Content, id)
+		}
+	}
+	if got := report.Projects[inbox]; got != inboxProjectID(t, target) {
+		t.Errorf("expected the Inbox to be restored into the target Inbox, received %s", got)
+	}
+	if len(report.Labels) != 1 || len(report.Filters) != 1 || len(report.Comments) != 2 || len(report.Reminders) != 1 {
+		t.Errorf("unexpected report %+v", report)
+	}
+
+	// The target account is no longer empty.
+	if _, err = target.Restore(ctx, read); err == nil || !strings.Contains(err.Error(), "not empty") {
+		t.Errorf("expected an error restoring into an account that is not empty, received %v", err)
+	}
+}
+
+func Test_Restore_PartialFailure(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newFakeClient(t)
+
+	// The second batch fails on a task the API rejects.
+	s := &Snapshot{Version: SnapshotVersion}
+	for i := 1; i <= restoreBatchSize+10; i++ {
+		s.Projects = append(s.Projects, Project{ID: ProjectID{NewIntID(i)}, Name: "Project", ChildOrder: i})
+	}
+	s.Tasks = []Task{{ID: TaskID{NewIntID(1000)}, ProjectID: ProjectID{NewIntID(1)}}}
+
+	report, err := client.Restore(ctx, s)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if report == nil {
+		t.Fatal("expected a partial report")
+	}
+
+	var restored int
+	for _, id := range report.Projects {
+		if !id.IsTemp() {
+			restored++
+		}
+	}
+	if restored != len(s.Projects) {
+		t.Errorf("expected %d restored projects in the report, received %d", len(s.Projects), restored)
+	}
+	if id := report.Tasks[TaskID{NewIntID(1000)}]; !id.IsTemp() {
+		t.Errorf("expected the failed task to keep its temp ID, received %s", id)
+	}
+}
+
+func Test_Snapshot_Commands(t *testing.T) {
+	id := func(s string) ID { return NewID(s) }
+	inbox := true
+	parent, child := TaskID{id("11")}, TaskID{id("12")}
+	home, cycleA, cycleB := ProjectID{id("2")}, ProjectID{id("5")}, ProjectID{id("6")}
+
+	s := &Snapshot{
+		Version: SnapshotVersion,
+		Projects: []Project{
+			{ID: cycleA, Name: "A", ParentID: &cycleB},
+			{ID: ProjectID{id("3")}, Name: "Deleted", IsDeleted: true},
+			{ID: home, Name: "Home", Color: "red", IsFavorite: true},
+			{ID: ProjectID{id("1")}, Name: "Inbox", InboxProject: &inbox},
+			{ID: cycleB, Name: "B", ParentID: &cycleA},
+		},
+		Sections: []Section{{ID: SectionID{id("20")}, ProjectID: home, Name: "Archived", IsArchived: true}},
+		Tasks: []Task{
+			{ID: child, ProjectID: home, ParentID: &parent, Content: "Child", Checked: true, Labels: []LabelID{{id("7")}, {id("8")}}},
+			{ID: parent, ProjectID: home, Content: "Parent", Checked: true},
+			{ID: TaskID{id("13")}, ProjectID: ProjectID{id("3")}, Content: "In a deleted project"},
+		},
+		Labels: []Label{{ID: LabelID{id("7")}, Name: "bills"}},
+	}
+
+	commands, report, err := s.Commands(ProjectID{id("100")}, APIVersionV8)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var types []string
+	for _, cmd := range commands {
+		types = append(types, cmd.Type)
+	}
+	wantTypes := []string{"label_add", "project_add", "project_add", "project_add", "section_add", "item_add", "item_add", "item_complete", "item_complete", "section_archive"}
+	if !reflect.DeepEqual(types, wantTypes) {
+		t.Fatalf("expected commands %v, received %v", wantTypes, types)
+	}
+
+	args := func(i int) map[string]interface{} {
+		b, err := json.Marshal(commands[i].Args)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var m map[string]interface{}
+		_ = json.Unmarshal(b, &m)
+		return m
+	}
+
+	if got := args(1); got["color"] != float64(Color("red").ID()) || got["is_favorite"] != float64(1) {
+		t.Errorf("expected the color and favorite flag, received %v", got)
+	}
+	// Projects in a parent cycle are restored after the others, with one of
+	// them as a root.
+	if a, b := args(2), args(3); a["parent_id"] != nil || b["parent_id"] != commands[2].TempID {
+		t.Errorf("expected the cycle to be broken, received %v and %v", a, b)
+	}
+	if got := report.Projects[ProjectID{id("1")}]; got != (ProjectID{id("100")}) {
+		t.Errorf("expected the Inbox to map to the target Inbox, received %s", got)
+	}
+	if _, ok := report.Projects[ProjectID{id("3")}]; ok {
+		t.Error("expected the deleted project to be skipped")
+	}
+
+	// Parents are added first and completed last; unknown labels are dropped.
+	if got := args(5); got["content"] != "Parent" {
+		t.Errorf("expected the parent first, received %v", got)
+	}
+	if got := args(6); got["parent_id"] != commands[5].TempID || !reflect.DeepEqual(got["labels"], []interface{}{commands[0].TempID}) {
+		t.Errorf("unexpected child args %v", got)
+	}
+	if got := args(7); got["id"] != commands[6].TempID {
+		t.Errorf("expected the child to be completed first, received %v", got)
+	}
+
+	// In v9, labels are sent by name.
+	commands, _, err = s.Commands(ProjectID{id("100")}, APIVersionV9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := args(6); !reflect.DeepEqual(got["labels"], []interface{}{"bills", "8"}) {
+		t.Errorf("expected label names, received %v", got["labels"])
+	}
+}
+
+func Test_ReadSnapshot_Version(t *testing.T) {
+	for _, in := range []string{`{}`, `{"version": 2}`, `not json`} {
+		if _, err := ReadSnapshot(strings.NewReader(in)); err == nil {
+			t.Errorf("%s: expected an error", in)
+		}
+	}
+}
+
+// describeSnapshot describes the structure of a snapshot independently of
+// its IDs, for comparing restored accounts.
+func describeSnapshot(s *Snapshot) string {
+	projects := map[ProjectID]string{}
+	for _, p := range s.Projects {
+		projects[p.ID] = p.Name
+	}
+	sections := map[SectionID]string{}
+	for _, section := range s.Sections {
+		sections[section.ID] = section.Name
+	}
+	tasks := map[TaskID]string{}
+	for _, task := range s.Tasks {
+		tasks[task.ID] = task.Content
+	}
+	labels := map[string]string{}
+	for _, l := range s.Labels {
+		labels[l.ID.String()] = l.Name
+	}
+
+	var lines []string
+	for _, p := range s.Projects {
+		var parent string
+		if p.ParentID != nil {
+			parent = projects[*p.ParentID]
+		}
+		lines = append(lines, "project "+p.Name+" parent="+parent+" archived="+boolString(bool(p.IsArchived)))
+	}
+	for _, section := range s.Sections {
+		lines = append(lines, "section "+section.Name+" project="+projects[section.ProjectID]+" archived="+boolString(section.IsArchived))
+	}
+	for _, task := range s.Tasks {
+		line := "task " + task.Content + " project=" + projects[task.ProjectID]
+		if task.SectionID != nil {
+			line += " section=" + sections[*task.SectionID]
+		}
+		if task.ParentID != nil {
+			line += " parent=" + tasks[*task.ParentID]
+		}
+		for _, l := range task.Labels {
+			line += " @" + labels[l.String()]
+		}
+		if task.Due != nil {
+			line += " due=" + task.Due.String
+		}
+		lines = append(lines, line)
+	}
+	for _, note := range s.Notes {
+		lines = append(lines, "note "+note.Content+" task="+tasks[*note.TaskID])
+	}
+	for _, note := range s.ProjectNotes {
+		lines = append(lines, "project note "+note.Content+" project="+projects[*note.ProjectID])
+	}
+	for _, l := range s.Labels {
+		lines = append(lines, "label "+l.Name+" color="+string(l.Color))
+	}
+	for _, f := range s.Filters {
+		lines = append(lines, "filter "+f.Name+" query="+f.Query+" favorite="+boolString(bool(f.IsFavorite)))
+	}
+	for _, r := range s.Reminders {
+		b, _ := json.Marshal(r.MinuteOffset)
+		lines = append(lines, "reminder task="+tasks[r.TaskID]+" type="+r.Type+" offset="+string(b))
+	}
+	sort.Strings(lines)
+
+	return strings.Join(lines, "\n")
+}
+
+func boolString(b bool) string {
+	if b {
+		return "true"
+	}
+	return "false"
+}
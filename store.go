@@ -0,0 +1,407 @@
+package todoist
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// StoreVersion is the version of the format written by Store.Save. LoadStore
+// reads stores up to this version.
+const StoreVersion = 1
+
+// storeResourceTypes are the resource types kept by a Store.
+var storeResourceTypes = []string{"projects", "sections", "items"}
+
+// Store is a local copy of the active projects, sections and tasks of an
+// account, kept up to date with incremental syncs: the first Sync is a full
+// sync, and every later one only fetches what changed since the previous one.
+// Deleted and archived projects and sections, and deleted and completed tasks,
+// are removed from the store.
+//
+// Commands can be applied to a store optimistically with ApplyLocal, before
+// Todoist confirms them, and reconciled with their response later. Fields
+// changed both by pending commands and by a later sync are conflicts, which
+// are resolved as set with SetConflictResolver and reported by Conflicts.
+//
+// A Store is safe for concurrent use. It can be saved and loaded, so that
+// syncs stay incremental across runs of a program.
+type Store struct {
+	mu sync.RWMutex
+
+	syncToken string
+
+	// The resources as last synced, without the pending commands.
+	data storeData
+
+	// The commands applied with ApplyLocal that have not been reconciled
+	// yet, in order, and the synced resources with them applied. local is
+	// nil when no command is pending.
+	pending []Command
+	local   *storeData
+
+	// The real IDs of the temp IDs of reconciled commands.
+	tempIDs tempIDMap
+
+	// The resolver of conflicts between pending commands and syncs, and the
+	// conflicts resolved when the last sync was applied.
+	resolver  ConflictResolver
+	conflicts []Conflict
+}
+
+// storeData holds the resources of a store.
+type storeData struct {
+	projects map[ProjectID]Project
+	sections map[SectionID]Section
+	tasks    map[TaskID]Task
+}
+
+func newStoreData() storeData {
+	return storeData{
+		projects: map[ProjectID]Project{},
+		sections: map[SectionID]Section{},
+		tasks:    map[TaskID]Task{},
+	}
+}
+
+func (d storeData) clone() storeData {
+	c := newStoreData()
+	for id, p := range d.projects {
+		c.projects[id] = p
+	}
+	for id, section := range d.sections {
+		c.sections[id] = section
+	}
+	for id, task := range d.tasks {
+		c.tasks[id] = task
+	}
+
+	return c
+}
+
+// NewStore returns an empty store, whose first Sync is a full sync.
+func NewStore() *Store {
+	return &Store{
+		data: newStoreData(),
+	}
+}
+
+// current returns the resources of the store as seen by its readers: the
+// synced ones, with the pending commands applied.
+func (s *Store) current() *storeData {
+	if s.local != nil {
+		return s.local
+	}
+
+	return &s.data
+}
+
+// SyncToken returns the sync token of the last sync applied to the store, or
+// an empty string if there was none.
+func (s *Store) SyncToken() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.syncToken
+}
+
+// Sync fetches the projects, sections and tasks changed since the last sync
+// and applies them to the store. The response is returned so that callers can
+// see what changed.
+func (s *Store) Sync(ctx context.Context, client *Client) (ReadResponse, error) {
+	client.Logln("---------- Store.Sync")
+
+	readResponse, err := s.fetch(ctx, client)
+	if err != nil {
+		return ReadResponse{}, err
+	}
+
+	s.Apply(readResponse)
+
+	return readResponse, nil
+}
+
+// SyncChanges is Sync, also returning the changes the sync made to the store
+// as the events Client.Watch reports, with the new sync token. Projects and
+// sections removed from the store by being archived are reported as deleted.
+func (s *Store) SyncChanges(ctx context.Context, client *Client) (ReadResponse, []WatchEvent, error) {
+	client.Logln("---------- Store.SyncChanges")
+
+	readResponse, err := s.fetch(ctx, client)
+	if err != nil {
+		return ReadResponse{}, nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d := s.differ()
+	s.apply(readResponse)
+	changes := d.diff(readResponse.Projects, readResponse.Sections, readResponse.Tasks, readResponse.FullSync)
+	for i := range changes {
+		changes[i].SyncToken = readResponse.SyncToken
+	}
+
+	return readResponse, changes, nil
+}
+
+// fetch syncs the store's resource types from its sync token.
+func (s *Store) fetch(ctx context.Context, client *Client) (ReadResponse, error) {
+	req, err := client.NewRequest(s.SyncToken(), storeResourceTypes, nil)
+	if err != nil {
+		return ReadResponse{}, err
+	}
+
+	var readResponse ReadResponse
+	if _, err = client.Do(ctx, req, &readResponse); err != nil {
+		return ReadResponse{}, err
+	}
+
+	return readResponse, nil
+}
+
+// differ returns a differ that has seen the current contents of the store, so
+// that resources removed from it are reported, and resources it never held
+// are not. The caller must hold s.mu.
+func (s *Store) differ() *differ {
+	d := newDiffer()
+	d.removeArchived = true
+	d.ignoreUnknownRemovals = true
+
+	current := s.current()
+	d.seed(current.projectsList(), current.sectionsList(), current.tasksList())
+
+	return d
+}
+
+// Apply applies the response of a sync of the store's resource types. A full
+// sync replaces the contents of the store. The store's sync token is replaced
+// by the response's.
+//
+// Conflicts between the response and pending commands are resolved first,
+// changing the pending commands to set the resolved values.
+func (s *Store) Apply(readResponse ReadResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.apply(readResponse)
+}
+
+func (s *Store) apply(readResponse ReadResponse) {
+	s.conflicts = s.resolveConflicts(s.data, s.local, readResponse.Projects, readResponse.Sections, readResponse.Tasks)
+
+	if readResponse.FullSync {
+		s.data = newStoreData()
+	}
+
+	s.data.apply(readResponse.Projects, readResponse.Sections, readResponse.Tasks)
+
+	if readResponse.SyncToken != "" {
+		s.syncToken = readResponse.SyncToken
+	}
+
+	s.replay()
+}
+
+// ApplyCommandResponse applies the resources returned with the response of a
+// command request, such as the ones of TasksService.Update.
+//
+// Unlike Apply, it keeps the store's sync token and never clears the store:
+// command requests only return the resource types of their service, so their
+// sync token does not account for changes to the other ones. Those are
+// fetched by the next Sync, which also returns the resources applied here
+// again.
+func (s *Store) ApplyCommandResponse(commandResponse CommandResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data.apply(commandResponse.Projects, commandResponse.Sections, commandResponse.Tasks)
+	s.replay()
+}
+
+// ApplyCommandResponseChanges is ApplyCommandResponse, also returning the
+// changes it made to the store, as SyncChanges does. The events have no sync
+// token.
+func (s *Store) ApplyCommandResponseChanges(commandResponse CommandResponse) []WatchEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d := s.differ()
+	s.data.apply(commandResponse.Projects, commandResponse.Sections, commandResponse.Tasks)
+	s.replay()
+
+	return d.diff(commandResponse.Projects, commandResponse.Sections, commandResponse.Tasks, false)
+}
+
+func (d storeData) apply(projects []Project, sections []Section, tasks []Task) {
+	for _, p := range projects {
+		if bool(p.IsDeleted) || bool(p.IsArchived) {
+			delete(d.projects, p.ID)
+			continue
+		}
+		d.projects[p.ID] = p
+	}
+
+	for _, section := range sections {
+		if section.IsDeleted || section.IsArchived {
+			delete(d.sections, section.ID)
+			continue
+		}
+		d.sections[section.ID] = section
+	}
+
+	for _, task := range tasks {
+		if bool(task.IsDeleted) || bool(task.Checked) {
+			delete(d.tasks, task.ID)
+			continue
+		}
+		d.tasks[task.ID] = task
+	}
+}
+
+// Project returns a project of the store. The temp ID of a reconciled
+// command adding a project finds the project by its real ID.
+func (s *Store) Project(id ProjectID) (Project, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	p, ok := s.current().projects[ProjectID{s.resolveID(id.ID)}]
+
+	return p, ok
+}
+
+// Section returns a section of the store, like Project.
+func (s *Store) Section(id SectionID) (Section, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	section, ok := s.current().sections[SectionID{s.resolveID(id.ID)}]
+
+	return section, ok
+}
+
+// Task returns a task of the store, like Project.
+func (s *Store) Task(id TaskID) (Task, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	task, ok := s.current().tasks[TaskID{s.resolveID(id.ID)}]
+
+	return task, ok
+}
+
+// Projects returns the projects of the store, by child order.
+func (s *Store) Projects() []Project {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.current().projectsList()
+}
+
+func (d storeData) projectsList() []Project {
+	projects := make([]Project, 0, len(d.projects))
+	for _, p := range d.projects {
+		projects = append(projects, p)
+	}
+	sort.Slice(projects, func(i, j int) bool {
+		if projects[i].ChildOrder != projects[j].ChildOrder {
+			return projects[i].ChildOrder < projects[j].ChildOrder
+		}
+		return projects[i].ID.String() < projects[j].ID.String()
+	})
+
+	return projects
+}
+
+// Sections returns the sections of the store, by section order.
+func (s *Store) Sections() []Section {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.current().sectionsList()
+}
+
+func (d storeData) sectionsList() []Section {
+	sections := make([]Section, 0, len(d.sections))
+	for _, section := range d.sections {
+		sections = append(sections, section)
+	}
+	sort.Slice(sections, func(i, j int) bool {
+		if sections[i].SectionOrder != sections[j].SectionOrder {
+			return sections[i].SectionOrder < sections[j].SectionOrder
+		}
+		return sections[i].ID.String() < sections[j].ID.String()
+	})
+
+	return sections
+}
+
+// Tasks returns the tasks of the store, by child order.
+func (s *Store) Tasks() []Task {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.current().tasksList()
+}
+
+func (d storeData) tasksList() []Task {
+	tasks := make([]Task, 0, len(d.tasks))
+	for _, task := range d.tasks {
+		tasks = append(tasks, task)
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		if tasks[i].ChildOrder != tasks[j].ChildOrder {
+			return tasks[i].ChildOrder < tasks[j].ChildOrder
+		}
+		return tasks[i].ID.String() < tasks[j].ID.String()
+	})
+
+	return tasks
+}
+
+// storeFile is the format of a saved store.
+type storeFile struct {
+	Version   int       `json:"version"`
+	SyncToken string    `json:"sync_token"`
+	Projects  []Project `json:"projects"`
+	Sections  []Section `json:"sections"`
+	Tasks     []Task    `json:"items"`
+}
+
+// Save writes the store as JSON, with its sync token. Pending commands are
+// not saved: the saved store holds the resources as last synced.
+func (s *Store) Save(w io.Writer) error {
+	s.mu.RLock()
+	f := storeFile{
+		Version:   StoreVersion,
+		SyncToken: s.syncToken,
+		Projects:  s.data.projectsList(),
+		Sections:  s.data.sectionsList(),
+		Tasks:     s.data.tasksList(),
+	}
+	s.mu.RUnlock()
+
+	return errors.Wrap(json.NewEncoder(w).Encode(f), "unable to save store")
+}
+
+// LoadStore reads a store written by Store.Save. Its next Sync is incremental.
+func LoadStore(r io.Reader) (*Store, error) {
+	var f storeFile
+	if err := json.NewDecoder(r).Decode(&f); err != nil {
+		return nil, errors.Wrap(err, "unable to load store")
+	}
+
+	if f.Version < 1 || f.Version > StoreVersion {
+		return nil, errors.Errorf("unsupported store version %d", f.Version)
+	}
+
+	s := NewStore()
+	s.syncToken = f.SyncToken
+	s.data.apply(f.Projects, f.Sections, f.Tasks)
+
+	return s, nil
+}
@@ -0,0 +1,228 @@
+package todoist
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ides15/todoist/todoisttest"
+)
+
+func Test_Store(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newFakeClient(t)
+	inbox := inboxProjectID(t, client)
+
+	store := NewStore()
+	readResponse, err := store.Sync(ctx, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !readResponse.FullSync || store.SyncToken() == "" {
+		t.Fatalf("expected a full sync, received %+v", readResponse)
+	}
+	if projects := store.Projects(); len(projects) != 1 || projects[0].ID != inbox {
+		t.Fatalf("expected the inbox, received %+v", projects)
+	}
+
+	_, _, err = client.Sections.Add(ctx, "", AddSection{Name: "Errands", ProjectID: inbox, TempID: "errands"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = client.Tasks.Add(ctx, "", AddTask{Content: "Buy milk", ProjectID: &inbox, TempID: "milk"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = client.Tasks.Add(ctx, "", AddTask{Content: "Buy bread", ProjectID: &inbox, TempID: "bread"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	milk := TaskID{client.ResolveID(NewTempID("milk"))}
+	bread := TaskID{client.ResolveID(NewTempID("bread"))}
+
+	readResponse, changes, err := store.SyncChanges(ctx, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if readResponse.FullSync || len(readResponse.Tasks) != 2 {
+		t.Fatalf("expected an incremental sync of 2 tasks, received %+v", readResponse)
+	}
+	if len(changes) != 3 || changes[0].Section == nil || changes[0].Type != WatchCreated || changes[2].SyncToken != readResponse.SyncToken {
+		t.Fatalf("expected a created section and 2 tasks, received %+v", changes)
+	}
+	if len(store.Sections()) != 1 || len(store.Tasks()) != 2 {
+		t.Fatalf("unexpected sections %+v and tasks %+v", store.Sections(), store.Tasks())
+	}
+
+	// Command responses update the store without changing its sync token.
+	syncToken := store.SyncToken()
+	_, resp, err := client.Tasks.Complete(ctx, syncToken, CompleteTask{ID: milk})
+	if err != nil {
+		t.Fatal(err)
+	}
+	changes = store.ApplyCommandResponseChanges(resp)
+	if len(changes) != 1 || changes[0].Type != WatchCompleted || changes[0].Task.ID != milk {
+		t.Errorf("expected a completed task, received %+v", changes)
+	}
+	if _, ok := store.Task(milk); ok || store.SyncToken() != syncToken {
+		t.Errorf("expected the completed task to be removed, with sync token %s", syncToken)
+	}
+
+	var buf bytes.Buffer
+	if err = store.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadStore(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.SyncToken() != syncToken || len(loaded.Tasks()) != 1 {
+		t.Fatalf("unexpected loaded store with token %s and tasks %+v", loaded.SyncToken(), loaded.Tasks())
+	}
+
+	_, _, err = client.Tasks.Update(ctx, "", UpdateTask{ID: bread, Content: "Buy rye bread"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = loaded.Sync(ctx, client); err != nil {
+		t.Fatal(err)
+	}
+	if task, ok := loaded.Task(bread); !ok || task.Content != "Buy rye bread" {
+		t.Errorf("expected the updated task, received %+v", task)
+	}
+
+	if _, err = LoadStore(strings.NewReader(`{"version": 2}`)); err == nil {
+		t.Error("expected an error loading an unsupported version")
+	}
+}
+
+func Test_Store_ApplyLocal(t *testing.T) {
+	ctx := context.Background()
+	client, srv := newFakeClient(t)
+	inbox := inboxProjectID(t, client)
+
+	store := NewStore()
+	if _, err := store.Sync(ctx, client); err != nil {
+		t.Fatal(err)
+	}
+
+	// Added tasks show right away, with their temp IDs.
+	milk := TaskID{NewTempID("milk")}
+	commands, err := store.ApplyLocal(
+		Command{Type: "item_add", Args: AddTask{Content: "Buy milk"}, TempID: "milk"},
+		Command{Type: "item_add", Args: AddTask{Content: "Check the date", ParentID: &milk}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task, ok := store.Task(milk); !ok || task.ProjectID != inbox {
+		t.Fatalf("expected the added task in the inbox, received %+v", task)
+	}
+	if len(store.Tasks()) != 2 || len(store.Pending()) != 2 {
+		t.Fatalf("expected 2 pending tasks, received %+v", store.Tasks())
+	}
+
+	syncErrs, err := store.Push(ctx, client, commands)
+	if err != nil || len(syncErrs) != 0 {
+		t.Fatalf("unexpected errors %v, %v", syncErrs, err)
+	}
+	task, ok := store.Task(milk)
+	if !ok || task.ID.IsTemp() || len(store.Pending()) != 0 {
+		t.Fatalf("expected the task to have its real ID, received %+v", task)
+	}
+	milk = task.ID
+	for _, task := range store.Tasks() {
+		if task.ID.IsTemp() || (task.ParentID != nil && *task.ParentID != milk) {
+			t.Errorf("unexpected reconciled task %+v", task)
+		}
+	}
+
+	// Failed commands are rolled back.
+	srv.InjectFault(todoisttest.Fault{Command: "item_update"})
+	commands, err = store.ApplyLocal(
+		Command{Type: "item_update", Args: UpdateTask{ID: milk, Content: "Buy oat milk"}},
+		Command{Type: "item_add", Args: AddTask{Content: "Buy bread", ProjectID: &inbox}, TempID: "bread"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task, _ := store.Task(milk); task.Content != "Buy oat milk" {
+		t.Fatalf("expected the updated task, received %+v", task)
+	}
+	syncErrs, err = store.Push(ctx, client, commands)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(syncErrs) != 1 || syncErrs[0].ID != commands[0].UUID {
+		t.Fatalf("expected the update to fail, received %v", syncErrs)
+	}
+	if task, _ := store.Task(milk); task.Content != "Buy milk" {
+		t.Errorf("expected the update to be rolled back, received %+v", task)
+	}
+	if _, ok := store.Task(TaskID{NewTempID("bread")}); !ok {
+		t.Error("expected the added task to be kept")
+	}
+	srv.ClearFaults()
+
+	// So are the commands of requests that are rejected.
+	srv.InjectFault(todoisttest.Fault{Path: "sync", StatusCode: http.StatusBadRequest})
+	commands, err = store.ApplyLocal(Command{Type: "item_delete", Args: DeleteTask{ID: milk}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = store.Push(ctx, client, commands); err == nil {
+		t.Fatal("expected an error pushing commands")
+	}
+	if _, ok := store.Task(milk); !ok || len(store.Pending()) != 0 {
+		t.Error("expected the deletion to be rolled back")
+	}
+	srv.ClearFaults()
+
+	// Commands of requests that may have been applied stay pending, to be
+	// pushed again.
+	srv.InjectFault(todoisttest.Fault{Path: "sync", StatusCode: http.StatusServiceUnavailable})
+	commands, err = store.ApplyLocal(Command{Type: "item_update", Args: UpdateTask{ID: milk, Content: "Buy whole milk"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = store.Push(ctx, client, commands); err == nil {
+		t.Fatal("expected an error pushing commands")
+	}
+	if task, _ := store.Task(milk); task.Content != "Buy whole milk" || len(store.Pending()) != 1 {
+		t.Errorf("expected the update to stay pending, received %+v", task)
+	}
+	srv.ClearFaults()
+	if _, err = store.Push(ctx, client, store.Pending()); err != nil {
+		t.Fatal(err)
+	}
+	if task, _ := store.Task(milk); task.Content != "Buy whole milk" || len(store.Pending()) != 0 {
+		t.Errorf("expected the update to be reconciled, received %+v", task)
+	}
+
+	// Pending commands stay applied across syncs.
+	if _, err = store.ApplyLocal(Command{Type: "item_complete", Args: CompleteTask{ID: milk}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err = client.Tasks.Add(ctx, "", AddTask{Content: "Buy eggs"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = store.Sync(ctx, client); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := store.Task(milk); ok || len(store.Tasks()) != 2 {
+		t.Errorf("expected the completion to stay applied, received %+v", store.Tasks())
+	}
+	store.Rollback(store.Pending()...)
+	if _, ok := store.Task(milk); !ok || len(store.Tasks()) != 4 {
+		t.Errorf("expected the completion to be rolled back, received %+v", store.Tasks())
+	}
+
+	if _, err = store.ApplyLocal(Command{Type: "item_update", Args: UpdateTask{ID: TaskID{NewID("999999")}}}); err == nil {
+		t.Error("expected an error updating an unknown task")
+	}
+	if _, err = store.ApplyLocal(Command{Type: "label_add", Args: map[string]string{"name": "errands"}}); err == nil {
+		t.Error("expected an error applying an unsupported command")
+	}
+}
@@ -2,6 +2,8 @@ package todoist
 
 import (
 	"context"
+	"encoding/json"
+	"net/url"
 
 	"github.com/google/uuid"
 )
@@ -14,17 +16,17 @@ type TasksService service
 
 type Task struct {
 	// The ID of the task.
-	ID int `json:"id"`
+	ID TaskID `json:"id"`
 
 	// The legacy ID of the task
 	// (only shown for objects created before 1 April 2017)
 	LegacyID *int `json:"legacy_id"`
 
 	// The owner of the task.
-	UserID int `json:"user_id"`
+	UserID UserID `json:"user_id"`
 
 	// The ID of the parent project.
-	ProjectID int `json:"project_id"`
+	ProjectID ProjectID `json:"project_id"`
 
 	// Legacy project ID for the project that the task resides in
 	// (only shown for objects created before 1 April 2017)
@@ -36,16 +38,15 @@ type Task struct {
 	// A description for the task. This value may contain markdown-formatted text and hyperlinks. Details on markdown support can be found in the Text Formatting article in the Help Center.
 	Description string `json:"description"`
 
-	// TODO: Date type
 	// The due date of the task. See the Due dates section for more details.
-	Due *interface{} `json:"due"`
+	Due *Due `json:"due"`
 
 	// The priority of the task (a number between 1 and 4, 4 for very urgent and 1 for natural).
 	// Note: Keep in mind that very urgent is the priority 1 on clients. So, p1 will return 4 in the API.
 	Priority int `json:"priority"`
 
 	// The ID of the parent task. Set to null for root tasks.
-	ParentID *int `json:"parent_id"`
+	ParentID *TaskID `json:"parent_id"`
 
 	// The legacy ID of the parent task. Set to null for root tasks
 	// (only shown for objects created before 1 April 2017)
@@ -55,45 +56,98 @@ type Task struct {
 	ChildOrder int `json:"child_order"`
 
 	// The ID of the parent section. Set to null for tasks not belonging to a section.
-	SectionID *int `json:"section_id"`
+	SectionID *SectionID `json:"section_id"`
 
 	// The order of the task inside the Today or Next 7 days view (a number, where the smallest value would place the task at the top).
 	DayOrder int `json:"day_order"`
 
-	// Whether the task's sub-tasks are collapsed (where 1 is true and 0 is false).
-	Collapsed int `json:"collapsed"`
+	// Whether the task's sub-tasks are collapsed.
+	Collapsed Bool `json:"collapsed"`
 
-	// The task's labels (a list of label IDs such as [2324,2525]).
-	Labels []int `json:"labels"`
+	// The task's labels (a list of label IDs such as [2324,2525]). The v9 API refers to labels by name instead.
+	Labels []LabelID `json:"labels"`
 
 	// The ID of the user who created the task. This makes sense for shared projects only. For tasks created before 31 Oct 2019 the value is set to null. Cannot be set explicitly or changed via API.
-	AddedByUID *int `json:"added_by_uid"`
+	AddedByUID *UserID `json:"added_by_uid"`
 
 	// The ID of the user who assigned the task. This makes sense for shared projects only. Accepts any user ID from the list of project collaborators. If this value is unset or invalid, it will automatically be set up to your uid.
-	AssignedByUID *int `json:"assigned_by_uid"`
+	AssignedByUID *UserID `json:"assigned_by_uid"`
 
 	// The ID of user who is responsible for accomplishing the current task. This makes sense for shared projects only. Accepts any user ID from the list of project collaborators or null or an empty string to unset.
-	ResponsibleUID *int `json:"responsible_uid"`
+	ResponsibleUID *UserID `json:"responsible_uid"`
 
-	// Whether the task is marked as completed (where 1 is true and 0 is false).
-	Checked int `json:"checked"`
+	// Whether the task is marked as completed.
+	Checked Bool `json:"checked"`
 
-	// Whether the task has been marked as completed and is marked to be moved to history, because all the child tasks of its parent are also marked as completed (where 1 is true and 0 is false)
-	InHistory int `json:"in_history"`
+	// Whether the task has been marked as completed and is marked to be moved to history, because all the child tasks of its parent are also marked as completed.
+	InHistory Bool `json:"in_history"`
 
-	// Whether the task is marked as deleted (where 1 is true and 0 is false).
-	IsDeleted int `json:"is_deleted"`
+	// Whether the task is marked as deleted.
+	IsDeleted Bool `json:"is_deleted"`
 
 	// Identifier to find the match between tasks in shared projects of different collaborators. When you share a task, its copy has a different ID in the projects of your collaborators. To find a task in another account that matches yours, you can use the "sync_id" attribute. For non-shared tasks, the attribute is null.
-	SyncID *int `json:"sync_id"`
+	SyncID *ID `json:"sync_id"`
 
-	// The date when the task was completed (or null if not completed).
+	// The date when the task was completed (or null if not completed). Sent as completed_at by the v9 API.
 	DateCompleted *string `json:"date_completed"`
 
-	// The date when the task was created.
+	// The date when the task was created. Sent as added_at by the v9 API.
 	DateAdded string `json:"date_added"`
 }
 
+// UnmarshalJSON implements json.Unmarshaler, accepting the field names of
+// the v8 and v9 Sync APIs and of the REST API.
+func (t *Task) UnmarshalJSON(data []byte) error {
+	type task Task
+
+	var v struct {
+		task
+
+		AddedAt     *string `json:"added_at"`
+		CompletedAt *string `json:"completed_at"`
+
+		// REST API field names.
+		CreatedAt   *string `json:"created_at"`
+		IsCompleted *Bool   `json:"is_completed"`
+		Order       *int    `json:"order"`
+		CreatorID   *UserID `json:"creator_id"`
+		AssigneeID  *UserID `json:"assignee_id"`
+		AssignerID  *UserID `json:"assigner_id"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	*t = Task(v.task)
+	if v.AddedAt != nil {
+		t.DateAdded = *v.AddedAt
+	}
+	if v.CompletedAt != nil {
+		t.DateCompleted = v.CompletedAt
+	}
+
+	if v.CreatedAt != nil {
+		t.DateAdded = *v.CreatedAt
+	}
+	if v.IsCompleted != nil {
+		t.Checked = *v.IsCompleted
+	}
+	if v.Order != nil {
+		t.ChildOrder = *v.Order
+	}
+	if v.CreatorID != nil {
+		t.AddedByUID = v.CreatorID
+	}
+	if v.AssigneeID != nil {
+		t.ResponsibleUID = v.AssigneeID
+	}
+	if v.AssignerID != nil {
+		t.AssignedByUID = v.AssignerID
+	}
+
+	return nil
+}
+
 // List the tasks for a user.
 func (s *TasksService) List(ctx context.Context, syncToken string) ([]Task, ReadResponse, error) {
 	s.client.Logln("---------- Tasks.List")
@@ -120,24 +174,23 @@ type AddTask struct {
 	Description string `json:"description,omitempty"`
 
 	// The ID of the project to add the task to (a number or a temp id). By default the task is added to the user’s Inbox project.
-	ProjectID string `json:"project_id,omitempty"`
+	ProjectID *ProjectID `json:"project_id,omitempty"`
 
-	// TODO: Date type
 	// The due date of the task. See the Due dates section for more details.
-	Due *interface{} `json:"due,omitempty"`
+	Due *Due `json:"due,omitempty"`
 
 	// The priority of the task (a number between 1 and 4, 4 for very urgent and 1 for natural).
 	// Note: Keep in mind that very urgent is the priority 1 on clients. So, p1 will return 4 in the API.
 	Priority int `json:"priority,omitempty"`
 
-	// The ID of the parent task. Set to null for root tasks.
-	ParentID *int `json:"parent_id,omitempty"`
+	// The ID of the parent task (could be a temp id). Set to null for root tasks.
+	ParentID *TaskID `json:"parent_id,omitempty"`
 
 	// The order of task. Defines the position of the task among all the tasks with the same parent.
 	ChildOrder int `json:"child_order,omitempty"`
 
-	// The ID of the section. Set to null for tasks not belonging to a section.
-	SectionID *int `json:"section_id,omitempty"`
+	// The ID of the section (could be a temp id). Set to null for tasks not belonging to a section.
+	SectionID *SectionID `json:"section_id,omitempty"`
 
 	// The order of the task inside the Today or Next 7 days view (a number, where the smallest value would place the task at the top).
 	DayOrder int `json:"day_order,omitempty"`
@@ -146,13 +199,13 @@ type AddTask struct {
 	Collapsed int `json:"collapsed,omitempty"`
 
 	// The tasks labels (a list of label IDs such as [2324,2525]).
-	Labels []int `json:"labels,omitempty"`
+	Labels []LabelID `json:"labels,omitempty"`
 
 	// The ID of user who assigns the current task. This makes sense for shared projects only. Accepts 0 or any user ID from the list of project collaborators. If this value is unset or invalid, it will be automatically setup to your uid.
-	AssignedByUID int `json:"assigned_by_uid,omitempty"`
+	AssignedByUID *UserID `json:"assigned_by_uid,omitempty"`
 
 	// The ID of user who is responsible for accomplishing the current task. This makes sense for shared projects only. Accepts any user ID from the list of project collaborators or null or an empty string to unset.
-	ResponsibleUID *int `json:"responsible_uid,omitempty"`
+	ResponsibleUID *UserID `json:"responsible_uid,omitempty"`
 
 	// When this option is enabled, the default reminder will be added to the new item if it has a due date with time set. See also the auto_reminder user option for more info about the default reminder.
 	AutoReminder bool `json:"auto_reminder,omitempty"`
@@ -195,3 +248,291 @@ func (s *TasksService) Add(ctx context.Context, syncToken string, addTask AddTas
 
 	return commandResponse.Tasks, commandResponse, nil
 }
+
+type UpdateTask struct {
+	// The ID of the task (could be a temp id).
+	ID TaskID `json:"id"`
+
+	// The text of the task. This value may contain markdown-formatted text and hyperlinks.
+	Content string `json:"content,omitempty"`
+
+	// A description for the task. This value may contain markdown-formatted text and hyperlinks.
+	Description string `json:"description,omitempty"`
+
+	// The due date of the task. See the Due dates section for more details.
+	Due *Due `json:"due,omitempty"`
+
+	// The priority of the task (a number between 1 and 4, 4 for very urgent and 1 for natural).
+	Priority int `json:"priority,omitempty"`
+
+	// Whether the task's sub-tasks are collapsed (where 1 is true and 0 is false).
+	Collapsed int `json:"collapsed,omitempty"`
+
+	// The tasks labels (a list of label IDs such as [2324,2525]).
+	Labels []LabelID `json:"labels,omitempty"`
+
+	// The ID of user who is responsible for accomplishing the current task. This makes sense for shared projects only.
+	ResponsibleUID *UserID `json:"responsible_uid,omitempty"`
+
+	TempID string `json:"-"`
+}
+
+// Update an existing task.
+func (s *TasksService) Update(ctx context.Context, syncToken string, updateTask UpdateTask) ([]Task, CommandResponse, error) {
+	s.client.Logln("---------- Tasks.Update")
+
+	id := uuid.New().String()
+	tempID := updateTask.TempID
+	if tempID == "" {
+		tempID = uuid.New().String()
+	}
+
+	updateCommand := Command{
+		Type:   "item_update",
+		Args:   updateTask,
+		UUID:   id,
+		TempID: tempID,
+	}
+
+	commands := []Command{updateCommand}
+
+	req, err := s.client.NewRequest(syncToken, []string{"items"}, commands)
+	if err != nil {
+		return nil, CommandResponse{}, err
+	}
+
+	var commandResponse CommandResponse
+	_, err = s.client.Do(ctx, req, &commandResponse)
+	if err != nil {
+		return nil, commandResponse, err
+	}
+
+	return commandResponse.Tasks, commandResponse, nil
+}
+
+type MoveTask struct {
+	// The ID of the task (could be a temp id).
+	ID TaskID `json:"id"`
+
+	// ID of the destination parent task (could be a temp id). The task becomes the last child task of the parent task.
+	ParentID *TaskID `json:"parent_id,omitempty"`
+
+	// ID of the destination section (could be a temp id). The task becomes the last root task of the section.
+	SectionID *SectionID `json:"section_id,omitempty"`
+
+	// ID of the destination project (could be a temp id). The task becomes the last root task of the project.
+	ProjectID *ProjectID `json:"project_id,omitempty"`
+
+	TempID string `json:"-"`
+}
+
+// Move a task to another project, section or parent task. Only one of ParentID, SectionID and ProjectID should be set.
+func (s *TasksService) Move(ctx context.Context, syncToken string, moveTask MoveTask) ([]Task, CommandResponse, error) {
+	s.client.Logln("---------- Tasks.Move")
+
+	id := uuid.New().String()
+	tempID := moveTask.TempID
+	if tempID == "" {
+		tempID = uuid.New().String()
+	}
+
+	moveCommand := Command{
+		Type:   "item_move",
+		Args:   moveTask,
+		UUID:   id,
+		TempID: tempID,
+	}
+
+	commands := []Command{moveCommand}
+
+	req, err := s.client.NewRequest(syncToken, []string{"items"}, commands)
+	if err != nil {
+		return nil, CommandResponse{}, err
+	}
+
+	var commandResponse CommandResponse
+	_, err = s.client.Do(ctx, req, &commandResponse)
+	if err != nil {
+		return nil, commandResponse, err
+	}
+
+	return commandResponse.Tasks, commandResponse, nil
+}
+
+type DeleteTask struct {
+	// ID of the task to delete (could be a temp id).
+	ID TaskID `json:"id"`
+
+	TempID string `json:"-"`
+}
+
+// Delete a task and all its sub-tasks.
+func (s *TasksService) Delete(ctx context.Context, syncToken string, deleteTask DeleteTask) ([]Task, CommandResponse, error) {
+	s.client.Logln("---------- Tasks.Delete")
+
+	id := uuid.New().String()
+	tempID := deleteTask.TempID
+	if tempID == "" {
+		tempID = uuid.New().String()
+	}
+
+	deleteCommand := Command{
+		Type:   "item_delete",
+		Args:   deleteTask,
+		UUID:   id,
+		TempID: tempID,
+	}
+
+	commands := []Command{deleteCommand}
+
+	req, err := s.client.NewRequest(syncToken, []string{"items"}, commands)
+	if err != nil {
+		return nil, CommandResponse{}, err
+	}
+
+	var commandResponse CommandResponse
+	_, err = s.client.Do(ctx, req, &commandResponse)
+	if err != nil {
+		return nil, commandResponse, err
+	}
+
+	return commandResponse.Tasks, commandResponse, nil
+}
+
+type CompleteTask struct {
+	// ID of the task to complete (could be a temp id).
+	ID TaskID `json:"id"`
+
+	// RFC3339-formatted date of completion of the task (in UTC). If not set, the server will set the value to the current timestamp.
+	DateCompleted string `json:"date_completed,omitempty"`
+
+	TempID string `json:"-"`
+}
+
+// Complete a task and all its sub-tasks.
+func (s *TasksService) Complete(ctx context.Context, syncToken string, completeTask CompleteTask) ([]Task, CommandResponse, error) {
+	s.client.Logln("---------- Tasks.Complete")
+
+	id := uuid.New().String()
+	tempID := completeTask.TempID
+	if tempID == "" {
+		tempID = uuid.New().String()
+	}
+
+	completeCommand := Command{
+		Type:   "item_complete",
+		Args:   completeTask,
+		UUID:   id,
+		TempID: tempID,
+	}
+
+	commands := []Command{completeCommand}
+
+	req, err := s.client.NewRequest(syncToken, []string{"items"}, commands)
+	if err != nil {
+		return nil, CommandResponse{}, err
+	}
+
+	var commandResponse CommandResponse
+	_, err = s.client.Do(ctx, req, &commandResponse)
+	if err != nil {
+		return nil, commandResponse, err
+	}
+
+	return commandResponse.Tasks, commandResponse, nil
+}
+
+type ReorderedTask struct {
+	// ID of the task to order (could be a temp id).
+	ID TaskID `json:"id"`
+
+	// The new order.
+	ChildOrder int `json:"child_order"`
+}
+
+type ReorderTasks struct {
+	// An array of objects to update. Each object contains two attributes: id of the task to update and child_order, the new order.
+	Tasks []ReorderedTask `json:"items"`
+
+	TempID string `json:"-"`
+}
+
+// The command updates `child_order` properties of tasks in bulk.
+func (s *TasksService) Reorder(ctx context.Context, syncToken string, reorderTasks ReorderTasks) ([]Task, CommandResponse, error) {
+	s.client.Logln("---------- Tasks.Reorder")
+
+	id := uuid.New().String()
+	tempID := reorderTasks.TempID
+	if tempID == "" {
+		tempID = uuid.New().String()
+	}
+
+	reorderCommand := Command{
+		Type:   "item_reorder",
+		Args:   reorderTasks,
+		UUID:   id,
+		TempID: tempID,
+	}
+
+	commands := []Command{reorderCommand}
+
+	req, err := s.client.NewRequest(syncToken, []string{"items"}, commands)
+	if err != nil {
+		return nil, CommandResponse{}, err
+	}
+
+	var commandResponse CommandResponse
+	_, err = s.client.Do(ctx, req, &commandResponse)
+	if err != nil {
+		return nil, commandResponse, err
+	}
+
+	return commandResponse.Tasks, commandResponse, nil
+}
+
+type QuickAddOptions struct {
+	// The content of the note to add to the new task.
+	Note string
+
+	// The date of the reminder, added as a default reminder, using the same syntax as the due dates.
+	Reminder string
+
+	// When enabled, the default reminder will be added to the new task if it has a due date with time set.
+	AutoReminder bool
+}
+
+// QuickAdd adds a new task using the Quick Add implementation of the Todoist
+// apps, parsing text such as "Pay rent every 1st #Home @bills p1" into the
+// task's project, labels, priority and due date. ParseQuickAdd previews the
+// result offline.
+//
+// Todoist API docs: https://developer.todoist.com/sync/v8/#quick-add-an-item
+func (s *TasksService) QuickAdd(ctx context.Context, text string, opts *QuickAddOptions) (Task, error) {
+	s.client.Logln("---------- Tasks.QuickAdd")
+
+	form := url.Values{}
+	form.Set("text", text)
+	if opts != nil {
+		if opts.Note != "" {
+			form.Set("note", opts.Note)
+		}
+		if opts.Reminder != "" {
+			form.Set("reminder", opts.Reminder)
+		}
+		if opts.AutoReminder {
+			form.Set("auto_reminder", "true")
+		}
+	}
+
+	req, err := s.client.newEndpointRequest("quick/add", form)
+	if err != nil {
+		return Task{}, err
+	}
+
+	var task Task
+	if _, err = s.client.Do(ctx, req, &task); err != nil {
+		return Task{}, err
+	}
+
+	return task, nil
+}
@@ -0,0 +1,415 @@
+package todoist
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Types of the rows of a template.
+const (
+	TemplateTask    = "task"
+	TemplateSection = "section"
+	TemplateNote    = "note"
+	TemplateMeta    = "meta"
+)
+
+// maxTemplateIndent is the deepest level of sub-tasks Todoist supports.
+const maxTemplateIndent = 5
+
+// templateColumns are the columns of a template, in the order Todoist writes them.
+var templateColumns = []string{"TYPE", "CONTENT", "DESCRIPTION", "PRIORITY", "INDENT", "AUTHOR", "RESPONSIBLE", "DATE", "DATE_LANG", "TIMEZONE"}
+
+// TemplateRow is a row of a template.
+type TemplateRow struct {
+	// The type of the row: TemplateTask, TemplateSection, TemplateNote or TemplateMeta.
+	Type string
+
+	// The content of a task or note, the name of a section, or a setting such as "view_style=board" for meta rows.
+	Content string
+
+	// The description of a task.
+	Description string
+
+	// The priority of a task as shown in the apps, from 1 (p1, the highest) to 4 (p4), or 0 if not set. This is the reverse of Task.Priority.
+	Priority int
+
+	// The level of a task, from 1 for a top-level task to 5. A task is a sub-task of the last task above it with a lower level.
+	Indent int
+
+	// The creator and the assignee of a task, as "Name (user ID)".
+	Author      string
+	Responsible string
+
+	// The due string of a task, its language, and the timezone it was written in.
+	Date     string
+	DateLang string
+	Timezone string
+}
+
+// Template is a Todoist project template: the CSV format the apps import and
+// export projects as.
+//
+// Todoist API docs: https://developer.todoist.com/sync/v8/#templates
+type Template struct {
+	Rows []TemplateRow
+}
+
+// ReadTemplate reads a template in CSV format. Columns are matched by their
+// header, so that unknown columns and different orders are accepted; blank
+// rows are skipped.
+func ReadTemplate(r io.Reader) (*Template, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, errors.New("empty template")
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read template header")
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToUpper(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, required := range []string{"TYPE", "CONTENT"} {
+		if _, ok := columns[required]; !ok {
+			return nil, errors.Errorf("template has no %s column", required)
+		}
+	}
+
+	t := &Template{}
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to read template")
+		}
+		line, _ := cr.FieldPos(0)
+
+		field := func(name string) string {
+			i, ok := columns[name]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		row := TemplateRow{
+			Type:        strings.ToLower(field("TYPE")),
+			Content:     field("CONTENT"),
+			Description: field("DESCRIPTION"),
+			Author:      field("AUTHOR"),
+			Responsible: field("RESPONSIBLE"),
+			Date:        field("DATE"),
+			DateLang:    field("DATE_LANG"),
+			Timezone:    field("TIMEZONE"),
+		}
+		if row.Type == "" && row.Content == "" {
+			continue
+		}
+
+		switch row.Type {
+		case TemplateTask, TemplateSection, TemplateNote, TemplateMeta:
+		default:
+			return nil, errors.Errorf("line %d: unknown row type %q", line, row.Type)
+		}
+
+		if row.Priority, err = templateInt(field("PRIORITY"), 0, 4); err != nil {
+			return nil, errors.Wrapf(err, "line %d: invalid priority", line)
+		}
+		if row.Indent, err = templateInt(field("INDENT"), 0, maxTemplateIndent); err != nil {
+			return nil, errors.Wrapf(err, "line %d: invalid indent", line)
+		}
+
+		t.Rows = append(t.Rows, row)
+	}
+
+	return t, nil
+}
+
+// templateInt parses an optional number between lo and hi.
+func templateInt(s string, lo, hi int) (int, error) {
+	if s == "" {
+		return 0, nil
+	}
+
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, err
+	}
+	if n < lo || n > hi {
+		return 0, errors.Errorf("%d is not between %d and %d", n, lo, hi)
+	}
+
+	return n, nil
+}
+
+// Write writes the template in CSV format.
+func (t *Template) Write(w io.Writer) error {
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write(templateColumns); err != nil {
+		return err
+	}
+
+	for _, row := range t.Rows {
+		record := []string{row.Type, row.Content, row.Description, "", "", row.Author, row.Responsible, row.Date, row.DateLang, row.Timezone}
+		if row.Priority != 0 {
+			record[3] = strconv.Itoa(row.Priority)
+		}
+		if row.Indent != 0 {
+			record[4] = strconv.Itoa(row.Indent)
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// ExportTemplate builds the template of a project from its sections and tasks
+// (for example from a full sync; resources of other projects are ignored).
+// Tasks without a section come first, followed by each section and its tasks,
+// with sub-tasks indented below their parent. Completed and deleted resources
+// are left out. Sub-tasks nested deeper than templates allow are kept at the
+// deepest indent, below their closest ancestor at that level.
+func ExportTemplate(project Project, sections []Section, tasks []Task) *Template {
+	t := &Template{}
+	if project.ViewStyle != "" {
+		t.Rows = append(t.Rows, TemplateRow{Type: TemplateMeta, Content: "view_style=" + project.ViewStyle})
+	}
+
+	// Children of each task, and top-level tasks of each section (keyed by
+	// section ID, or "" for tasks without a section).
+	children := map[string][]*Task{}
+	roots := map[string][]*Task{}
+	active := map[string]bool{}
+	for i := range tasks {
+		task := &tasks[i]
+		if task.ProjectID.String() == project.ID.String() && !bool(task.Checked) && !bool(task.IsDeleted) {
+			active[task.ID.String()] = true
+		}
+	}
+	for i := range tasks {
+		task := &tasks[i]
+		if !active[task.ID.String()] {
+			continue
+		}
+
+		if task.ParentID != nil && active[task.ParentID.String()] {
+			children[task.ParentID.String()] = append(children[task.ParentID.String()], task)
+			continue
+		}
+
+		section := ""
+		if task.SectionID != nil {
+			section = task.SectionID.String()
+		}
+		roots[section] = append(roots[section], task)
+	}
+
+	var addTasks func(tasks []*Task, indent int)
+	addTasks = func(tasks []*Task, indent int) {
+		sort.SliceStable(tasks, func(i, j int) bool { return tasks[i].ChildOrder < tasks[j].ChildOrder })
+		for _, task := range tasks {
+			t.Rows = append(t.Rows, templateTaskRow(task, indent))
+			childIndent := indent + 1
+			if childIndent > maxTemplateIndent {
+				childIndent = maxTemplateIndent
+			}
+			addTasks(children[task.ID.String()], childIndent)
+		}
+	}
+
+	addTasks(roots[""], 1)
+
+	var projectSections []Section
+	for _, s := range sections {
+		if s.ProjectID.String() == project.ID.String() && !s.IsDeleted {
+			projectSections = append(projectSections, s)
+		}
+	}
+	sort.SliceStable(projectSections, func(i, j int) bool { return projectSections[i].SectionOrder < projectSections[j].SectionOrder })
+
+	for _, s := range projectSections {
+		t.Rows = append(t.Rows, TemplateRow{Type: TemplateSection, Content: s.Name})
+		addTasks(roots[s.ID.String()], 1)
+	}
+
+	return t
+}
+
+func templateTaskRow(task *Task, indent int) TemplateRow {
+	row := TemplateRow{
+		Type:        TemplateTask,
+		Content:     task.Content,
+		Description: task.Description,
+		Priority:    4,
+		Indent:      indent,
+	}
+	if task.Priority >= 1 && task.Priority <= 4 {
+		row.Priority = 5 - task.Priority
+	}
+	if task.AddedByUID != nil {
+		row.Author = "(" + task.AddedByUID.String() + ")"
+	}
+	if task.ResponsibleUID != nil {
+		row.Responsible = "(" + task.ResponsibleUID.String() + ")"
+	}
+
+	if task.Due != nil {
+		row.Date = task.Due.String
+		if row.Date == "" {
+			row.Date = task.Due.Date
+		}
+		row.DateLang = task.Due.Lang
+		if task.Due.Timezone != nil {
+			row.Timezone = *task.Due.Timezone
+		}
+	}
+
+	return row
+}
+
+// TemplateTarget is the project a template is imported into.
+type TemplateTarget struct {
+	// An existing project. If nil, a new project named ProjectName is created.
+	ProjectID *ProjectID
+
+	// The name of the project to create.
+	ProjectName string
+}
+
+// templateNote holds the arguments of a note_add or project_note_add command.
+type templateNote struct {
+	ItemID    *TaskID    `json:"item_id,omitempty"`
+	ProjectID *ProjectID `json:"project_id,omitempty"`
+	Content   string     `json:"content"`
+}
+
+var templateUserPattern = regexp.MustCompile(`\((\d+)\)\s*$`)
+
+// Commands returns the commands that import the template into target, along
+// with the ID of the project (a temp ID if it is created). The commands refer
+// to each other with temp IDs, so that they can be sent in a single request.
+//
+// Due dates are sent as due strings for Todoist to parse. Meta rows, authors
+// and timezones are not imported; responsible users are, when the column holds
+// a user ID.
+func (t *Template) Commands(target TemplateTarget) ([]Command, ProjectID, error) {
+	var batch commandBatch
+
+	var projectID ProjectID
+	switch {
+	case target.ProjectID != nil:
+		projectID = *target.ProjectID
+	case target.ProjectName != "":
+		projectID = ProjectID{NewTempID(batch.add("project_add", AddProject{Name: target.ProjectName}))}
+	default:
+		return nil, ProjectID{}, errors.New("template target needs a project ID or a project name")
+	}
+
+	var (
+		sectionID *SectionID
+		parents   []TaskID // The last task at each level.
+		sectionN  int
+	)
+	for i, row := range t.Rows {
+		switch row.Type {
+		case TemplateSection:
+			if row.Content == "" {
+				return nil, ProjectID{}, errors.Errorf("row %d: section without a name", i+1)
+			}
+			sectionN++
+			id := SectionID{NewTempID(batch.add("section_add", AddSection{Name: row.Content, ProjectID: projectID, SectionOrder: sectionN}))}
+			sectionID = &id
+			parents = parents[:0]
+
+		case TemplateTask:
+			if row.Content == "" {
+				return nil, ProjectID{}, errors.Errorf("row %d: task without content", i+1)
+			}
+
+			indent := row.Indent
+			if indent == 0 {
+				indent = 1
+			}
+			if indent > len(parents)+1 {
+				return nil, ProjectID{}, errors.Errorf("row %d: indent %d has no parent task", i+1, indent)
+			}
+			parents = parents[:indent-1]
+
+			pid := projectID
+			addTask := AddTask{
+				Content:     row.Content,
+				Description: row.Description,
+				ProjectID:   &pid,
+				SectionID:   sectionID,
+			}
+			if indent > 1 {
+				parent := parents[indent-2]
+				addTask.ParentID = &parent
+			}
+			if row.Priority != 0 {
+				addTask.Priority = 5 - row.Priority
+			}
+			if row.Date != "" {
+				addTask.Due = &Due{String: row.Date, Lang: row.DateLang}
+			}
+			if m := templateUserPattern.FindStringSubmatch(row.Responsible); m != nil {
+				addTask.ResponsibleUID = &UserID{NewID(m[1])}
+			}
+
+			parents = append(parents, TaskID{NewTempID(batch.add("item_add", addTask))})
+
+		case TemplateNote:
+			if len(parents) == 0 {
+				pid := projectID
+				batch.add("project_note_add", templateNote{ProjectID: &pid, Content: row.Content})
+				continue
+			}
+			task := parents[len(parents)-1]
+			batch.add("note_add", templateNote{ItemID: &task, Content: row.Content})
+		}
+	}
+
+	return batch.commands, projectID, nil
+}
+
+// ImportTemplate imports a template into a new or existing project, in a
+// single request, and returns the ID of the project.
+func (s *ProjectsService) ImportTemplate(ctx context.Context, syncToken string, template *Template, target TemplateTarget) (ProjectID, CommandResponse, error) {
+	s.client.Logln("---------- Projects.ImportTemplate")
+
+	commands, projectID, err := template.Commands(target)
+	if err != nil {
+		return ProjectID{}, CommandResponse{}, err
+	}
+
+	req, err := s.client.NewRequest(syncToken, []string{"projects", "sections", "items"}, commands)
+	if err != nil {
+		return ProjectID{}, CommandResponse{}, err
+	}
+
+	var commandResponse CommandResponse
+	_, err = s.client.Do(ctx, req, &commandResponse)
+	if err != nil {
+		return ProjectID{}, commandResponse, err
+	}
+
+	return ProjectID{s.client.ResolveID(projectID.ID)}, commandResponse, nil
+}
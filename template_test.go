@@ -0,0 +1,297 @@
+package todoist
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+const testTemplateCSV = "\ufeffTYPE,CONTENT,DESCRIPTION,PRIORITY,INDENT,AUTHOR,RESPONSIBLE,DATE,DATE_LANG,TIMEZONE,DURATION\n" +
+	"meta,view_style=board,,,,,,,,,\n" +
+	"note,Shared with the whole team,,,,,,,,,\n" +
+	"task,Book venue,Call first,1,1,Ann (1001),Bob (1002),next monday,en,Europe/Rome,\n" +
+	",,,,,,,,,,\n" +
+	"task,Compare prices,,4,2,Ann (1001),,,,,\n" +
+	"note,Try the river side,,,,,,,,,\n" +
+	"\n" +
+	"section,Catering,,,,,,,,,\n" +
+	"task,\"Order food, drinks\",,2,1,Ann (1001),,every fri,en,,\n"
+
+func Test_ReadTemplate(t *testing.T) {
+	tmpl, err := ReadTemplate(strings.NewReader(testTemplateCSV))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []TemplateRow{
+		{Type: TemplateMeta, Content: "view_style=board"},
+		{Type: TemplateNote, Content: "Shared with the whole team"},
+		{Type: TemplateTask, Content: "Book venue", Description: "Call first", Priority: 1, Indent: 1, Author: "Ann (1001)", Responsible: "Bob (1002)", Date: "next monday", DateLang: "en", Timezone: "Europe/Rome"},
+		{Type: TemplateTask, Content: "Compare prices", Priority: 4, Indent: 2, Author: "Ann (1001)"},
+		{Type: TemplateNote, Content: "Try the river side"},
+		{Type: TemplateSection, Content: "Catering"},
+		{Type: TemplateTask, Content: "Order food, drinks", Priority: 2, Indent: 1, Author: "Ann (1001)", Date: "every fri", DateLang: "en"},
+	}
+	if !reflect.DeepEqual(tmpl.Rows, want) {
+		t.Errorf("expected rows\n%+v\nreceived\n%+v", want, tmpl.Rows)
+	}
+
+	// Writing and reading back keeps the rows.
+	var buf bytes.Buffer
+	if err = tmpl.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(buf.String(), "TYPE,CONTENT,DESCRIPTION,PRIORITY,INDENT,AUTHOR,RESPONSIBLE,DATE,DATE_LANG,TIMEZONE\n") {
+		t.Errorf("unexpected header in\n%s", buf.String())
+	}
+
+	again, err := ReadTemplate(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(again.Rows, want) {
+		t.Errorf("expected the written template to read back the same rows, received\n%+v", again.Rows)
+	}
+}
+
+func Test_ReadTemplate_Errors(t *testing.T) {
+	tests := map[string]string{
+		"empty":             "",
+		"no type column":    "CONTENT,PRIORITY\nBuy milk,4\n",
+		"unknown row type":  "TYPE,CONTENT\nproject,Groceries\n",
+		"invalid priority":  "TYPE,CONTENT,PRIORITY\ntask,Buy milk,high\n",
+		"priority too high": "TYPE,CONTENT,PRIORITY\ntask,Buy milk,5\n",
+		"indent too deep":   "TYPE,CONTENT,INDENT\ntask,Buy milk,6\n",
+		"malformed csv":     "TYPE,CONTENT\ntask,\"Buy milk\n",
+	}
+
+	for name, csv := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ReadTemplate(strings.NewReader(csv)); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func Test_ExportTemplate(t *testing.T) {
+	id := func(s string) ID { return NewID(s) }
+	project := Project{ID: ProjectID{id("1")}, Name: "Party", ViewStyle: "list"}
+	other := ProjectID{id("2")}
+	catering := SectionID{id("20")}
+	venue := SectionID{id("10")}
+	rome := "Europe/Rome"
+
+	sections := []Section{
+		{ID: catering, ProjectID: project.ID, Name: "Catering", SectionOrder: 2},
+		{ID: venue, ProjectID: project.ID, Name: "Venue", SectionOrder: 1},
+		{ID: SectionID{id("30")}, ProjectID: other, Name: "Elsewhere"},
+		{ID: SectionID{id("40")}, ProjectID: project.ID, Name: "Deleted", IsDeleted: true},
+	}
+
+	parent := TaskID{id("101")}
+	tasks := []Task{
+		{ID: TaskID{id("102")}, ProjectID: project.ID, Content: "Send invites", ChildOrder: 2, Priority: 1},
+		{ID: parent, ProjectID: project.ID, Content: "Pick a date", ChildOrder: 1, Priority: 4, Due: &Due{Date: "2022-03-14", String: "next monday", Lang: "en", Timezone: &rome}},
+		{ID: TaskID{id("103")}, ProjectID: project.ID, ParentID: &parent, Content: "Ask everyone", Description: "By email", ChildOrder: 1, Priority: 2, AddedByUID: &UserID{id("1001")}, ResponsibleUID: &UserID{id("1002")}},
+		{ID: TaskID{id("104")}, ProjectID: project.ID, SectionID: &venue, Content: "Book venue", Priority: 3},
+		{ID: TaskID{id("105")}, ProjectID: project.ID, SectionID: &catering, Content: "Order food", Due: &Due{Date: "2022-03-11"}},
+		{ID: TaskID{id("106")}, ProjectID: project.ID, Content: "Done already", Checked: true},
+		{ID: TaskID{id("107")}, ProjectID: other, Content: "Other project"},
+	}
+
+	got := ExportTemplate(project, sections, tasks).Rows
+	want := []TemplateRow{
+		{Type: TemplateMeta, Content: "view_style=list"},
+		{Type: TemplateTask, Content: "Pick a date", Priority: 1, Indent: 1, Date: "next monday", DateLang: "en", Timezone: "Europe/Rome"},
+		{Type: TemplateTask, Content: "Ask everyone", Description: "By email", Priority: 3, Indent: 2, Author: "(1001)", Responsible: "(1002)"},
+		{Type: TemplateTask, Content: "Send invites", Priority: 4, Indent: 1},
+		{Type: TemplateSection, Content: "Venue"},
+		{Type: TemplateTask, Content: "Book venue", Priority: 2, Indent: 1},
+		{Type: TemplateSection, Content: "Catering"},
+		{Type: TemplateTask, Content: "Order food", Priority: 4, Indent: 1, Date: "2022-03-11"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected rows\n%+v\nreceived\n%+v", want, got)
+	}
+}
+
+func Test_ExportTemplate_Deep(t *testing.T) {
+	project := Project{ID: ProjectID{NewID("1")}, Name: "Deep"}
+
+	var tasks []Task
+	var parent *TaskID
+	for i := 1; i <= maxTemplateIndent+2; i++ {
+		id := TaskID{NewID(strconv.Itoa(100 + i))}
+		tasks = append(tasks, Task{ID: id, ProjectID: project.ID, ParentID: parent, Content: "Level " + strconv.Itoa(i), Priority: 1})
+		parent = &id
+	}
+
+	got := ExportTemplate(project, nil, tasks).Rows
+	if len(got) != len(tasks) {
+		t.Fatalf("expected %d rows, received %d: %+v", len(tasks), len(got), got)
+	}
+	for i, row := range got {
+		indent := i + 1
+		if indent > maxTemplateIndent {
+			indent = maxTemplateIndent
+		}
+		if row.Content != tasks[i].Content || row.Indent != indent {
+			t.Errorf("row %d: expected %q at indent %d, received %q at indent %d", i, tasks[i].Content, indent, row.Content, row.Indent)
+		}
+	}
+
+	if _, _, err := (&Template{Rows: got}).Commands(TemplateTarget{ProjectName: "Deep"}); err != nil {
+		t.Errorf("expected the exported template to import, received %v", err)
+	}
+}
+
+func Test_Template_Commands(t *testing.T) {
+	tmpl, err := ReadTemplate(strings.NewReader(testTemplateCSV))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	commands, projectID, err := tmpl.Commands(TemplateTarget{ProjectName: "Party"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !projectID.IsTemp() || projectID.String() != commands[0].TempID {
+		t.Errorf("expected the temp ID of the created project, received %v", projectID)
+	}
+
+	var types []string
+	for _, c := range commands {
+		types = append(types, c.Type)
+	}
+	wantTypes := []string{"project_add", "project_note_add", "item_add", "item_add", "note_add", "section_add", "item_add"}
+	if !reflect.DeepEqual(types, wantTypes) {
+		t.Fatalf("expected commands %v, received %v", wantTypes, types)
+	}
+
+	args := func(i int) map[string]interface{} {
+		b, err := json.Marshal(commands[i].Args)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var m map[string]interface{}
+		_ = json.Unmarshal(b, &m)
+		return m
+	}
+
+	project := commands[0].TempID
+	book, compare, section := commands[2].TempID, commands[3].TempID, commands[5].TempID
+
+	checks := []struct {
+		i    int
+		want map[string]interface{}
+	}{
+		{0, map[string]interface{}{"name": "Party"}},
+		{1, map[string]interface{}{"project_id": project, "content": "Shared with the whole team"}},
+		{2, map[string]interface{}{"content": "Book venue", "description": "Call first", "project_id": project, "priority": float64(4), "responsible_uid": "1002", "due": map[string]interface{}{"string": "next monday", "lang": "en"}}},
+		{3, map[string]interface{}{"content": "Compare prices", "project_id": project, "parent_id": book, "priority": float64(1)}},
+		{4, map[string]interface{}{"item_id": compare, "content": "Try the river side"}},
+		{5, map[string]interface{}{"name": "Catering", "project_id": project, "section_order": float64(1)}},
+		{6, map[string]interface{}{"content": "Order food, drinks", "project_id": project, "section_id": section, "priority": float64(3), "due": map[string]interface{}{"string": "every fri", "lang": "en"}}},
+	}
+	for _, c := range checks {
+		if got := args(c.i); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("command %d (%s): expected args %v, received %v", c.i, commands[c.i].Type, c.want, got)
+		}
+	}
+
+	// Importing into an existing project adds no project.
+	inbox := ProjectID{NewID("1000")}
+	commands, projectID, err = tmpl.Commands(TemplateTarget{ProjectID: &inbox})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if projectID != inbox || commands[0].Type != "project_note_add" {
+		t.Errorf("expected commands for the existing project, received %v and %v", projectID, commands[0])
+	}
+}
+
+func Test_Template_Commands_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		rows   []TemplateRow
+		target TemplateTarget
+	}{
+		{name: "no target", rows: []TemplateRow{{Type: TemplateTask, Content: "Buy milk"}}},
+		{name: "indent without parent", rows: []TemplateRow{{Type: TemplateTask, Content: "Buy milk", Indent: 2}}, target: TemplateTarget{ProjectName: "Groceries"}},
+		{name: "indent after section", rows: []TemplateRow{{Type: TemplateTask, Content: "Buy milk"}, {Type: TemplateSection, Content: "Dairy"}, {Type: TemplateTask, Content: "Cheese", Indent: 2}}, target: TemplateTarget{ProjectName: "Groceries"}},
+		{name: "task without content", rows: []TemplateRow{{Type: TemplateTask}}, target: TemplateTarget{ProjectName: "Groceries"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := (&Template{Rows: tt.rows}).Commands(tt.target); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func Test_Projects_ImportTemplate(t *testing.T) {
+	client, srv := newFakeClient(t)
+	ctx := context.Background()
+
+	tmpl := &Template{Rows: []TemplateRow{
+		{Type: TemplateTask, Content: "Pick a date", Priority: 1, Indent: 1},
+		{Type: TemplateTask, Content: "Ask everyone", Indent: 2},
+		{Type: TemplateSection, Content: "Catering"},
+		{Type: TemplateTask, Content: "Order food", Indent: 1},
+	}}
+
+	before := len(srv.Requests())
+	projectID, _, err := client.Projects.ImportTemplate(ctx, "", tmpl, TemplateTarget{ProjectName: "Party"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if projectID.IsTemp() {
+		t.Errorf("expected the real ID of the created project, received %v", projectID)
+	}
+	if n := len(srv.Requests()) - before; n != 1 {
+		t.Errorf("expected a single request, received %d", n)
+	}
+
+	sections, _, err := client.Sections.List(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tasks, _, err := client.Tasks.List(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var project Project
+	projects, _, err := client.Projects.List(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range projects {
+		if p.ID == projectID {
+			project = p
+		}
+	}
+	if project.Name != "Party" {
+		t.Fatalf("expected the Party project, received %+v", project)
+	}
+
+	// Exporting the imported project gives back the template.
+	got := ExportTemplate(project, sections, tasks).Rows
+	want := []TemplateRow{
+		{Type: TemplateTask, Content: "Pick a date", Priority: 1, Indent: 1},
+		{Type: TemplateTask, Content: "Ask everyone", Priority: 4, Indent: 2, Author: "(1)"},
+		{Type: TemplateSection, Content: "Catering"},
+		{Type: TemplateTask, Content: "Order food", Priority: 4, Indent: 1, Author: "(1)"},
+	}
+	want[0].Author = got[0].Author
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected rows\n%+v\nreceived\n%+v", want, got)
+	}
+}
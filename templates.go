@@ -0,0 +1,161 @@
+package todoist
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/url"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// TemplatesService handles the /templates endpoints of the Sync API, which
+// import and export projects as template files on Todoist's side. Template
+// files are in the CSV format read and written by ReadTemplate and
+// Template.Write.
+type TemplatesService service
+
+// TemplateImport is the result of importing a template into a project.
+type TemplateImport struct {
+	// The status of the import, "ok" on success.
+	Status string `json:"status"`
+
+	// The kind of template that was imported (for example "tasks" for a CSV template).
+	TemplateType string `json:"template_type"`
+
+	// The projects created by the import.
+	Projects []Project `json:"projects"`
+
+	// The sections created by the import.
+	Sections []Section `json:"sections"`
+
+	// The tasks created by the import.
+	Tasks []Task `json:"tasks"`
+
+	// The task comments created by the import.
+	Comments []Comment `json:"comments"`
+
+	// The project comments created by the import.
+	ProjectNotes []Comment `json:"project_notes"`
+}
+
+// TemplateFile is a template exported to a file hosted by Todoist.
+type TemplateFile struct {
+	// The name of the exported file.
+	FileName string `json:"file_name"`
+
+	// The URL the file can be downloaded from.
+	FileURL string `json:"file_url"`
+}
+
+// ImportIntoProject imports a template into an existing project, adding its
+// sections, tasks and comments to the ones already in the project.
+func (s *TemplatesService) ImportIntoProject(ctx context.Context, projectID ProjectID, template *Template) (TemplateImport, error) {
+	var file bytes.Buffer
+	if err := template.Write(&file); err != nil {
+		return TemplateImport{}, err
+	}
+
+	return s.ImportFile(ctx, projectID, "template.csv", &file)
+}
+
+// ImportFile imports a template file, such as a CSV file downloaded from
+// Todoist, into an existing project. fileName is sent as the name of the
+// uploaded file.
+func (s *TemplatesService) ImportFile(ctx context.Context, projectID ProjectID, fileName string, file io.Reader) (TemplateImport, error) {
+	s.client.Logln("---------- Templates.ImportFile")
+
+	form := url.Values{}
+	form.Set("project_id", s.client.ResolveID(projectID.ID).String())
+
+	req, err := s.client.newUploadRequest("templates/import_into_project", form, fileName, "text/csv", file)
+	if err != nil {
+		return TemplateImport{}, err
+	}
+
+	var templateImport TemplateImport
+	if _, err = s.client.Do(ctx, req, &templateImport); err != nil {
+		return TemplateImport{}, err
+	}
+
+	return templateImport, nil
+}
+
+// ExportAsFile exports a project as a template and downloads it.
+func (s *TemplatesService) ExportAsFile(ctx context.Context, projectID ProjectID) (*Template, error) {
+	s.client.Logln("---------- Templates.ExportAsFile")
+
+	form := url.Values{}
+	form.Set("project_id", s.client.ResolveID(projectID.ID).String())
+
+	req, err := s.client.newEndpointRequest("templates/export_as_file", form)
+	if err != nil {
+		return nil, err
+	}
+
+	var file bytes.Buffer
+	if _, err = s.client.Do(ctx, req, &file); err != nil {
+		return nil, err
+	}
+
+	template, err := ReadTemplate(&file)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read exported template")
+	}
+
+	return template, nil
+}
+
+// ExportAsURL exports a project as a template file hosted by Todoist, and
+// returns where it can be downloaded from.
+func (s *TemplatesService) ExportAsURL(ctx context.Context, projectID ProjectID) (TemplateFile, error) {
+	s.client.Logln("---------- Templates.ExportAsURL")
+
+	form := url.Values{}
+	form.Set("project_id", s.client.ResolveID(projectID.ID).String())
+
+	req, err := s.client.newEndpointRequest("templates/export_as_url", form)
+	if err != nil {
+		return TemplateFile{}, err
+	}
+
+	var templateFile TemplateFile
+	if _, err = s.client.Do(ctx, req, &templateFile); err != nil {
+		return TemplateFile{}, err
+	}
+
+	return templateFile, nil
+}
+
+// Clone copies a project's sections and uncompleted tasks into a new root
+// project named name, by exporting the project as a template and importing
+// it into the new project. It returns the ID of the new project.
+func (s *ProjectsService) Clone(ctx context.Context, projectID ProjectID, name string) (ProjectID, error) {
+	s.client.Logln("---------- Projects.Clone")
+
+	if name == "" {
+		return ProjectID{}, errors.New("name cannot be empty")
+	}
+
+	template, err := s.client.Templates.ExportAsFile(ctx, projectID)
+	if err != nil {
+		return ProjectID{}, err
+	}
+
+	tempID := uuid.New().String()
+	if _, _, err = s.Add(ctx, "", AddProject{Name: name, TempID: tempID}); err != nil {
+		return ProjectID{}, err
+	}
+
+	clone := ProjectID{s.client.ResolveID(NewTempID(tempID))}
+	if clone.IsTemp() {
+		return ProjectID{}, errors.Errorf("no ID was returned for project %q", name)
+	}
+
+	if _, err = s.client.Templates.ImportIntoProject(ctx, clone, template); err != nil {
+		return clone, err
+	}
+
+	return clone, nil
+}
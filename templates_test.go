@@ -0,0 +1,189 @@
+package todoist
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_Templates(t *testing.T) {
+	client, srv := newFakeClient(t)
+	ctx := context.Background()
+
+	rows := []TemplateRow{
+		{Type: TemplateTask, Content: "Pick a date", Priority: 1, Indent: 1, Date: "next monday", DateLang: "en"},
+		{Type: TemplateTask, Content: "Ask everyone", Priority: 4, Indent: 2},
+		{Type: TemplateSection, Content: "Catering"},
+		{Type: TemplateTask, Content: "Order food", Priority: 3, Indent: 1},
+	}
+
+	projectID, _, err := client.Projects.ImportTemplate(ctx, "", &Template{Rows: rows}, TemplateTarget{ProjectName: "Party"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exported, err := client.Templates.ExportAsFile(ctx, projectID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(exported.Rows, rows) {
+		t.Errorf("expected exported rows\n%+v\nreceived\n%+v", rows, exported.Rows)
+	}
+
+	// Importing the template again adds a copy of every section and task.
+	imported, err := client.Templates.ImportIntoProject(ctx, projectID, exported)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported.Status != "ok" || len(imported.Sections) != 1 || len(imported.Tasks) != 3 {
+		t.Errorf("unexpected import %+v", imported)
+	}
+	if parent := imported.Tasks[1].ParentID; parent == nil || *parent != imported.Tasks[0].ID {
+		t.Errorf("expected the sub-task to be imported under its parent, received %v", parent)
+	}
+	if section := imported.Tasks[2].SectionID; section == nil || *section != imported.Sections[0].ID {
+		t.Errorf("expected the task to be imported into its section, received %v", section)
+	}
+
+	file, err := client.Templates.ExportAsURL(ctx, projectID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file.FileName != "Party.csv" {
+		t.Errorf("unexpected file name %q", file.FileName)
+	}
+
+	resp, err := http.Get(file.FileURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	downloaded, err := ReadTemplate(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(downloaded.Rows) != 2*len(rows) {
+		t.Errorf("expected the download to contain both copies, received\n%+v", downloaded.Rows)
+	}
+
+	if _, err = client.Templates.ExportAsFile(ctx, ProjectID{NewID("999")}); err == nil {
+		t.Error("expected an error for an unknown project")
+	}
+
+	var paths []string
+	for _, r := range srv.Requests() {
+		paths = append(paths, r.Path)
+	}
+	want := []string{"sync", "templates/export_as_file", "templates/import_into_project", "templates/export_as_url", "templates/export_as_file"}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("expected requests %v, received %v", want, paths)
+	}
+}
+
+func Test_Projects_Clone(t *testing.T) {
+	client, _ := newFakeClient(t)
+	ctx := context.Background()
+
+	rows := []TemplateRow{
+		{Type: TemplateTask, Content: "Buy milk", Priority: 4, Indent: 1},
+		{Type: TemplateSection, Content: "Produce"},
+		{Type: TemplateTask, Content: "Apples", Priority: 2, Indent: 1},
+		{Type: TemplateTask, Content: "Green ones", Priority: 4, Indent: 2},
+	}
+
+	original, _, err := client.Projects.ImportTemplate(ctx, "", &Template{Rows: rows}, TemplateTarget{ProjectName: "Groceries"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clone, err := client.Projects.Clone(ctx, original, "Groceries copy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clone == original || clone.IsTemp() {
+		t.Fatalf("expected a new project, received %v", clone)
+	}
+
+	projects, _, err := client.Projects.List(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sections, _, err := client.Sections.List(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tasks, _, err := client.Tasks.List(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, p := range projects {
+		if p.ID != clone {
+			continue
+		}
+		if p.Name != "Groceries copy" {
+			t.Errorf("unexpected name %q", p.Name)
+		}
+
+		got := ExportTemplate(p, sections, tasks).Rows
+		for i := range got {
+			got[i].Author = ""
+		}
+		if !reflect.DeepEqual(got, rows) {
+			t.Errorf("expected the clone to contain\n%+v\nreceived\n%+v", rows, got)
+		}
+		return
+	}
+	t.Error("clone not found")
+}
+
+func Test_Templates_ImportFile(t *testing.T) {
+	var form url.Values
+	var fileName, content string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/sync/v8/templates/import_into_project" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Fatal(err)
+		}
+		form = r.PostForm
+
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := ioutil.ReadAll(file)
+		fileName, content = header.Filename, string(b)
+
+		_, _ = w.Write([]byte(`{"status": "ok", "template_type": "tasks", "projects": [], "sections": [], "tasks": [{"id": 1, "content": "Buy milk"}], "comments": [], "project_notes": []}`))
+	}))
+	defer srv.Close()
+
+	client, err := NewClient("upload-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.BaseURL, _ = url.Parse(srv.URL + "/sync/v8/sync")
+
+	csv := "TYPE,CONTENT\ntask,Buy milk\n"
+	imported, err := client.Templates.ImportFile(context.Background(), ProjectID{NewID("42")}, "groceries.csv", strings.NewReader(csv))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if form.Get("token") != "upload-token" || form.Get("project_id") != "42" {
+		t.Errorf("unexpected form %v", form)
+	}
+	if fileName != "groceries.csv" || content != csv {
+		t.Errorf("unexpected file %q: %q", fileName, content)
+	}
+	if len(imported.Tasks) != 1 || imported.Tasks[0].Content != "Buy milk" {
+		t.Errorf("unexpected import %+v", imported)
+	}
+}
@@ -1,21 +1,29 @@
 package todoist
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"log"
+	"mime/multipart"
 	"net/http"
+	"net/textproto"
 	"net/url"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/pkg/errors"
 )
 
 const (
-	defaultBaseURL = "https://api.todoist.com/sync/v8"
-	userAgent      = "todoist-go/1.0.0"
+	defaultBaseURL     = "https://api.todoist.com/sync"
+	defaultRESTBaseURL = "https://api.todoist.com/rest/v2/"
+	userAgent          = "todoist-go/1.0.0"
 )
 
 // A Client manages communication with the Todoist API.
@@ -26,16 +34,33 @@ type Client struct {
 
 	BaseURL *url.URL // Base URL for API endpoints. Defaults to the public Todoist API (Sync API).
 
+	RESTBaseURL *url.URL // Base URL for REST API endpoints, with a trailing slash. Defaults to the public Todoist REST API (v2).
+
 	APIToken string // API Token for authenticating API calls. Found in the Integrations tab of the Todoist user settings.
 
+	tokenSource TokenSource // Source of OAuth tokens, used instead of APIToken if set.
+
 	userAgent string // User agent used when communicating with the Todoist API.
 
+	version APIVersion // Version of the Sync API the client talks to.
+
+	retry RetryPolicy // Policy for retrying rate limited and failed requests.
+
 	common service // Reuse a single struct instead of allocating one for each service on the heap.
 
+	tempIDsMu sync.Mutex // Guards tempIDs.
+	tempIDs   tempIDMap  // Real IDs of resources created by earlier commands, keyed by temp ID.
+
 	// Services used for talking to different parts of the Todoist API.
-	Projects *ProjectsService
-	Sections *SectionsService
-	Tasks    *TasksService
+	Projects  *ProjectsService
+	Sections  *SectionsService
+	Tasks     *TasksService
+	Templates *TemplatesService
+	Backups   *BackupsService
+	Uploads   *UploadsService
+
+	// Services used for talking to the REST API.
+	REST *RESTService
 }
 
 // Logf logs a format string and values to output if the client's debug mode is set to true.
@@ -60,32 +85,58 @@ func (c *Client) SetHTTPClient(client *http.Client) {
 	c.client = client
 }
 
+// endpointURL returns the URL of a Sync API endpoint other than /sync (such as
+// "projects/get"), resolved relative to the client's BaseURL so that requests
+// follow the client to whichever server it is pointed at.
+func (c *Client) endpointURL(endpoint string) *url.URL {
+	return c.BaseURL.ResolveReference(&url.URL{Path: endpoint})
+}
+
 type service struct {
 	client *Client
 }
 
-// NewClient returns a new Todoist API client.
-func NewClient(apiToken string) (*Client, error) {
-	if apiToken == "" {
-		return nil, errors.New("apiToken cannot be empty")
-	}
-
-	baseURL, _ := url.Parse(defaultBaseURL + "/sync")
-
+// NewClient returns a new Todoist API client. Options are applied in order.
+// apiToken may be empty if a token source is set with WithTokenSource.
+func NewClient(apiToken string, opts ...ClientOption) (*Client, error) {
 	c := &Client{
 		client:    &http.Client{},
-		BaseURL:   baseURL,
 		APIToken:  apiToken,
 		userAgent: userAgent,
 		debug:     false,
+		version:   APIVersionV8,
+	}
+
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return nil, err
+		}
+	}
+
+	if apiToken == "" && c.tokenSource == nil {
+		return nil, errors.New("apiToken cannot be empty")
 	}
 
+	c.BaseURL, _ = url.Parse(defaultBaseURL + "/" + string(c.version) + "/sync")
+	c.RESTBaseURL, _ = url.Parse(defaultRESTBaseURL)
+
 	c.common.client = c
 
 	// c.Projects = (*ProjectsService)(&c.common)
 	c.Projects = &ProjectsService{client: c}
 	c.Sections = &SectionsService{client: c}
 	c.Tasks = &TasksService{client: c}
+	c.Templates = &TemplatesService{client: c}
+	c.Backups = &BackupsService{client: c}
+	c.Uploads = &UploadsService{client: c}
+
+	c.REST = &RESTService{
+		Tasks:    &RESTTasksService{client: c},
+		Projects: &RESTProjectsService{client: c},
+		Sections: &RESTSectionsService{client: c},
+		Labels:   &RESTLabelsService{client: c},
+		Comments: &RESTCommentsService{client: c},
+	}
 
 	return c, nil
 }
@@ -98,6 +149,21 @@ type Command struct {
 	TempID string      `json:"temp_id"`
 }
 
+// commandBatch builds the commands of a single request, such as the ones
+// importing a template, where later commands refer to the resources added
+// by earlier ones through their temp IDs.
+type commandBatch struct {
+	commands []Command
+}
+
+// add appends a command with a new UUID and temp ID, and returns the temp ID.
+func (b *commandBatch) add(commandType string, args interface{}) string {
+	tempID := uuid.New().String()
+	b.commands = append(b.commands, Command{Type: commandType, Args: args, UUID: uuid.New().String(), TempID: tempID})
+
+	return tempID
+}
+
 // NewRequest creates an API request. If specified, the value pointed to
 // by body is JSON encoded and included as the request body.
 func (c *Client) NewRequest(syncToken string, resourceTypes []string, commands []Command) (*http.Request, error) {
@@ -116,6 +182,22 @@ func (c *Client) NewRequest(syncToken string, resourceTypes []string, commands [
 	form.Add("resource_types", resourceTypesStr)
 
 	if len(commands) != 0 {
+		// Replace temp IDs created by earlier requests with their real IDs,
+		// since Todoist only resolves temp IDs within a single request. Temp
+		// IDs defined again by these commands refer to the new resources.
+		c.forgetTempIDs(commands)
+		resolved := make([]Command, len(commands))
+		for i, cmd := range commands {
+			args, err := c.argsForVersion(c.resolveTempIDs(cmd.Args))
+			if err != nil {
+				return nil, errors.Wrap(err, fmt.Sprintf("unable to serialize command args: %v", cmd.Args))
+			}
+
+			cmd.Args = args
+			resolved[i] = cmd
+		}
+		commands = resolved
+
 		commandsBytes, err := json.Marshal(commands)
 		if err != nil {
 			return nil, errors.Wrap(err, fmt.Sprintf("unable to serialize commands: %v", commands))
@@ -124,7 +206,11 @@ func (c *Client) NewRequest(syncToken string, resourceTypes []string, commands [
 		form.Add("commands", commandsStr)
 	}
 
-	form.Add("token", c.APIToken)
+	token, err := c.token()
+	if err != nil {
+		return nil, err
+	}
+	form.Add("token", token)
 
 	for k := range form {
 		c.Logf("%-15s %-30s\n", k, form.Get(k))
@@ -144,23 +230,147 @@ func (c *Client) NewRequest(syncToken string, resourceTypes []string, commands [
 	return req, nil
 }
 
+// newEndpointRequest creates a request for a Sync API endpoint other than
+// /sync (such as "quick/add"), sending form along with the client's token.
+func (c *Client) newEndpointRequest(endpoint string, form url.Values) (*http.Request, error) {
+	token, err := c.token()
+	if err != nil {
+		return nil, err
+	}
+	form.Set("token", token)
+
+	for k := range form {
+		c.Logf("%-15s %-30s\n", k, form.Get(k))
+	}
+	c.Logln()
+
+	req, err := http.NewRequest(http.MethodPost, c.endpointURL(endpoint).String(), strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+
+	return req, nil
+}
+
+// newUploadRequest creates a multipart request for a Sync API endpoint that
+// takes a file (such as "uploads/add"), sending form and the client's token
+// along with the contents of file under the "file" field, with the given MIME
+// type (application/octet-stream if empty).
+//
+// The file is streamed as the request is sent rather than read up front, so
+// the request must be sent for file to be read. Errors reading file fail the
+// request, and since the file cannot be read again, upload requests are not
+// retried.
+func (c *Client) newUploadRequest(endpoint string, form url.Values, fileName, contentType string, file io.Reader) (*http.Request, error) {
+	token, err := c.token()
+	if err != nil {
+		return nil, err
+	}
+	form.Set("token", token)
+
+	for k := range form {
+		c.Logf("%-15s %-30s\n", k, form.Get(k))
+	}
+	c.Logf("%-15s %-30s\n", "file", fileName)
+	c.Logln()
+
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+	body := &uploadBody{
+		pr: pr,
+		write: func() {
+			pw.CloseWithError(writeUpload(mw, form, fileName, contentType, file))
+		},
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.endpointURL(endpoint).String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+
+	return req, nil
+}
+
+// uploadBody is the body of an upload request. The multipart body is written
+// to the pipe by a goroutine started on the first Read, so that requests that
+// are never sent do not leave it blocked, and closing the body stops it.
+type uploadBody struct {
+	start sync.Once
+	pr    *io.PipeReader
+	write func()
+}
+
+func (b *uploadBody) Read(p []byte) (int, error) {
+	b.start.Do(func() {
+		go b.write()
+	})
+
+	return b.pr.Read(p)
+}
+
+func (b *uploadBody) Close() error {
+	return b.pr.Close()
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// writeUpload writes the multipart body of an upload request.
+func writeUpload(mw *multipart.Writer, form url.Values, fileName, contentType string, file io.Reader) error {
+	for k, values := range form {
+		for _, v := range values {
+			if err := mw.WriteField(k, v); err != nil {
+				return err
+			}
+		}
+	}
+
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename="%s"`, quoteEscaper.Replace(fileName)))
+	header.Set("Content-Type", contentType)
+
+	part, err := mw.CreatePart(header)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(part, file); err != nil {
+		return errors.Wrap(err, "unable to read file")
+	}
+
+	return mw.Close()
+}
+
 // TODO: find out if I really need a ReadResponse and CommandResponse, and if I can just combine them.
 
 // ReadResponse is a Todoist API response for a read request.
 type ReadResponse struct {
-	FullSync      bool           `json:"full_sync"`
-	SyncToken     string         `json:"sync_token"`
-	TempIDMapping map[string]int `json:"temp_id_mapping"`
+	FullSync      bool          `json:"full_sync"`
+	SyncToken     string        `json:"sync_token"`
+	TempIDMapping map[string]ID `json:"temp_id_mapping"`
 
 	Projects []Project `json:"projects"`
 	Sections []Section `json:"sections"`
 	Tasks    []Task    `json:"items"`
-	// notes	An array of item note objects.
-	// project_notes	An array of project note objects.
-	// labels	An array of label objects.
-	// filters	A array of filter objects.
+	User     *User     `json:"user"`
+	Labels   []Label   `json:"labels"`
+
+	Notes        []Comment  `json:"notes"`
+	ProjectNotes []Comment  `json:"project_notes"`
+	Filters      []Filter   `json:"filters"`
+	Reminders    []Reminder `json:"reminders"`
 	// day_orders	A JSON object specifying the order of items in daily agenda.
-	// reminders	An array of reminder objects.
 	// collaborators	A JSON object containing all collaborators for all shared projects. The projects field contains the list of all shared projects, where the user acts as one of collaborators.
 	// collaborators_states	An array specifying the state of each collaborator in each project. The state can be invited, active, inactive, deleted.
 	// live_notifications	An array of live_notification objects
@@ -174,11 +384,12 @@ type CommandResponse struct {
 	FullSync      bool                   `json:"full_sync"`
 	SyncToken     string                 `json:"sync_token"`
 	SyncStatus    map[string]interface{} `json:"sync_status"`
-	TempIDMapping map[string]int         `json:"temp_id_mapping"`
+	TempIDMapping map[string]ID          `json:"temp_id_mapping"`
 
 	Projects []Project `json:"projects"`
 	Sections []Section `json:"sections"`
 	Tasks    []Task    `json:"items"`
+	Notes    []Comment `json:"notes"`
 }
 
 // Do sends an API request and returns the API response. The API response is
@@ -187,6 +398,9 @@ type CommandResponse struct {
 // interface, the raw response body will be written to v, without attempting to
 // first decode it.
 //
+// Requests that fail because of a rate limit or a transient server error are
+// retried according to the client's RetryPolicy.
+//
 // The provided ctx must be non-nil, if it is nil an error is returned. If it is canceled or times out,
 // ctx.Err() will be returned.
 func (c *Client) Do(ctx context.Context, req *http.Request, v interface{}) (*http.Response, error) {
@@ -195,6 +409,56 @@ func (c *Client) Do(ctx context.Context, req *http.Request, v interface{}) (*htt
 	}
 	req = req.WithContext(ctx)
 
+	// Buffer the body so that it can be resent, since some requests have their
+	// body replaced after NewRequest, leaving a stale GetBody. Upload bodies
+	// are streamed and left without GetBody, so uploads are not retried.
+	if _, upload := req.Body.(*uploadBody); c.retry.MaxRetries > 0 && !upload && req.Body != nil && req.Body != http.NoBody {
+		body, err := ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+		req.GetBody = func() (io.ReadCloser, error) {
+			return ioutil.NopCloser(bytes.NewReader(body)), nil
+		}
+	}
+
+	for retry := 0; ; retry++ {
+		resp, err := c.do(ctx, req, v)
+		if retry >= c.retry.MaxRetries || !shouldRetry(resp) {
+			return resp, err
+		}
+
+		// The body of the previous attempt has been consumed.
+		if req.Body != nil {
+			if req.GetBody == nil {
+				return resp, err
+			}
+
+			body, bodyErr := req.GetBody()
+			if bodyErr != nil {
+				return resp, err
+			}
+			req.Body = body
+		}
+
+		wait := c.retry.backoff(retry, resp, err)
+		c.Logf("retrying %s %s in %s (retry %d of %d): %v\n", req.Method, req.URL, wait, retry+1, c.retry.MaxRetries, err)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return resp, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// do sends a single attempt of an API request for Do.
+func (c *Client) do(ctx context.Context, req *http.Request, v interface{}) (*http.Response, error) {
 	resp, err := c.client.Do(req)
 	if err != nil {
 		// If we got an error, and the context has been canceled,
@@ -211,6 +475,12 @@ func (c *Client) Do(ctx context.Context, req *http.Request, v interface{}) (*htt
 	defer resp.Body.Close()
 
 	err = checkResponseForErrors(resp, v)
+
+	// Remember the IDs of created resources even if some commands failed.
+	if cr, ok := v.(*CommandResponse); ok {
+		c.recordTempIDs(cr.TempIDMapping)
+	}
+
 	if err != nil {
 		return resp, err
 	}
@@ -7,24 +7,117 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"path/filepath"
 	"reflect"
-	"strconv"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/ides15/todoist/todoisttest"
 )
 
 var (
 	// Get the Todoist API token from an environment variable
 	apiToken = os.Getenv("TODOIST_API_TOKEN")
+
+	// Set TODOIST_RECORD to regenerate the cassettes in testdata/cassettes,
+	// from the fake server, or from the live API with TODOIST_API_TOKEN
+	record = os.Getenv("TODOIST_RECORD") != ""
 )
 
-func Test_Projects(t *testing.T) {
-	// Create the client to interact with Todoist
-	client, err := NewClient(apiToken)
+// newTestClient returns a client for the flow tests. Without an API token, the
+// client replays the test's cassette from testdata/cassettes if there is one,
+// and talks to an in-process fake server otherwise. With an API token, it talks
+// to the live Todoist API. When recording, every exchange with the fake server
+// or the live API is written to the test's cassette once the test has passed.
+func newTestClient(t *testing.T) *Client {
+	t.Helper()
+
+	path := filepath.Join("testdata", "cassettes", t.Name()+".json")
+
+	if apiToken == "" && !record {
+		if cassette, err := todoisttest.LoadCassette(path); err == nil {
+			client, err := NewClient("REDACTED")
+			if err != nil {
+				t.Fatal(err)
+			}
+			client.SetHTTPClient(&http.Client{Transport: todoisttest.NewReplayer(cassette)})
+
+			return client
+		}
+	}
+
+	var client *Client
+	var err error
+	if apiToken != "" {
+		client, err = NewClient(apiToken)
+		if err != nil {
+			t.Fatal(err)
+		}
+	} else {
+		client, _ = newFakeClient(t)
+	}
+
+	if record {
+		rec := todoisttest.NewRecorder(nil)
+		client.SetHTTPClient(&http.Client{Transport: rec})
+
+		t.Cleanup(func() {
+			if t.Failed() {
+				return
+			}
+			if err := rec.Cassette().Save(path); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+
+	return client
+}
+
+// newFakeClient returns a client talking to an in-process fake server, along
+// with the server.
+func newFakeClient(t *testing.T) (*Client, *todoisttest.Server) {
+	t.Helper()
+
+	srv := todoisttest.NewServer()
+	t.Cleanup(srv.Close)
+
+	client, err := NewClient(srv.Token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client.BaseURL, err = url.Parse(srv.SyncURL())
 	if err != nil {
 		t.Fatal(err)
 	}
+
+	return client, srv
+}
+
+// inboxProjectID returns the ID of the user's Inbox project.
+func inboxProjectID(t *testing.T, client *Client) ProjectID {
+	t.Helper()
+
+	projects, _, err := client.Projects.List(context.Background(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, p := range projects {
+		if p.InboxProject != nil && *p.InboxProject {
+			return p.ID
+		}
+	}
+
+	t.Fatal("no inbox project found")
+	return ProjectID{}
+}
+
+func Test_Projects(t *testing.T) {
+	// Create the client to interact with Todoist
+	client := newTestClient(t)
 	client.SetDebug(false)
 
 	// List all projects
@@ -44,10 +137,11 @@ func Test_Projects(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	parentProjectID := strconv.Itoa(int(resp.TempIDMapping[parentProjectTempID]))
+	// The real ID can be read from the response's temp ID mapping...
+	parentProjectID := ProjectID{ID: resp.TempIDMapping[parentProjectTempID]}
 
 	childProject1TempID := "project2"
-	_, resp, err = client.Projects.Add(context.Background(), "", AddProject{
+	_, _, err = client.Projects.Add(context.Background(), "", AddProject{
 		Name:   "Child Project 1",
 		TempID: childProject1TempID,
 	})
@@ -55,10 +149,12 @@ func Test_Projects(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	childProject1ID := strconv.Itoa(int(resp.TempIDMapping[childProject1TempID]))
+	// ...or the temp ID can be used directly, in which case the client
+	// resolves it to the real ID in later requests
+	childProject1ID := ProjectID{ID: NewTempID(childProject1TempID)}
 
 	childProject2TempID := "project3"
-	_, resp, err = client.Projects.Add(context.Background(), "", AddProject{
+	_, _, err = client.Projects.Add(context.Background(), "", AddProject{
 		Name:   "Child Project 2",
 		TempID: childProject2TempID,
 	})
@@ -66,11 +162,10 @@ func Test_Projects(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	childProject2ID := strconv.Itoa(int(resp.TempIDMapping[childProject2TempID]))
+	childProject2ID := ProjectID{ID: NewTempID(childProject2TempID)}
 
 	// Update the project we just added
 	_, _, err = client.Projects.Update(context.Background(), "", UpdateProject{
-		// get the temp ID of the project we just added so we can update the title
 		ID:   parentProjectID,
 		Name: "Updated Project 1",
 	})
@@ -81,7 +176,7 @@ func Test_Projects(t *testing.T) {
 	// Make project 2 a child of project 1
 	_, _, err = client.Projects.Move(context.Background(), "", MoveProject{
 		ID:       childProject1ID,
-		ParentID: parentProjectID,
+		ParentID: &parentProjectID,
 	})
 	if err != nil {
 		t.Fatal(err)
@@ -90,7 +185,7 @@ func Test_Projects(t *testing.T) {
 	// Make project 3 a child of project 1
 	_, _, err = client.Projects.Move(context.Background(), "", MoveProject{
 		ID:       childProject2ID,
-		ParentID: parentProjectID,
+		ParentID: &parentProjectID,
 	})
 	if err != nil {
 		t.Fatal(err)
@@ -148,8 +243,12 @@ func Test_Projects(t *testing.T) {
 
 	// Cleanup
 	for _, project := range projects {
+		if project.InboxProject != nil && *project.InboxProject {
+			continue
+		}
+
 		if _, _, err = client.Projects.Delete(context.Background(), "", DeleteProject{
-			ID: strconv.Itoa(project.ID),
+			ID: project.ID,
 		}); err != nil {
 			t.Fatal(err)
 		}
@@ -157,7 +256,7 @@ func Test_Projects(t *testing.T) {
 
 	for _, archivedProject := range archivedProejcts {
 		if _, _, err = client.Projects.Delete(context.Background(), "", DeleteProject{
-			ID: strconv.Itoa(archivedProject.ID),
+			ID: archivedProject.ID,
 		}); err != nil {
 			t.Fatal(err)
 		}
@@ -165,16 +264,13 @@ func Test_Projects(t *testing.T) {
 }
 
 func Test_Sections(t *testing.T) {
-	client, err := NewClient(apiToken)
-	if err != nil {
-		t.Fatal(err)
-	}
+	client := newTestClient(t)
 	client.SetDebug(false)
 
 	tempInboxSectionID := "inboxSectionID"
-	_, resp, err := client.Sections.Add(context.Background(), "", AddSection{
+	_, _, err := client.Sections.Add(context.Background(), "", AddSection{
 		Name:         "New Inbox section",
-		ProjectID:    2252888543, // Inbox project
+		ProjectID:    inboxProjectID(t, client),
 		SectionOrder: 0,
 		TempID:       tempInboxSectionID,
 	})
@@ -182,7 +278,7 @@ func Test_Sections(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	inboxSectionID := strconv.Itoa(int(resp.TempIDMapping[tempInboxSectionID]))
+	inboxSectionID := SectionID{ID: NewTempID(tempInboxSectionID)}
 
 	_, _, err = client.Sections.Update(context.Background(), "", UpdateSection{
 		ID:        inboxSectionID,
@@ -194,7 +290,7 @@ func Test_Sections(t *testing.T) {
 	}
 
 	tempSectionMoveProjectID := "sectionMoveProjectID"
-	_, resp, err = client.Projects.Add(context.Background(), "", AddProject{
+	_, resp, err := client.Projects.Add(context.Background(), "", AddProject{
 		Name:   "Section Move test",
 		TempID: tempSectionMoveProjectID,
 	})
@@ -202,12 +298,11 @@ func Test_Sections(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	sectionMoveProjectID := resp.TempIDMapping[tempSectionMoveProjectID]
-	strSectionMoveProjectID := strconv.Itoa(int(resp.TempIDMapping[tempSectionMoveProjectID]))
+	sectionMoveProjectID := ProjectID{ID: resp.TempIDMapping[tempSectionMoveProjectID]}
 
 	_, _, err = client.Sections.Move(context.Background(), "", MoveSection{
 		ID:        inboxSectionID,
-		ProjectID: strSectionMoveProjectID,
+		ProjectID: sectionMoveProjectID,
 	})
 	if err != nil {
 		t.Fatal(err)
@@ -223,7 +318,7 @@ func Test_Sections(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	sectionReorderSectionID := strconv.Itoa(int(resp.TempIDMapping[tempSectionReorderSectionID]))
+	sectionReorderSectionID := SectionID{ID: resp.TempIDMapping[tempSectionReorderSectionID]}
 
 	_, _, err = client.Sections.Reorder(context.Background(), "", ReorderSections{
 		Sections: []ReorderedSection{
@@ -257,7 +352,7 @@ func Test_Sections(t *testing.T) {
 
 	// Cleanup
 	_, _, err = client.Projects.Delete(context.Background(), "", DeleteProject{
-		ID: strSectionMoveProjectID,
+		ID: sectionMoveProjectID,
 	})
 	if err != nil {
 		t.Fatal(err)
@@ -270,7 +365,7 @@ func Test_Sections(t *testing.T) {
 
 	for _, section := range sections {
 		_, _, err = client.Sections.Delete(context.Background(), "", DeleteSection{
-			ID: strconv.Itoa(section.ID),
+			ID: section.ID,
 		})
 		if err != nil {
 			t.Fatal(err)
@@ -279,14 +374,11 @@ func Test_Sections(t *testing.T) {
 }
 
 func Test_Tasks(t *testing.T) {
-	client, err := NewClient(apiToken)
-	if err != nil {
-		t.Fatal(err)
-	}
+	client := newTestClient(t)
 	client.SetDebug(true)
 
 	// TODO - add more testing for Add
-	_, _, err = client.Tasks.Add(context.Background(), "", AddTask{
+	_, _, err := client.Tasks.Add(context.Background(), "", AddTask{
 		Content:     "New task content",
 		Description: "New task description",
 		Priority:    4,
@@ -480,3 +572,91 @@ func Test_NewRequest(t *testing.T) {
 		t.Errorf("expected err in new request, received nil")
 	}
 }
+
+func Test_Tasks_Changes(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newFakeClient(t)
+	inbox := inboxProjectID(t, client)
+
+	_, resp, err := client.Tasks.Add(ctx, "", AddTask{Content: "Buy milk", ProjectID: &inbox, TempID: "milk"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = client.Tasks.Add(ctx, resp.SyncToken, AddTask{Content: "Buy bread", ProjectID: &inbox, TempID: "bread"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	milk := TaskID{client.ResolveID(NewTempID("milk"))}
+	bread := TaskID{client.ResolveID(NewTempID("bread"))}
+
+	find := func(tasks []Task, id TaskID) *Task {
+		for i := range tasks {
+			if tasks[i].ID == id {
+				return &tasks[i]
+			}
+		}
+		return nil
+	}
+
+	tasks, _, err := client.Tasks.Update(ctx, "", UpdateTask{ID: milk, Content: "Buy oat milk", Priority: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task := find(tasks, milk); task == nil || task.Content != "Buy oat milk" || task.Priority != 4 {
+		t.Errorf("unexpected task %+v", task)
+	}
+
+	tasks, _, err = client.Tasks.Move(ctx, "", MoveTask{ID: bread, ParentID: &milk})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task := find(tasks, bread); task == nil || task.ParentID == nil || *task.ParentID != milk {
+		t.Errorf("expected a sub-task of %s, received %+v", milk, task)
+	}
+
+	// Completing a task completes its sub-tasks, which a full sync leaves out.
+	tasks, _, err = client.Tasks.Complete(ctx, "", CompleteTask{ID: milk})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if find(tasks, milk) != nil || find(tasks, bread) != nil {
+		t.Errorf("expected the tasks to be completed, received %+v", tasks)
+	}
+
+	if _, _, err = client.Tasks.Delete(ctx, "", DeleteTask{ID: milk}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err = client.Tasks.Update(ctx, "", UpdateTask{ID: bread, Content: "Gone"}); err == nil {
+		t.Error("expected an error updating a deleted task")
+	}
+}
+
+func Test_Tasks_Reorder(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newFakeClient(t)
+
+	_, _, err := client.Tasks.Add(ctx, "", AddTask{Content: "First", ChildOrder: 1, TempID: "first"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = client.Tasks.Add(ctx, "", AddTask{Content: "Second", ChildOrder: 2, TempID: "second"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tasks, _, err := client.Tasks.Reorder(ctx, "", ReorderTasks{Tasks: []ReorderedTask{
+		{ID: TaskID{NewTempID("second")}, ChildOrder: 1},
+		{ID: TaskID{NewTempID("first")}, ChildOrder: 2},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	orders := map[string]int{}
+	for _, task := range tasks {
+		orders[task.Content] = task.ChildOrder
+	}
+	if orders["First"] != 2 || orders["Second"] != 1 {
+		t.Errorf("unexpected orders %v", orders)
+	}
+}
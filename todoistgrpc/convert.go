@@ -0,0 +1,344 @@
+package todoistgrpc
+
+import (
+	"github.com/ides15/todoist"
+)
+
+// ProjectToProto converts a project to its proto message. Legacy IDs are not
+// converted.
+func ProjectToProto(p todoist.Project) *Project {
+	return &Project{
+		Id:           p.ID.String(),
+		Name:         p.Name,
+		Color:        string(p.Color),
+		ParentId:     projectIDString(p.ParentID),
+		ChildOrder:   int32(p.ChildOrder),
+		Collapsed:    bool(p.Collapsed),
+		Shared:       p.Shared,
+		IsDeleted:    bool(p.IsDeleted),
+		IsArchived:   bool(p.IsArchived),
+		IsFavorite:   bool(p.IsFavorite),
+		SyncId:       idString(p.SyncID),
+		ViewStyle:    p.ViewStyle,
+		InboxProject: p.InboxProject != nil && *p.InboxProject,
+		TeamInbox:    p.TeamInbox != nil && *p.TeamInbox,
+	}
+}
+
+// ProjectFromProto converts a proto message to a project.
+func ProjectFromProto(p *Project) todoist.Project {
+	if p == nil {
+		return todoist.Project{}
+	}
+
+	return todoist.Project{
+		ID:           todoist.ProjectID{ID: todoist.NewID(p.Id)},
+		Name:         p.Name,
+		Color:        todoist.Color(p.Color),
+		ParentID:     projectID(p.ParentId),
+		ChildOrder:   int(p.ChildOrder),
+		Collapsed:    todoist.Bool(p.Collapsed),
+		Shared:       p.Shared,
+		IsDeleted:    todoist.Bool(p.IsDeleted),
+		IsArchived:   todoist.Bool(p.IsArchived),
+		IsFavorite:   todoist.Bool(p.IsFavorite),
+		SyncID:       newID(p.SyncId),
+		ViewStyle:    p.ViewStyle,
+		InboxProject: trueOrNil(p.InboxProject),
+		TeamInbox:    trueOrNil(p.TeamInbox),
+	}
+}
+
+// SectionToProto converts a section to its proto message. Legacy IDs are not
+// converted.
+func SectionToProto(s todoist.Section) *Section {
+	return &Section{
+		Id:           s.ID.String(),
+		Name:         s.Name,
+		ProjectId:    s.ProjectID.String(),
+		SectionOrder: int32(s.SectionOrder),
+		Collapsed:    s.Collapsed,
+		SyncId:       idString(s.SyncID),
+		IsDeleted:    s.IsDeleted,
+		IsArchived:   s.IsArchived,
+		DateArchived: stringValue(s.DateArchived),
+		DateAdded:    s.DateAdded,
+	}
+}
+
+// SectionFromProto converts a proto message to a section.
+func SectionFromProto(s *Section) todoist.Section {
+	if s == nil {
+		return todoist.Section{}
+	}
+
+	return todoist.Section{
+		ID:           todoist.SectionID{ID: todoist.NewID(s.Id)},
+		Name:         s.Name,
+		ProjectID:    todoist.ProjectID{ID: todoist.NewID(s.ProjectId)},
+		SectionOrder: int(s.SectionOrder),
+		Collapsed:    s.Collapsed,
+		SyncID:       newID(s.SyncId),
+		IsDeleted:    s.IsDeleted,
+		IsArchived:   s.IsArchived,
+		DateArchived: stringPtr(s.DateArchived),
+		DateAdded:    s.DateAdded,
+	}
+}
+
+// TaskToProto converts a task to its proto message. Legacy IDs are not
+// converted.
+func TaskToProto(t todoist.Task) *Task {
+	return &Task{
+		Id:             t.ID.String(),
+		UserId:         t.UserID.String(),
+		ProjectId:      t.ProjectID.String(),
+		Content:        t.Content,
+		Description:    t.Description,
+		Due:            DueToProto(t.Due),
+		Priority:       int32(t.Priority),
+		ParentId:       taskIDString(t.ParentID),
+		ChildOrder:     int32(t.ChildOrder),
+		SectionId:      sectionIDString(t.SectionID),
+		DayOrder:       int32(t.DayOrder),
+		Collapsed:      bool(t.Collapsed),
+		Labels:         labelStrings(t.Labels),
+		AddedByUid:     userIDString(t.AddedByUID),
+		AssignedByUid:  userIDString(t.AssignedByUID),
+		ResponsibleUid: userIDString(t.ResponsibleUID),
+		Checked:        bool(t.Checked),
+		InHistory:      bool(t.InHistory),
+		IsDeleted:      bool(t.IsDeleted),
+		SyncId:         idString(t.SyncID),
+		DateCompleted:  stringValue(t.DateCompleted),
+		DateAdded:      t.DateAdded,
+	}
+}
+
+// TaskFromProto converts a proto message to a task.
+func TaskFromProto(t *Task) todoist.Task {
+	if t == nil {
+		return todoist.Task{}
+	}
+
+	return todoist.Task{
+		ID:             todoist.TaskID{ID: todoist.NewID(t.Id)},
+		UserID:         todoist.UserID{ID: todoist.NewID(t.UserId)},
+		ProjectID:      todoist.ProjectID{ID: todoist.NewID(t.ProjectId)},
+		Content:        t.Content,
+		Description:    t.Description,
+		Due:            DueFromProto(t.Due),
+		Priority:       int(t.Priority),
+		ParentID:       taskID(t.ParentId),
+		ChildOrder:     int(t.ChildOrder),
+		SectionID:      sectionID(t.SectionId),
+		DayOrder:       int(t.DayOrder),
+		Collapsed:      todoist.Bool(t.Collapsed),
+		Labels:         labelIDs(t.Labels),
+		AddedByUID:     userID(t.AddedByUid),
+		AssignedByUID:  userID(t.AssignedByUid),
+		ResponsibleUID: userID(t.ResponsibleUid),
+		Checked:        todoist.Bool(t.Checked),
+		InHistory:      todoist.Bool(t.InHistory),
+		IsDeleted:      todoist.Bool(t.IsDeleted),
+		SyncID:         newID(t.SyncId),
+		DateCompleted:  stringPtr(t.DateCompleted),
+		DateAdded:      t.DateAdded,
+	}
+}
+
+// DueToProto converts a due date to its proto message. A nil due date
+// converts to nil.
+func DueToProto(d *todoist.Due) *Due {
+	if d == nil {
+		return nil
+	}
+
+	return &Due{
+		Date:        d.Date,
+		Timezone:    stringValue(d.Timezone),
+		String_:     d.String,
+		Lang:        d.Lang,
+		IsRecurring: d.IsRecurring,
+		Datetime:    d.Datetime,
+	}
+}
+
+// DueFromProto converts a proto message to a due date. A nil message converts
+// to nil.
+func DueFromProto(d *Due) *todoist.Due {
+	if d == nil {
+		return nil
+	}
+
+	return &todoist.Due{
+		Date:        d.Date,
+		Timezone:    stringPtr(d.Timezone),
+		String:      d.String_,
+		Lang:        d.Lang,
+		IsRecurring: d.IsRecurring,
+		Datetime:    d.Datetime,
+	}
+}
+
+var watchEventTypes = map[todoist.WatchEventType]WatchEvent_Type{
+	todoist.WatchCreated:   WatchEvent_CREATED,
+	todoist.WatchUpdated:   WatchEvent_UPDATED,
+	todoist.WatchDeleted:   WatchEvent_DELETED,
+	todoist.WatchCompleted: WatchEvent_COMPLETED,
+	todoist.WatchError:     WatchEvent_ERROR,
+}
+
+// WatchEventToProto converts a watch event to its proto message.
+func WatchEventToProto(e todoist.WatchEvent) *WatchEvent {
+	event := &WatchEvent{
+		Type:      watchEventTypes[e.Type],
+		SyncToken: e.SyncToken,
+	}
+
+	switch {
+	case e.Project != nil:
+		event.Resource = &WatchEvent_Project{Project: ProjectToProto(*e.Project)}
+	case e.Section != nil:
+		event.Resource = &WatchEvent_Section{Section: SectionToProto(*e.Section)}
+	case e.Task != nil:
+		event.Resource = &WatchEvent_Task{Task: TaskToProto(*e.Task)}
+	}
+	if e.Err != nil {
+		event.Error = e.Err.Error()
+	}
+
+	return event
+}
+
+func idString(id *todoist.ID) string {
+	if id == nil {
+		return ""
+	}
+
+	return id.String()
+}
+
+func projectIDString(id *todoist.ProjectID) string {
+	if id == nil {
+		return ""
+	}
+
+	return id.String()
+}
+
+func sectionIDString(id *todoist.SectionID) string {
+	if id == nil {
+		return ""
+	}
+
+	return id.String()
+}
+
+func taskIDString(id *todoist.TaskID) string {
+	if id == nil {
+		return ""
+	}
+
+	return id.String()
+}
+
+func userIDString(id *todoist.UserID) string {
+	if id == nil {
+		return ""
+	}
+
+	return id.String()
+}
+
+func newID(s string) *todoist.ID {
+	if s == "" {
+		return nil
+	}
+
+	id := todoist.NewID(s)
+
+	return &id
+}
+
+func projectID(s string) *todoist.ProjectID {
+	if s == "" {
+		return nil
+	}
+
+	return &todoist.ProjectID{ID: todoist.NewID(s)}
+}
+
+func sectionID(s string) *todoist.SectionID {
+	if s == "" {
+		return nil
+	}
+
+	return &todoist.SectionID{ID: todoist.NewID(s)}
+}
+
+func taskID(s string) *todoist.TaskID {
+	if s == "" {
+		return nil
+	}
+
+	return &todoist.TaskID{ID: todoist.NewID(s)}
+}
+
+func userID(s string) *todoist.UserID {
+	if s == "" {
+		return nil
+	}
+
+	return &todoist.UserID{ID: todoist.NewID(s)}
+}
+
+func labelStrings(labels []todoist.LabelID) []string {
+	if labels == nil {
+		return nil
+	}
+
+	s := make([]string, len(labels))
+	for i, label := range labels {
+		s[i] = label.String()
+	}
+
+	return s
+}
+
+func labelIDs(labels []string) []todoist.LabelID {
+	if labels == nil {
+		return nil
+	}
+
+	ids := make([]todoist.LabelID, len(labels))
+	for i, label := range labels {
+		ids[i] = todoist.LabelID{ID: todoist.NewID(label)}
+	}
+
+	return ids
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+
+	return *s
+}
+
+func stringPtr(s string) *string {
+	if s == "" {
+		return nil
+	}
+
+	return &s
+}
+
+func trueOrNil(b bool) *bool {
+	if !b {
+		return nil
+	}
+
+	return &b
+}
@@ -0,0 +1,21 @@
+module github.com/ides15/todoist/todoistgrpc
+
+go 1.17
+
+require (
+	github.com/google/uuid v1.1.2
+	github.com/ides15/todoist v0.0.0
+	github.com/pkg/errors v0.9.1
+	google.golang.org/grpc v1.50.1
+	google.golang.org/protobuf v1.28.1
+)
+
+require (
+	github.com/golang/protobuf v1.5.2 // indirect
+	golang.org/x/net v0.0.0-20201021035429-f5854403a974 // indirect
+	golang.org/x/sys v0.0.0-20210119212857-b64e53b001e4 // indirect
+	golang.org/x/text v0.3.3 // indirect
+	google.golang.org/genproto v0.0.0-20200526211855-cb27e3aa2013 // indirect
+)
+
+replace github.com/ides15/todoist => ../
@@ -0,0 +1,554 @@
+// Package todoistgrpc serves the projects, sections and tasks of a Todoist
+// account over gRPC.
+//
+// The Todoist service is described by todoist.proto, from which the messages
+// and the client and server stubs of this package are generated. Server
+// implements it by delegating to the ProjectsService, SectionsService and
+// TasksService of a todoist.Client, converting between the proto messages and
+// the library's structs with the ProjectToProto, ProjectFromProto (and so on)
+// functions. Its Watch RPC streams the changes found by Client.Watch.
+//
+//	lis, _ := net.Listen("tcp", ":9090")
+//	s := grpc.NewServer()
+//	todoistgrpc.RegisterTodoistServer(s, todoistgrpc.NewServer(client))
+//	log.Fatal(s.Serve(lis))
+package todoistgrpc
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative todoist.proto
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/ides15/todoist"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Server implements TodoistServer with a todoist.Client.
+type Server struct {
+	UnimplementedTodoistServer
+
+	api   todoist.API
+	watch func(ctx context.Context, resourceTypes []string, opts ...todoist.WatchOption) (<-chan todoist.WatchEvent, error)
+}
+
+// NewServer returns a Server sending the calls it receives to Todoist with
+// client.
+func NewServer(client *todoist.Client) *Server {
+	return &Server{api: client, watch: client.Watch}
+}
+
+// ListProjects implements TodoistServer.
+func (s *Server) ListProjects(ctx context.Context, req *ListProjectsRequest) (*ListProjectsResponse, error) {
+	projects, readResponse, err := s.api.ProjectsAPI().List(ctx, req.SyncToken)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &ListProjectsResponse{SyncToken: readResponse.SyncToken, FullSync: readResponse.FullSync}
+	for _, p := range projects {
+		resp.Projects = append(resp.Projects, ProjectToProto(p))
+	}
+
+	return resp, nil
+}
+
+// AddProject implements TodoistServer.
+func (s *Server) AddProject(ctx context.Context, req *AddProjectRequest) (*Project, error) {
+	if req.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	}
+	color, err := colorID(req.Color)
+	if err != nil {
+		return nil, err
+	}
+
+	addProject := todoist.AddProject{
+		Name:       req.Name,
+		Color:      color,
+		ParentID:   projectID(req.ParentId),
+		ChildOrder: int(req.ChildOrder),
+		IsFavorite: boolInt(req.IsFavorite),
+		TempID:     uuid.New().String(),
+	}
+	projects, commandResponse, err := s.api.ProjectsAPI().Add(ctx, "", addProject)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return findProject(projects, commandResponse.TempIDMapping[addProject.TempID])
+}
+
+// UpdateProject implements TodoistServer.
+func (s *Server) UpdateProject(ctx context.Context, req *UpdateProjectRequest) (*Project, error) {
+	if req.Id == "" {
+		return nil, errMissingID
+	}
+	color, err := colorID(req.Color)
+	if err != nil {
+		return nil, err
+	}
+
+	commandResponse, err := s.update(ctx, "project_update", "projects", todoist.UpdateProject{
+		ID:    todoist.ProjectID{ID: todoist.NewID(req.Id)},
+		Name:  req.Name,
+		Color: color,
+	}, map[string]*bool{"collapsed": req.Collapsed, "is_favorite": req.IsFavorite})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return findProject(commandResponse.Projects, todoist.NewID(req.Id))
+}
+
+// MoveProject implements TodoistServer.
+func (s *Server) MoveProject(ctx context.Context, req *MoveProjectRequest) (*Project, error) {
+	if req.Id == "" {
+		return nil, errMissingID
+	}
+
+	projects, _, err := s.api.ProjectsAPI().Move(ctx, "", todoist.MoveProject{
+		ID:       todoist.ProjectID{ID: todoist.NewID(req.Id)},
+		ParentID: projectID(req.ParentId),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return findProject(projects, todoist.NewID(req.Id))
+}
+
+// ArchiveProject implements TodoistServer.
+func (s *Server) ArchiveProject(ctx context.Context, req *ArchiveProjectRequest) (*ArchiveProjectResponse, error) {
+	if req.Id == "" {
+		return nil, errMissingID
+	}
+
+	_, _, err := s.api.ProjectsAPI().Archive(ctx, "", todoist.ArchiveProject{ID: todoist.ProjectID{ID: todoist.NewID(req.Id)}})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ArchiveProjectResponse{}, nil
+}
+
+// DeleteProject implements TodoistServer.
+func (s *Server) DeleteProject(ctx context.Context, req *DeleteProjectRequest) (*DeleteProjectResponse, error) {
+	if req.Id == "" {
+		return nil, errMissingID
+	}
+
+	_, _, err := s.api.ProjectsAPI().Delete(ctx, "", todoist.DeleteProject{ID: todoist.ProjectID{ID: todoist.NewID(req.Id)}})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &DeleteProjectResponse{}, nil
+}
+
+// ListSections implements TodoistServer.
+func (s *Server) ListSections(ctx context.Context, req *ListSectionsRequest) (*ListSectionsResponse, error) {
+	sections, readResponse, err := s.api.SectionsAPI().List(ctx, req.SyncToken)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &ListSectionsResponse{SyncToken: readResponse.SyncToken, FullSync: readResponse.FullSync}
+	for _, section := range sections {
+		resp.Sections = append(resp.Sections, SectionToProto(section))
+	}
+
+	return resp, nil
+}
+
+// AddSection implements TodoistServer.
+func (s *Server) AddSection(ctx context.Context, req *AddSectionRequest) (*Section, error) {
+	if req.Name == "" || req.ProjectId == "" {
+		return nil, status.Error(codes.InvalidArgument, "name and project_id are required")
+	}
+
+	addSection := todoist.AddSection{
+		Name:         req.Name,
+		ProjectID:    todoist.ProjectID{ID: todoist.NewID(req.ProjectId)},
+		SectionOrder: int(req.SectionOrder),
+		TempID:       uuid.New().String(),
+	}
+	sections, commandResponse, err := s.api.SectionsAPI().Add(ctx, "", addSection)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return findSection(sections, commandResponse.TempIDMapping[addSection.TempID])
+}
+
+// UpdateSection implements TodoistServer.
+func (s *Server) UpdateSection(ctx context.Context, req *UpdateSectionRequest) (*Section, error) {
+	if req.Id == "" {
+		return nil, errMissingID
+	}
+
+	sections, _, err := s.api.SectionsAPI().Update(ctx, "", todoist.UpdateSection{
+		ID:        todoist.SectionID{ID: todoist.NewID(req.Id)},
+		Name:      req.Name,
+		Collapsed: req.Collapsed,
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return findSection(sections, todoist.NewID(req.Id))
+}
+
+// MoveSection implements TodoistServer.
+func (s *Server) MoveSection(ctx context.Context, req *MoveSectionRequest) (*Section, error) {
+	if req.Id == "" || req.ProjectId == "" {
+		return nil, status.Error(codes.InvalidArgument, "id and project_id are required")
+	}
+
+	sections, _, err := s.api.SectionsAPI().Move(ctx, "", todoist.MoveSection{
+		ID:        todoist.SectionID{ID: todoist.NewID(req.Id)},
+		ProjectID: todoist.ProjectID{ID: todoist.NewID(req.ProjectId)},
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return findSection(sections, todoist.NewID(req.Id))
+}
+
+// ArchiveSection implements TodoistServer.
+func (s *Server) ArchiveSection(ctx context.Context, req *ArchiveSectionRequest) (*ArchiveSectionResponse, error) {
+	if req.Id == "" {
+		return nil, errMissingID
+	}
+
+	_, _, err := s.api.SectionsAPI().Archive(ctx, "", todoist.ArchiveSection{ID: todoist.SectionID{ID: todoist.NewID(req.Id)}})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ArchiveSectionResponse{}, nil
+}
+
+// DeleteSection implements TodoistServer.
+func (s *Server) DeleteSection(ctx context.Context, req *DeleteSectionRequest) (*DeleteSectionResponse, error) {
+	if req.Id == "" {
+		return nil, errMissingID
+	}
+
+	_, _, err := s.api.SectionsAPI().Delete(ctx, "", todoist.DeleteSection{ID: todoist.SectionID{ID: todoist.NewID(req.Id)}})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &DeleteSectionResponse{}, nil
+}
+
+// ListTasks implements TodoistServer.
+func (s *Server) ListTasks(ctx context.Context, req *ListTasksRequest) (*ListTasksResponse, error) {
+	tasks, readResponse, err := s.api.TasksAPI().List(ctx, req.SyncToken)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &ListTasksResponse{SyncToken: readResponse.SyncToken, FullSync: readResponse.FullSync}
+	for _, t := range tasks {
+		resp.Tasks = append(resp.Tasks, TaskToProto(t))
+	}
+
+	return resp, nil
+}
+
+// AddTask implements TodoistServer.
+func (s *Server) AddTask(ctx context.Context, req *AddTaskRequest) (*Task, error) {
+	if req.Content == "" {
+		return nil, status.Error(codes.InvalidArgument, "content is required")
+	}
+
+	addTask := todoist.AddTask{
+		Content:     req.Content,
+		Description: req.Description,
+		ProjectID:   projectID(req.ProjectId),
+		SectionID:   sectionID(req.SectionId),
+		ParentID:    taskID(req.ParentId),
+		Due:         DueFromProto(req.Due),
+		Priority:    int(req.Priority),
+		ChildOrder:  int(req.ChildOrder),
+		Labels:      labelIDs(req.Labels),
+		TempID:      uuid.New().String(),
+	}
+	tasks, commandResponse, err := s.api.TasksAPI().Add(ctx, "", addTask)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return findTask(tasks, commandResponse.TempIDMapping[addTask.TempID])
+}
+
+// UpdateTask implements TodoistServer.
+func (s *Server) UpdateTask(ctx context.Context, req *UpdateTaskRequest) (*Task, error) {
+	if req.Id == "" {
+		return nil, errMissingID
+	}
+
+	commandResponse, err := s.update(ctx, "item_update", "items", todoist.UpdateTask{
+		ID:             todoist.TaskID{ID: todoist.NewID(req.Id)},
+		Content:        req.Content,
+		Description:    req.Description,
+		Due:            DueFromProto(req.Due),
+		Priority:       int(req.Priority),
+		Labels:         labelIDs(req.Labels),
+		ResponsibleUID: userID(req.ResponsibleUid),
+	}, map[string]*bool{"collapsed": req.Collapsed})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return findTask(commandResponse.Tasks, todoist.NewID(req.Id))
+}
+
+// MoveTask implements TodoistServer.
+func (s *Server) MoveTask(ctx context.Context, req *MoveTaskRequest) (*Task, error) {
+	if req.Id == "" {
+		return nil, errMissingID
+	}
+	destinations := 0
+	for _, id := range []string{req.ParentId, req.SectionId, req.ProjectId} {
+		if id != "" {
+			destinations++
+		}
+	}
+	if destinations != 1 {
+		return nil, status.Error(codes.InvalidArgument, "exactly one of parent_id, section_id and project_id must be set")
+	}
+
+	tasks, _, err := s.api.TasksAPI().Move(ctx, "", todoist.MoveTask{
+		ID:        todoist.TaskID{ID: todoist.NewID(req.Id)},
+		ParentID:  taskID(req.ParentId),
+		SectionID: sectionID(req.SectionId),
+		ProjectID: projectID(req.ProjectId),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return findTask(tasks, todoist.NewID(req.Id))
+}
+
+// CompleteTask implements TodoistServer.
+func (s *Server) CompleteTask(ctx context.Context, req *CompleteTaskRequest) (*CompleteTaskResponse, error) {
+	if req.Id == "" {
+		return nil, errMissingID
+	}
+
+	_, _, err := s.api.TasksAPI().Complete(ctx, "", todoist.CompleteTask{
+		ID:            todoist.TaskID{ID: todoist.NewID(req.Id)},
+		DateCompleted: req.DateCompleted,
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &CompleteTaskResponse{}, nil
+}
+
+// DeleteTask implements TodoistServer.
+func (s *Server) DeleteTask(ctx context.Context, req *DeleteTaskRequest) (*DeleteTaskResponse, error) {
+	if req.Id == "" {
+		return nil, errMissingID
+	}
+
+	_, _, err := s.api.TasksAPI().Delete(ctx, "", todoist.DeleteTask{ID: todoist.TaskID{ID: todoist.NewID(req.Id)}})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &DeleteTaskResponse{}, nil
+}
+
+// Watch implements TodoistServer. It streams the events of Client.Watch until
+// the call is canceled.
+func (s *Server) Watch(req *WatchRequest, stream Todoist_WatchServer) error {
+	resourceTypes := req.ResourceTypes
+	if len(resourceTypes) == 0 {
+		resourceTypes = []string{"projects", "sections", "items"}
+	}
+	for _, rt := range resourceTypes {
+		if rt != "projects" && rt != "sections" && rt != "items" {
+			return status.Errorf(codes.InvalidArgument, "unable to watch resource type %q", rt)
+		}
+	}
+
+	var opts []todoist.WatchOption
+	if req.SyncToken != "" {
+		opts = append(opts, todoist.WatchSyncToken(req.SyncToken))
+	}
+	switch {
+	case req.MinInterval != nil && req.MaxInterval != nil:
+		minInterval, maxInterval := req.MinInterval.AsDuration(), req.MaxInterval.AsDuration()
+		if minInterval <= 0 || maxInterval < minInterval {
+			return status.Errorf(codes.InvalidArgument, "invalid watch interval [%s, %s]", minInterval, maxInterval)
+		}
+		opts = append(opts, todoist.WatchInterval(minInterval, maxInterval))
+	case req.MinInterval != nil || req.MaxInterval != nil:
+		return status.Error(codes.InvalidArgument, "min_interval and max_interval must be set together")
+	}
+
+	ctx := stream.Context()
+	events, err := s.watch(ctx, resourceTypes, opts...)
+	if err != nil {
+		return toStatus(err)
+	}
+
+	for e := range events {
+		if err = stream.Send(WatchEventToProto(e)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+var errMissingID = status.Error(codes.InvalidArgument, "id is required")
+
+func findProject(projects []todoist.Project, id todoist.ID) (*Project, error) {
+	for _, p := range projects {
+		if p.ID.ID == id {
+			return ProjectToProto(p), nil
+		}
+	}
+
+	return nil, status.Errorf(codes.NotFound, "project %s not found in the response", id)
+}
+
+func findSection(sections []todoist.Section, id todoist.ID) (*Section, error) {
+	for _, section := range sections {
+		if section.ID.ID == id {
+			return SectionToProto(section), nil
+		}
+	}
+
+	return nil, status.Errorf(codes.NotFound, "section %s not found in the response", id)
+}
+
+func findTask(tasks []todoist.Task, id todoist.ID) (*Task, error) {
+	for _, t := range tasks {
+		if t.ID.ID == id {
+			return TaskToProto(t), nil
+		}
+	}
+
+	return nil, status.Errorf(codes.NotFound, "task %s not found in the response", id)
+}
+
+// colorID returns the numeric ID of a color name, which the commands take.
+func colorID(name string) (int, error) {
+	if name == "" {
+		return 0, nil
+	}
+
+	id := todoist.Color(name).ID()
+	if id == 0 {
+		return 0, status.Errorf(codes.InvalidArgument, "unknown color %q", name)
+	}
+
+	return id, nil
+}
+
+// update sends an update command with the arguments of an update struct and
+// the flags that are set. The update structs omit flags set to 0, so they
+// cannot clear them on their own.
+func (s *Server) update(ctx context.Context, commandType, resourceType string, update interface{}, flags map[string]*bool) (todoist.CommandResponse, error) {
+	b, err := json.Marshal(update)
+	if err != nil {
+		return todoist.CommandResponse{}, err
+	}
+
+	var args map[string]interface{}
+	if err = json.Unmarshal(b, &args); err != nil {
+		return todoist.CommandResponse{}, err
+	}
+	for name, flag := range flags {
+		if flag != nil {
+			args[name] = boolInt(*flag)
+		}
+	}
+
+	req, err := s.api.NewRequest("", []string{resourceType}, []todoist.Command{{
+		Type:   commandType,
+		Args:   args,
+		UUID:   uuid.New().String(),
+		TempID: uuid.New().String(),
+	}})
+	if err != nil {
+		return todoist.CommandResponse{}, err
+	}
+
+	var commandResponse todoist.CommandResponse
+	_, err = s.api.Do(ctx, req, &commandResponse)
+
+	return commandResponse, err
+}
+
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+
+	return 0
+}
+
+// httpCodes maps the HTTP status codes of Todoist errors to gRPC codes.
+var httpCodes = map[int]codes.Code{
+	http.StatusBadRequest:          codes.InvalidArgument,
+	http.StatusUnauthorized:        codes.Unauthenticated,
+	http.StatusForbidden:           codes.PermissionDenied,
+	http.StatusNotFound:            codes.NotFound,
+	http.StatusTooManyRequests:     codes.ResourceExhausted,
+	http.StatusInternalServerError: codes.Internal,
+	http.StatusServiceUnavailable:  codes.Unavailable,
+}
+
+// toStatus converts an error returned by the todoist package to a gRPC status
+// error, with a code matching the HTTP status code of Todoist errors.
+func toStatus(err error) error {
+	if errors.Is(err, context.Canceled) {
+		return status.Error(codes.Canceled, err.Error())
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+
+	var httpCode int
+	switch e := errors.Cause(err).(type) {
+	case todoist.SyncError:
+		httpCode = e.HTTPCode
+	case todoist.BadRequestError:
+		httpCode = e.HTTPCode
+	case todoist.UnauthorizedError:
+		httpCode = e.HTTPCode
+	case todoist.ForbiddenError:
+		httpCode = e.HTTPCode
+	case todoist.NotFoundError:
+		httpCode = e.HTTPCode
+	case todoist.TooManyRequestsError:
+		httpCode = e.HTTPCode
+	case todoist.InternalServerError:
+		httpCode = e.HTTPCode
+	case todoist.ServiceUnavailableError:
+		httpCode = e.HTTPCode
+	case todoist.BaseError:
+		httpCode = e.HTTPCode
+	}
+
+	code, ok := httpCodes[httpCode]
+	if !ok {
+		code = codes.Unknown
+	}
+
+	return status.Error(code, err.Error())
+}
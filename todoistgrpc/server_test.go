@@ -0,0 +1,291 @@
+package todoistgrpc_test
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ides15/todoist"
+	"github.com/ides15/todoist/todoistgrpc"
+	"github.com/ides15/todoist/todoisttest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// newClient starts a Server backed by a fake Todoist server and returns a
+// client connected to it.
+func newClient(t *testing.T) (todoistgrpc.TodoistClient, *todoisttest.Server) {
+	t.Helper()
+
+	fake := todoisttest.NewServer()
+	t.Cleanup(fake.Close)
+
+	client, err := todoist.NewClient(fake.Token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.BaseURL, _ = url.Parse(fake.SyncURL())
+
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer()
+	todoistgrpc.RegisterTodoistServer(s, todoistgrpc.NewServer(client))
+	go func() { _ = s.Serve(lis) }()
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return todoistgrpc.NewTodoistClient(conn), fake
+}
+
+func Test_Server(t *testing.T) {
+	c, _ := newClient(t)
+	ctx := context.Background()
+
+	project, err := c.AddProject(ctx, &todoistgrpc.AddProjectRequest{Name: "Home", Color: "berry_red"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if project.Name != "Home" || project.Id == "" || project.Color != "berry_red" {
+		t.Fatalf("unexpected project %v", project)
+	}
+
+	section, err := c.AddSection(ctx, &todoistgrpc.AddSectionRequest{Name: "Garden", ProjectId: project.Id})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	task, err := c.AddTask(ctx, &todoistgrpc.AddTaskRequest{Content: "Mow the lawn", ProjectId: project.Id, Priority: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.ProjectId != project.Id || task.Priority != 3 || task.SectionId != "" {
+		t.Fatalf("unexpected task %v", task)
+	}
+
+	task, err = c.UpdateTask(ctx, &todoistgrpc.UpdateTaskRequest{Id: task.Id, Content: "Mow the back lawn"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	task, err = c.MoveTask(ctx, &todoistgrpc.MoveTaskRequest{Id: task.Id, SectionId: section.Id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.Content != "Mow the back lawn" || task.SectionId != section.Id {
+		t.Errorf("unexpected changed task %v", task)
+	}
+
+	list, err := c.ListTasks(ctx, &todoistgrpc.ListTasksRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !list.FullSync || len(list.Tasks) != 1 || !proto.Equal(list.Tasks[0], task) {
+		t.Fatalf("expected the task in a full sync, received %v", list)
+	}
+
+	if _, err = c.CompleteTask(ctx, &todoistgrpc.CompleteTaskRequest{Id: task.Id}); err != nil {
+		t.Fatal(err)
+	}
+	changed, err := c.ListTasks(ctx, &todoistgrpc.ListTasksRequest{SyncToken: list.SyncToken})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed.FullSync || len(changed.Tasks) != 1 || !changed.Tasks[0].Checked {
+		t.Errorf("expected the completed task in an incremental sync, received %v", changed)
+	}
+
+	if _, err = c.ArchiveProject(ctx, &todoistgrpc.ArchiveProjectRequest{Id: project.Id}); err != nil {
+		t.Fatal(err)
+	}
+	projects, err := c.ListProjects(ctx, &todoistgrpc.ListProjectsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(projects.Projects) != 1 || !projects.Projects[0].InboxProject {
+		t.Errorf("expected only the inbox, received %v", projects.Projects)
+	}
+}
+
+func Test_Server_UpdateFlags(t *testing.T) {
+	c, _ := newClient(t)
+	ctx := context.Background()
+
+	project, err := c.AddProject(ctx, &todoistgrpc.AddProjectRequest{Name: "Home", IsFavorite: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !project.IsFavorite {
+		t.Fatalf("expected a favorite project, received %v", project)
+	}
+
+	// Unset flags are kept, and flags set to false are cleared.
+	project, err = c.UpdateProject(ctx, &todoistgrpc.UpdateProjectRequest{Id: project.Id, Name: "House", Collapsed: proto.Bool(true)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if project.Name != "House" || !project.IsFavorite || !project.Collapsed {
+		t.Fatalf("unexpected updated project %v", project)
+	}
+	project, err = c.UpdateProject(ctx, &todoistgrpc.UpdateProjectRequest{Id: project.Id, IsFavorite: proto.Bool(false)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if project.Name != "House" || project.IsFavorite || !project.Collapsed {
+		t.Errorf("expected the project to no longer be a favorite, received %v", project)
+	}
+
+	task, err := c.AddTask(ctx, &todoistgrpc.AddTaskRequest{Content: "Mow the lawn"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, collapsed := range []bool{true, false} {
+		task, err = c.UpdateTask(ctx, &todoistgrpc.UpdateTaskRequest{Id: task.Id, Collapsed: proto.Bool(collapsed)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if task.Collapsed != collapsed || task.Content != "Mow the lawn" {
+			t.Errorf("expected a task with collapsed %t, received %v", collapsed, task)
+		}
+	}
+}
+
+func Test_Server_Errors(t *testing.T) {
+	c, fake := newClient(t)
+	ctx := context.Background()
+
+	task, err := c.AddTask(ctx, &todoistgrpc.AddTaskRequest{Content: "Mow the lawn"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fake.InjectFault(todoisttest.Fault{Command: "item_update"})
+
+	tests := []struct {
+		name string
+		call func() error
+		code codes.Code
+	}{
+		{"missing content", func() error {
+			_, err := c.AddTask(ctx, &todoistgrpc.AddTaskRequest{})
+			return err
+		}, codes.InvalidArgument},
+		{"unknown color", func() error {
+			_, err := c.AddProject(ctx, &todoistgrpc.AddProjectRequest{Name: "Home", Color: "plaid"})
+			return err
+		}, codes.InvalidArgument},
+		{"two destinations", func() error {
+			_, err := c.MoveTask(ctx, &todoistgrpc.MoveTaskRequest{Id: task.Id, ProjectId: "1", SectionId: "2"})
+			return err
+		}, codes.InvalidArgument},
+		{"rejected command", func() error {
+			_, err := c.UpdateTask(ctx, &todoistgrpc.UpdateTaskRequest{Id: task.Id, Content: "Mow"})
+			return err
+		}, codes.InvalidArgument},
+		{"unknown task", func() error {
+			_, err := c.DeleteTask(ctx, &todoistgrpc.DeleteTaskRequest{Id: "999999"})
+			return err
+		}, codes.NotFound},
+		{"unknown resource type", func() error {
+			stream, err := c.Watch(ctx, &todoistgrpc.WatchRequest{ResourceTypes: []string{"labels"}})
+			if err != nil {
+				return err
+			}
+			_, err = stream.Recv()
+			return err
+		}, codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := status.Code(tt.call()); code != tt.code {
+				t.Errorf("expected %s, received %s", tt.code, code)
+			}
+		})
+	}
+}
+
+func Test_Server_Watch(t *testing.T) {
+	c, fake := newClient(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	list, err := c.ListTasks(ctx, &todoistgrpc.ListTasksRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A change made by another client after the sync token is reported.
+	client, _ := todoist.NewClient(fake.Token)
+	client.BaseURL, _ = url.Parse(fake.SyncURL())
+	if _, _, err = client.Tasks.Add(ctx, "", todoist.AddTask{Content: "Rake leaves"}); err != nil {
+		t.Fatal(err)
+	}
+
+	stream, err := c.Watch(ctx, &todoistgrpc.WatchRequest{
+		ResourceTypes: []string{"items"},
+		SyncToken:     list.SyncToken,
+		MinInterval:   durationpb.New(10 * time.Millisecond),
+		MaxInterval:   durationpb.New(20 * time.Millisecond),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Type != todoistgrpc.WatchEvent_CREATED || e.GetTask().GetContent() != "Rake leaves" || e.SyncToken == "" {
+		t.Errorf("unexpected event %v", e)
+	}
+}
+
+func Test_Convert(t *testing.T) {
+	parentID := todoist.TaskID{ID: todoist.NewID("10")}
+	sectionID := todoist.SectionID{ID: todoist.NewID("20")}
+	dateCompleted := "2021-01-02T03:04:05Z"
+	timezone := "Europe/Paris"
+	task := todoist.Task{
+		ID:            todoist.TaskID{ID: todoist.NewID("11")},
+		UserID:        todoist.UserID{ID: todoist.NewID("1")},
+		ProjectID:     todoist.ProjectID{ID: todoist.NewID("30")},
+		Content:       "Mow the lawn",
+		Due:           &todoist.Due{Date: "2021-01-02T10:00:00", Timezone: &timezone, String: "every day at 10", IsRecurring: true},
+		Priority:      4,
+		ParentID:      &parentID,
+		ChildOrder:    2,
+		SectionID:     &sectionID,
+		Labels:        []todoist.LabelID{{ID: todoist.NewID("40")}},
+		Checked:       true,
+		DateCompleted: &dateCompleted,
+		DateAdded:     "2021-01-01T00:00:00Z",
+	}
+
+	if got := todoistgrpc.TaskFromProto(todoistgrpc.TaskToProto(task)); !reflect.DeepEqual(got, task) {
+		t.Errorf("expected %+v, received %+v", task, got)
+	}
+
+	inbox := true
+	project := todoist.Project{ID: todoist.ProjectID{ID: todoist.NewID("30")}, Name: "Inbox", Color: "grey", InboxProject: &inbox}
+	if got := todoistgrpc.ProjectFromProto(todoistgrpc.ProjectToProto(project)); !reflect.DeepEqual(got, project) {
+		t.Errorf("expected %+v, received %+v", project, got)
+	}
+
+	archived := "2021-01-03T00:00:00Z"
+	section := todoist.Section{ID: sectionID, Name: "Garden", ProjectID: project.ID, IsArchived: true, DateArchived: &archived}
+	if got := todoistgrpc.SectionFromProto(todoistgrpc.SectionToProto(section)); !reflect.DeepEqual(got, section) {
+		t.Errorf("expected %+v, received %+v", section, got)
+	}
+}
@@ -0,0 +1,797 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v3.21.12
+// source: todoist.proto
+
+package todoistgrpc
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// TodoistClient is the client API for Todoist service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type TodoistClient interface {
+	// Lists the projects changed since sync_token, or all of them for an empty
+	// sync_token.
+	ListProjects(ctx context.Context, in *ListProjectsRequest, opts ...grpc.CallOption) (*ListProjectsResponse, error)
+	AddProject(ctx context.Context, in *AddProjectRequest, opts ...grpc.CallOption) (*Project, error)
+	UpdateProject(ctx context.Context, in *UpdateProjectRequest, opts ...grpc.CallOption) (*Project, error)
+	MoveProject(ctx context.Context, in *MoveProjectRequest, opts ...grpc.CallOption) (*Project, error)
+	ArchiveProject(ctx context.Context, in *ArchiveProjectRequest, opts ...grpc.CallOption) (*ArchiveProjectResponse, error)
+	DeleteProject(ctx context.Context, in *DeleteProjectRequest, opts ...grpc.CallOption) (*DeleteProjectResponse, error)
+	// Lists the sections changed since sync_token, or all of them for an empty
+	// sync_token.
+	ListSections(ctx context.Context, in *ListSectionsRequest, opts ...grpc.CallOption) (*ListSectionsResponse, error)
+	AddSection(ctx context.Context, in *AddSectionRequest, opts ...grpc.CallOption) (*Section, error)
+	UpdateSection(ctx context.Context, in *UpdateSectionRequest, opts ...grpc.CallOption) (*Section, error)
+	MoveSection(ctx context.Context, in *MoveSectionRequest, opts ...grpc.CallOption) (*Section, error)
+	ArchiveSection(ctx context.Context, in *ArchiveSectionRequest, opts ...grpc.CallOption) (*ArchiveSectionResponse, error)
+	DeleteSection(ctx context.Context, in *DeleteSectionRequest, opts ...grpc.CallOption) (*DeleteSectionResponse, error)
+	// Lists the tasks changed since sync_token, or all of them for an empty
+	// sync_token.
+	ListTasks(ctx context.Context, in *ListTasksRequest, opts ...grpc.CallOption) (*ListTasksResponse, error)
+	AddTask(ctx context.Context, in *AddTaskRequest, opts ...grpc.CallOption) (*Task, error)
+	UpdateTask(ctx context.Context, in *UpdateTaskRequest, opts ...grpc.CallOption) (*Task, error)
+	MoveTask(ctx context.Context, in *MoveTaskRequest, opts ...grpc.CallOption) (*Task, error)
+	CompleteTask(ctx context.Context, in *CompleteTaskRequest, opts ...grpc.CallOption) (*CompleteTaskResponse, error)
+	DeleteTask(ctx context.Context, in *DeleteTaskRequest, opts ...grpc.CallOption) (*DeleteTaskResponse, error)
+	// Streams the changes to projects, sections and tasks, polling the Sync API
+	// until the call is canceled.
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (Todoist_WatchClient, error)
+}
+
+type todoistClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTodoistClient(cc grpc.ClientConnInterface) TodoistClient {
+	return &todoistClient{cc}
+}
+
+func (c *todoistClient) ListProjects(ctx context.Context, in *ListProjectsRequest, opts ...grpc.CallOption) (*ListProjectsResponse, error) {
+	out := new(ListProjectsResponse)
+	err := c.cc.Invoke(ctx, "/todoist.v1.Todoist/ListProjects", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoistClient) AddProject(ctx context.Context, in *AddProjectRequest, opts ...grpc.CallOption) (*Project, error) {
+	out := new(Project)
+	err := c.cc.Invoke(ctx, "/todoist.v1.Todoist/AddProject", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoistClient) UpdateProject(ctx context.Context, in *UpdateProjectRequest, opts ...grpc.CallOption) (*Project, error) {
+	out := new(Project)
+	err := c.cc.Invoke(ctx, "/todoist.v1.Todoist/UpdateProject", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoistClient) MoveProject(ctx context.Context, in *MoveProjectRequest, opts ...grpc.CallOption) (*Project, error) {
+	out := new(Project)
+	err := c.cc.Invoke(ctx, "/todoist.v1.Todoist/MoveProject", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoistClient) ArchiveProject(ctx context.Context, in *ArchiveProjectRequest, opts ...grpc.CallOption) (*ArchiveProjectResponse, error) {
+	out := new(ArchiveProjectResponse)
+	err := c.cc.Invoke(ctx, "/todoist.v1.Todoist/ArchiveProject", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoistClient) DeleteProject(ctx context.Context, in *DeleteProjectRequest, opts ...grpc.CallOption) (*DeleteProjectResponse, error) {
+	out := new(DeleteProjectResponse)
+	err := c.cc.Invoke(ctx, "/todoist.v1.Todoist/DeleteProject", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoistClient) ListSections(ctx context.Context, in *ListSectionsRequest, opts ...grpc.CallOption) (*ListSectionsResponse, error) {
+	out := new(ListSectionsResponse)
+	err := c.cc.Invoke(ctx, "/todoist.v1.Todoist/ListSections", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoistClient) AddSection(ctx context.Context, in *AddSectionRequest, opts ...grpc.CallOption) (*Section, error) {
+	out := new(Section)
+	err := c.cc.Invoke(ctx, "/todoist.v1.Todoist/AddSection", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoistClient) UpdateSection(ctx context.Context, in *UpdateSectionRequest, opts ...grpc.CallOption) (*Section, error) {
+	out := new(Section)
+	err := c.cc.Invoke(ctx, "/todoist.v1.Todoist/UpdateSection", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoistClient) MoveSection(ctx context.Context, in *MoveSectionRequest, opts ...grpc.CallOption) (*Section, error) {
+	out := new(Section)
+	err := c.cc.Invoke(ctx, "/todoist.v1.Todoist/MoveSection", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoistClient) ArchiveSection(ctx context.Context, in *ArchiveSectionRequest, opts ...grpc.CallOption) (*ArchiveSectionResponse, error) {
+	out := new(ArchiveSectionResponse)
+	err := c.cc.Invoke(ctx, "/todoist.v1.Todoist/ArchiveSection", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoistClient) DeleteSection(ctx context.Context, in *DeleteSectionRequest, opts ...grpc.CallOption) (*DeleteSectionResponse, error) {
+	out := new(DeleteSectionResponse)
+	err := c.cc.Invoke(ctx, "/todoist.v1.Todoist/DeleteSection", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoistClient) ListTasks(ctx context.Context, in *ListTasksRequest, opts ...grpc.CallOption) (*ListTasksResponse, error) {
+	out := new(ListTasksResponse)
+	err := c.cc.Invoke(ctx, "/todoist.v1.Todoist/ListTasks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoistClient) AddTask(ctx context.Context, in *AddTaskRequest, opts ...grpc.CallOption) (*Task, error) {
+	out := new(Task)
+	err := c.cc.Invoke(ctx, "/todoist.v1.Todoist/AddTask", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoistClient) UpdateTask(ctx context.Context, in *UpdateTaskRequest, opts ...grpc.CallOption) (*Task, error) {
+	out := new(Task)
+	err := c.cc.Invoke(ctx, "/todoist.v1.Todoist/UpdateTask", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoistClient) MoveTask(ctx context.Context, in *MoveTaskRequest, opts ...grpc.CallOption) (*Task, error) {
+	out := new(Task)
+	err := c.cc.Invoke(ctx, "/todoist.v1.Todoist/MoveTask", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoistClient) CompleteTask(ctx context.Context, in *CompleteTaskRequest, opts ...grpc.CallOption) (*CompleteTaskResponse, error) {
+	out := new(CompleteTaskResponse)
+	err := c.cc.Invoke(ctx, "/todoist.v1.Todoist/CompleteTask", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoistClient) DeleteTask(ctx context.Context, in *DeleteTaskRequest, opts ...grpc.CallOption) (*DeleteTaskResponse, error) {
+	out := new(DeleteTaskResponse)
+	err := c.cc.Invoke(ctx, "/todoist.v1.Todoist/DeleteTask", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoistClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (Todoist_WatchClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Todoist_ServiceDesc.Streams[0], "/todoist.v1.Todoist/Watch", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &todoistWatchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Todoist_WatchClient interface {
+	Recv() (*WatchEvent, error)
+	grpc.ClientStream
+}
+
+type todoistWatchClient struct {
+	grpc.ClientStream
+}
+
+func (x *todoistWatchClient) Recv() (*WatchEvent, error) {
+	m := new(WatchEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// TodoistServer is the server API for Todoist service.
+// All implementations must embed UnimplementedTodoistServer
+// for forward compatibility
+type TodoistServer interface {
+	// Lists the projects changed since sync_token, or all of them for an empty
+	// sync_token.
+	ListProjects(context.Context, *ListProjectsRequest) (*ListProjectsResponse, error)
+	AddProject(context.Context, *AddProjectRequest) (*Project, error)
+	UpdateProject(context.Context, *UpdateProjectRequest) (*Project, error)
+	MoveProject(context.Context, *MoveProjectRequest) (*Project, error)
+	ArchiveProject(context.Context, *ArchiveProjectRequest) (*ArchiveProjectResponse, error)
+	DeleteProject(context.Context, *DeleteProjectRequest) (*DeleteProjectResponse, error)
+	// Lists the sections changed since sync_token, or all of them for an empty
+	// sync_token.
+	ListSections(context.Context, *ListSectionsRequest) (*ListSectionsResponse, error)
+	AddSection(context.Context, *AddSectionRequest) (*Section, error)
+	UpdateSection(context.Context, *UpdateSectionRequest) (*Section, error)
+	MoveSection(context.Context, *MoveSectionRequest) (*Section, error)
+	ArchiveSection(context.Context, *ArchiveSectionRequest) (*ArchiveSectionResponse, error)
+	DeleteSection(context.Context, *DeleteSectionRequest) (*DeleteSectionResponse, error)
+	// Lists the tasks changed since sync_token, or all of them for an empty
+	// sync_token.
+	ListTasks(context.Context, *ListTasksRequest) (*ListTasksResponse, error)
+	AddTask(context.Context, *AddTaskRequest) (*Task, error)
+	UpdateTask(context.Context, *UpdateTaskRequest) (*Task, error)
+	MoveTask(context.Context, *MoveTaskRequest) (*Task, error)
+	CompleteTask(context.Context, *CompleteTaskRequest) (*CompleteTaskResponse, error)
+	DeleteTask(context.Context, *DeleteTaskRequest) (*DeleteTaskResponse, error)
+	// Streams the changes to projects, sections and tasks, polling the Sync API
+	// until the call is canceled.
+	Watch(*WatchRequest, Todoist_WatchServer) error
+	mustEmbedUnimplementedTodoistServer()
+}
+
+// UnimplementedTodoistServer must be embedded to have forward compatible implementations.
+type UnimplementedTodoistServer struct {
+}
+
+func (UnimplementedTodoistServer) ListProjects(context.Context, *ListProjectsRequest) (*ListProjectsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListProjects not implemented")
+}
+func (UnimplementedTodoistServer) AddProject(context.Context, *AddProjectRequest) (*Project, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddProject not implemented")
+}
+func (UnimplementedTodoistServer) UpdateProject(context.Context, *UpdateProjectRequest) (*Project, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateProject not implemented")
+}
+func (UnimplementedTodoistServer) MoveProject(context.Context, *MoveProjectRequest) (*Project, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MoveProject not implemented")
+}
+func (UnimplementedTodoistServer) ArchiveProject(context.Context, *ArchiveProjectRequest) (*ArchiveProjectResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ArchiveProject not implemented")
+}
+func (UnimplementedTodoistServer) DeleteProject(context.Context, *DeleteProjectRequest) (*DeleteProjectResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteProject not implemented")
+}
+func (UnimplementedTodoistServer) ListSections(context.Context, *ListSectionsRequest) (*ListSectionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSections not implemented")
+}
+func (UnimplementedTodoistServer) AddSection(context.Context, *AddSectionRequest) (*Section, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddSection not implemented")
+}
+func (UnimplementedTodoistServer) UpdateSection(context.Context, *UpdateSectionRequest) (*Section, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateSection not implemented")
+}
+func (UnimplementedTodoistServer) MoveSection(context.Context, *MoveSectionRequest) (*Section, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MoveSection not implemented")
+}
+func (UnimplementedTodoistServer) ArchiveSection(context.Context, *ArchiveSectionRequest) (*ArchiveSectionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ArchiveSection not implemented")
+}
+func (UnimplementedTodoistServer) DeleteSection(context.Context, *DeleteSectionRequest) (*DeleteSectionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteSection not implemented")
+}
+func (UnimplementedTodoistServer) ListTasks(context.Context, *ListTasksRequest) (*ListTasksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTasks not implemented")
+}
+func (UnimplementedTodoistServer) AddTask(context.Context, *AddTaskRequest) (*Task, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddTask not implemented")
+}
+func (UnimplementedTodoistServer) UpdateTask(context.Context, *UpdateTaskRequest) (*Task, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateTask not implemented")
+}
+func (UnimplementedTodoistServer) MoveTask(context.Context, *MoveTaskRequest) (*Task, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MoveTask not implemented")
+}
+func (UnimplementedTodoistServer) CompleteTask(context.Context, *CompleteTaskRequest) (*CompleteTaskResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CompleteTask not implemented")
+}
+func (UnimplementedTodoistServer) DeleteTask(context.Context, *DeleteTaskRequest) (*DeleteTaskResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteTask not implemented")
+}
+func (UnimplementedTodoistServer) Watch(*WatchRequest, Todoist_WatchServer) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
+func (UnimplementedTodoistServer) mustEmbedUnimplementedTodoistServer() {}
+
+// UnsafeTodoistServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TodoistServer will
+// result in compilation errors.
+type UnsafeTodoistServer interface {
+	mustEmbedUnimplementedTodoistServer()
+}
+
+func RegisterTodoistServer(s grpc.ServiceRegistrar, srv TodoistServer) {
+	s.RegisterService(&Todoist_ServiceDesc, srv)
+}
+
+func _Todoist_ListProjects_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListProjectsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoistServer).ListProjects(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todoist.v1.Todoist/ListProjects",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoistServer).ListProjects(ctx, req.(*ListProjectsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Todoist_AddProject_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddProjectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoistServer).AddProject(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todoist.v1.Todoist/AddProject",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoistServer).AddProject(ctx, req.(*AddProjectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Todoist_UpdateProject_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateProjectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoistServer).UpdateProject(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todoist.v1.Todoist/UpdateProject",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoistServer).UpdateProject(ctx, req.(*UpdateProjectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Todoist_MoveProject_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MoveProjectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoistServer).MoveProject(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todoist.v1.Todoist/MoveProject",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoistServer).MoveProject(ctx, req.(*MoveProjectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Todoist_ArchiveProject_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ArchiveProjectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoistServer).ArchiveProject(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todoist.v1.Todoist/ArchiveProject",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoistServer).ArchiveProject(ctx, req.(*ArchiveProjectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Todoist_DeleteProject_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteProjectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoistServer).DeleteProject(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todoist.v1.Todoist/DeleteProject",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoistServer).DeleteProject(ctx, req.(*DeleteProjectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Todoist_ListSections_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSectionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoistServer).ListSections(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todoist.v1.Todoist/ListSections",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoistServer).ListSections(ctx, req.(*ListSectionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Todoist_AddSection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddSectionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoistServer).AddSection(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todoist.v1.Todoist/AddSection",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoistServer).AddSection(ctx, req.(*AddSectionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Todoist_UpdateSection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateSectionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoistServer).UpdateSection(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todoist.v1.Todoist/UpdateSection",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoistServer).UpdateSection(ctx, req.(*UpdateSectionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Todoist_MoveSection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MoveSectionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoistServer).MoveSection(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todoist.v1.Todoist/MoveSection",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoistServer).MoveSection(ctx, req.(*MoveSectionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Todoist_ArchiveSection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ArchiveSectionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoistServer).ArchiveSection(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todoist.v1.Todoist/ArchiveSection",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoistServer).ArchiveSection(ctx, req.(*ArchiveSectionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Todoist_DeleteSection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteSectionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoistServer).DeleteSection(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todoist.v1.Todoist/DeleteSection",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoistServer).DeleteSection(ctx, req.(*DeleteSectionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Todoist_ListTasks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTasksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoistServer).ListTasks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todoist.v1.Todoist/ListTasks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoistServer).ListTasks(ctx, req.(*ListTasksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Todoist_AddTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddTaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoistServer).AddTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todoist.v1.Todoist/AddTask",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoistServer).AddTask(ctx, req.(*AddTaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Todoist_UpdateTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateTaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoistServer).UpdateTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todoist.v1.Todoist/UpdateTask",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoistServer).UpdateTask(ctx, req.(*UpdateTaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Todoist_MoveTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MoveTaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoistServer).MoveTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todoist.v1.Todoist/MoveTask",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoistServer).MoveTask(ctx, req.(*MoveTaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Todoist_CompleteTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CompleteTaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoistServer).CompleteTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todoist.v1.Todoist/CompleteTask",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoistServer).CompleteTask(ctx, req.(*CompleteTaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Todoist_DeleteTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteTaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoistServer).DeleteTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todoist.v1.Todoist/DeleteTask",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoistServer).DeleteTask(ctx, req.(*DeleteTaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Todoist_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TodoistServer).Watch(m, &todoistWatchServer{stream})
+}
+
+type Todoist_WatchServer interface {
+	Send(*WatchEvent) error
+	grpc.ServerStream
+}
+
+type todoistWatchServer struct {
+	grpc.ServerStream
+}
+
+func (x *todoistWatchServer) Send(m *WatchEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// Todoist_ServiceDesc is the grpc.ServiceDesc for Todoist service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Todoist_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "todoist.v1.Todoist",
+	HandlerType: (*TodoistServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListProjects",
+			Handler:    _Todoist_ListProjects_Handler,
+		},
+		{
+			MethodName: "AddProject",
+			Handler:    _Todoist_AddProject_Handler,
+		},
+		{
+			MethodName: "UpdateProject",
+			Handler:    _Todoist_UpdateProject_Handler,
+		},
+		{
+			MethodName: "MoveProject",
+			Handler:    _Todoist_MoveProject_Handler,
+		},
+		{
+			MethodName: "ArchiveProject",
+			Handler:    _Todoist_ArchiveProject_Handler,
+		},
+		{
+			MethodName: "DeleteProject",
+			Handler:    _Todoist_DeleteProject_Handler,
+		},
+		{
+			MethodName: "ListSections",
+			Handler:    _Todoist_ListSections_Handler,
+		},
+		{
+			MethodName: "AddSection",
+			Handler:    _Todoist_AddSection_Handler,
+		},
+		{
+			MethodName: "UpdateSection",
+			Handler:    _Todoist_UpdateSection_Handler,
+		},
+		{
+			MethodName: "MoveSection",
+			Handler:    _Todoist_MoveSection_Handler,
+		},
+		{
+			MethodName: "ArchiveSection",
+			Handler:    _Todoist_ArchiveSection_Handler,
+		},
+		{
+			MethodName: "DeleteSection",
+			Handler:    _Todoist_DeleteSection_Handler,
+		},
+		{
+			MethodName: "ListTasks",
+			Handler:    _Todoist_ListTasks_Handler,
+		},
+		{
+			MethodName: "AddTask",
+			Handler:    _Todoist_AddTask_Handler,
+		},
+		{
+			MethodName: "UpdateTask",
+			Handler:    _Todoist_UpdateTask_Handler,
+		},
+		{
+			MethodName: "MoveTask",
+			Handler:    _Todoist_MoveTask_Handler,
+		},
+		{
+			MethodName: "CompleteTask",
+			Handler:    _Todoist_CompleteTask_Handler,
+		},
+		{
+			MethodName: "DeleteTask",
+			Handler:    _Todoist_DeleteTask_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Watch",
+			Handler:       _Todoist_Watch_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "todoist.proto",
+}
@@ -0,0 +1,2286 @@
+// Code generated by genmock from api.go. DO NOT EDIT.
+
+package todoistmock
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/ides15/todoist"
+)
+
+// API is a mock implementation of todoist.API.
+type API struct {
+	mu sync.Mutex
+
+	// ProjectsAPIFunc, if set, is called by ProjectsAPI.
+	ProjectsAPIFunc func() todoist.ProjectsAPI
+	// ProjectsAPICalls records the arguments of every call to ProjectsAPI.
+	ProjectsAPICalls []APIProjectsAPICall
+
+	// SectionsAPIFunc, if set, is called by SectionsAPI.
+	SectionsAPIFunc func() todoist.SectionsAPI
+	// SectionsAPICalls records the arguments of every call to SectionsAPI.
+	SectionsAPICalls []APISectionsAPICall
+
+	// TasksAPIFunc, if set, is called by TasksAPI.
+	TasksAPIFunc func() todoist.TasksAPI
+	// TasksAPICalls records the arguments of every call to TasksAPI.
+	TasksAPICalls []APITasksAPICall
+
+	// TemplatesAPIFunc, if set, is called by TemplatesAPI.
+	TemplatesAPIFunc func() todoist.TemplatesAPI
+	// TemplatesAPICalls records the arguments of every call to TemplatesAPI.
+	TemplatesAPICalls []APITemplatesAPICall
+
+	// BackupsAPIFunc, if set, is called by BackupsAPI.
+	BackupsAPIFunc func() todoist.BackupsAPI
+	// BackupsAPICalls records the arguments of every call to BackupsAPI.
+	BackupsAPICalls []APIBackupsAPICall
+
+	// UploadsAPIFunc, if set, is called by UploadsAPI.
+	UploadsAPIFunc func() todoist.UploadsAPI
+	// UploadsAPICalls records the arguments of every call to UploadsAPI.
+	UploadsAPICalls []APIUploadsAPICall
+
+	// RESTTasksAPIFunc, if set, is called by RESTTasksAPI.
+	RESTTasksAPIFunc func() todoist.RESTTasksAPI
+	// RESTTasksAPICalls records the arguments of every call to RESTTasksAPI.
+	RESTTasksAPICalls []APIRESTTasksAPICall
+
+	// RESTProjectsAPIFunc, if set, is called by RESTProjectsAPI.
+	RESTProjectsAPIFunc func() todoist.RESTProjectsAPI
+	// RESTProjectsAPICalls records the arguments of every call to RESTProjectsAPI.
+	RESTProjectsAPICalls []APIRESTProjectsAPICall
+
+	// RESTSectionsAPIFunc, if set, is called by RESTSectionsAPI.
+	RESTSectionsAPIFunc func() todoist.RESTSectionsAPI
+	// RESTSectionsAPICalls records the arguments of every call to RESTSectionsAPI.
+	RESTSectionsAPICalls []APIRESTSectionsAPICall
+
+	// RESTLabelsAPIFunc, if set, is called by RESTLabelsAPI.
+	RESTLabelsAPIFunc func() todoist.RESTLabelsAPI
+	// RESTLabelsAPICalls records the arguments of every call to RESTLabelsAPI.
+	RESTLabelsAPICalls []APIRESTLabelsAPICall
+
+	// RESTCommentsAPIFunc, if set, is called by RESTCommentsAPI.
+	RESTCommentsAPIFunc func() todoist.RESTCommentsAPI
+	// RESTCommentsAPICalls records the arguments of every call to RESTCommentsAPI.
+	RESTCommentsAPICalls []APIRESTCommentsAPICall
+
+	// NewRequestFunc, if set, is called by NewRequest.
+	NewRequestFunc func(syncToken string, resourceTypes []string, commands []todoist.Command) (*http.Request, error)
+	// NewRequestCalls records the arguments of every call to NewRequest.
+	NewRequestCalls []APINewRequestCall
+
+	// DoFunc, if set, is called by Do.
+	DoFunc func(ctx context.Context, req *http.Request, v interface{}) (*http.Response, error)
+	// DoCalls records the arguments of every call to Do.
+	DoCalls []APIDoCall
+}
+
+// APIProjectsAPICall records the arguments of a call to API.ProjectsAPI.
+type APIProjectsAPICall struct {
+}
+
+// ProjectsAPI implements todoist.API.
+func (m *API) ProjectsAPI() todoist.ProjectsAPI {
+	m.mu.Lock()
+	m.ProjectsAPICalls = append(m.ProjectsAPICalls, APIProjectsAPICall{})
+	fn := m.ProjectsAPIFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn()
+	}
+
+	var r0 todoist.ProjectsAPI
+	return r0
+}
+
+// APISectionsAPICall records the arguments of a call to API.SectionsAPI.
+type APISectionsAPICall struct {
+}
+
+// SectionsAPI implements todoist.API.
+func (m *API) SectionsAPI() todoist.SectionsAPI {
+	m.mu.Lock()
+	m.SectionsAPICalls = append(m.SectionsAPICalls, APISectionsAPICall{})
+	fn := m.SectionsAPIFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn()
+	}
+
+	var r0 todoist.SectionsAPI
+	return r0
+}
+
+// APITasksAPICall records the arguments of a call to API.TasksAPI.
+type APITasksAPICall struct {
+}
+
+// TasksAPI implements todoist.API.
+func (m *API) TasksAPI() todoist.TasksAPI {
+	m.mu.Lock()
+	m.TasksAPICalls = append(m.TasksAPICalls, APITasksAPICall{})
+	fn := m.TasksAPIFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn()
+	}
+
+	var r0 todoist.TasksAPI
+	return r0
+}
+
+// APITemplatesAPICall records the arguments of a call to API.TemplatesAPI.
+type APITemplatesAPICall struct {
+}
+
+// TemplatesAPI implements todoist.API.
+func (m *API) TemplatesAPI() todoist.TemplatesAPI {
+	m.mu.Lock()
+	m.TemplatesAPICalls = append(m.TemplatesAPICalls, APITemplatesAPICall{})
+	fn := m.TemplatesAPIFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn()
+	}
+
+	var r0 todoist.TemplatesAPI
+	return r0
+}
+
+// APIBackupsAPICall records the arguments of a call to API.BackupsAPI.
+type APIBackupsAPICall struct {
+}
+
+// BackupsAPI implements todoist.API.
+func (m *API) BackupsAPI() todoist.BackupsAPI {
+	m.mu.Lock()
+	m.BackupsAPICalls = append(m.BackupsAPICalls, APIBackupsAPICall{})
+	fn := m.BackupsAPIFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn()
+	}
+
+	var r0 todoist.BackupsAPI
+	return r0
+}
+
+// APIUploadsAPICall records the arguments of a call to API.UploadsAPI.
+type APIUploadsAPICall struct {
+}
+
+// UploadsAPI implements todoist.API.
+func (m *API) UploadsAPI() todoist.UploadsAPI {
+	m.mu.Lock()
+	m.UploadsAPICalls = append(m.UploadsAPICalls, APIUploadsAPICall{})
+	fn := m.UploadsAPIFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn()
+	}
+
+	var r0 todoist.UploadsAPI
+	return r0
+}
+
+// APIRESTTasksAPICall records the arguments of a call to API.RESTTasksAPI.
+type APIRESTTasksAPICall struct {
+}
+
+// RESTTasksAPI implements todoist.API.
+func (m *API) RESTTasksAPI() todoist.RESTTasksAPI {
+	m.mu.Lock()
+	m.RESTTasksAPICalls = append(m.RESTTasksAPICalls, APIRESTTasksAPICall{})
+	fn := m.RESTTasksAPIFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn()
+	}
+
+	var r0 todoist.RESTTasksAPI
+	return r0
+}
+
+// APIRESTProjectsAPICall records the arguments of a call to API.RESTProjectsAPI.
+type APIRESTProjectsAPICall struct {
+}
+
+// RESTProjectsAPI implements todoist.API.
+func (m *API) RESTProjectsAPI() todoist.RESTProjectsAPI {
+	m.mu.Lock()
+	m.RESTProjectsAPICalls = append(m.RESTProjectsAPICalls, APIRESTProjectsAPICall{})
+	fn := m.RESTProjectsAPIFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn()
+	}
+
+	var r0 todoist.RESTProjectsAPI
+	return r0
+}
+
+// APIRESTSectionsAPICall records the arguments of a call to API.RESTSectionsAPI.
+type APIRESTSectionsAPICall struct {
+}
+
+// RESTSectionsAPI implements todoist.API.
+func (m *API) RESTSectionsAPI() todoist.RESTSectionsAPI {
+	m.mu.Lock()
+	m.RESTSectionsAPICalls = append(m.RESTSectionsAPICalls, APIRESTSectionsAPICall{})
+	fn := m.RESTSectionsAPIFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn()
+	}
+
+	var r0 todoist.RESTSectionsAPI
+	return r0
+}
+
+// APIRESTLabelsAPICall records the arguments of a call to API.RESTLabelsAPI.
+type APIRESTLabelsAPICall struct {
+}
+
+// RESTLabelsAPI implements todoist.API.
+func (m *API) RESTLabelsAPI() todoist.RESTLabelsAPI {
+	m.mu.Lock()
+	m.RESTLabelsAPICalls = append(m.RESTLabelsAPICalls, APIRESTLabelsAPICall{})
+	fn := m.RESTLabelsAPIFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn()
+	}
+
+	var r0 todoist.RESTLabelsAPI
+	return r0
+}
+
+// APIRESTCommentsAPICall records the arguments of a call to API.RESTCommentsAPI.
+type APIRESTCommentsAPICall struct {
+}
+
+// RESTCommentsAPI implements todoist.API.
+func (m *API) RESTCommentsAPI() todoist.RESTCommentsAPI {
+	m.mu.Lock()
+	m.RESTCommentsAPICalls = append(m.RESTCommentsAPICalls, APIRESTCommentsAPICall{})
+	fn := m.RESTCommentsAPIFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn()
+	}
+
+	var r0 todoist.RESTCommentsAPI
+	return r0
+}
+
+// APINewRequestCall records the arguments of a call to API.NewRequest.
+type APINewRequestCall struct {
+	SyncToken     string
+	ResourceTypes []string
+	Commands      []todoist.Command
+}
+
+// NewRequest implements todoist.API.
+func (m *API) NewRequest(syncToken string, resourceTypes []string, commands []todoist.Command) (*http.Request, error) {
+	m.mu.Lock()
+	m.NewRequestCalls = append(m.NewRequestCalls, APINewRequestCall{SyncToken: syncToken, ResourceTypes: resourceTypes, Commands: commands})
+	fn := m.NewRequestFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(syncToken, resourceTypes, commands)
+	}
+
+	var r0 *http.Request
+	var r1 error
+	return r0, r1
+}
+
+// APIDoCall records the arguments of a call to API.Do.
+type APIDoCall struct {
+	Ctx context.Context
+	Req *http.Request
+	V   interface{}
+}
+
+// Do implements todoist.API.
+func (m *API) Do(ctx context.Context, req *http.Request, v interface{}) (*http.Response, error) {
+	m.mu.Lock()
+	m.DoCalls = append(m.DoCalls, APIDoCall{Ctx: ctx, Req: req, V: v})
+	fn := m.DoFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, req, v)
+	}
+
+	var r0 *http.Response
+	var r1 error
+	return r0, r1
+}
+
+// ProjectsAPI is a mock implementation of todoist.ProjectsAPI.
+type ProjectsAPI struct {
+	mu sync.Mutex
+
+	// ListFunc, if set, is called by List.
+	ListFunc func(ctx context.Context, syncToken string) ([]todoist.Project, todoist.ReadResponse, error)
+	// ListCalls records the arguments of every call to List.
+	ListCalls []ProjectsAPIListCall
+
+	// AddFunc, if set, is called by Add.
+	AddFunc func(ctx context.Context, syncToken string, addProject todoist.AddProject) ([]todoist.Project, todoist.CommandResponse, error)
+	// AddCalls records the arguments of every call to Add.
+	AddCalls []ProjectsAPIAddCall
+
+	// UpdateFunc, if set, is called by Update.
+	UpdateFunc func(ctx context.Context, syncToken string, updateProject todoist.UpdateProject) ([]todoist.Project, todoist.CommandResponse, error)
+	// UpdateCalls records the arguments of every call to Update.
+	UpdateCalls []ProjectsAPIUpdateCall
+
+	// MoveFunc, if set, is called by Move.
+	MoveFunc func(ctx context.Context, syncToken string, moveProject todoist.MoveProject) ([]todoist.Project, todoist.CommandResponse, error)
+	// MoveCalls records the arguments of every call to Move.
+	MoveCalls []ProjectsAPIMoveCall
+
+	// DeleteFunc, if set, is called by Delete.
+	DeleteFunc func(ctx context.Context, syncToken string, deleteProject todoist.DeleteProject) ([]todoist.Project, todoist.CommandResponse, error)
+	// DeleteCalls records the arguments of every call to Delete.
+	DeleteCalls []ProjectsAPIDeleteCall
+
+	// ArchiveFunc, if set, is called by Archive.
+	ArchiveFunc func(ctx context.Context, syncToken string, archiveProject todoist.ArchiveProject) ([]todoist.Project, todoist.CommandResponse, error)
+	// ArchiveCalls records the arguments of every call to Archive.
+	ArchiveCalls []ProjectsAPIArchiveCall
+
+	// UnarchiveFunc, if set, is called by Unarchive.
+	UnarchiveFunc func(ctx context.Context, syncToken string, unarchiveProject todoist.UnarchiveProject) ([]todoist.Project, todoist.CommandResponse, error)
+	// UnarchiveCalls records the arguments of every call to Unarchive.
+	UnarchiveCalls []ProjectsAPIUnarchiveCall
+
+	// ReorderFunc, if set, is called by Reorder.
+	ReorderFunc func(ctx context.Context, syncToken string, reorderProjects todoist.ReorderProjects) ([]todoist.Project, todoist.CommandResponse, error)
+	// ReorderCalls records the arguments of every call to Reorder.
+	ReorderCalls []ProjectsAPIReorderCall
+
+	// GetProjectInfoFunc, if set, is called by GetProjectInfo.
+	GetProjectInfoFunc func(ctx context.Context, syncToken string, ID todoist.ProjectID, allData bool) (todoist.ProjectInfo, error)
+	// GetProjectInfoCalls records the arguments of every call to GetProjectInfo.
+	GetProjectInfoCalls []ProjectsAPIGetProjectInfoCall
+
+	// GetProjectDataFunc, if set, is called by GetProjectData.
+	GetProjectDataFunc func(ctx context.Context, syncToken string, projectID todoist.ProjectID) (todoist.ProjectData, error)
+	// GetProjectDataCalls records the arguments of every call to GetProjectData.
+	GetProjectDataCalls []ProjectsAPIGetProjectDataCall
+
+	// GetArchivedProjectsFunc, if set, is called by GetArchivedProjects.
+	GetArchivedProjectsFunc func(ctx context.Context, syncToken string, pagination *todoist.Pagination) ([]todoist.Project, error)
+	// GetArchivedProjectsCalls records the arguments of every call to GetArchivedProjects.
+	GetArchivedProjectsCalls []ProjectsAPIGetArchivedProjectsCall
+
+	// ImportTemplateFunc, if set, is called by ImportTemplate.
+	ImportTemplateFunc func(ctx context.Context, syncToken string, template *todoist.Template, target todoist.TemplateTarget) (todoist.ProjectID, todoist.CommandResponse, error)
+	// ImportTemplateCalls records the arguments of every call to ImportTemplate.
+	ImportTemplateCalls []ProjectsAPIImportTemplateCall
+
+	// CloneFunc, if set, is called by Clone.
+	CloneFunc func(ctx context.Context, projectID todoist.ProjectID, name string) (todoist.ProjectID, error)
+	// CloneCalls records the arguments of every call to Clone.
+	CloneCalls []ProjectsAPICloneCall
+}
+
+// ProjectsAPIListCall records the arguments of a call to ProjectsAPI.List.
+type ProjectsAPIListCall struct {
+	Ctx       context.Context
+	SyncToken string
+}
+
+// List implements todoist.ProjectsAPI.
+func (m *ProjectsAPI) List(ctx context.Context, syncToken string) ([]todoist.Project, todoist.ReadResponse, error) {
+	m.mu.Lock()
+	m.ListCalls = append(m.ListCalls, ProjectsAPIListCall{Ctx: ctx, SyncToken: syncToken})
+	fn := m.ListFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, syncToken)
+	}
+
+	var r0 []todoist.Project
+	var r1 todoist.ReadResponse
+	var r2 error
+	return r0, r1, r2
+}
+
+// ProjectsAPIAddCall records the arguments of a call to ProjectsAPI.Add.
+type ProjectsAPIAddCall struct {
+	Ctx        context.Context
+	SyncToken  string
+	AddProject todoist.AddProject
+}
+
+// Add implements todoist.ProjectsAPI.
+func (m *ProjectsAPI) Add(ctx context.Context, syncToken string, addProject todoist.AddProject) ([]todoist.Project, todoist.CommandResponse, error) {
+	m.mu.Lock()
+	m.AddCalls = append(m.AddCalls, ProjectsAPIAddCall{Ctx: ctx, SyncToken: syncToken, AddProject: addProject})
+	fn := m.AddFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, syncToken, addProject)
+	}
+
+	var r0 []todoist.Project
+	var r1 todoist.CommandResponse
+	var r2 error
+	return r0, r1, r2
+}
+
+// ProjectsAPIUpdateCall records the arguments of a call to ProjectsAPI.Update.
+type ProjectsAPIUpdateCall struct {
+	Ctx           context.Context
+	SyncToken     string
+	UpdateProject todoist.UpdateProject
+}
+
+// Update implements todoist.ProjectsAPI.
+func (m *ProjectsAPI) Update(ctx context.Context, syncToken string, updateProject todoist.UpdateProject) ([]todoist.Project, todoist.CommandResponse, error) {
+	m.mu.Lock()
+	m.UpdateCalls = append(m.UpdateCalls, ProjectsAPIUpdateCall{Ctx: ctx, SyncToken: syncToken, UpdateProject: updateProject})
+	fn := m.UpdateFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, syncToken, updateProject)
+	}
+
+	var r0 []todoist.Project
+	var r1 todoist.CommandResponse
+	var r2 error
+	return r0, r1, r2
+}
+
+// ProjectsAPIMoveCall records the arguments of a call to ProjectsAPI.Move.
+type ProjectsAPIMoveCall struct {
+	Ctx         context.Context
+	SyncToken   string
+	MoveProject todoist.MoveProject
+}
+
+// Move implements todoist.ProjectsAPI.
+func (m *ProjectsAPI) Move(ctx context.Context, syncToken string, moveProject todoist.MoveProject) ([]todoist.Project, todoist.CommandResponse, error) {
+	m.mu.Lock()
+	m.MoveCalls = append(m.MoveCalls, ProjectsAPIMoveCall{Ctx: ctx, SyncToken: syncToken, MoveProject: moveProject})
+	fn := m.MoveFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, syncToken, moveProject)
+	}
+
+	var r0 []todoist.Project
+	var r1 todoist.CommandResponse
+	var r2 error
+	return r0, r1, r2
+}
+
+// ProjectsAPIDeleteCall records the arguments of a call to ProjectsAPI.Delete.
+type ProjectsAPIDeleteCall struct {
+	Ctx           context.Context
+	SyncToken     string
+	DeleteProject todoist.DeleteProject
+}
+
+// Delete implements todoist.ProjectsAPI.
+func (m *ProjectsAPI) Delete(ctx context.Context, syncToken string, deleteProject todoist.DeleteProject) ([]todoist.Project, todoist.CommandResponse, error) {
+	m.mu.Lock()
+	m.DeleteCalls = append(m.DeleteCalls, ProjectsAPIDeleteCall{Ctx: ctx, SyncToken: syncToken, DeleteProject: deleteProject})
+	fn := m.DeleteFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, syncToken, deleteProject)
+	}
+
+	var r0 []todoist.Project
+	var r1 todoist.CommandResponse
+	var r2 error
+	return r0, r1, r2
+}
+
+// ProjectsAPIArchiveCall records the arguments of a call to ProjectsAPI.Archive.
+type ProjectsAPIArchiveCall struct {
+	Ctx            context.Context
+	SyncToken      string
+	ArchiveProject todoist.ArchiveProject
+}
+
+// Archive implements todoist.ProjectsAPI.
+func (m *ProjectsAPI) Archive(ctx context.Context, syncToken string, archiveProject todoist.ArchiveProject) ([]todoist.Project, todoist.CommandResponse, error) {
+	m.mu.Lock()
+	m.ArchiveCalls = append(m.ArchiveCalls, ProjectsAPIArchiveCall{Ctx: ctx, SyncToken: syncToken, ArchiveProject: archiveProject})
+	fn := m.ArchiveFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, syncToken, archiveProject)
+	}
+
+	var r0 []todoist.Project
+	var r1 todoist.CommandResponse
+	var r2 error
+	return r0, r1, r2
+}
+
+// ProjectsAPIUnarchiveCall records the arguments of a call to ProjectsAPI.Unarchive.
+type ProjectsAPIUnarchiveCall struct {
+	Ctx              context.Context
+	SyncToken        string
+	UnarchiveProject todoist.UnarchiveProject
+}
+
+// Unarchive implements todoist.ProjectsAPI.
+func (m *ProjectsAPI) Unarchive(ctx context.Context, syncToken string, unarchiveProject todoist.UnarchiveProject) ([]todoist.Project, todoist.CommandResponse, error) {
+	m.mu.Lock()
+	m.UnarchiveCalls = append(m.UnarchiveCalls, ProjectsAPIUnarchiveCall{Ctx: ctx, SyncToken: syncToken, UnarchiveProject: unarchiveProject})
+	fn := m.UnarchiveFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, syncToken, unarchiveProject)
+	}
+
+	var r0 []todoist.Project
+	var r1 todoist.CommandResponse
+	var r2 error
+	return r0, r1, r2
+}
+
+// ProjectsAPIReorderCall records the arguments of a call to ProjectsAPI.Reorder.
+type ProjectsAPIReorderCall struct {
+	Ctx             context.Context
+	SyncToken       string
+	ReorderProjects todoist.ReorderProjects
+}
+
+// Reorder implements todoist.ProjectsAPI.
+func (m *ProjectsAPI) Reorder(ctx context.Context, syncToken string, reorderProjects todoist.ReorderProjects) ([]todoist.Project, todoist.CommandResponse, error) {
+	m.mu.Lock()
+	m.ReorderCalls = append(m.ReorderCalls, ProjectsAPIReorderCall{Ctx: ctx, SyncToken: syncToken, ReorderProjects: reorderProjects})
+	fn := m.ReorderFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, syncToken, reorderProjects)
+	}
+
+	var r0 []todoist.Project
+	var r1 todoist.CommandResponse
+	var r2 error
+	return r0, r1, r2
+}
+
+// ProjectsAPIGetProjectInfoCall records the arguments of a call to ProjectsAPI.GetProjectInfo.
+type ProjectsAPIGetProjectInfoCall struct {
+	Ctx       context.Context
+	SyncToken string
+	ID        todoist.ProjectID
+	AllData   bool
+}
+
+// GetProjectInfo implements todoist.ProjectsAPI.
+func (m *ProjectsAPI) GetProjectInfo(ctx context.Context, syncToken string, ID todoist.ProjectID, allData bool) (todoist.ProjectInfo, error) {
+	m.mu.Lock()
+	m.GetProjectInfoCalls = append(m.GetProjectInfoCalls, ProjectsAPIGetProjectInfoCall{Ctx: ctx, SyncToken: syncToken, ID: ID, AllData: allData})
+	fn := m.GetProjectInfoFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, syncToken, ID, allData)
+	}
+
+	var r0 todoist.ProjectInfo
+	var r1 error
+	return r0, r1
+}
+
+// ProjectsAPIGetProjectDataCall records the arguments of a call to ProjectsAPI.GetProjectData.
+type ProjectsAPIGetProjectDataCall struct {
+	Ctx       context.Context
+	SyncToken string
+	ProjectID todoist.ProjectID
+}
+
+// GetProjectData implements todoist.ProjectsAPI.
+func (m *ProjectsAPI) GetProjectData(ctx context.Context, syncToken string, projectID todoist.ProjectID) (todoist.ProjectData, error) {
+	m.mu.Lock()
+	m.GetProjectDataCalls = append(m.GetProjectDataCalls, ProjectsAPIGetProjectDataCall{Ctx: ctx, SyncToken: syncToken, ProjectID: projectID})
+	fn := m.GetProjectDataFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, syncToken, projectID)
+	}
+
+	var r0 todoist.ProjectData
+	var r1 error
+	return r0, r1
+}
+
+// ProjectsAPIGetArchivedProjectsCall records the arguments of a call to ProjectsAPI.GetArchivedProjects.
+type ProjectsAPIGetArchivedProjectsCall struct {
+	Ctx        context.Context
+	SyncToken  string
+	Pagination *todoist.Pagination
+}
+
+// GetArchivedProjects implements todoist.ProjectsAPI.
+func (m *ProjectsAPI) GetArchivedProjects(ctx context.Context, syncToken string, pagination *todoist.Pagination) ([]todoist.Project, error) {
+	m.mu.Lock()
+	m.GetArchivedProjectsCalls = append(m.GetArchivedProjectsCalls, ProjectsAPIGetArchivedProjectsCall{Ctx: ctx, SyncToken: syncToken, Pagination: pagination})
+	fn := m.GetArchivedProjectsFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, syncToken, pagination)
+	}
+
+	var r0 []todoist.Project
+	var r1 error
+	return r0, r1
+}
+
+// ProjectsAPIImportTemplateCall records the arguments of a call to ProjectsAPI.ImportTemplate.
+type ProjectsAPIImportTemplateCall struct {
+	Ctx       context.Context
+	SyncToken string
+	Template  *todoist.Template
+	Target    todoist.TemplateTarget
+}
+
+// ImportTemplate implements todoist.ProjectsAPI.
+func (m *ProjectsAPI) ImportTemplate(ctx context.Context, syncToken string, template *todoist.Template, target todoist.TemplateTarget) (todoist.ProjectID, todoist.CommandResponse, error) {
+	m.mu.Lock()
+	m.ImportTemplateCalls = append(m.ImportTemplateCalls, ProjectsAPIImportTemplateCall{Ctx: ctx, SyncToken: syncToken, Template: template, Target: target})
+	fn := m.ImportTemplateFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, syncToken, template, target)
+	}
+
+	var r0 todoist.ProjectID
+	var r1 todoist.CommandResponse
+	var r2 error
+	return r0, r1, r2
+}
+
+// ProjectsAPICloneCall records the arguments of a call to ProjectsAPI.Clone.
+type ProjectsAPICloneCall struct {
+	Ctx       context.Context
+	ProjectID todoist.ProjectID
+	Name      string
+}
+
+// Clone implements todoist.ProjectsAPI.
+func (m *ProjectsAPI) Clone(ctx context.Context, projectID todoist.ProjectID, name string) (todoist.ProjectID, error) {
+	m.mu.Lock()
+	m.CloneCalls = append(m.CloneCalls, ProjectsAPICloneCall{Ctx: ctx, ProjectID: projectID, Name: name})
+	fn := m.CloneFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, projectID, name)
+	}
+
+	var r0 todoist.ProjectID
+	var r1 error
+	return r0, r1
+}
+
+// SectionsAPI is a mock implementation of todoist.SectionsAPI.
+type SectionsAPI struct {
+	mu sync.Mutex
+
+	// ListFunc, if set, is called by List.
+	ListFunc func(ctx context.Context, syncToken string) ([]todoist.Section, todoist.ReadResponse, error)
+	// ListCalls records the arguments of every call to List.
+	ListCalls []SectionsAPIListCall
+
+	// AddFunc, if set, is called by Add.
+	AddFunc func(ctx context.Context, syncToken string, addSection todoist.AddSection) ([]todoist.Section, todoist.CommandResponse, error)
+	// AddCalls records the arguments of every call to Add.
+	AddCalls []SectionsAPIAddCall
+
+	// UpdateFunc, if set, is called by Update.
+	UpdateFunc func(ctx context.Context, syncToken string, updateSection todoist.UpdateSection) ([]todoist.Section, todoist.CommandResponse, error)
+	// UpdateCalls records the arguments of every call to Update.
+	UpdateCalls []SectionsAPIUpdateCall
+
+	// MoveFunc, if set, is called by Move.
+	MoveFunc func(ctx context.Context, syncToken string, moveSection todoist.MoveSection) ([]todoist.Section, todoist.CommandResponse, error)
+	// MoveCalls records the arguments of every call to Move.
+	MoveCalls []SectionsAPIMoveCall
+
+	// ReorderFunc, if set, is called by Reorder.
+	ReorderFunc func(ctx context.Context, syncToken string, reorderSections todoist.ReorderSections) ([]todoist.Section, todoist.CommandResponse, error)
+	// ReorderCalls records the arguments of every call to Reorder.
+	ReorderCalls []SectionsAPIReorderCall
+
+	// DeleteFunc, if set, is called by Delete.
+	DeleteFunc func(ctx context.Context, syncToken string, deleteSection todoist.DeleteSection) ([]todoist.Section, todoist.CommandResponse, error)
+	// DeleteCalls records the arguments of every call to Delete.
+	DeleteCalls []SectionsAPIDeleteCall
+
+	// ArchiveFunc, if set, is called by Archive.
+	ArchiveFunc func(ctx context.Context, syncToken string, archiveSection todoist.ArchiveSection) ([]todoist.Section, todoist.CommandResponse, error)
+	// ArchiveCalls records the arguments of every call to Archive.
+	ArchiveCalls []SectionsAPIArchiveCall
+
+	// UnarchiveFunc, if set, is called by Unarchive.
+	UnarchiveFunc func(ctx context.Context, syncToken string, unarchiveSection todoist.UnarchiveSection) ([]todoist.Section, todoist.CommandResponse, error)
+	// UnarchiveCalls records the arguments of every call to Unarchive.
+	UnarchiveCalls []SectionsAPIUnarchiveCall
+}
+
+// SectionsAPIListCall records the arguments of a call to SectionsAPI.List.
+type SectionsAPIListCall struct {
+	Ctx       context.Context
+	SyncToken string
+}
+
+// List implements todoist.SectionsAPI.
+func (m *SectionsAPI) List(ctx context.Context, syncToken string) ([]todoist.Section, todoist.ReadResponse, error) {
+	m.mu.Lock()
+	m.ListCalls = append(m.ListCalls, SectionsAPIListCall{Ctx: ctx, SyncToken: syncToken})
+	fn := m.ListFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, syncToken)
+	}
+
+	var r0 []todoist.Section
+	var r1 todoist.ReadResponse
+	var r2 error
+	return r0, r1, r2
+}
+
+// SectionsAPIAddCall records the arguments of a call to SectionsAPI.Add.
+type SectionsAPIAddCall struct {
+	Ctx        context.Context
+	SyncToken  string
+	AddSection todoist.AddSection
+}
+
+// Add implements todoist.SectionsAPI.
+func (m *SectionsAPI) Add(ctx context.Context, syncToken string, addSection todoist.AddSection) ([]todoist.Section, todoist.CommandResponse, error) {
+	m.mu.Lock()
+	m.AddCalls = append(m.AddCalls, SectionsAPIAddCall{Ctx: ctx, SyncToken: syncToken, AddSection: addSection})
+	fn := m.AddFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, syncToken, addSection)
+	}
+
+	var r0 []todoist.Section
+	var r1 todoist.CommandResponse
+	var r2 error
+	return r0, r1, r2
+}
+
+// SectionsAPIUpdateCall records the arguments of a call to SectionsAPI.Update.
+type SectionsAPIUpdateCall struct {
+	Ctx           context.Context
+	SyncToken     string
+	UpdateSection todoist.UpdateSection
+}
+
+// Update implements todoist.SectionsAPI.
+func (m *SectionsAPI) Update(ctx context.Context, syncToken string, updateSection todoist.UpdateSection) ([]todoist.Section, todoist.CommandResponse, error) {
+	m.mu.Lock()
+	m.UpdateCalls = append(m.UpdateCalls, SectionsAPIUpdateCall{Ctx: ctx, SyncToken: syncToken, UpdateSection: updateSection})
+	fn := m.UpdateFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, syncToken, updateSection)
+	}
+
+	var r0 []todoist.Section
+	var r1 todoist.CommandResponse
+	var r2 error
+	return r0, r1, r2
+}
+
+// SectionsAPIMoveCall records the arguments of a call to SectionsAPI.Move.
+type SectionsAPIMoveCall struct {
+	Ctx         context.Context
+	SyncToken   string
+	MoveSection todoist.MoveSection
+}
+
+// Move implements todoist.SectionsAPI.
+func (m *SectionsAPI) Move(ctx context.Context, syncToken string, moveSection todoist.MoveSection) ([]todoist.Section, todoist.CommandResponse, error) {
+	m.mu.Lock()
+	m.MoveCalls = append(m.MoveCalls, SectionsAPIMoveCall{Ctx: ctx, SyncToken: syncToken, MoveSection: moveSection})
+	fn := m.MoveFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, syncToken, moveSection)
+	}
+
+	var r0 []todoist.Section
+	var r1 todoist.CommandResponse
+	var r2 error
+	return r0, r1, r2
+}
+
+// SectionsAPIReorderCall records the arguments of a call to SectionsAPI.Reorder.
+type SectionsAPIReorderCall struct {
+	Ctx             context.Context
+	SyncToken       string
+	ReorderSections todoist.ReorderSections
+}
+
+// Reorder implements todoist.SectionsAPI.
+func (m *SectionsAPI) Reorder(ctx context.Context, syncToken string, reorderSections todoist.ReorderSections) ([]todoist.Section, todoist.CommandResponse, error) {
+	m.mu.Lock()
+	m.ReorderCalls = append(m.ReorderCalls, SectionsAPIReorderCall{Ctx: ctx, SyncToken: syncToken, ReorderSections: reorderSections})
+	fn := m.ReorderFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, syncToken, reorderSections)
+	}
+
+	var r0 []todoist.Section
+	var r1 todoist.CommandResponse
+	var r2 error
+	return r0, r1, r2
+}
+
+// SectionsAPIDeleteCall records the arguments of a call to SectionsAPI.Delete.
+type SectionsAPIDeleteCall struct {
+	Ctx           context.Context
+	SyncToken     string
+	DeleteSection todoist.DeleteSection
+}
+
+// Delete implements todoist.SectionsAPI.
+func (m *SectionsAPI) Delete(ctx context.Context, syncToken string, deleteSection todoist.DeleteSection) ([]todoist.Section, todoist.CommandResponse, error) {
+	m.mu.Lock()
+	m.DeleteCalls = append(m.DeleteCalls, SectionsAPIDeleteCall{Ctx: ctx, SyncToken: syncToken, DeleteSection: deleteSection})
+	fn := m.DeleteFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, syncToken, deleteSection)
+	}
+
+	var r0 []todoist.Section
+	var r1 todoist.CommandResponse
+	var r2 error
+	return r0, r1, r2
+}
+
+// SectionsAPIArchiveCall records the arguments of a call to SectionsAPI.Archive.
+type SectionsAPIArchiveCall struct {
+	Ctx            context.Context
+	SyncToken      string
+	ArchiveSection todoist.ArchiveSection
+}
+
+// Archive implements todoist.SectionsAPI.
+func (m *SectionsAPI) Archive(ctx context.Context, syncToken string, archiveSection todoist.ArchiveSection) ([]todoist.Section, todoist.CommandResponse, error) {
+	m.mu.Lock()
+	m.ArchiveCalls = append(m.ArchiveCalls, SectionsAPIArchiveCall{Ctx: ctx, SyncToken: syncToken, ArchiveSection: archiveSection})
+	fn := m.ArchiveFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, syncToken, archiveSection)
+	}
+
+	var r0 []todoist.Section
+	var r1 todoist.CommandResponse
+	var r2 error
+	return r0, r1, r2
+}
+
+// SectionsAPIUnarchiveCall records the arguments of a call to SectionsAPI.Unarchive.
+type SectionsAPIUnarchiveCall struct {
+	Ctx              context.Context
+	SyncToken        string
+	UnarchiveSection todoist.UnarchiveSection
+}
+
+// Unarchive implements todoist.SectionsAPI.
+func (m *SectionsAPI) Unarchive(ctx context.Context, syncToken string, unarchiveSection todoist.UnarchiveSection) ([]todoist.Section, todoist.CommandResponse, error) {
+	m.mu.Lock()
+	m.UnarchiveCalls = append(m.UnarchiveCalls, SectionsAPIUnarchiveCall{Ctx: ctx, SyncToken: syncToken, UnarchiveSection: unarchiveSection})
+	fn := m.UnarchiveFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, syncToken, unarchiveSection)
+	}
+
+	var r0 []todoist.Section
+	var r1 todoist.CommandResponse
+	var r2 error
+	return r0, r1, r2
+}
+
+// TasksAPI is a mock implementation of todoist.TasksAPI.
+type TasksAPI struct {
+	mu sync.Mutex
+
+	// ListFunc, if set, is called by List.
+	ListFunc func(ctx context.Context, syncToken string) ([]todoist.Task, todoist.ReadResponse, error)
+	// ListCalls records the arguments of every call to List.
+	ListCalls []TasksAPIListCall
+
+	// AddFunc, if set, is called by Add.
+	AddFunc func(ctx context.Context, syncToken string, addTask todoist.AddTask) ([]todoist.Task, todoist.CommandResponse, error)
+	// AddCalls records the arguments of every call to Add.
+	AddCalls []TasksAPIAddCall
+
+	// UpdateFunc, if set, is called by Update.
+	UpdateFunc func(ctx context.Context, syncToken string, updateTask todoist.UpdateTask) ([]todoist.Task, todoist.CommandResponse, error)
+	// UpdateCalls records the arguments of every call to Update.
+	UpdateCalls []TasksAPIUpdateCall
+
+	// MoveFunc, if set, is called by Move.
+	MoveFunc func(ctx context.Context, syncToken string, moveTask todoist.MoveTask) ([]todoist.Task, todoist.CommandResponse, error)
+	// MoveCalls records the arguments of every call to Move.
+	MoveCalls []TasksAPIMoveCall
+
+	// DeleteFunc, if set, is called by Delete.
+	DeleteFunc func(ctx context.Context, syncToken string, deleteTask todoist.DeleteTask) ([]todoist.Task, todoist.CommandResponse, error)
+	// DeleteCalls records the arguments of every call to Delete.
+	DeleteCalls []TasksAPIDeleteCall
+
+	// CompleteFunc, if set, is called by Complete.
+	CompleteFunc func(ctx context.Context, syncToken string, completeTask todoist.CompleteTask) ([]todoist.Task, todoist.CommandResponse, error)
+	// CompleteCalls records the arguments of every call to Complete.
+	CompleteCalls []TasksAPICompleteCall
+
+	// ReorderFunc, if set, is called by Reorder.
+	ReorderFunc func(ctx context.Context, syncToken string, reorderTasks todoist.ReorderTasks) ([]todoist.Task, todoist.CommandResponse, error)
+	// ReorderCalls records the arguments of every call to Reorder.
+	ReorderCalls []TasksAPIReorderCall
+
+	// QuickAddFunc, if set, is called by QuickAdd.
+	QuickAddFunc func(ctx context.Context, text string, opts *todoist.QuickAddOptions) (todoist.Task, error)
+	// QuickAddCalls records the arguments of every call to QuickAdd.
+	QuickAddCalls []TasksAPIQuickAddCall
+
+	// ImportChecklistFunc, if set, is called by ImportChecklist.
+	ImportChecklistFunc func(ctx context.Context, syncToken string, checklist *todoist.Checklist) (todoist.CommandResponse, error)
+	// ImportChecklistCalls records the arguments of every call to ImportChecklist.
+	ImportChecklistCalls []TasksAPIImportChecklistCall
+}
+
+// TasksAPIListCall records the arguments of a call to TasksAPI.List.
+type TasksAPIListCall struct {
+	Ctx       context.Context
+	SyncToken string
+}
+
+// List implements todoist.TasksAPI.
+func (m *TasksAPI) List(ctx context.Context, syncToken string) ([]todoist.Task, todoist.ReadResponse, error) {
+	m.mu.Lock()
+	m.ListCalls = append(m.ListCalls, TasksAPIListCall{Ctx: ctx, SyncToken: syncToken})
+	fn := m.ListFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, syncToken)
+	}
+
+	var r0 []todoist.Task
+	var r1 todoist.ReadResponse
+	var r2 error
+	return r0, r1, r2
+}
+
+// TasksAPIAddCall records the arguments of a call to TasksAPI.Add.
+type TasksAPIAddCall struct {
+	Ctx       context.Context
+	SyncToken string
+	AddTask   todoist.AddTask
+}
+
+// Add implements todoist.TasksAPI.
+func (m *TasksAPI) Add(ctx context.Context, syncToken string, addTask todoist.AddTask) ([]todoist.Task, todoist.CommandResponse, error) {
+	m.mu.Lock()
+	m.AddCalls = append(m.AddCalls, TasksAPIAddCall{Ctx: ctx, SyncToken: syncToken, AddTask: addTask})
+	fn := m.AddFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, syncToken, addTask)
+	}
+
+	var r0 []todoist.Task
+	var r1 todoist.CommandResponse
+	var r2 error
+	return r0, r1, r2
+}
+
+// TasksAPIUpdateCall records the arguments of a call to TasksAPI.Update.
+type TasksAPIUpdateCall struct {
+	Ctx        context.Context
+	SyncToken  string
+	UpdateTask todoist.UpdateTask
+}
+
+// Update implements todoist.TasksAPI.
+func (m *TasksAPI) Update(ctx context.Context, syncToken string, updateTask todoist.UpdateTask) ([]todoist.Task, todoist.CommandResponse, error) {
+	m.mu.Lock()
+	m.UpdateCalls = append(m.UpdateCalls, TasksAPIUpdateCall{Ctx: ctx, SyncToken: syncToken, UpdateTask: updateTask})
+	fn := m.UpdateFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, syncToken, updateTask)
+	}
+
+	var r0 []todoist.Task
+	var r1 todoist.CommandResponse
+	var r2 error
+	return r0, r1, r2
+}
+
+// TasksAPIMoveCall records the arguments of a call to TasksAPI.Move.
+type TasksAPIMoveCall struct {
+	Ctx       context.Context
+	SyncToken string
+	MoveTask  todoist.MoveTask
+}
+
+// Move implements todoist.TasksAPI.
+func (m *TasksAPI) Move(ctx context.Context, syncToken string, moveTask todoist.MoveTask) ([]todoist.Task, todoist.CommandResponse, error) {
+	m.mu.Lock()
+	m.MoveCalls = append(m.MoveCalls, TasksAPIMoveCall{Ctx: ctx, SyncToken: syncToken, MoveTask: moveTask})
+	fn := m.MoveFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, syncToken, moveTask)
+	}
+
+	var r0 []todoist.Task
+	var r1 todoist.CommandResponse
+	var r2 error
+	return r0, r1, r2
+}
+
+// TasksAPIDeleteCall records the arguments of a call to TasksAPI.Delete.
+type TasksAPIDeleteCall struct {
+	Ctx        context.Context
+	SyncToken  string
+	DeleteTask todoist.DeleteTask
+}
+
+// Delete implements todoist.TasksAPI.
+func (m *TasksAPI) Delete(ctx context.Context, syncToken string, deleteTask todoist.DeleteTask) ([]todoist.Task, todoist.CommandResponse, error) {
+	m.mu.Lock()
+	m.DeleteCalls = append(m.DeleteCalls, TasksAPIDeleteCall{Ctx: ctx, SyncToken: syncToken, DeleteTask: deleteTask})
+	fn := m.DeleteFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, syncToken, deleteTask)
+	}
+
+	var r0 []todoist.Task
+	var r1 todoist.CommandResponse
+	var r2 error
+	return r0, r1, r2
+}
+
+// TasksAPICompleteCall records the arguments of a call to TasksAPI.Complete.
+type TasksAPICompleteCall struct {
+	Ctx          context.Context
+	SyncToken    string
+	CompleteTask todoist.CompleteTask
+}
+
+// Complete implements todoist.TasksAPI.
+func (m *TasksAPI) Complete(ctx context.Context, syncToken string, completeTask todoist.CompleteTask) ([]todoist.Task, todoist.CommandResponse, error) {
+	m.mu.Lock()
+	m.CompleteCalls = append(m.CompleteCalls, TasksAPICompleteCall{Ctx: ctx, SyncToken: syncToken, CompleteTask: completeTask})
+	fn := m.CompleteFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, syncToken, completeTask)
+	}
+
+	var r0 []todoist.Task
+	var r1 todoist.CommandResponse
+	var r2 error
+	return r0, r1, r2
+}
+
+// TasksAPIReorderCall records the arguments of a call to TasksAPI.Reorder.
+type TasksAPIReorderCall struct {
+	Ctx          context.Context
+	SyncToken    string
+	ReorderTasks todoist.ReorderTasks
+}
+
+// Reorder implements todoist.TasksAPI.
+func (m *TasksAPI) Reorder(ctx context.Context, syncToken string, reorderTasks todoist.ReorderTasks) ([]todoist.Task, todoist.CommandResponse, error) {
+	m.mu.Lock()
+	m.ReorderCalls = append(m.ReorderCalls, TasksAPIReorderCall{Ctx: ctx, SyncToken: syncToken, ReorderTasks: reorderTasks})
+	fn := m.ReorderFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, syncToken, reorderTasks)
+	}
+
+	var r0 []todoist.Task
+	var r1 todoist.CommandResponse
+	var r2 error
+	return r0, r1, r2
+}
+
+// TasksAPIQuickAddCall records the arguments of a call to TasksAPI.QuickAdd.
+type TasksAPIQuickAddCall struct {
+	Ctx  context.Context
+	Text string
+	Opts *todoist.QuickAddOptions
+}
+
+// QuickAdd implements todoist.TasksAPI.
+func (m *TasksAPI) QuickAdd(ctx context.Context, text string, opts *todoist.QuickAddOptions) (todoist.Task, error) {
+	m.mu.Lock()
+	m.QuickAddCalls = append(m.QuickAddCalls, TasksAPIQuickAddCall{Ctx: ctx, Text: text, Opts: opts})
+	fn := m.QuickAddFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, text, opts)
+	}
+
+	var r0 todoist.Task
+	var r1 error
+	return r0, r1
+}
+
+// TasksAPIImportChecklistCall records the arguments of a call to TasksAPI.ImportChecklist.
+type TasksAPIImportChecklistCall struct {
+	Ctx       context.Context
+	SyncToken string
+	Checklist *todoist.Checklist
+}
+
+// ImportChecklist implements todoist.TasksAPI.
+func (m *TasksAPI) ImportChecklist(ctx context.Context, syncToken string, checklist *todoist.Checklist) (todoist.CommandResponse, error) {
+	m.mu.Lock()
+	m.ImportChecklistCalls = append(m.ImportChecklistCalls, TasksAPIImportChecklistCall{Ctx: ctx, SyncToken: syncToken, Checklist: checklist})
+	fn := m.ImportChecklistFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, syncToken, checklist)
+	}
+
+	var r0 todoist.CommandResponse
+	var r1 error
+	return r0, r1
+}
+
+// TemplatesAPI is a mock implementation of todoist.TemplatesAPI.
+type TemplatesAPI struct {
+	mu sync.Mutex
+
+	// ImportIntoProjectFunc, if set, is called by ImportIntoProject.
+	ImportIntoProjectFunc func(ctx context.Context, projectID todoist.ProjectID, template *todoist.Template) (todoist.TemplateImport, error)
+	// ImportIntoProjectCalls records the arguments of every call to ImportIntoProject.
+	ImportIntoProjectCalls []TemplatesAPIImportIntoProjectCall
+
+	// ImportFileFunc, if set, is called by ImportFile.
+	ImportFileFunc func(ctx context.Context, projectID todoist.ProjectID, fileName string, file io.Reader) (todoist.TemplateImport, error)
+	// ImportFileCalls records the arguments of every call to ImportFile.
+	ImportFileCalls []TemplatesAPIImportFileCall
+
+	// ExportAsFileFunc, if set, is called by ExportAsFile.
+	ExportAsFileFunc func(ctx context.Context, projectID todoist.ProjectID) (*todoist.Template, error)
+	// ExportAsFileCalls records the arguments of every call to ExportAsFile.
+	ExportAsFileCalls []TemplatesAPIExportAsFileCall
+
+	// ExportAsURLFunc, if set, is called by ExportAsURL.
+	ExportAsURLFunc func(ctx context.Context, projectID todoist.ProjectID) (todoist.TemplateFile, error)
+	// ExportAsURLCalls records the arguments of every call to ExportAsURL.
+	ExportAsURLCalls []TemplatesAPIExportAsURLCall
+}
+
+// TemplatesAPIImportIntoProjectCall records the arguments of a call to TemplatesAPI.ImportIntoProject.
+type TemplatesAPIImportIntoProjectCall struct {
+	Ctx       context.Context
+	ProjectID todoist.ProjectID
+	Template  *todoist.Template
+}
+
+// ImportIntoProject implements todoist.TemplatesAPI.
+func (m *TemplatesAPI) ImportIntoProject(ctx context.Context, projectID todoist.ProjectID, template *todoist.Template) (todoist.TemplateImport, error) {
+	m.mu.Lock()
+	m.ImportIntoProjectCalls = append(m.ImportIntoProjectCalls, TemplatesAPIImportIntoProjectCall{Ctx: ctx, ProjectID: projectID, Template: template})
+	fn := m.ImportIntoProjectFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, projectID, template)
+	}
+
+	var r0 todoist.TemplateImport
+	var r1 error
+	return r0, r1
+}
+
+// TemplatesAPIImportFileCall records the arguments of a call to TemplatesAPI.ImportFile.
+type TemplatesAPIImportFileCall struct {
+	Ctx       context.Context
+	ProjectID todoist.ProjectID
+	FileName  string
+	File      io.Reader
+}
+
+// ImportFile implements todoist.TemplatesAPI.
+func (m *TemplatesAPI) ImportFile(ctx context.Context, projectID todoist.ProjectID, fileName string, file io.Reader) (todoist.TemplateImport, error) {
+	m.mu.Lock()
+	m.ImportFileCalls = append(m.ImportFileCalls, TemplatesAPIImportFileCall{Ctx: ctx, ProjectID: projectID, FileName: fileName, File: file})
+	fn := m.ImportFileFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, projectID, fileName, file)
+	}
+
+	var r0 todoist.TemplateImport
+	var r1 error
+	return r0, r1
+}
+
+// TemplatesAPIExportAsFileCall records the arguments of a call to TemplatesAPI.ExportAsFile.
+type TemplatesAPIExportAsFileCall struct {
+	Ctx       context.Context
+	ProjectID todoist.ProjectID
+}
+
+// ExportAsFile implements todoist.TemplatesAPI.
+func (m *TemplatesAPI) ExportAsFile(ctx context.Context, projectID todoist.ProjectID) (*todoist.Template, error) {
+	m.mu.Lock()
+	m.ExportAsFileCalls = append(m.ExportAsFileCalls, TemplatesAPIExportAsFileCall{Ctx: ctx, ProjectID: projectID})
+	fn := m.ExportAsFileFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, projectID)
+	}
+
+	var r0 *todoist.Template
+	var r1 error
+	return r0, r1
+}
+
+// TemplatesAPIExportAsURLCall records the arguments of a call to TemplatesAPI.ExportAsURL.
+type TemplatesAPIExportAsURLCall struct {
+	Ctx       context.Context
+	ProjectID todoist.ProjectID
+}
+
+// ExportAsURL implements todoist.TemplatesAPI.
+func (m *TemplatesAPI) ExportAsURL(ctx context.Context, projectID todoist.ProjectID) (todoist.TemplateFile, error) {
+	m.mu.Lock()
+	m.ExportAsURLCalls = append(m.ExportAsURLCalls, TemplatesAPIExportAsURLCall{Ctx: ctx, ProjectID: projectID})
+	fn := m.ExportAsURLFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, projectID)
+	}
+
+	var r0 todoist.TemplateFile
+	var r1 error
+	return r0, r1
+}
+
+// BackupsAPI is a mock implementation of todoist.BackupsAPI.
+type BackupsAPI struct {
+	mu sync.Mutex
+
+	// ListFunc, if set, is called by List.
+	ListFunc func(ctx context.Context) ([]todoist.Backup, error)
+	// ListCalls records the arguments of every call to List.
+	ListCalls []BackupsAPIListCall
+
+	// DownloadFunc, if set, is called by Download.
+	DownloadFunc func(ctx context.Context, backup todoist.Backup, w io.Writer) error
+	// DownloadCalls records the arguments of every call to Download.
+	DownloadCalls []BackupsAPIDownloadCall
+
+	// GetFunc, if set, is called by Get.
+	GetFunc func(ctx context.Context, backup todoist.Backup) (*todoist.BackupArchive, error)
+	// GetCalls records the arguments of every call to Get.
+	GetCalls []BackupsAPIGetCall
+}
+
+// BackupsAPIListCall records the arguments of a call to BackupsAPI.List.
+type BackupsAPIListCall struct {
+	Ctx context.Context
+}
+
+// List implements todoist.BackupsAPI.
+func (m *BackupsAPI) List(ctx context.Context) ([]todoist.Backup, error) {
+	m.mu.Lock()
+	m.ListCalls = append(m.ListCalls, BackupsAPIListCall{Ctx: ctx})
+	fn := m.ListFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx)
+	}
+
+	var r0 []todoist.Backup
+	var r1 error
+	return r0, r1
+}
+
+// BackupsAPIDownloadCall records the arguments of a call to BackupsAPI.Download.
+type BackupsAPIDownloadCall struct {
+	Ctx    context.Context
+	Backup todoist.Backup
+	W      io.Writer
+}
+
+// Download implements todoist.BackupsAPI.
+func (m *BackupsAPI) Download(ctx context.Context, backup todoist.Backup, w io.Writer) error {
+	m.mu.Lock()
+	m.DownloadCalls = append(m.DownloadCalls, BackupsAPIDownloadCall{Ctx: ctx, Backup: backup, W: w})
+	fn := m.DownloadFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, backup, w)
+	}
+
+	var r0 error
+	return r0
+}
+
+// BackupsAPIGetCall records the arguments of a call to BackupsAPI.Get.
+type BackupsAPIGetCall struct {
+	Ctx    context.Context
+	Backup todoist.Backup
+}
+
+// Get implements todoist.BackupsAPI.
+func (m *BackupsAPI) Get(ctx context.Context, backup todoist.Backup) (*todoist.BackupArchive, error) {
+	m.mu.Lock()
+	m.GetCalls = append(m.GetCalls, BackupsAPIGetCall{Ctx: ctx, Backup: backup})
+	fn := m.GetFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, backup)
+	}
+
+	var r0 *todoist.BackupArchive
+	var r1 error
+	return r0, r1
+}
+
+// UploadsAPI is a mock implementation of todoist.UploadsAPI.
+type UploadsAPI struct {
+	mu sync.Mutex
+
+	// AddFunc, if set, is called by Add.
+	AddFunc func(ctx context.Context, fileName string, contentType string, file io.Reader) (todoist.FileAttachment, error)
+	// AddCalls records the arguments of every call to Add.
+	AddCalls []UploadsAPIAddCall
+
+	// ListFunc, if set, is called by List.
+	ListFunc func(ctx context.Context, limit int) ([]todoist.FileAttachment, error)
+	// ListCalls records the arguments of every call to List.
+	ListCalls []UploadsAPIListCall
+
+	// DeleteFunc, if set, is called by Delete.
+	DeleteFunc func(ctx context.Context, fileURL string) error
+	// DeleteCalls records the arguments of every call to Delete.
+	DeleteCalls []UploadsAPIDeleteCall
+
+	// AddToTaskFunc, if set, is called by AddToTask.
+	AddToTaskFunc func(ctx context.Context, syncToken string, taskID todoist.TaskID, content string, fileName string, contentType string, file io.Reader) (todoist.Comment, todoist.CommandResponse, error)
+	// AddToTaskCalls records the arguments of every call to AddToTask.
+	AddToTaskCalls []UploadsAPIAddToTaskCall
+}
+
+// UploadsAPIAddCall records the arguments of a call to UploadsAPI.Add.
+type UploadsAPIAddCall struct {
+	Ctx         context.Context
+	FileName    string
+	ContentType string
+	File        io.Reader
+}
+
+// Add implements todoist.UploadsAPI.
+func (m *UploadsAPI) Add(ctx context.Context, fileName string, contentType string, file io.Reader) (todoist.FileAttachment, error) {
+	m.mu.Lock()
+	m.AddCalls = append(m.AddCalls, UploadsAPIAddCall{Ctx: ctx, FileName: fileName, ContentType: contentType, File: file})
+	fn := m.AddFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, fileName, contentType, file)
+	}
+
+	var r0 todoist.FileAttachment
+	var r1 error
+	return r0, r1
+}
+
+// UploadsAPIListCall records the arguments of a call to UploadsAPI.List.
+type UploadsAPIListCall struct {
+	Ctx   context.Context
+	Limit int
+}
+
+// List implements todoist.UploadsAPI.
+func (m *UploadsAPI) List(ctx context.Context, limit int) ([]todoist.FileAttachment, error) {
+	m.mu.Lock()
+	m.ListCalls = append(m.ListCalls, UploadsAPIListCall{Ctx: ctx, Limit: limit})
+	fn := m.ListFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, limit)
+	}
+
+	var r0 []todoist.FileAttachment
+	var r1 error
+	return r0, r1
+}
+
+// UploadsAPIDeleteCall records the arguments of a call to UploadsAPI.Delete.
+type UploadsAPIDeleteCall struct {
+	Ctx     context.Context
+	FileURL string
+}
+
+// Delete implements todoist.UploadsAPI.
+func (m *UploadsAPI) Delete(ctx context.Context, fileURL string) error {
+	m.mu.Lock()
+	m.DeleteCalls = append(m.DeleteCalls, UploadsAPIDeleteCall{Ctx: ctx, FileURL: fileURL})
+	fn := m.DeleteFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, fileURL)
+	}
+
+	var r0 error
+	return r0
+}
+
+// UploadsAPIAddToTaskCall records the arguments of a call to UploadsAPI.AddToTask.
+type UploadsAPIAddToTaskCall struct {
+	Ctx         context.Context
+	SyncToken   string
+	TaskID      todoist.TaskID
+	Content     string
+	FileName    string
+	ContentType string
+	File        io.Reader
+}
+
+// AddToTask implements todoist.UploadsAPI.
+func (m *UploadsAPI) AddToTask(ctx context.Context, syncToken string, taskID todoist.TaskID, content string, fileName string, contentType string, file io.Reader) (todoist.Comment, todoist.CommandResponse, error) {
+	m.mu.Lock()
+	m.AddToTaskCalls = append(m.AddToTaskCalls, UploadsAPIAddToTaskCall{Ctx: ctx, SyncToken: syncToken, TaskID: taskID, Content: content, FileName: fileName, ContentType: contentType, File: file})
+	fn := m.AddToTaskFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, syncToken, taskID, content, fileName, contentType, file)
+	}
+
+	var r0 todoist.Comment
+	var r1 todoist.CommandResponse
+	var r2 error
+	return r0, r1, r2
+}
+
+// RESTTasksAPI is a mock implementation of todoist.RESTTasksAPI.
+type RESTTasksAPI struct {
+	mu sync.Mutex
+
+	// ListFunc, if set, is called by List.
+	ListFunc func(ctx context.Context, filter *todoist.TaskFilter) ([]todoist.Task, error)
+	// ListCalls records the arguments of every call to List.
+	ListCalls []RESTTasksAPIListCall
+
+	// GetFunc, if set, is called by Get.
+	GetFunc func(ctx context.Context, id todoist.TaskID) (todoist.Task, error)
+	// GetCalls records the arguments of every call to Get.
+	GetCalls []RESTTasksAPIGetCall
+
+	// CreateFunc, if set, is called by Create.
+	CreateFunc func(ctx context.Context, createTask todoist.CreateTaskParams) (todoist.Task, error)
+	// CreateCalls records the arguments of every call to Create.
+	CreateCalls []RESTTasksAPICreateCall
+
+	// UpdateFunc, if set, is called by Update.
+	UpdateFunc func(ctx context.Context, id todoist.TaskID, updateTask todoist.UpdateTaskParams) (todoist.Task, error)
+	// UpdateCalls records the arguments of every call to Update.
+	UpdateCalls []RESTTasksAPIUpdateCall
+
+	// CloseFunc, if set, is called by Close.
+	CloseFunc func(ctx context.Context, id todoist.TaskID) error
+	// CloseCalls records the arguments of every call to Close.
+	CloseCalls []RESTTasksAPICloseCall
+
+	// ReopenFunc, if set, is called by Reopen.
+	ReopenFunc func(ctx context.Context, id todoist.TaskID) error
+	// ReopenCalls records the arguments of every call to Reopen.
+	ReopenCalls []RESTTasksAPIReopenCall
+
+	// DeleteFunc, if set, is called by Delete.
+	DeleteFunc func(ctx context.Context, id todoist.TaskID) error
+	// DeleteCalls records the arguments of every call to Delete.
+	DeleteCalls []RESTTasksAPIDeleteCall
+}
+
+// RESTTasksAPIListCall records the arguments of a call to RESTTasksAPI.List.
+type RESTTasksAPIListCall struct {
+	Ctx    context.Context
+	Filter *todoist.TaskFilter
+}
+
+// List implements todoist.RESTTasksAPI.
+func (m *RESTTasksAPI) List(ctx context.Context, filter *todoist.TaskFilter) ([]todoist.Task, error) {
+	m.mu.Lock()
+	m.ListCalls = append(m.ListCalls, RESTTasksAPIListCall{Ctx: ctx, Filter: filter})
+	fn := m.ListFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, filter)
+	}
+
+	var r0 []todoist.Task
+	var r1 error
+	return r0, r1
+}
+
+// RESTTasksAPIGetCall records the arguments of a call to RESTTasksAPI.Get.
+type RESTTasksAPIGetCall struct {
+	Ctx context.Context
+	Id  todoist.TaskID
+}
+
+// Get implements todoist.RESTTasksAPI.
+func (m *RESTTasksAPI) Get(ctx context.Context, id todoist.TaskID) (todoist.Task, error) {
+	m.mu.Lock()
+	m.GetCalls = append(m.GetCalls, RESTTasksAPIGetCall{Ctx: ctx, Id: id})
+	fn := m.GetFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, id)
+	}
+
+	var r0 todoist.Task
+	var r1 error
+	return r0, r1
+}
+
+// RESTTasksAPICreateCall records the arguments of a call to RESTTasksAPI.Create.
+type RESTTasksAPICreateCall struct {
+	Ctx        context.Context
+	CreateTask todoist.CreateTaskParams
+}
+
+// Create implements todoist.RESTTasksAPI.
+func (m *RESTTasksAPI) Create(ctx context.Context, createTask todoist.CreateTaskParams) (todoist.Task, error) {
+	m.mu.Lock()
+	m.CreateCalls = append(m.CreateCalls, RESTTasksAPICreateCall{Ctx: ctx, CreateTask: createTask})
+	fn := m.CreateFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, createTask)
+	}
+
+	var r0 todoist.Task
+	var r1 error
+	return r0, r1
+}
+
+// RESTTasksAPIUpdateCall records the arguments of a call to RESTTasksAPI.Update.
+type RESTTasksAPIUpdateCall struct {
+	Ctx        context.Context
+	Id         todoist.TaskID
+	UpdateTask todoist.UpdateTaskParams
+}
+
+// Update implements todoist.RESTTasksAPI.
+func (m *RESTTasksAPI) Update(ctx context.Context, id todoist.TaskID, updateTask todoist.UpdateTaskParams) (todoist.Task, error) {
+	m.mu.Lock()
+	m.UpdateCalls = append(m.UpdateCalls, RESTTasksAPIUpdateCall{Ctx: ctx, Id: id, UpdateTask: updateTask})
+	fn := m.UpdateFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, id, updateTask)
+	}
+
+	var r0 todoist.Task
+	var r1 error
+	return r0, r1
+}
+
+// RESTTasksAPICloseCall records the arguments of a call to RESTTasksAPI.Close.
+type RESTTasksAPICloseCall struct {
+	Ctx context.Context
+	Id  todoist.TaskID
+}
+
+// Close implements todoist.RESTTasksAPI.
+func (m *RESTTasksAPI) Close(ctx context.Context, id todoist.TaskID) error {
+	m.mu.Lock()
+	m.CloseCalls = append(m.CloseCalls, RESTTasksAPICloseCall{Ctx: ctx, Id: id})
+	fn := m.CloseFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, id)
+	}
+
+	var r0 error
+	return r0
+}
+
+// RESTTasksAPIReopenCall records the arguments of a call to RESTTasksAPI.Reopen.
+type RESTTasksAPIReopenCall struct {
+	Ctx context.Context
+	Id  todoist.TaskID
+}
+
+// Reopen implements todoist.RESTTasksAPI.
+func (m *RESTTasksAPI) Reopen(ctx context.Context, id todoist.TaskID) error {
+	m.mu.Lock()
+	m.ReopenCalls = append(m.ReopenCalls, RESTTasksAPIReopenCall{Ctx: ctx, Id: id})
+	fn := m.ReopenFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, id)
+	}
+
+	var r0 error
+	return r0
+}
+
+// RESTTasksAPIDeleteCall records the arguments of a call to RESTTasksAPI.Delete.
+type RESTTasksAPIDeleteCall struct {
+	Ctx context.Context
+	Id  todoist.TaskID
+}
+
+// Delete implements todoist.RESTTasksAPI.
+func (m *RESTTasksAPI) Delete(ctx context.Context, id todoist.TaskID) error {
+	m.mu.Lock()
+	m.DeleteCalls = append(m.DeleteCalls, RESTTasksAPIDeleteCall{Ctx: ctx, Id: id})
+	fn := m.DeleteFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, id)
+	}
+
+	var r0 error
+	return r0
+}
+
+// RESTProjectsAPI is a mock implementation of todoist.RESTProjectsAPI.
+type RESTProjectsAPI struct {
+	mu sync.Mutex
+
+	// ListFunc, if set, is called by List.
+	ListFunc func(ctx context.Context) ([]todoist.Project, error)
+	// ListCalls records the arguments of every call to List.
+	ListCalls []RESTProjectsAPIListCall
+
+	// GetFunc, if set, is called by Get.
+	GetFunc func(ctx context.Context, id todoist.ProjectID) (todoist.Project, error)
+	// GetCalls records the arguments of every call to Get.
+	GetCalls []RESTProjectsAPIGetCall
+
+	// CreateFunc, if set, is called by Create.
+	CreateFunc func(ctx context.Context, createProject todoist.CreateProjectParams) (todoist.Project, error)
+	// CreateCalls records the arguments of every call to Create.
+	CreateCalls []RESTProjectsAPICreateCall
+
+	// UpdateFunc, if set, is called by Update.
+	UpdateFunc func(ctx context.Context, id todoist.ProjectID, updateProject todoist.UpdateProjectParams) (todoist.Project, error)
+	// UpdateCalls records the arguments of every call to Update.
+	UpdateCalls []RESTProjectsAPIUpdateCall
+
+	// DeleteFunc, if set, is called by Delete.
+	DeleteFunc func(ctx context.Context, id todoist.ProjectID) error
+	// DeleteCalls records the arguments of every call to Delete.
+	DeleteCalls []RESTProjectsAPIDeleteCall
+}
+
+// RESTProjectsAPIListCall records the arguments of a call to RESTProjectsAPI.List.
+type RESTProjectsAPIListCall struct {
+	Ctx context.Context
+}
+
+// List implements todoist.RESTProjectsAPI.
+func (m *RESTProjectsAPI) List(ctx context.Context) ([]todoist.Project, error) {
+	m.mu.Lock()
+	m.ListCalls = append(m.ListCalls, RESTProjectsAPIListCall{Ctx: ctx})
+	fn := m.ListFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx)
+	}
+
+	var r0 []todoist.Project
+	var r1 error
+	return r0, r1
+}
+
+// RESTProjectsAPIGetCall records the arguments of a call to RESTProjectsAPI.Get.
+type RESTProjectsAPIGetCall struct {
+	Ctx context.Context
+	Id  todoist.ProjectID
+}
+
+// Get implements todoist.RESTProjectsAPI.
+func (m *RESTProjectsAPI) Get(ctx context.Context, id todoist.ProjectID) (todoist.Project, error) {
+	m.mu.Lock()
+	m.GetCalls = append(m.GetCalls, RESTProjectsAPIGetCall{Ctx: ctx, Id: id})
+	fn := m.GetFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, id)
+	}
+
+	var r0 todoist.Project
+	var r1 error
+	return r0, r1
+}
+
+// RESTProjectsAPICreateCall records the arguments of a call to RESTProjectsAPI.Create.
+type RESTProjectsAPICreateCall struct {
+	Ctx           context.Context
+	CreateProject todoist.CreateProjectParams
+}
+
+// Create implements todoist.RESTProjectsAPI.
+func (m *RESTProjectsAPI) Create(ctx context.Context, createProject todoist.CreateProjectParams) (todoist.Project, error) {
+	m.mu.Lock()
+	m.CreateCalls = append(m.CreateCalls, RESTProjectsAPICreateCall{Ctx: ctx, CreateProject: createProject})
+	fn := m.CreateFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, createProject)
+	}
+
+	var r0 todoist.Project
+	var r1 error
+	return r0, r1
+}
+
+// RESTProjectsAPIUpdateCall records the arguments of a call to RESTProjectsAPI.Update.
+type RESTProjectsAPIUpdateCall struct {
+	Ctx           context.Context
+	Id            todoist.ProjectID
+	UpdateProject todoist.UpdateProjectParams
+}
+
+// Update implements todoist.RESTProjectsAPI.
+func (m *RESTProjectsAPI) Update(ctx context.Context, id todoist.ProjectID, updateProject todoist.UpdateProjectParams) (todoist.Project, error) {
+	m.mu.Lock()
+	m.UpdateCalls = append(m.UpdateCalls, RESTProjectsAPIUpdateCall{Ctx: ctx, Id: id, UpdateProject: updateProject})
+	fn := m.UpdateFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, id, updateProject)
+	}
+
+	var r0 todoist.Project
+	var r1 error
+	return r0, r1
+}
+
+// RESTProjectsAPIDeleteCall records the arguments of a call to RESTProjectsAPI.Delete.
+type RESTProjectsAPIDeleteCall struct {
+	Ctx context.Context
+	Id  todoist.ProjectID
+}
+
+// Delete implements todoist.RESTProjectsAPI.
+func (m *RESTProjectsAPI) Delete(ctx context.Context, id todoist.ProjectID) error {
+	m.mu.Lock()
+	m.DeleteCalls = append(m.DeleteCalls, RESTProjectsAPIDeleteCall{Ctx: ctx, Id: id})
+	fn := m.DeleteFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, id)
+	}
+
+	var r0 error
+	return r0
+}
+
+// RESTSectionsAPI is a mock implementation of todoist.RESTSectionsAPI.
+type RESTSectionsAPI struct {
+	mu sync.Mutex
+
+	// ListFunc, if set, is called by List.
+	ListFunc func(ctx context.Context, projectID *todoist.ProjectID) ([]todoist.Section, error)
+	// ListCalls records the arguments of every call to List.
+	ListCalls []RESTSectionsAPIListCall
+
+	// GetFunc, if set, is called by Get.
+	GetFunc func(ctx context.Context, id todoist.SectionID) (todoist.Section, error)
+	// GetCalls records the arguments of every call to Get.
+	GetCalls []RESTSectionsAPIGetCall
+
+	// CreateFunc, if set, is called by Create.
+	CreateFunc func(ctx context.Context, createSection todoist.CreateSectionParams) (todoist.Section, error)
+	// CreateCalls records the arguments of every call to Create.
+	CreateCalls []RESTSectionsAPICreateCall
+
+	// UpdateFunc, if set, is called by Update.
+	UpdateFunc func(ctx context.Context, id todoist.SectionID, updateSection todoist.UpdateSectionParams) (todoist.Section, error)
+	// UpdateCalls records the arguments of every call to Update.
+	UpdateCalls []RESTSectionsAPIUpdateCall
+
+	// DeleteFunc, if set, is called by Delete.
+	DeleteFunc func(ctx context.Context, id todoist.SectionID) error
+	// DeleteCalls records the arguments of every call to Delete.
+	DeleteCalls []RESTSectionsAPIDeleteCall
+}
+
+// RESTSectionsAPIListCall records the arguments of a call to RESTSectionsAPI.List.
+type RESTSectionsAPIListCall struct {
+	Ctx       context.Context
+	ProjectID *todoist.ProjectID
+}
+
+// List implements todoist.RESTSectionsAPI.
+func (m *RESTSectionsAPI) List(ctx context.Context, projectID *todoist.ProjectID) ([]todoist.Section, error) {
+	m.mu.Lock()
+	m.ListCalls = append(m.ListCalls, RESTSectionsAPIListCall{Ctx: ctx, ProjectID: projectID})
+	fn := m.ListFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, projectID)
+	}
+
+	var r0 []todoist.Section
+	var r1 error
+	return r0, r1
+}
+
+// RESTSectionsAPIGetCall records the arguments of a call to RESTSectionsAPI.Get.
+type RESTSectionsAPIGetCall struct {
+	Ctx context.Context
+	Id  todoist.SectionID
+}
+
+// Get implements todoist.RESTSectionsAPI.
+func (m *RESTSectionsAPI) Get(ctx context.Context, id todoist.SectionID) (todoist.Section, error) {
+	m.mu.Lock()
+	m.GetCalls = append(m.GetCalls, RESTSectionsAPIGetCall{Ctx: ctx, Id: id})
+	fn := m.GetFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, id)
+	}
+
+	var r0 todoist.Section
+	var r1 error
+	return r0, r1
+}
+
+// RESTSectionsAPICreateCall records the arguments of a call to RESTSectionsAPI.Create.
+type RESTSectionsAPICreateCall struct {
+	Ctx           context.Context
+	CreateSection todoist.CreateSectionParams
+}
+
+// Create implements todoist.RESTSectionsAPI.
+func (m *RESTSectionsAPI) Create(ctx context.Context, createSection todoist.CreateSectionParams) (todoist.Section, error) {
+	m.mu.Lock()
+	m.CreateCalls = append(m.CreateCalls, RESTSectionsAPICreateCall{Ctx: ctx, CreateSection: createSection})
+	fn := m.CreateFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, createSection)
+	}
+
+	var r0 todoist.Section
+	var r1 error
+	return r0, r1
+}
+
+// RESTSectionsAPIUpdateCall records the arguments of a call to RESTSectionsAPI.Update.
+type RESTSectionsAPIUpdateCall struct {
+	Ctx           context.Context
+	Id            todoist.SectionID
+	UpdateSection todoist.UpdateSectionParams
+}
+
+// Update implements todoist.RESTSectionsAPI.
+func (m *RESTSectionsAPI) Update(ctx context.Context, id todoist.SectionID, updateSection todoist.UpdateSectionParams) (todoist.Section, error) {
+	m.mu.Lock()
+	m.UpdateCalls = append(m.UpdateCalls, RESTSectionsAPIUpdateCall{Ctx: ctx, Id: id, UpdateSection: updateSection})
+	fn := m.UpdateFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, id, updateSection)
+	}
+
+	var r0 todoist.Section
+	var r1 error
+	return r0, r1
+}
+
+// RESTSectionsAPIDeleteCall records the arguments of a call to RESTSectionsAPI.Delete.
+type RESTSectionsAPIDeleteCall struct {
+	Ctx context.Context
+	Id  todoist.SectionID
+}
+
+// Delete implements todoist.RESTSectionsAPI.
+func (m *RESTSectionsAPI) Delete(ctx context.Context, id todoist.SectionID) error {
+	m.mu.Lock()
+	m.DeleteCalls = append(m.DeleteCalls, RESTSectionsAPIDeleteCall{Ctx: ctx, Id: id})
+	fn := m.DeleteFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, id)
+	}
+
+	var r0 error
+	return r0
+}
+
+// RESTLabelsAPI is a mock implementation of todoist.RESTLabelsAPI.
+type RESTLabelsAPI struct {
+	mu sync.Mutex
+
+	// ListFunc, if set, is called by List.
+	ListFunc func(ctx context.Context) ([]todoist.Label, error)
+	// ListCalls records the arguments of every call to List.
+	ListCalls []RESTLabelsAPIListCall
+
+	// GetFunc, if set, is called by Get.
+	GetFunc func(ctx context.Context, id todoist.LabelID) (todoist.Label, error)
+	// GetCalls records the arguments of every call to Get.
+	GetCalls []RESTLabelsAPIGetCall
+
+	// CreateFunc, if set, is called by Create.
+	CreateFunc func(ctx context.Context, createLabel todoist.CreateLabelParams) (todoist.Label, error)
+	// CreateCalls records the arguments of every call to Create.
+	CreateCalls []RESTLabelsAPICreateCall
+
+	// UpdateFunc, if set, is called by Update.
+	UpdateFunc func(ctx context.Context, id todoist.LabelID, updateLabel todoist.UpdateLabelParams) (todoist.Label, error)
+	// UpdateCalls records the arguments of every call to Update.
+	UpdateCalls []RESTLabelsAPIUpdateCall
+
+	// DeleteFunc, if set, is called by Delete.
+	DeleteFunc func(ctx context.Context, id todoist.LabelID) error
+	// DeleteCalls records the arguments of every call to Delete.
+	DeleteCalls []RESTLabelsAPIDeleteCall
+}
+
+// RESTLabelsAPIListCall records the arguments of a call to RESTLabelsAPI.List.
+type RESTLabelsAPIListCall struct {
+	Ctx context.Context
+}
+
+// List implements todoist.RESTLabelsAPI.
+func (m *RESTLabelsAPI) List(ctx context.Context) ([]todoist.Label, error) {
+	m.mu.Lock()
+	m.ListCalls = append(m.ListCalls, RESTLabelsAPIListCall{Ctx: ctx})
+	fn := m.ListFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx)
+	}
+
+	var r0 []todoist.Label
+	var r1 error
+	return r0, r1
+}
+
+// RESTLabelsAPIGetCall records the arguments of a call to RESTLabelsAPI.Get.
+type RESTLabelsAPIGetCall struct {
+	Ctx context.Context
+	Id  todoist.LabelID
+}
+
+// Get implements todoist.RESTLabelsAPI.
+func (m *RESTLabelsAPI) Get(ctx context.Context, id todoist.LabelID) (todoist.Label, error) {
+	m.mu.Lock()
+	m.GetCalls = append(m.GetCalls, RESTLabelsAPIGetCall{Ctx: ctx, Id: id})
+	fn := m.GetFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, id)
+	}
+
+	var r0 todoist.Label
+	var r1 error
+	return r0, r1
+}
+
+// RESTLabelsAPICreateCall records the arguments of a call to RESTLabelsAPI.Create.
+type RESTLabelsAPICreateCall struct {
+	Ctx         context.Context
+	CreateLabel todoist.CreateLabelParams
+}
+
+// Create implements todoist.RESTLabelsAPI.
+func (m *RESTLabelsAPI) Create(ctx context.Context, createLabel todoist.CreateLabelParams) (todoist.Label, error) {
+	m.mu.Lock()
+	m.CreateCalls = append(m.CreateCalls, RESTLabelsAPICreateCall{Ctx: ctx, CreateLabel: createLabel})
+	fn := m.CreateFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, createLabel)
+	}
+
+	var r0 todoist.Label
+	var r1 error
+	return r0, r1
+}
+
+// RESTLabelsAPIUpdateCall records the arguments of a call to RESTLabelsAPI.Update.
+type RESTLabelsAPIUpdateCall struct {
+	Ctx         context.Context
+	Id          todoist.LabelID
+	UpdateLabel todoist.UpdateLabelParams
+}
+
+// Update implements todoist.RESTLabelsAPI.
+func (m *RESTLabelsAPI) Update(ctx context.Context, id todoist.LabelID, updateLabel todoist.UpdateLabelParams) (todoist.Label, error) {
+	m.mu.Lock()
+	m.UpdateCalls = append(m.UpdateCalls, RESTLabelsAPIUpdateCall{Ctx: ctx, Id: id, UpdateLabel: updateLabel})
+	fn := m.UpdateFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, id, updateLabel)
+	}
+
+	var r0 todoist.Label
+	var r1 error
+	return r0, r1
+}
+
+// RESTLabelsAPIDeleteCall records the arguments of a call to RESTLabelsAPI.Delete.
+type RESTLabelsAPIDeleteCall struct {
+	Ctx context.Context
+	Id  todoist.LabelID
+}
+
+// Delete implements todoist.RESTLabelsAPI.
+func (m *RESTLabelsAPI) Delete(ctx context.Context, id todoist.LabelID) error {
+	m.mu.Lock()
+	m.DeleteCalls = append(m.DeleteCalls, RESTLabelsAPIDeleteCall{Ctx: ctx, Id: id})
+	fn := m.DeleteFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, id)
+	}
+
+	var r0 error
+	return r0
+}
+
+// RESTCommentsAPI is a mock implementation of todoist.RESTCommentsAPI.
+type RESTCommentsAPI struct {
+	mu sync.Mutex
+
+	// ListFunc, if set, is called by List.
+	ListFunc func(ctx context.Context, filter todoist.CommentFilter) ([]todoist.Comment, error)
+	// ListCalls records the arguments of every call to List.
+	ListCalls []RESTCommentsAPIListCall
+
+	// GetFunc, if set, is called by Get.
+	GetFunc func(ctx context.Context, id todoist.CommentID) (todoist.Comment, error)
+	// GetCalls records the arguments of every call to Get.
+	GetCalls []RESTCommentsAPIGetCall
+
+	// CreateFunc, if set, is called by Create.
+	CreateFunc func(ctx context.Context, createComment todoist.CreateCommentParams) (todoist.Comment, error)
+	// CreateCalls records the arguments of every call to Create.
+	CreateCalls []RESTCommentsAPICreateCall
+
+	// UpdateFunc, if set, is called by Update.
+	UpdateFunc func(ctx context.Context, id todoist.CommentID, updateComment todoist.UpdateCommentParams) (todoist.Comment, error)
+	// UpdateCalls records the arguments of every call to Update.
+	UpdateCalls []RESTCommentsAPIUpdateCall
+
+	// DeleteFunc, if set, is called by Delete.
+	DeleteFunc func(ctx context.Context, id todoist.CommentID) error
+	// DeleteCalls records the arguments of every call to Delete.
+	DeleteCalls []RESTCommentsAPIDeleteCall
+}
+
+// RESTCommentsAPIListCall records the arguments of a call to RESTCommentsAPI.List.
+type RESTCommentsAPIListCall struct {
+	Ctx    context.Context
+	Filter todoist.CommentFilter
+}
+
+// List implements todoist.RESTCommentsAPI.
+func (m *RESTCommentsAPI) List(ctx context.Context, filter todoist.CommentFilter) ([]todoist.Comment, error) {
+	m.mu.Lock()
+	m.ListCalls = append(m.ListCalls, RESTCommentsAPIListCall{Ctx: ctx, Filter: filter})
+	fn := m.ListFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, filter)
+	}
+
+	var r0 []todoist.Comment
+	var r1 error
+	return r0, r1
+}
+
+// RESTCommentsAPIGetCall records the arguments of a call to RESTCommentsAPI.Get.
+type RESTCommentsAPIGetCall struct {
+	Ctx context.Context
+	Id  todoist.CommentID
+}
+
+// Get implements todoist.RESTCommentsAPI.
+func (m *RESTCommentsAPI) Get(ctx context.Context, id todoist.CommentID) (todoist.Comment, error) {
+	m.mu.Lock()
+	m.GetCalls = append(m.GetCalls, RESTCommentsAPIGetCall{Ctx: ctx, Id: id})
+	fn := m.GetFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, id)
+	}
+
+	var r0 todoist.Comment
+	var r1 error
+	return r0, r1
+}
+
+// RESTCommentsAPICreateCall records the arguments of a call to RESTCommentsAPI.Create.
+type RESTCommentsAPICreateCall struct {
+	Ctx           context.Context
+	CreateComment todoist.CreateCommentParams
+}
+
+// Create implements todoist.RESTCommentsAPI.
+func (m *RESTCommentsAPI) Create(ctx context.Context, createComment todoist.CreateCommentParams) (todoist.Comment, error) {
+	m.mu.Lock()
+	m.CreateCalls = append(m.CreateCalls, RESTCommentsAPICreateCall{Ctx: ctx, CreateComment: createComment})
+	fn := m.CreateFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, createComment)
+	}
+
+	var r0 todoist.Comment
+	var r1 error
+	return r0, r1
+}
+
+// RESTCommentsAPIUpdateCall records the arguments of a call to RESTCommentsAPI.Update.
+type RESTCommentsAPIUpdateCall struct {
+	Ctx           context.Context
+	Id            todoist.CommentID
+	UpdateComment todoist.UpdateCommentParams
+}
+
+// Update implements todoist.RESTCommentsAPI.
+func (m *RESTCommentsAPI) Update(ctx context.Context, id todoist.CommentID, updateComment todoist.UpdateCommentParams) (todoist.Comment, error) {
+	m.mu.Lock()
+	m.UpdateCalls = append(m.UpdateCalls, RESTCommentsAPIUpdateCall{Ctx: ctx, Id: id, UpdateComment: updateComment})
+	fn := m.UpdateFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, id, updateComment)
+	}
+
+	var r0 todoist.Comment
+	var r1 error
+	return r0, r1
+}
+
+// RESTCommentsAPIDeleteCall records the arguments of a call to RESTCommentsAPI.Delete.
+type RESTCommentsAPIDeleteCall struct {
+	Ctx context.Context
+	Id  todoist.CommentID
+}
+
+// Delete implements todoist.RESTCommentsAPI.
+func (m *RESTCommentsAPI) Delete(ctx context.Context, id todoist.CommentID) error {
+	m.mu.Lock()
+	m.DeleteCalls = append(m.DeleteCalls, RESTCommentsAPIDeleteCall{Ctx: ctx, Id: id})
+	fn := m.DeleteFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, id)
+	}
+
+	var r0 error
+	return r0
+}
@@ -0,0 +1,53 @@
+// Package todoistmock provides mock implementations of the todoist package's
+// service interfaces, for unit testing code that depends on todoist.API
+// without any HTTP traffic.
+//
+// Each mock has a <Method>Func field per interface method, which is called if
+// set, and a <Method>Calls field recording the arguments of every call. Unset
+// methods return zero values.
+//
+// The mocks in mocks.go are generated from api.go; run go generate from the
+// repository root after changing an interface.
+package todoistmock
+
+import "github.com/ides15/todoist"
+
+// NewAPI returns an API mock whose service accessors return the given service
+// mocks. Any of them may be nil, in which case an empty mock is used. The
+// accessors of the other services return empty mocks, and can be changed
+// through their Func fields.
+func NewAPI(projects *ProjectsAPI, sections *SectionsAPI, tasks *TasksAPI) *API {
+	if projects == nil {
+		projects = &ProjectsAPI{}
+	}
+	if sections == nil {
+		sections = &SectionsAPI{}
+	}
+	if tasks == nil {
+		tasks = &TasksAPI{}
+	}
+
+	templates := &TemplatesAPI{}
+	backups := &BackupsAPI{}
+	uploads := &UploadsAPI{}
+	restTasks := &RESTTasksAPI{}
+	restProjects := &RESTProjectsAPI{}
+	restSections := &RESTSectionsAPI{}
+	restLabels := &RESTLabelsAPI{}
+	restComments := &RESTCommentsAPI{}
+
+	return &API{
+		ProjectsAPIFunc: func() todoist.ProjectsAPI { return projects },
+		SectionsAPIFunc: func() todoist.SectionsAPI { return sections },
+		TasksAPIFunc:    func() todoist.TasksAPI { return tasks },
+
+		TemplatesAPIFunc:    func() todoist.TemplatesAPI { return templates },
+		BackupsAPIFunc:      func() todoist.BackupsAPI { return backups },
+		UploadsAPIFunc:      func() todoist.UploadsAPI { return uploads },
+		RESTTasksAPIFunc:    func() todoist.RESTTasksAPI { return restTasks },
+		RESTProjectsAPIFunc: func() todoist.RESTProjectsAPI { return restProjects },
+		RESTSectionsAPIFunc: func() todoist.RESTSectionsAPI { return restSections },
+		RESTLabelsAPIFunc:   func() todoist.RESTLabelsAPI { return restLabels },
+		RESTCommentsAPIFunc: func() todoist.RESTCommentsAPI { return restComments },
+	}
+}
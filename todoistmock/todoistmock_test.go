@@ -0,0 +1,71 @@
+package todoistmock_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ides15/todoist"
+	"github.com/ides15/todoist/todoistmock"
+)
+
+var (
+	_ todoist.API          = (*todoistmock.API)(nil)
+	_ todoist.ProjectsAPI  = (*todoistmock.ProjectsAPI)(nil)
+	_ todoist.SectionsAPI  = (*todoistmock.SectionsAPI)(nil)
+	_ todoist.TasksAPI     = (*todoistmock.TasksAPI)(nil)
+	_ todoist.TemplatesAPI = (*todoistmock.TemplatesAPI)(nil)
+	_ todoist.BackupsAPI   = (*todoistmock.BackupsAPI)(nil)
+	_ todoist.UploadsAPI   = (*todoistmock.UploadsAPI)(nil)
+
+	_ todoist.RESTTasksAPI    = (*todoistmock.RESTTasksAPI)(nil)
+	_ todoist.RESTProjectsAPI = (*todoistmock.RESTProjectsAPI)(nil)
+	_ todoist.RESTSectionsAPI = (*todoistmock.RESTSectionsAPI)(nil)
+	_ todoist.RESTLabelsAPI   = (*todoistmock.RESTLabelsAPI)(nil)
+	_ todoist.RESTCommentsAPI = (*todoistmock.RESTCommentsAPI)(nil)
+)
+
+// projectNames is an example of application code depending on todoist.API.
+func projectNames(ctx context.Context, api todoist.API) ([]string, error) {
+	projects, _, err := api.ProjectsAPI().List(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, p := range projects {
+		names = append(names, p.Name)
+	}
+
+	return names, nil
+}
+
+func Test_NewAPI(t *testing.T) {
+	projects := &todoistmock.ProjectsAPI{
+		ListFunc: func(ctx context.Context, syncToken string) ([]todoist.Project, todoist.ReadResponse, error) {
+			return []todoist.Project{{Name: "Inbox"}, {Name: "Work"}}, todoist.ReadResponse{}, nil
+		},
+	}
+
+	api := todoistmock.NewAPI(projects, nil, nil)
+
+	names, err := projectNames(context.Background(), api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 2 || names[0] != "Inbox" || names[1] != "Work" {
+		t.Errorf("expected [Inbox Work], received %v", names)
+	}
+
+	if len(projects.ListCalls) != 1 || projects.ListCalls[0].SyncToken != "" {
+		t.Errorf("expected a single recorded List call, received %+v", projects.ListCalls)
+	}
+
+	tasks, _, err := api.TasksAPI().List(context.Background(), "token")
+	if err != nil || tasks != nil {
+		t.Errorf("expected zero values from an unset method, received %v, %v", tasks, err)
+	}
+
+	if _, err = api.RESTTasksAPI().Get(context.Background(), todoist.TaskID{}); err != nil {
+		t.Errorf("expected an empty REST tasks mock, received %v", err)
+	}
+}
@@ -0,0 +1,175 @@
+package todoistserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ides15/todoist"
+	"github.com/pkg/errors"
+)
+
+// eventBuffer is the number of events buffered for each /events client. A
+// client falling further behind is disconnected, rather than holding up the
+// server; EventSource clients reconnect on their own.
+const eventBuffer = 64
+
+// Event is a change to the store, sent to the /events clients. It is sent as
+// a Server-Sent Event named after the resource and the type of the change,
+// such as "task.created" or "project.deleted", or "error" for a failed
+// background sync, with the JSON encoding of the Event as its data.
+//
+// Projects and sections removed from the store by being archived are
+// reported as deleted.
+type Event struct {
+	Type todoist.WatchEventType `json:"type"`
+
+	Project *todoist.Project `json:"project,omitempty"`
+	Section *todoist.Section `json:"section,omitempty"`
+	Task    *todoist.Task    `json:"task,omitempty"`
+
+	// The error for WatchError events.
+	Error string `json:"error,omitempty"`
+
+	// The sync token of the sync the change was fetched by. Empty for changes
+	// made by the Server's own commands.
+	SyncToken string `json:"sync_token,omitempty"`
+
+	id int64
+}
+
+// Name returns the name the event is sent with.
+func (e Event) Name() string {
+	switch {
+	case e.Project != nil:
+		return "project." + string(e.Type)
+	case e.Section != nil:
+		return "section." + string(e.Type)
+	case e.Task != nil:
+		return "task." + string(e.Type)
+	}
+
+	return string(e.Type)
+}
+
+// broadcaster sends events to the subscribed /events clients.
+type broadcaster struct {
+	mu     sync.Mutex
+	lastID int64
+	subs   map[chan Event]struct{}
+}
+
+func newBroadcaster() *broadcaster {
+	return &broadcaster{subs: map[chan Event]struct{}{}}
+}
+
+func (b *broadcaster) subscribe() chan Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ch := make(chan Event, eventBuffer)
+	b.subs[ch] = struct{}{}
+
+	return ch
+}
+
+func (b *broadcaster) unsubscribe(ch chan Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.subs[ch]; ok {
+		delete(b.subs, ch)
+		close(ch)
+	}
+}
+
+// publish numbers the events and sends them to every subscriber, dropping
+// the subscribers whose buffer is full.
+func (b *broadcaster) publish(events []Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, e := range events {
+		b.lastID++
+		e.id = b.lastID
+
+		for ch := range b.subs {
+			select {
+			case ch <- e:
+			default:
+				delete(b.subs, ch)
+				close(ch)
+			}
+		}
+	}
+}
+
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeMethodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, errors.New("streaming is not supported"))
+		return
+	}
+
+	events := s.events.subscribe()
+	defer s.events.unsubscribe(events)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepAlive := s.KeepAlive
+	if keepAlive <= 0 {
+		keepAlive = DefaultKeepAlive
+	}
+	ticker := time.NewTicker(keepAlive)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case e, ok := <-events:
+			if !ok {
+				return
+			}
+			if err := writeEvent(w, e); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		case <-r.Context().Done():
+			return
+		}
+		flusher.Flush()
+	}
+}
+
+func writeEvent(w http.ResponseWriter, e Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.id, e.Name(), data)
+
+	return err
+}
+
+// storeEvents converts the changes made to a store to events.
+func storeEvents(changes []todoist.WatchEvent) []Event {
+	events := make([]Event, len(changes))
+	for i, c := range changes {
+		events[i] = Event{Type: c.Type, Project: c.Project, Section: c.Section, Task: c.Task, SyncToken: c.SyncToken}
+	}
+
+	return events
+}
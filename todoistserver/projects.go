@@ -0,0 +1,123 @@
+package todoistserver
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/ides15/todoist"
+)
+
+func (s *Server) handleProjects(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		projects := s.store.Projects()
+		if projects == nil {
+			projects = []todoist.Project{}
+		}
+		writeJSON(w, http.StatusOK, projects)
+	case http.MethodPost:
+		var addProject todoist.AddProject
+		if !decode(w, r, &addProject) {
+			return
+		}
+		addProject.TempID = newTempID()
+
+		commandResponse, ok := s.command(w, r, func(ctx context.Context, syncToken string) (todoist.CommandResponse, error) {
+			_, commandResponse, err := s.client.Projects.Add(ctx, syncToken, addProject)
+			return commandResponse, err
+		})
+		if !ok {
+			return
+		}
+		s.writeProject(w, http.StatusCreated, todoist.ProjectID{ID: commandResponse.TempIDMapping[addProject.TempID]})
+	default:
+		writeMethodNotAllowed(w, http.MethodGet, http.MethodPost)
+	}
+}
+
+func (s *Server) handleProject(w http.ResponseWriter, r *http.Request) {
+	id, action, ok := route(r, "/projects/")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	projectID := todoist.ProjectID{ID: id}
+	if _, ok = s.store.Project(projectID); !ok {
+		writeNotFound(w, "project", id)
+		return
+	}
+
+	var send func(ctx context.Context, syncToken string) ([]todoist.Project, todoist.CommandResponse, error)
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		s.writeProject(w, http.StatusOK, projectID)
+		return
+	case action == "" && r.Method == http.MethodPost:
+		var change todoist.UpdateProject
+		if !decode(w, r, &change) {
+			return
+		}
+		change.ID = projectID
+		send = func(ctx context.Context, syncToken string) ([]todoist.Project, todoist.CommandResponse, error) {
+			return s.client.Projects.Update(ctx, syncToken, change)
+		}
+	case action == "" && r.Method == http.MethodDelete:
+		send = func(ctx context.Context, syncToken string) ([]todoist.Project, todoist.CommandResponse, error) {
+			return s.client.Projects.Delete(ctx, syncToken, todoist.DeleteProject{ID: projectID})
+		}
+	case action == "move" && r.Method == http.MethodPost:
+		var move todoist.MoveProject
+		if !decode(w, r, &move) {
+			return
+		}
+		move.ID = projectID
+		send = func(ctx context.Context, syncToken string) ([]todoist.Project, todoist.CommandResponse, error) {
+			return s.client.Projects.Move(ctx, syncToken, move)
+		}
+	case action == "archive" && r.Method == http.MethodPost:
+		send = func(ctx context.Context, syncToken string) ([]todoist.Project, todoist.CommandResponse, error) {
+			return s.client.Projects.Archive(ctx, syncToken, todoist.ArchiveProject{ID: projectID})
+		}
+	case action == "" || action == "move" || action == "archive":
+		writeMethodNotAllowed(w, allowedMethods(action)...)
+		return
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	_, ok = s.command(w, r, func(ctx context.Context, syncToken string) (todoist.CommandResponse, error) {
+		_, commandResponse, err := send(ctx, syncToken)
+		return commandResponse, err
+	})
+	if !ok {
+		return
+	}
+	if r.Method == http.MethodDelete || action == "archive" {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	s.writeProject(w, http.StatusOK, projectID)
+}
+
+// writeProject writes a project of the store, or 404 Not Found if it is not
+// in the store.
+func (s *Server) writeProject(w http.ResponseWriter, status int, id todoist.ProjectID) {
+	p, ok := s.store.Project(id)
+	if !ok {
+		writeNotFound(w, "project", id.ID)
+		return
+	}
+
+	writeJSON(w, status, p)
+}
+
+// allowedMethods returns the methods allowed for an action on a single
+// resource.
+func allowedMethods(action string) []string {
+	if action == "" {
+		return []string{http.MethodGet, http.MethodPost, http.MethodDelete}
+	}
+
+	return []string{http.MethodPost}
+}
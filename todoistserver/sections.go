@@ -0,0 +1,117 @@
+package todoistserver
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/ides15/todoist"
+)
+
+func (s *Server) handleSections(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		projectID := r.URL.Query().Get("project_id")
+
+		sections := []todoist.Section{}
+		for _, section := range s.store.Sections() {
+			if projectID == "" || section.ProjectID.String() == projectID {
+				sections = append(sections, section)
+			}
+		}
+		writeJSON(w, http.StatusOK, sections)
+	case http.MethodPost:
+		var addSection todoist.AddSection
+		if !decode(w, r, &addSection) {
+			return
+		}
+		addSection.TempID = newTempID()
+
+		commandResponse, ok := s.command(w, r, func(ctx context.Context, syncToken string) (todoist.CommandResponse, error) {
+			_, commandResponse, err := s.client.Sections.Add(ctx, syncToken, addSection)
+			return commandResponse, err
+		})
+		if !ok {
+			return
+		}
+		s.writeSection(w, http.StatusCreated, todoist.SectionID{ID: commandResponse.TempIDMapping[addSection.TempID]})
+	default:
+		writeMethodNotAllowed(w, http.MethodGet, http.MethodPost)
+	}
+}
+
+func (s *Server) handleSection(w http.ResponseWriter, r *http.Request) {
+	id, action, ok := route(r, "/sections/")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	sectionID := todoist.SectionID{ID: id}
+	if _, ok = s.store.Section(sectionID); !ok {
+		writeNotFound(w, "section", id)
+		return
+	}
+
+	var send func(ctx context.Context, syncToken string) ([]todoist.Section, todoist.CommandResponse, error)
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		s.writeSection(w, http.StatusOK, sectionID)
+		return
+	case action == "" && r.Method == http.MethodPost:
+		var change todoist.UpdateSection
+		if !decode(w, r, &change) {
+			return
+		}
+		change.ID = sectionID
+		send = func(ctx context.Context, syncToken string) ([]todoist.Section, todoist.CommandResponse, error) {
+			return s.client.Sections.Update(ctx, syncToken, change)
+		}
+	case action == "" && r.Method == http.MethodDelete:
+		send = func(ctx context.Context, syncToken string) ([]todoist.Section, todoist.CommandResponse, error) {
+			return s.client.Sections.Delete(ctx, syncToken, todoist.DeleteSection{ID: sectionID})
+		}
+	case action == "move" && r.Method == http.MethodPost:
+		var move todoist.MoveSection
+		if !decode(w, r, &move) {
+			return
+		}
+		move.ID = sectionID
+		send = func(ctx context.Context, syncToken string) ([]todoist.Section, todoist.CommandResponse, error) {
+			return s.client.Sections.Move(ctx, syncToken, move)
+		}
+	case action == "archive" && r.Method == http.MethodPost:
+		send = func(ctx context.Context, syncToken string) ([]todoist.Section, todoist.CommandResponse, error) {
+			return s.client.Sections.Archive(ctx, syncToken, todoist.ArchiveSection{ID: sectionID})
+		}
+	case action == "" || action == "move" || action == "archive":
+		writeMethodNotAllowed(w, allowedMethods(action)...)
+		return
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	_, ok = s.command(w, r, func(ctx context.Context, syncToken string) (todoist.CommandResponse, error) {
+		_, commandResponse, err := send(ctx, syncToken)
+		return commandResponse, err
+	})
+	if !ok {
+		return
+	}
+	if r.Method == http.MethodDelete || action == "archive" {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	s.writeSection(w, http.StatusOK, sectionID)
+}
+
+// writeSection writes a section of the store, or 404 Not Found if it is not
+// in the store.
+func (s *Server) writeSection(w http.ResponseWriter, status int, id todoist.SectionID) {
+	section, ok := s.store.Section(id)
+	if !ok {
+		writeNotFound(w, "section", id.ID)
+		return
+	}
+
+	writeJSON(w, status, section)
+}
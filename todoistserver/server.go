@@ -0,0 +1,243 @@
+// Package todoistserver serves a Todoist account over a small REST-style HTTP
+// API, backed by a locally synced todoist.Store.
+//
+// Reads are served from the store. Changes are sent to Todoist as Sync API
+// commands, applied to the store from the command responses, and broadcast to
+// the clients of /events as Server-Sent Events, as are the changes fetched by
+// the background syncs of Run.
+//
+//	srv := todoistserver.New(client, todoist.NewStore())
+//	go srv.Run(ctx, time.Minute)
+//	log.Fatal(http.ListenAndServe(":8080", srv))
+//
+// The endpoints are:
+//
+//	GET    /projects                   active projects
+//	POST   /projects                   add a project (a todoist.AddProject)
+//	GET    /projects/{id}
+//	POST   /projects/{id}              update a project (a todoist.UpdateProject)
+//	POST   /projects/{id}/move         move a project (a todoist.MoveProject)
+//	POST   /projects/{id}/archive
+//	DELETE /projects/{id}
+//	GET    /sections?project_id={id}   active sections, optionally of a project
+//	POST   /sections                   add a section (a todoist.AddSection)
+//	GET    /sections/{id}
+//	POST   /sections/{id}              update a section (a todoist.UpdateSection)
+//	POST   /sections/{id}/move         move a section (a todoist.MoveSection)
+//	POST   /sections/{id}/archive
+//	DELETE /sections/{id}
+//	GET    /tasks?project_id={id}&section_id={id}
+//	                                   active tasks, optionally of a project or section
+//	POST   /tasks                      add a task (a todoist.AddTask)
+//	GET    /tasks/{id}
+//	POST   /tasks/{id}                 update a task (a todoist.UpdateTask)
+//	POST   /tasks/{id}/move            move a task (a todoist.MoveTask)
+//	POST   /tasks/{id}/close           complete a task
+//	DELETE /tasks/{id}
+//	POST   /sync                       sync the store now
+//	GET    /events                     change notifications, as Server-Sent Events
+//
+// Request and response bodies are JSON, using the library's types. The ID in
+// the path of an update or move takes precedence over the one in the body.
+// Errors are reported as {"error": "..."}, with 400 Bad Request for invalid
+// requests and commands rejected by Todoist, 404 Not Found for resources that
+// are not in the store, and 502 Bad Gateway when Todoist cannot be reached.
+package todoistserver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ides15/todoist"
+	"github.com/pkg/errors"
+)
+
+// DefaultKeepAlive is the default interval of the comments sent to idle
+// /events clients, so that proxies do not close their connections.
+const DefaultKeepAlive = 15 * time.Second
+
+// Server is an http.Handler serving a Todoist account from a synced store.
+// The store should only be changed through the Server, so that every change
+// is broadcast to the /events clients.
+type Server struct {
+	// KeepAlive is the interval of the comments sent to idle /events clients.
+	// Defaults to DefaultKeepAlive.
+	KeepAlive time.Duration
+
+	client *todoist.Client
+	store  *todoist.Store
+	mux    *http.ServeMux
+
+	// mu serializes syncs and commands, so that the changes they return are
+	// diffed against the store they are applied to.
+	mu sync.Mutex
+
+	events *broadcaster
+}
+
+// New returns a Server sending commands with client and serving store. The
+// store is synced by the first call to Sync or Run; until then, it is served
+// as is.
+func New(client *todoist.Client, store *todoist.Store) *Server {
+	s := &Server{
+		KeepAlive: DefaultKeepAlive,
+		client:    client,
+		store:     store,
+		mux:       http.NewServeMux(),
+		events:    newBroadcaster(),
+	}
+
+	s.mux.HandleFunc("/projects", s.handleProjects)
+	s.mux.HandleFunc("/projects/", s.handleProject)
+	s.mux.HandleFunc("/sections", s.handleSections)
+	s.mux.HandleFunc("/sections/", s.handleSection)
+	s.mux.HandleFunc("/tasks", s.handleTasks)
+	s.mux.HandleFunc("/tasks/", s.handleTask)
+	s.mux.HandleFunc("/sync", s.handleSync)
+	s.mux.HandleFunc("/events", s.handleEvents)
+
+	return s
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// Sync syncs the store and broadcasts the changes it fetched.
+func (s *Server) Sync(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, changes, err := s.store.SyncChanges(ctx, s.client)
+	if err != nil {
+		return err
+	}
+
+	s.events.publish(storeEvents(changes))
+
+	return nil
+}
+
+// Run syncs the store right away and then every interval, until ctx is done.
+// A failed sync is reported to the /events clients as an error event and
+// retried at the next interval. Run returns ctx's error.
+func (s *Server) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Sync(ctx); err != nil && ctx.Err() == nil {
+			s.client.Logf("todoistserver: sync failed: %v\n", err)
+			s.events.publish([]Event{{Type: todoist.WatchError, Error: err.Error()}})
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// command sends the commands of send, applies the response to the store and
+// broadcasts the changes. It writes an error response and returns false if
+// the commands failed.
+func (s *Server) command(w http.ResponseWriter, r *http.Request, send func(ctx context.Context, syncToken string) (todoist.CommandResponse, error)) (todoist.CommandResponse, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	commandResponse, err := send(r.Context(), s.store.SyncToken())
+	if err != nil {
+		writeUpstreamError(w, err)
+		return todoist.CommandResponse{}, false
+	}
+
+	changes := s.store.ApplyCommandResponseChanges(commandResponse)
+	s.events.publish(storeEvents(changes))
+
+	return commandResponse, true
+}
+
+func (s *Server) handleSync(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeMethodNotAllowed(w, http.MethodPost)
+		return
+	}
+
+	if err := s.Sync(r.Context()); err != nil {
+		writeUpstreamError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// route splits the path of a request to a single resource, such as
+// /tasks/123/close, into the ID and the action after it, if any. It returns
+// false if the path has more parts.
+func route(r *http.Request, prefix string) (id todoist.ID, action string, ok bool) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, prefix), "/")
+	if parts[0] == "" || len(parts) > 2 {
+		return todoist.ID{}, "", false
+	}
+	if len(parts) == 2 {
+		action = parts[1]
+	}
+
+	return todoist.NewID(parts[0]), action, true
+}
+
+// newTempID returns a temp ID for a command adding a resource.
+func newTempID() string {
+	return uuid.New().String()
+}
+
+// decode decodes the JSON body of a request into v, writing an error
+// response and returning false if it is invalid.
+func decode(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, errors.Wrap(err, "invalid request body"))
+		return false
+	}
+
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, struct {
+		Error string `json:"error"`
+	}{err.Error()})
+}
+
+// writeUpstreamError writes the error of a request to Todoist: commands
+// rejected by Todoist are the client's fault, anything else is reported as a
+// bad gateway.
+func writeUpstreamError(w http.ResponseWriter, err error) {
+	if _, ok := errors.Cause(err).(todoist.SyncError); ok {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	writeError(w, http.StatusBadGateway, err)
+}
+
+func writeNotFound(w http.ResponseWriter, kind string, id todoist.ID) {
+	writeError(w, http.StatusNotFound, errors.Errorf("%s %s not found", kind, id))
+}
+
+func writeMethodNotAllowed(w http.ResponseWriter, allowed ...string) {
+	w.Header().Set("Allow", strings.Join(allowed, ", "))
+	writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+}
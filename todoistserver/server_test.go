@@ -0,0 +1,251 @@
+package todoistserver_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ides15/todoist"
+	"github.com/ides15/todoist/todoistserver"
+	"github.com/ides15/todoist/todoisttest"
+)
+
+func newServer(t *testing.T) (*httptest.Server, *todoisttest.Server) {
+	t.Helper()
+
+	fake := todoisttest.NewServer()
+	t.Cleanup(fake.Close)
+
+	client, err := todoist.NewClient(fake.Token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.BaseURL, _ = url.Parse(fake.SyncURL())
+
+	s := todoistserver.New(client, todoist.NewStore())
+	if err = s.Sync(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewServer(s)
+	t.Cleanup(srv.Close)
+
+	return srv, fake
+}
+
+// call sends a request with a JSON body, if any, and decodes the JSON
+// response into v, if any. It returns the status code.
+func call(t *testing.T, method, u, body string, v interface{}) int {
+	t.Helper()
+
+	req, err := http.NewRequest(method, u, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if v != nil {
+		if err = json.NewDecoder(resp.Body).Decode(v); err != nil {
+			t.Fatalf("%s %s: %v", method, u, err)
+		}
+	}
+
+	return resp.StatusCode
+}
+
+type sseEvent struct {
+	name string
+	data todoistserver.Event
+}
+
+// subscribe connects to /events and returns the events it receives.
+func subscribe(t *testing.T, srv *httptest.Server) <-chan sseEvent {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/events", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("expected an event stream, received %q", ct)
+	}
+
+	events := make(chan sseEvent, 16)
+	go func() {
+		defer resp.Body.Close()
+		defer close(events)
+
+		var e sseEvent
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			line := scanner.Text()
+			switch {
+			case strings.HasPrefix(line, "event: "):
+				e.name = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				_ = json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &e.data)
+			case line == "" && e.name != "":
+				events <- e
+				e = sseEvent{}
+			}
+		}
+	}()
+
+	return events
+}
+
+func next(t *testing.T, events <-chan sseEvent) sseEvent {
+	t.Helper()
+
+	select {
+	case e, ok := <-events:
+		if !ok {
+			t.Fatal("event stream closed")
+		}
+		return e
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event received")
+	}
+
+	return sseEvent{}
+}
+
+func Test_Server(t *testing.T) {
+	srv, _ := newServer(t)
+	events := subscribe(t, srv)
+
+	var project todoist.Project
+	if status := call(t, http.MethodPost, srv.URL+"/projects", `{"name": "Home"}`, &project); status != http.StatusCreated {
+		t.Fatalf("expected 201 Created, received %d", status)
+	}
+	if project.Name != "Home" || project.ID.IsZero() || project.ID.IsTemp() {
+		t.Fatalf("unexpected project %+v", project)
+	}
+	if e := next(t, events); e.name != "project.created" || e.data.Project.ID != project.ID {
+		t.Errorf("unexpected event %s %+v", e.name, e.data)
+	}
+
+	var task todoist.Task
+	body := `{"content": "Mow the lawn", "project_id": "` + project.ID.String() + `"}`
+	if status := call(t, http.MethodPost, srv.URL+"/tasks", body, &task); status != http.StatusCreated {
+		t.Fatalf("expected 201 Created, received %d", status)
+	}
+	if e := next(t, events); e.name != "task.created" || e.data.Task.Content != "Mow the lawn" {
+		t.Errorf("unexpected event %s %+v", e.name, e.data)
+	}
+
+	if status := call(t, http.MethodPost, srv.URL+"/tasks/"+task.ID.String(), `{"content": "Mow the back lawn"}`, &task); status != http.StatusOK {
+		t.Fatalf("expected 200 OK, received %d", status)
+	}
+	if task.Content != "Mow the back lawn" || task.ProjectID != project.ID {
+		t.Errorf("unexpected updated task %+v", task)
+	}
+	if e := next(t, events); e.name != "task.updated" {
+		t.Errorf("unexpected event %s", e.name)
+	}
+
+	var tasks []todoist.Task
+	call(t, http.MethodGet, srv.URL+"/tasks?project_id="+project.ID.String(), "", &tasks)
+	if len(tasks) != 1 || tasks[0].ID != task.ID {
+		t.Fatalf("expected the task in the project, received %+v", tasks)
+	}
+	call(t, http.MethodGet, srv.URL+"/tasks?project_id=999999", "", &tasks)
+	if len(tasks) != 0 {
+		t.Errorf("expected no tasks in another project, received %+v", tasks)
+	}
+
+	if status := call(t, http.MethodPost, srv.URL+"/tasks/"+task.ID.String()+"/close", "", nil); status != http.StatusNoContent {
+		t.Fatalf("expected 204 No Content, received %d", status)
+	}
+	if e := next(t, events); e.name != "task.completed" || e.data.Task.ID != task.ID {
+		t.Errorf("unexpected event %s %+v", e.name, e.data)
+	}
+	if status := call(t, http.MethodGet, srv.URL+"/tasks/"+task.ID.String(), "", nil); status != http.StatusNotFound {
+		t.Errorf("expected the completed task to be gone, received %d", status)
+	}
+
+	if status := call(t, http.MethodPost, srv.URL+"/projects/"+project.ID.String()+"/archive", "", nil); status != http.StatusNoContent {
+		t.Fatalf("expected 204 No Content, received %d", status)
+	}
+	if e := next(t, events); e.name != "project.deleted" {
+		t.Errorf("unexpected event %s", e.name)
+	}
+
+	var projects []todoist.Project
+	call(t, http.MethodGet, srv.URL+"/projects", "", &projects)
+	if len(projects) != 1 || projects[0].Name != "Inbox" {
+		t.Errorf("expected only the inbox, received %+v", projects)
+	}
+}
+
+func Test_Server_Sync(t *testing.T) {
+	srv, fake := newServer(t)
+	events := subscribe(t, srv)
+
+	// A change made elsewhere shows up after a sync.
+	client, _ := todoist.NewClient(fake.Token)
+	client.BaseURL, _ = url.Parse(fake.SyncURL())
+	if _, _, err := client.Sections.Add(context.Background(), "", todoist.AddSection{Name: "Later", ProjectID: todoist.ProjectID{ID: todoist.NewIntID(todoisttest.InboxProjectID)}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if status := call(t, http.MethodPost, srv.URL+"/sync", "", nil); status != http.StatusNoContent {
+		t.Fatalf("expected 204 No Content, received %d", status)
+	}
+	e := next(t, events)
+	if e.name != "section.created" || e.data.Section.Name != "Later" || e.data.SyncToken == "" {
+		t.Errorf("unexpected event %s %+v", e.name, e.data)
+	}
+
+	var sections []todoist.Section
+	call(t, http.MethodGet, srv.URL+"/sections?project_id="+e.data.Section.ProjectID.String(), "", &sections)
+	if len(sections) != 1 || sections[0].Name != "Later" {
+		t.Errorf("expected the synced section, received %+v", sections)
+	}
+}
+
+func Test_Server_Errors(t *testing.T) {
+	srv, fake := newServer(t)
+
+	var task todoist.Task
+	call(t, http.MethodPost, srv.URL+"/tasks", `{"content": "Mow the lawn"}`, &task)
+
+	fake.InjectFault(todoisttest.Fault{Command: "item_update"})
+	var body struct{ Error string }
+	if status := call(t, http.MethodPost, srv.URL+"/tasks/"+task.ID.String(), `{"content": "Mow"}`, &body); status != http.StatusBadRequest || body.Error == "" {
+		t.Errorf("expected 400 Bad Request for a rejected command, received %d %q", status, body.Error)
+	}
+	fake.ClearFaults()
+
+	tests := []struct {
+		method, path, body string
+		status             int
+	}{
+		{http.MethodGet, "/tasks/1", "", http.StatusNotFound},
+		{http.MethodDelete, "/projects/1", "", http.StatusNotFound},
+		{http.MethodPost, "/tasks", `{"content":`, http.StatusBadRequest},
+		{http.MethodPut, "/tasks", "", http.StatusMethodNotAllowed},
+		{http.MethodPut, "/tasks/" + task.ID.String(), "", http.StatusMethodNotAllowed},
+		{http.MethodPost, "/tasks/" + task.ID.String() + "/archive", "", http.StatusNotFound},
+		{http.MethodGet, "/sync", "", http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		if status := call(t, tt.method, srv.URL+tt.path, tt.body, nil); status != tt.status {
+			t.Errorf("%s %s: expected %d, received %d", tt.method, tt.path, tt.status, status)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package todoistserver
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/ides15/todoist"
+)
+
+func (s *Server) handleTasks(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		projectID := r.URL.Query().Get("project_id")
+		sectionID := r.URL.Query().Get("section_id")
+
+		tasks := []todoist.Task{}
+		for _, t := range s.store.Tasks() {
+			if projectID != "" && t.ProjectID.String() != projectID {
+				continue
+			}
+			if sectionID != "" && (t.SectionID == nil || t.SectionID.String() != sectionID) {
+				continue
+			}
+			tasks = append(tasks, t)
+		}
+		writeJSON(w, http.StatusOK, tasks)
+	case http.MethodPost:
+		var addTask todoist.AddTask
+		if !decode(w, r, &addTask) {
+			return
+		}
+		addTask.TempID = newTempID()
+
+		commandResponse, ok := s.command(w, r, func(ctx context.Context, syncToken string) (todoist.CommandResponse, error) {
+			_, commandResponse, err := s.client.Tasks.Add(ctx, syncToken, addTask)
+			return commandResponse, err
+		})
+		if !ok {
+			return
+		}
+		s.writeTask(w, http.StatusCreated, todoist.TaskID{ID: commandResponse.TempIDMapping[addTask.TempID]})
+	default:
+		writeMethodNotAllowed(w, http.MethodGet, http.MethodPost)
+	}
+}
+
+func (s *Server) handleTask(w http.ResponseWriter, r *http.Request) {
+	id, action, ok := route(r, "/tasks/")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	taskID := todoist.TaskID{ID: id}
+	if _, ok = s.store.Task(taskID); !ok {
+		writeNotFound(w, "task", id)
+		return
+	}
+
+	var send func(ctx context.Context, syncToken string) ([]todoist.Task, todoist.CommandResponse, error)
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		s.writeTask(w, http.StatusOK, taskID)
+		return
+	case action == "" && r.Method == http.MethodPost:
+		var change todoist.UpdateTask
+		if !decode(w, r, &change) {
+			return
+		}
+		change.ID = taskID
+		send = func(ctx context.Context, syncToken string) ([]todoist.Task, todoist.CommandResponse, error) {
+			return s.client.Tasks.Update(ctx, syncToken, change)
+		}
+	case action == "" && r.Method == http.MethodDelete:
+		send = func(ctx context.Context, syncToken string) ([]todoist.Task, todoist.CommandResponse, error) {
+			return s.client.Tasks.Delete(ctx, syncToken, todoist.DeleteTask{ID: taskID})
+		}
+	case action == "move" && r.Method == http.MethodPost:
+		var move todoist.MoveTask
+		if !decode(w, r, &move) {
+			return
+		}
+		move.ID = taskID
+		send = func(ctx context.Context, syncToken string) ([]todoist.Task, todoist.CommandResponse, error) {
+			return s.client.Tasks.Move(ctx, syncToken, move)
+		}
+	case action == "close" && r.Method == http.MethodPost:
+		send = func(ctx context.Context, syncToken string) ([]todoist.Task, todoist.CommandResponse, error) {
+			return s.client.Tasks.Complete(ctx, syncToken, todoist.CompleteTask{ID: taskID})
+		}
+	case action == "" || action == "move" || action == "close":
+		writeMethodNotAllowed(w, allowedMethods(action)...)
+		return
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	_, ok = s.command(w, r, func(ctx context.Context, syncToken string) (todoist.CommandResponse, error) {
+		_, commandResponse, err := send(ctx, syncToken)
+		return commandResponse, err
+	})
+	if !ok {
+		return
+	}
+	if r.Method == http.MethodDelete || action == "close" {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	s.writeTask(w, http.StatusOK, taskID)
+}
+
+// writeTask writes a task of the store, or 404 Not Found if it is not in the
+// store.
+func (s *Server) writeTask(w http.ResponseWriter, status int, id todoist.TaskID) {
+	t, ok := s.store.Task(id)
+	if !ok {
+		writeNotFound(w, "task", id.ID)
+		return
+	}
+
+	writeJSON(w, status, t)
+}
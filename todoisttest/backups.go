@@ -0,0 +1,91 @@
+package todoisttest
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// backupsPath is the path backups are downloaded from. Unlike exported
+// templates, backups can only be downloaded with the account's token.
+const backupsPath = "/backups/download/"
+
+// backup is an automatic backup of the account.
+type backup struct {
+	Version string `json:"version"`
+	URL     string `json:"url"`
+
+	data []byte
+}
+
+// AddBackup records a backup of the account's current state, like the ones
+// Todoist makes automatically, and returns its version. The backup is a zip
+// archive with a template CSV file per active project, named after the
+// project and its ID, as in "Inbox [1000].csv".
+func (s *Server) AddBackup() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, p := range sortedProjects(s.projects) {
+		if p.IsDeleted == 1 || p.IsArchived == 1 {
+			continue
+		}
+
+		f, err := zw.Create(fmt.Sprintf("%s [%d].csv", p.Name, p.ID))
+		if err != nil {
+			panic(err)
+		}
+		_, _ = f.Write(s.exportTemplate(p.ID))
+	}
+	if err := zw.Close(); err != nil {
+		panic(err)
+	}
+
+	n := len(s.backups)
+	b := &backup{
+		Version: time.Now().UTC().Add(time.Duration(n) * time.Minute).Format("2006-01-02 15:04"),
+		URL:     s.URL + backupsPath + strconv.Itoa(n) + ".zip",
+		data:    buf.Bytes(),
+	}
+	s.backups = append(s.backups, b)
+
+	return b.Version
+}
+
+// handleBackupsGet lists the backups, newest first.
+func (s *Server) handleBackupsGet(w http.ResponseWriter, r *http.Request) {
+	if !s.begin(w, r, "backups/get") {
+		return
+	}
+	defer s.mu.Unlock()
+
+	backups := []*backup{}
+	for i := len(s.backups) - 1; i >= 0; i-- {
+		backups = append(backups, s.backups[i])
+	}
+
+	writeJSON(w, http.StatusOK, backups)
+}
+
+// handleBackupsDownload serves a backup's zip archive.
+func (s *Server) handleBackupsDownload(w http.ResponseWriter, r *http.Request) {
+	if !s.begin(w, r, "backups/download") {
+		return
+	}
+	defer s.mu.Unlock()
+
+	n, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, backupsPath), ".zip"))
+	if err != nil || n < 0 || n >= len(s.backups) {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	_, _ = w.Write(s.backups[n].data)
+}
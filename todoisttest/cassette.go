@@ -0,0 +1,481 @@
+package todoisttest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// redacted replaces API tokens in recorded requests.
+const redacted = "REDACTED"
+
+var uuidPattern = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`)
+
+// placeholderPattern matches the values UUIDs are normalized to in a cassette.
+var placeholderPattern = regexp.MustCompile(`uuid-[0-9]+`)
+
+// A Cassette is a recorded sequence of API requests and their responses.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a single recorded request/response pair.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is a request as stored in a cassette, with the API token
+// redacted and UUIDs normalized to stable placeholders.
+//
+// Form-encoded and multipart bodies are stored as Form, with the file parts
+// of multipart bodies stored as their file name. Other bodies, such as the
+// JSON bodies of REST requests, are stored as Body.
+type RecordedRequest struct {
+	Method        string     `json:"method"`
+	Endpoint      string     `json:"endpoint"`
+	ResourceTypes []string   `json:"resource_types,omitempty"`
+	CommandTypes  []string   `json:"command_types,omitempty"`
+	Form          url.Values `json:"form,omitempty"`
+	Body          string     `json:"body,omitempty"`
+}
+
+// RecordedResponse is a response as stored in a cassette.
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header"`
+	Body       string      `json:"body"`
+}
+
+// LoadCassette reads a cassette from a file.
+func LoadCassette(path string) (*Cassette, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var c Cassette
+	if err = json.Unmarshal(b, &c); err != nil {
+		return nil, fmt.Errorf("todoisttest: decoding cassette %s: %w", path, err)
+	}
+
+	return &c, nil
+}
+
+// Save writes the cassette to a file, creating parent directories as needed.
+func (c *Cassette) Save(path string) error {
+	b, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, append(b, '\n'), 0644)
+}
+
+// endpoint returns the API endpoint a URL refers to, relative to the API root
+// (for example "sync" or "projects/get").
+func endpoint(u *url.URL) string {
+	path := strings.TrimPrefix(u.Path, "/")
+	if i := strings.Index(path, "/v"); i >= 0 {
+		if j := strings.Index(path[i+1:], "/"); j >= 0 {
+			return path[i+1+j+1:]
+		}
+	}
+
+	return path
+}
+
+// readBody reads and closes the body of a request.
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	body, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+
+	return body, err
+}
+
+// parseBody parses a request body according to its content type, returning
+// the form of form-encoded and multipart bodies, and other bodies verbatim.
+func parseBody(contentType string, body []byte) (url.Values, string, error) {
+	if len(body) == 0 {
+		return url.Values{}, "", nil
+	}
+
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, string(body), nil
+	}
+
+	switch mediaType {
+	case "application/x-www-form-urlencoded":
+		form, err := url.ParseQuery(string(body))
+		return form, "", err
+
+	case "multipart/form-data":
+		form := url.Values{}
+		mr := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+		for {
+			part, err := mr.NextPart()
+			if err == io.EOF {
+				return form, "", nil
+			}
+			if err != nil {
+				return nil, "", err
+			}
+
+			if part.FileName() != "" {
+				form.Add(part.FormName(), part.FileName())
+				continue
+			}
+
+			value, err := ioutil.ReadAll(part)
+			if err != nil {
+				return nil, "", err
+			}
+			form.Add(part.FormName(), string(value))
+		}
+	}
+
+	return nil, string(body), nil
+}
+
+// commandIDs returns the types of the commands in a request form, along with
+// their uuid and temp_id values in order.
+func commandIDs(form url.Values) (types []string, ids []string) {
+	var cmds []command
+	_ = json.Unmarshal([]byte(form.Get("commands")), &cmds)
+
+	for _, c := range cmds {
+		types = append(types, c.Type)
+		ids = append(ids, c.UUID, c.TempID)
+	}
+
+	return types, ids
+}
+
+// Recorder is an http.RoundTripper that forwards requests to another
+// transport and records every exchange into a Cassette. It is meant to be
+// installed with the client's SetHTTPClient:
+//
+//	rec := todoisttest.NewRecorder(nil)
+//	client.SetHTTPClient(&http.Client{Transport: rec})
+//	...
+//	rec.Cassette().Save("testdata/cassettes/projects.json")
+type Recorder struct {
+	transport http.RoundTripper
+
+	mu       sync.Mutex
+	cassette Cassette
+	uuids    map[string]string // real UUID -> placeholder
+}
+
+// NewRecorder returns a Recorder forwarding requests to transport, or to
+// http.DefaultTransport if transport is nil.
+func NewRecorder(transport http.RoundTripper) *Recorder {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	return &Recorder{transport: transport, uuids: map[string]string{}}
+}
+
+// RoundTrip implements http.RoundTripper. The request is forwarded as a
+// clone, since its body is read to be recorded.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	form, rawBody, err := parseBody(req.Header.Get("Content-Type"), reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	out := req.Clone(req.Context())
+	if reqBody != nil {
+		out.Body = ioutil.NopCloser(bytes.NewReader(reqBody))
+		out.GetBody = func() (io.ReadCloser, error) {
+			return ioutil.NopCloser(bytes.NewReader(reqBody)), nil
+		}
+	}
+
+	resp, err := r.transport.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var resourceTypes []string
+	_ = json.Unmarshal([]byte(form.Get("resource_types")), &resourceTypes)
+	commandTypes, _ := commandIDs(form)
+
+	var recForm url.Values
+	if len(form) > 0 {
+		recForm = url.Values{}
+	}
+	for k, v := range form {
+		if k == "token" {
+			v = []string{redacted}
+		}
+		for _, s := range v {
+			recForm.Add(k, r.normalize(s))
+		}
+	}
+
+	header := resp.Header.Clone()
+	header.Del("Set-Cookie")
+	header.Del("Date")
+
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request: RecordedRequest{
+			Method:        req.Method,
+			Endpoint:      endpoint(req.URL),
+			ResourceTypes: resourceTypes,
+			CommandTypes:  commandTypes,
+			Form:          recForm,
+			Body:          r.normalize(rawBody),
+		},
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     header,
+			Body:       r.normalize(string(body)),
+		},
+	})
+
+	return resp, nil
+}
+
+// normalize replaces every UUID in s with a placeholder that is stable for the
+// lifetime of the Recorder. r.mu must be held.
+func (r *Recorder) normalize(s string) string {
+	return uuidPattern.ReplaceAllStringFunc(s, func(u string) string {
+		if p, ok := r.uuids[u]; ok {
+			return p
+		}
+
+		p := fmt.Sprintf("uuid-%d", len(r.uuids)+1)
+		r.uuids[u] = p
+
+		return p
+	})
+}
+
+// Cassette returns a copy of the interactions recorded so far.
+func (r *Recorder) Cassette() *Cassette {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return &Cassette{Interactions: append([]Interaction(nil), r.cassette.Interactions...)}
+}
+
+// Replayer is an http.RoundTripper that answers requests from a Cassette
+// without any network access. Each request is matched to the first unused
+// interaction with the same endpoint and the same form or body, including
+// the arguments of its commands, so a test replays correctly as long as it
+// makes the same calls in the same order. The API token is not compared.
+//
+// UUID placeholders in a recorded request stand for the UUIDs the client
+// sends in their place: the uuid and temp_id values of the commands are
+// mapped in order, and the UUIDs of later requests are compared through that
+// mapping. The placeholders in a replayed response are replaced the same way,
+// so that sync_status and temp_id_mapping refer to the commands the client
+// actually sent.
+type Replayer struct {
+	mu           sync.Mutex
+	cassette     *Cassette
+	used         []bool
+	placeholders map[string]string // sent UUID -> placeholder
+	sent         map[string]string // placeholder -> sent UUID
+}
+
+// NewReplayer returns a Replayer for the given cassette.
+func NewReplayer(c *Cassette) *Replayer {
+	return &Replayer{
+		cassette:     c,
+		used:         make([]bool, len(c.Interactions)),
+		placeholders: map[string]string{},
+		sent:         map[string]string{},
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	form, rawBody, err := parseBody(req.Header.Get("Content-Type"), reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	var resourceTypes []string
+	_ = json.Unmarshal([]byte(form.Get("resource_types")), &resourceTypes)
+	commandTypes, ids := commandIDs(form)
+	ep := endpoint(req.URL)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, in := range r.cassette.Interactions {
+		rec := in.Request
+		if r.used[i] || rec.Method != req.Method || rec.Endpoint != ep ||
+			!equalStrings(rec.ResourceTypes, resourceTypes) || !equalStrings(rec.CommandTypes, commandTypes) {
+			continue
+		}
+
+		// Map the placeholders of the recorded commands onto the values sent
+		// with this request.
+		placeholders := map[string]string{}
+		for k, v := range r.placeholders {
+			placeholders[k] = v
+		}
+		_, recIDs := commandIDs(rec.Form)
+		for j, id := range recIDs {
+			if j < len(ids) && placeholderPattern.MatchString(id) {
+				placeholders[ids[j]] = id
+			}
+		}
+
+		if !sameRequest(rec, form, rawBody, placeholders) {
+			continue
+		}
+
+		r.used[i] = true
+		for u, p := range placeholders {
+			r.placeholders[u] = p
+			r.sent[p] = u
+		}
+
+		body := placeholderPattern.ReplaceAllStringFunc(in.Response.Body, func(p string) string {
+			if u, ok := r.sent[p]; ok {
+				return u
+			}
+			return p
+		})
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", in.Response.StatusCode, http.StatusText(in.Response.StatusCode)),
+			StatusCode:    in.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        in.Response.Header.Clone(),
+			Body:          ioutil.NopCloser(strings.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("todoisttest: no recorded interaction for %s %s (resource_types %v, commands %v)",
+		req.Method, ep, resourceTypes, commandTypes)
+}
+
+// sameRequest reports whether a request has the form or body of a recorded
+// request, once its UUIDs are replaced with their placeholders.
+func sameRequest(rec RecordedRequest, form url.Values, body string, placeholders map[string]string) bool {
+	normalize := func(s string) string {
+		return uuidPattern.ReplaceAllStringFunc(s, func(u string) string {
+			if p, ok := placeholders[u]; ok {
+				return p
+			}
+			return u
+		})
+	}
+
+	if !equalValues(rec.Body, normalize(body)) {
+		return false
+	}
+
+	keys := map[string]bool{}
+	for k := range rec.Form {
+		keys[k] = true
+	}
+	for k := range form {
+		keys[k] = true
+	}
+	delete(keys, "token")
+
+	for k := range keys {
+		recValues, values := rec.Form[k], form[k]
+		if len(recValues) != len(values) {
+			return false
+		}
+		for j := range values {
+			if !equalValues(recValues[j], normalize(values[j])) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// equalValues reports whether two recorded values are equal, comparing JSON
+// values by their decoded content.
+func equalValues(a, b string) bool {
+	if a == b {
+		return true
+	}
+
+	var av, bv interface{}
+	if json.Unmarshal([]byte(a), &av) != nil || json.Unmarshal([]byte(b), &bv) != nil {
+		return false
+	}
+
+	return reflect.DeepEqual(av, bv)
+}
+
+// Unused returns the number of recorded interactions that have not been
+// replayed.
+func (r *Replayer) Unused() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n := 0
+	for _, u := range r.used {
+		if !u {
+			n++
+		}
+	}
+
+	return n
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+
+	return reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,172 @@
+package todoisttest_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ides15/todoist"
+	"github.com/ides15/todoist/todoisttest"
+)
+
+func Test_Cassette_RecordAndReplay(t *testing.T) {
+	srv := todoisttest.NewServer()
+
+	client := newClient(t, srv)
+	rec := todoisttest.NewRecorder(nil)
+	client.SetHTTPClient(&http.Client{Transport: rec})
+
+	ctx := context.Background()
+
+	_, resp, err := client.Projects.Add(ctx, "", todoist.AddProject{Name: "Recorded", TempID: "recorded"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	recordedID := resp.TempIDMapping["recorded"]
+
+	if _, _, err = client.Projects.List(ctx, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// Nothing should be served by the fake from here on.
+	srv.Close()
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	if err = rec.Cassette().Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	cassette, err := todoisttest.LoadCassette(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cassette.Interactions) != 2 {
+		t.Fatalf("expected 2 recorded interactions, received %d", len(cassette.Interactions))
+	}
+
+	for _, in := range cassette.Interactions {
+		if token := in.Request.Form.Get("token"); token != "REDACTED" {
+			t.Errorf("expected the token to be redacted, received %q", token)
+		}
+	}
+
+	commands := cassette.Interactions[0].Request.Form.Get("commands")
+	if !strings.Contains(commands, `"uuid":"uuid-1"`) {
+		t.Errorf("expected command UUIDs to be normalized, received %s", commands)
+	}
+
+	replayer := todoisttest.NewReplayer(cassette)
+	client.SetHTTPClient(&http.Client{Transport: replayer})
+
+	_, resp, err = client.Projects.Add(ctx, "", todoist.AddProject{Name: "Recorded", TempID: "recorded"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.TempIDMapping["recorded"] != recordedID {
+		t.Errorf("expected replayed temp id mapping %s, received %s", recordedID, resp.TempIDMapping["recorded"])
+	}
+	for cmdID := range resp.SyncStatus {
+		if strings.HasPrefix(cmdID, "uuid-") {
+			t.Errorf("expected sync_status to be keyed by the sent command UUID, received %s", cmdID)
+		}
+	}
+
+	projects, _, err := client.Projects.List(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(projects) != 2 {
+		t.Errorf("expected 2 replayed projects, received %d", len(projects))
+	}
+
+	if n := replayer.Unused(); n != 0 {
+		t.Errorf("expected every interaction to be replayed, %d left", n)
+	}
+
+	if _, _, err = client.Sections.List(ctx, ""); err == nil {
+		t.Error("expected an error for a request missing from the cassette")
+	}
+}
+
+func Test_Replayer_MatchesArgs(t *testing.T) {
+	srv := todoisttest.NewServer()
+	defer srv.Close()
+
+	client := newClient(t, srv)
+	rec := todoisttest.NewRecorder(nil)
+	client.SetHTTPClient(&http.Client{Transport: rec})
+
+	ctx := context.Background()
+
+	if _, _, err := client.Projects.Add(ctx, "", todoist.AddProject{Name: "Recorded"}); err != nil {
+		t.Fatal(err)
+	}
+
+	client.SetHTTPClient(&http.Client{Transport: todoisttest.NewReplayer(rec.Cassette())})
+
+	if _, _, err := client.Projects.Add(ctx, "", todoist.AddProject{Name: "Changed"}); err == nil {
+		t.Error("expected an error for a command with different args")
+	}
+	if _, _, err := client.Projects.Add(ctx, "", todoist.AddProject{Name: "Recorded"}); err != nil {
+		t.Errorf("expected the recorded command to be replayed, received %v", err)
+	}
+}
+
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func Test_Recorder_Bodies(t *testing.T) {
+	var sent []*http.Request
+	rec := todoisttest.NewRecorder(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		body, _ := ioutil.ReadAll(req.Body)
+		if len(body) == 0 {
+			t.Error("expected the forwarded request to have a body")
+		}
+		sent = append(sent, req)
+
+		return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: ioutil.NopCloser(strings.NewReader("{}"))}, nil
+	}))
+
+	var multipartBody bytes.Buffer
+	mw := multipart.NewWriter(&multipartBody)
+	_ = mw.WriteField("token", "secret")
+	fw, _ := mw.CreateFormFile("file", "notes.txt")
+	_, _ = fw.Write([]byte("file contents"))
+	mw.Close()
+
+	upload, _ := http.NewRequest(http.MethodPost, "https://api.todoist.com/sync/v8/uploads/add", &multipartBody)
+	upload.Header.Set("Content-Type", mw.FormDataContentType())
+
+	rest, _ := http.NewRequest(http.MethodPost, "https://api.todoist.com/rest/v2/tasks", strings.NewReader(`{"content":"Buy milk"}`))
+	rest.Header.Set("Content-Type", "application/json")
+
+	for _, req := range []*http.Request{upload, rest} {
+		resp, err := rec.RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+
+	for i, req := range []*http.Request{upload, rest} {
+		if sent[i] == req {
+			t.Errorf("expected request %d to be forwarded as a clone", i)
+		}
+	}
+
+	interactions := rec.Cassette().Interactions
+	if form := interactions[0].Request.Form; form.Get("token") != "REDACTED" || form.Get("file") != "notes.txt" {
+		t.Errorf("unexpected multipart form %v", form)
+	}
+	if body := interactions[1].Request.Body; body != `{"content":"Buy milk"}` {
+		t.Errorf("unexpected JSON body %q", body)
+	}
+}
@@ -0,0 +1,839 @@
+package todoisttest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+type command struct {
+	Type   string          `json:"type"`
+	Args   json.RawMessage `json:"args"`
+	UUID   string          `json:"uuid"`
+	TempID string          `json:"temp_id"`
+}
+
+// args holds the decoded arguments of a command, along with the temp ID
+// mapping of the request so that ID arguments can reference resources created
+// earlier in the same request.
+type args struct {
+	raw     map[string]json.RawMessage
+	tempIDs map[string]int
+}
+
+func (a args) has(name string) bool {
+	v, ok := a.raw[name]
+	return ok && string(v) != "null"
+}
+
+func (a args) str(name string) string {
+	var s string
+	_ = json.Unmarshal(a.raw[name], &s)
+	return s
+}
+
+func (a args) num(name string) int {
+	var n int
+	if err := json.Unmarshal(a.raw[name], &n); err != nil {
+		var b bool
+		if json.Unmarshal(a.raw[name], &b) == nil && b {
+			return 1
+		}
+	}
+	return n
+}
+
+func (a args) boolean(name string) bool {
+	var b bool
+	if err := json.Unmarshal(a.raw[name], &b); err != nil {
+		return a.num(name) != 0
+	}
+	return b
+}
+
+// id resolves an ID argument given as a number, a numeric string or a temp ID
+// from the current request. It returns 0 if the argument is missing, null or
+// cannot be resolved.
+func (a args) id(name string) int {
+	return resolveID(a.raw[name], a.tempIDs)
+}
+
+// ids resolves a list of ID arguments, as accepted by id.
+func (a args) ids(name string) []int {
+	var raw []json.RawMessage
+	_ = json.Unmarshal(a.raw[name], &raw)
+
+	ids := []int{}
+	for _, r := range raw {
+		ids = append(ids, resolveID(r, a.tempIDs))
+	}
+	return ids
+}
+
+func resolveID(raw json.RawMessage, tempIDs map[string]int) int {
+	var n int
+	if err := json.Unmarshal(raw, &n); err == nil {
+		return n
+	}
+
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return 0
+	}
+
+	if n, err := strconv.Atoi(s); err == nil {
+		return n
+	}
+
+	return tempIDs[s]
+}
+
+// apply executes a single command against the store and returns the ID of the
+// resource it created, if any. s.mu must be held.
+func (s *Server) apply(c command, tempIDs map[string]int) (int, *apiError) {
+	a := args{tempIDs: tempIDs}
+	if err := json.Unmarshal(c.Args, &a.raw); err != nil {
+		return 0, errInvalidArgument("args")
+	}
+
+	switch c.Type {
+	case "project_add":
+		return s.projectAdd(a)
+	case "project_update":
+		return 0, s.projectUpdate(a)
+	case "project_move":
+		return 0, s.projectMove(a)
+	case "project_delete":
+		return 0, s.projectDelete(a)
+	case "project_archive":
+		return 0, s.projectArchive(a)
+	case "project_unarchive":
+		return 0, s.projectUnarchive(a)
+	case "project_reorder":
+		return 0, s.projectReorder(a)
+
+	case "section_add":
+		return s.sectionAdd(a)
+	case "section_update":
+		return 0, s.sectionUpdate(a)
+	case "section_move":
+		return 0, s.sectionMove(a)
+	case "section_reorder":
+		return 0, s.sectionReorder(a)
+	case "section_delete":
+		return 0, s.sectionDelete(a)
+	case "section_archive":
+		return 0, s.sectionArchive(a, true)
+	case "section_unarchive":
+		return 0, s.sectionArchive(a, false)
+
+	case "item_add":
+		return s.itemAdd(a)
+	case "item_update":
+		return 0, s.itemUpdate(a)
+	case "item_move":
+		return 0, s.itemMove(a)
+	case "item_reorder":
+		return 0, s.itemReorder(a)
+	case "item_delete":
+		return 0, s.itemDelete(a)
+	case "item_close", "item_complete":
+		return 0, s.itemComplete(a, true)
+	case "item_uncomplete":
+		return 0, s.itemComplete(a, false)
+
+	case "label_add":
+		return s.labelAdd(a)
+	case "note_add":
+		return s.noteAdd(a, true)
+	case "project_note_add":
+		return s.noteAdd(a, false)
+	case "filter_add":
+		return s.filterAdd(a)
+	case "reminder_add":
+		return s.reminderAdd(a)
+
+	default:
+		return 0, &apiError{
+			Tag:      "INVALID_COMMAND",
+			Code:     15,
+			Message:  "Invalid command type: " + c.Type,
+			HTTPCode: http.StatusBadRequest,
+			Extra:    map[string]interface{}{},
+		}
+	}
+}
+
+func (s *Server) activeProject(id int) (*project, *apiError) {
+	p, ok := s.projects[id]
+	if !ok || p.IsDeleted == 1 {
+		return nil, errNotFound("project")
+	}
+
+	return p, nil
+}
+
+func (s *Server) activeSection(id int) (*section, *apiError) {
+	sec, ok := s.sections[id]
+	if !ok || sec.IsDeleted {
+		return nil, errNotFound("section")
+	}
+
+	return sec, nil
+}
+
+func (s *Server) activeItem(id int) (*item, *apiError) {
+	it, ok := s.items[id]
+	if !ok || it.IsDeleted == 1 {
+		return nil, errNotFound("item")
+	}
+
+	return it, nil
+}
+
+func (s *Server) projectAdd(a args) (int, *apiError) {
+	if a.str("name") == "" {
+		return 0, errInvalidArgument("name")
+	}
+
+	p := &project{
+		ID:         s.newID(),
+		Name:       a.str("name"),
+		Color:      a.num("color"),
+		ChildOrder: a.num("child_order"),
+		IsFavorite: a.num("is_favorite"),
+	}
+	if p.Color == 0 {
+		p.Color = 48
+	}
+
+	if a.has("parent_id") {
+		parent, err := s.activeProject(a.id("parent_id"))
+		if err != nil {
+			return 0, err
+		}
+		p.ParentID = intPtr(parent.ID)
+	}
+
+	s.touch(&p.seq)
+	s.projects[p.ID] = p
+
+	return p.ID, nil
+}
+
+func (s *Server) projectUpdate(a args) *apiError {
+	p, err := s.activeProject(a.id("id"))
+	if err != nil {
+		return err
+	}
+
+	if a.has("name") {
+		p.Name = a.str("name")
+	}
+	if a.has("color") {
+		p.Color = a.num("color")
+	}
+	if a.has("collapsed") {
+		p.Collapsed = a.num("collapsed")
+	}
+	if a.has("is_favorite") {
+		p.IsFavorite = a.num("is_favorite")
+	}
+
+	s.touch(&p.seq)
+
+	return nil
+}
+
+func (s *Server) projectMove(a args) *apiError {
+	p, err := s.activeProject(a.id("id"))
+	if err != nil {
+		return err
+	}
+
+	p.ParentID = nil
+	if a.has("parent_id") && a.str("parent_id") != "" {
+		parent, err := s.activeProject(a.id("parent_id"))
+		if err != nil {
+			return err
+		}
+		if parent.ID == p.ID {
+			return errInvalidArgument("parent_id")
+		}
+		p.ParentID = intPtr(parent.ID)
+	}
+
+	s.touch(&p.seq)
+
+	return nil
+}
+
+// projectTree returns root followed by all of its
+// descendants.
+func (s *Server) projectTree(root *project) []*project {
+	tree := []*project{root}
+	for i := 0; i < len(tree); i++ {
+		for _, p := range sortedProjects(s.projects) {
+			if p.ParentID != nil && *p.ParentID == tree[i].ID && p.IsDeleted == 0 {
+				tree = append(tree, p)
+			}
+		}
+	}
+
+	return tree
+}
+
+func (s *Server) projectDelete(a args) *apiError {
+	// Deleting a project also deletes its descendants, so deleting one of
+	// them afterwards is accepted as a no-op.
+	if p, ok := s.projects[a.id("id")]; ok && p.IsDeleted == 1 {
+		return nil
+	}
+
+	p, err := s.activeProject(a.id("id"))
+	if err != nil {
+		return err
+	}
+	if p.InboxProject != nil {
+		return errForbidden("Inbox project can't be deleted")
+	}
+
+	for _, tp := range s.projectTree(p) {
+		tp.IsDeleted = 1
+		s.touch(&tp.seq)
+
+		for _, sec := range s.sections {
+			if sec.ProjectID == tp.ID && !sec.IsDeleted {
+				sec.IsDeleted = true
+				s.touch(&sec.seq)
+			}
+		}
+		for _, it := range s.items {
+			if it.ProjectID == tp.ID && it.IsDeleted == 0 {
+				it.IsDeleted = 1
+				s.touch(&it.seq)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) projectArchive(a args) *apiError {
+	p, err := s.activeProject(a.id("id"))
+	if err != nil {
+		return err
+	}
+	if p.InboxProject != nil {
+		return errForbidden("Inbox project can't be archived")
+	}
+
+	for _, tp := range s.projectTree(p) {
+		tp.IsArchived = 1
+		s.touch(&tp.seq)
+	}
+
+	return nil
+}
+
+func (s *Server) projectUnarchive(a args) *apiError {
+	p, err := s.activeProject(a.id("id"))
+	if err != nil {
+		return err
+	}
+
+	maxOrder := 0
+	for _, other := range s.projects {
+		if other.ParentID == nil && other.IsArchived == 0 && other.ChildOrder > maxOrder {
+			maxOrder = other.ChildOrder
+		}
+	}
+
+	p.IsArchived = 0
+	p.ParentID = nil
+	p.ChildOrder = maxOrder + 1
+	s.touch(&p.seq)
+
+	return nil
+}
+
+type reordered struct {
+	ID           json.RawMessage `json:"id"`
+	ChildOrder   int             `json:"child_order"`
+	SectionOrder int             `json:"section_order"`
+}
+
+func (s *Server) projectReorder(a args) *apiError {
+	var projects []reordered
+	if err := json.Unmarshal(a.raw["projects"], &projects); err != nil {
+		return errInvalidArgument("projects")
+	}
+
+	for _, r := range projects {
+		p, err := s.activeProject(resolveID(r.ID, a.tempIDs))
+		if err != nil {
+			return err
+		}
+		p.ChildOrder = r.ChildOrder
+		s.touch(&p.seq)
+	}
+
+	return nil
+}
+
+func (s *Server) sectionAdd(a args) (int, *apiError) {
+	if a.str("name") == "" {
+		return 0, errInvalidArgument("name")
+	}
+
+	p, err := s.activeProject(a.id("project_id"))
+	if err != nil {
+		return 0, err
+	}
+
+	sec := &section{
+		ID:           s.newID(),
+		Name:         a.str("name"),
+		ProjectID:    p.ID,
+		SectionOrder: a.num("section_order"),
+		DateAdded:    now(),
+	}
+
+	s.touch(&sec.seq)
+	s.sections[sec.ID] = sec
+
+	return sec.ID, nil
+}
+
+func (s *Server) sectionUpdate(a args) *apiError {
+	sec, err := s.activeSection(a.id("id"))
+	if err != nil {
+		return err
+	}
+
+	if a.has("name") {
+		sec.Name = a.str("name")
+	}
+	if a.has("collapsed") {
+		sec.Collapsed = a.boolean("collapsed")
+	}
+
+	s.touch(&sec.seq)
+
+	return nil
+}
+
+func (s *Server) sectionMove(a args) *apiError {
+	sec, err := s.activeSection(a.id("id"))
+	if err != nil {
+		return err
+	}
+
+	if a.has("project_id") {
+		p, err := s.activeProject(a.id("project_id"))
+		if err != nil {
+			return err
+		}
+		sec.ProjectID = p.ID
+
+		for _, it := range s.items {
+			if it.SectionID != nil && *it.SectionID == sec.ID {
+				it.ProjectID = p.ID
+				s.touch(&it.seq)
+			}
+		}
+	}
+
+	s.touch(&sec.seq)
+
+	return nil
+}
+
+func (s *Server) sectionReorder(a args) *apiError {
+	var sections []reordered
+	if err := json.Unmarshal(a.raw["sections"], &sections); err != nil {
+		return errInvalidArgument("sections")
+	}
+
+	for _, r := range sections {
+		sec, err := s.activeSection(resolveID(r.ID, a.tempIDs))
+		if err != nil {
+			return err
+		}
+		sec.SectionOrder = r.SectionOrder
+		s.touch(&sec.seq)
+	}
+
+	return nil
+}
+
+func (s *Server) sectionDelete(a args) *apiError {
+	sec, err := s.activeSection(a.id("id"))
+	if err != nil {
+		return err
+	}
+
+	sec.IsDeleted = true
+	s.touch(&sec.seq)
+
+	for _, it := range s.items {
+		if it.SectionID != nil && *it.SectionID == sec.ID && it.IsDeleted == 0 {
+			it.IsDeleted = 1
+			s.touch(&it.seq)
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) sectionArchive(a args, archive bool) *apiError {
+	sec, err := s.activeSection(a.id("id"))
+	if err != nil {
+		return err
+	}
+
+	sec.IsArchived = archive
+	sec.DateArchived = nil
+	if archive {
+		archived := now()
+		sec.DateArchived = &archived
+	}
+	s.touch(&sec.seq)
+
+	return nil
+}
+
+func (s *Server) itemAdd(a args) (int, *apiError) {
+	if a.str("content") == "" {
+		return 0, errInvalidArgument("content")
+	}
+
+	it := &item{
+		ID:          s.newID(),
+		UserID:      UserID,
+		ProjectID:   InboxProjectID,
+		Content:     a.str("content"),
+		Description: a.str("description"),
+		Due:         json.RawMessage("null"),
+		Priority:    a.num("priority"),
+		ChildOrder:  a.num("child_order"),
+		DayOrder:    a.num("day_order"),
+		Collapsed:   a.num("collapsed"),
+		Labels:      []int{},
+		AddedByUID:  intPtr(UserID),
+		DateAdded:   now(),
+	}
+	if it.Priority == 0 {
+		it.Priority = 1
+	}
+	if a.has("due") {
+		it.Due = a.raw["due"]
+	}
+	if a.has("labels") {
+		it.Labels = a.ids("labels")
+	}
+	if a.has("responsible_uid") {
+		it.ResponsibleUID = intPtr(a.num("responsible_uid"))
+	}
+
+	if a.has("project_id") {
+		p, err := s.activeProject(a.id("project_id"))
+		if err != nil {
+			return 0, err
+		}
+		it.ProjectID = p.ID
+	}
+	if a.has("section_id") {
+		sec, err := s.activeSection(a.id("section_id"))
+		if err != nil {
+			return 0, err
+		}
+		it.ProjectID = sec.ProjectID
+		it.SectionID = intPtr(sec.ID)
+	}
+	if a.has("parent_id") {
+		parent, err := s.activeItem(a.id("parent_id"))
+		if err != nil {
+			return 0, err
+		}
+		it.ProjectID = parent.ProjectID
+		it.SectionID = parent.SectionID
+		it.ParentID = intPtr(parent.ID)
+	}
+
+	s.touch(&it.seq)
+	s.items[it.ID] = it
+
+	return it.ID, nil
+}
+
+func (s *Server) itemUpdate(a args) *apiError {
+	it, err := s.activeItem(a.id("id"))
+	if err != nil {
+		return err
+	}
+
+	if a.has("content") {
+		it.Content = a.str("content")
+	}
+	if a.has("description") {
+		it.Description = a.str("description")
+	}
+	if _, ok := a.raw["due"]; ok {
+		it.Due = a.raw["due"]
+	}
+	if a.has("priority") {
+		it.Priority = a.num("priority")
+	}
+	if a.has("collapsed") {
+		it.Collapsed = a.num("collapsed")
+	}
+	if a.has("day_order") {
+		it.DayOrder = a.num("day_order")
+	}
+	if a.has("labels") {
+		it.Labels = a.ids("labels")
+	}
+	if a.has("responsible_uid") {
+		it.ResponsibleUID = intPtr(a.num("responsible_uid"))
+	}
+
+	s.touch(&it.seq)
+
+	return nil
+}
+
+func (s *Server) itemMove(a args) *apiError {
+	it, err := s.activeItem(a.id("id"))
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case a.has("parent_id"):
+		parent, err := s.activeItem(a.id("parent_id"))
+		if err != nil {
+			return err
+		}
+		it.ParentID = intPtr(parent.ID)
+		it.ProjectID = parent.ProjectID
+		it.SectionID = parent.SectionID
+
+	case a.has("section_id"):
+		sec, err := s.activeSection(a.id("section_id"))
+		if err != nil {
+			return err
+		}
+		it.ParentID = nil
+		it.ProjectID = sec.ProjectID
+		it.SectionID = intPtr(sec.ID)
+
+	case a.has("project_id"):
+		p, err := s.activeProject(a.id("project_id"))
+		if err != nil {
+			return err
+		}
+		it.ParentID = nil
+		it.ProjectID = p.ID
+		it.SectionID = nil
+
+	default:
+		return errInvalidArgument("project_id")
+	}
+
+	s.touch(&it.seq)
+
+	return nil
+}
+
+func (s *Server) itemReorder(a args) *apiError {
+	var items []reordered
+	if err := json.Unmarshal(a.raw["items"], &items); err != nil {
+		return errInvalidArgument("items")
+	}
+
+	for _, r := range items {
+		it, err := s.activeItem(resolveID(r.ID, a.tempIDs))
+		if err != nil {
+			return err
+		}
+		it.ChildOrder = r.ChildOrder
+		s.touch(&it.seq)
+	}
+
+	return nil
+}
+
+// itemTree returns the item followed by all of its descendants.
+func (s *Server) itemTree(root *item) []*item {
+	tree := []*item{root}
+	for i := 0; i < len(tree); i++ {
+		for _, it := range sortedItems(s.items) {
+			if it.ParentID != nil && *it.ParentID == tree[i].ID && it.IsDeleted == 0 {
+				tree = append(tree, it)
+			}
+		}
+	}
+
+	return tree
+}
+
+func (s *Server) itemDelete(a args) *apiError {
+	it, err := s.activeItem(a.id("id"))
+	if err != nil {
+		return err
+	}
+
+	for _, ti := range s.itemTree(it) {
+		ti.IsDeleted = 1
+		s.touch(&ti.seq)
+	}
+
+	return nil
+}
+
+func (s *Server) itemComplete(a args, complete bool) *apiError {
+	it, err := s.activeItem(a.id("id"))
+	if err != nil {
+		return err
+	}
+
+	tree := []*item{it}
+	if complete {
+		tree = s.itemTree(it)
+	}
+
+	for _, ti := range tree {
+		ti.Checked = 0
+		ti.DateCompleted = nil
+		if complete {
+			completed := now()
+			ti.Checked = 1
+			ti.DateCompleted = &completed
+		}
+		s.touch(&ti.seq)
+	}
+
+	return nil
+}
+
+func (s *Server) labelAdd(a args) (int, *apiError) {
+	if a.str("name") == "" {
+		return 0, errInvalidArgument("name")
+	}
+
+	l := &label{
+		ID:         s.newID(),
+		Name:       a.str("name"),
+		Color:      a.num("color"),
+		ItemOrder:  a.num("item_order"),
+		IsFavorite: a.num("is_favorite"),
+	}
+	if l.Color == 0 {
+		l.Color = 47
+	}
+
+	s.touch(&l.seq)
+	s.labels[l.ID] = l
+
+	return l.ID, nil
+}
+
+// noteAdd adds a task comment (note_add, if item is true) or a project
+// comment (project_note_add).
+func (s *Server) noteAdd(a args, item bool) (int, *apiError) {
+	if a.str("content") == "" && !a.has("file_attachment") {
+		return 0, errInvalidArgument("content")
+	}
+
+	n := &note{
+		ID:             s.newID(),
+		PostedUID:      UserID,
+		Content:        a.str("content"),
+		FileAttachment: json.RawMessage("null"),
+		Posted:         now(),
+	}
+	if a.has("file_attachment") {
+		n.FileAttachment = a.raw["file_attachment"]
+	}
+
+	if item {
+		it, err := s.activeItem(a.id("item_id"))
+		if err != nil {
+			return 0, err
+		}
+		n.ItemID = intPtr(it.ID)
+		n.ProjectID = it.ProjectID
+	} else {
+		p, err := s.activeProject(a.id("project_id"))
+		if err != nil {
+			return 0, err
+		}
+		n.ProjectID = p.ID
+	}
+
+	s.touch(&n.seq)
+	s.notes[n.ID] = n
+
+	return n.ID, nil
+}
+
+func (s *Server) filterAdd(a args) (int, *apiError) {
+	if a.str("name") == "" {
+		return 0, errInvalidArgument("name")
+	}
+	if a.str("query") == "" {
+		return 0, errInvalidArgument("query")
+	}
+
+	f := &filter{
+		ID:         s.newID(),
+		Name:       a.str("name"),
+		Query:      a.str("query"),
+		Color:      a.num("color"),
+		ItemOrder:  a.num("item_order"),
+		IsFavorite: a.num("is_favorite"),
+	}
+	if f.Color == 0 {
+		f.Color = 47
+	}
+
+	s.touch(&f.seq)
+	s.filters[f.ID] = f
+
+	return f.ID, nil
+}
+
+func (s *Server) reminderAdd(a args) (int, *apiError) {
+	it, err := s.activeItem(a.id("item_id"))
+	if err != nil {
+		return 0, err
+	}
+
+	r := &reminder{
+		ID:        s.newID(),
+		NotifyUID: UserID,
+		ItemID:    it.ID,
+		Service:   a.str("service"),
+		Type:      a.str("type"),
+		Due:       json.RawMessage("null"),
+		MmOffset:  a.num("minute_offset"),
+	}
+	if r.Type == "" {
+		r.Type = "relative"
+	}
+	if r.Service == "" {
+		r.Service = "push"
+	}
+	if a.has("due") {
+		r.Due = a.raw["due"]
+	}
+	if a.has("mm_offset") {
+		r.MmOffset = a.num("mm_offset")
+	}
+
+	s.touch(&r.seq)
+	s.reminders[r.ID] = r
+
+	return r.ID, nil
+}
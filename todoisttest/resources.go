@@ -0,0 +1,289 @@
+package todoisttest
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+// The resource types below mirror the wire format of the v8 Sync API. They are
+// deliberately independent of the todoist package so that its own tests can
+// use the fake server without an import cycle.
+
+type project struct {
+	ID             int    `json:"id"`
+	LegacyID       *int   `json:"legacy_id"`
+	Name           string `json:"name"`
+	Color          int    `json:"color"`
+	ParentID       *int   `json:"parent_id"`
+	LegacyParentID *int   `json:"legacy_parent_id"`
+	ChildOrder     int    `json:"child_order"`
+	Collapsed      int    `json:"collapsed"`
+	Shared         bool   `json:"shared"`
+	IsDeleted      int    `json:"is_deleted"`
+	IsArchived     int    `json:"is_archived"`
+	IsFavorite     int    `json:"is_favorite"`
+	SyncID         *int   `json:"sync_id"`
+	InboxProject   *bool  `json:"inbox_project,omitempty"`
+	TeamInbox      *bool  `json:"team_inbox,omitempty"`
+
+	seq int
+}
+
+type section struct {
+	ID              int     `json:"id"`
+	Name            string  `json:"name"`
+	ProjectID       int     `json:"project_id"`
+	LegacyProjectID *int    `json:"legacy_project_id"`
+	SectionOrder    int     `json:"section_order"`
+	Collapsed       bool    `json:"collapsed"`
+	SyncID          *int    `json:"sync_id"`
+	IsDeleted       bool    `json:"is_deleted"`
+	IsArchived      bool    `json:"is_archived"`
+	DateArchived    *string `json:"date_archived"`
+	DateAdded       string  `json:"date_added"`
+
+	seq int
+}
+
+type item struct {
+	ID              int             `json:"id"`
+	LegacyID        *int            `json:"legacy_id"`
+	UserID          int             `json:"user_id"`
+	ProjectID       int             `json:"project_id"`
+	LegacyProjectID *int            `json:"legacy_project_id"`
+	Content         string          `json:"content"`
+	Description     string          `json:"description"`
+	Due             json.RawMessage `json:"due"`
+	Priority        int             `json:"priority"`
+	ParentID        *int            `json:"parent_id"`
+	LegacyParentID  *int            `json:"legacy_parent_id"`
+	ChildOrder      int             `json:"child_order"`
+	SectionID       *int            `json:"section_id"`
+	DayOrder        int             `json:"day_order"`
+	Collapsed       int             `json:"collapsed"`
+	Labels          []int           `json:"labels"`
+	AddedByUID      *int            `json:"added_by_uid"`
+	AssignedByUID   *int            `json:"assigned_by_uid"`
+	ResponsibleUID  *int            `json:"responsible_uid"`
+	Checked         int             `json:"checked"`
+	InHistory       int             `json:"in_history"`
+	IsDeleted       int             `json:"is_deleted"`
+	SyncID          *int            `json:"sync_id"`
+	DateCompleted   *string         `json:"date_completed"`
+	DateAdded       string          `json:"date_added"`
+
+	seq int
+}
+
+func now() string {
+	return time.Now().UTC().Format("2006-01-02T15:04:05Z")
+}
+
+func intPtr(n int) *int {
+	return &n
+}
+
+func sortedProjects(m map[int]*project) []*project {
+	out := make([]*project, 0, len(m))
+	for _, p := range m {
+		out = append(out, p)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+
+	return out
+}
+
+func sortedSections(m map[int]*section) []*section {
+	out := make([]*section, 0, len(m))
+	for _, s := range m {
+		out = append(out, s)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+
+	return out
+}
+
+func sortedItems(m map[int]*item) []*item {
+	out := make([]*item, 0, len(m))
+	for _, it := range m {
+		out = append(out, it)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+
+	return out
+}
+
+// changedProjects returns the projects changed after the change sequence
+// number since, or every active project if since is negative (a full sync).
+// s.mu must be held.
+func (s *Server) changedProjects(since int) []*project {
+	out := []*project{}
+	for _, p := range sortedProjects(s.projects) {
+		if since < 0 && (p.IsDeleted == 1 || p.IsArchived == 1) {
+			continue
+		}
+		if since >= 0 && p.seq <= since {
+			continue
+		}
+		out = append(out, p)
+	}
+
+	return out
+}
+
+// changedSections is the sections equivalent of changedProjects.
+func (s *Server) changedSections(since int) []*section {
+	out := []*section{}
+	for _, sec := range sortedSections(s.sections) {
+		if since < 0 && sec.IsDeleted {
+			continue
+		}
+		if since >= 0 && sec.seq <= since {
+			continue
+		}
+		out = append(out, sec)
+	}
+
+	return out
+}
+
+// changedItems is the items equivalent of changedProjects.
+func (s *Server) changedItems(since int) []*item {
+	out := []*item{}
+	for _, it := range sortedItems(s.items) {
+		if since < 0 && (it.IsDeleted == 1 || it.Checked == 1) {
+			continue
+		}
+		if since >= 0 && it.seq <= since {
+			continue
+		}
+		out = append(out, it)
+	}
+
+	return out
+}
+
+// touch records a change to the resource whose seq field is pointed to by seq.
+// s.mu must be held.
+func (s *Server) touch(seq *int) {
+	s.seq++
+	*seq = s.seq
+}
+
+func (s *Server) newID() int {
+	id := s.nextID
+	s.nextID++
+
+	return id
+}
+
+type label struct {
+	ID         int    `json:"id"`
+	Name       string `json:"name"`
+	Color      int    `json:"color"`
+	ItemOrder  int    `json:"item_order"`
+	IsDeleted  int    `json:"is_deleted"`
+	IsFavorite int    `json:"is_favorite"`
+
+	seq int
+}
+
+// note is a task comment (with ItemID set) or a project comment.
+type note struct {
+	ID             int             `json:"id"`
+	PostedUID      int             `json:"posted_uid"`
+	ItemID         *int            `json:"item_id,omitempty"`
+	ProjectID      int             `json:"project_id"`
+	Content        string          `json:"content"`
+	FileAttachment json.RawMessage `json:"file_attachment"`
+	Posted         string          `json:"posted"`
+	IsDeleted      int             `json:"is_deleted"`
+
+	seq int
+}
+
+type filter struct {
+	ID         int    `json:"id"`
+	Name       string `json:"name"`
+	Query      string `json:"query"`
+	Color      int    `json:"color"`
+	ItemOrder  int    `json:"item_order"`
+	IsDeleted  int    `json:"is_deleted"`
+	IsFavorite int    `json:"is_favorite"`
+
+	seq int
+}
+
+type reminder struct {
+	ID        int             `json:"id"`
+	NotifyUID int             `json:"notify_uid"`
+	ItemID    int             `json:"item_id"`
+	Service   string          `json:"service"`
+	Type      string          `json:"type"`
+	Due       json.RawMessage `json:"due"`
+	MmOffset  int             `json:"mm_offset"`
+	IsDeleted int             `json:"is_deleted"`
+
+	seq int
+}
+
+// changedLabels is the labels equivalent of changedProjects.
+func (s *Server) changedLabels(since int) []*label {
+	out := []*label{}
+	for _, l := range s.labels {
+		if (since < 0 && l.IsDeleted == 1) || (since >= 0 && l.seq <= since) {
+			continue
+		}
+		out = append(out, l)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+
+	return out
+}
+
+// changedNotes is the notes equivalent of changedProjects, for the task
+// comments (if items is true) or the project comments.
+func (s *Server) changedNotes(since int, items bool) []*note {
+	out := []*note{}
+	for _, n := range s.notes {
+		if (n.ItemID != nil) != items {
+			continue
+		}
+		if (since < 0 && n.IsDeleted == 1) || (since >= 0 && n.seq <= since) {
+			continue
+		}
+		out = append(out, n)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+
+	return out
+}
+
+// changedFilters is the filters equivalent of changedProjects.
+func (s *Server) changedFilters(since int) []*filter {
+	out := []*filter{}
+	for _, f := range s.filters {
+		if (since < 0 && f.IsDeleted == 1) || (since >= 0 && f.seq <= since) {
+			continue
+		}
+		out = append(out, f)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+
+	return out
+}
+
+// changedReminders is the reminders equivalent of changedProjects.
+func (s *Server) changedReminders(since int) []*reminder {
+	out := []*reminder{}
+	for _, r := range s.reminders {
+		if (since < 0 && r.IsDeleted == 1) || (since >= 0 && r.seq <= since) {
+			continue
+		}
+		out = append(out, r)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+
+	return out
+}
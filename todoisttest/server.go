@@ -0,0 +1,581 @@
+// Package todoisttest provides an in-process fake of the Todoist Sync API for
+// use in tests.
+//
+// The fake keeps its state in memory and understands the /sync endpoint (reads
+// with sync tokens, and commands with temp IDs and per-command sync_status
+// results) as well as the /projects/*, /templates/*, /backups/* and /uploads/*
+// endpoints. Faults such as rate limiting, server errors and partial command
+// failures can be injected to exercise error handling in code built on top of
+// the client.
+//
+//	srv := todoisttest.NewServer()
+//	defer srv.Close()
+//
+//	client, _ := todoist.NewClient(srv.Token)
+//	client.BaseURL, _ = url.Parse(srv.SyncURL())
+package todoisttest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultToken is the API token accepted by a Server unless Token is changed.
+	DefaultToken = "todoisttest-token"
+
+	// InboxProjectID is the ID of the Inbox project every Server starts with.
+	InboxProjectID = 1000
+
+	// UserID is the ID of the user owning all of the Server's resources.
+	UserID = 1
+
+	// APIPath is the path prefix the Server serves the Sync API under.
+	APIPath = "/sync/v8"
+)
+
+// Server is a fake Todoist API server backed by an in-memory store.
+type Server struct {
+	*httptest.Server
+
+	// Token is the API token requests must present. Requests with a different
+	// token are rejected with 401 Unauthorized.
+	Token string
+
+	mu sync.Mutex
+
+	seq    int // incremented on every change, used to build sync tokens
+	nextID int // next ID handed out to a created resource
+
+	projects map[int]*project
+	sections map[int]*section
+	items    map[int]*item
+
+	labels    map[int]*label
+	notes     map[int]*note
+	filters   map[int]*filter
+	reminders map[int]*reminder
+
+	files   map[string][]byte // exported files served under filesPath, by name
+	backups []*backup
+	uploads []*upload
+
+	faults   []*Fault
+	requests []Request
+}
+
+// Request records a request received by the Server.
+type Request struct {
+	Path          string   // URL path of the request
+	SyncToken     string   // sync_token form value
+	ResourceTypes []string // decoded resource_types form value
+	Commands      []string // types of the commands sent with the request
+}
+
+// NewServer starts and returns a new Server with an empty account containing
+// only an Inbox project. The caller should call Close when finished, to shut
+// it down.
+func NewServer() *Server {
+	s := &Server{
+		Token:    DefaultToken,
+		nextID:   InboxProjectID + 1,
+		projects: map[int]*project{},
+		sections: map[int]*section{},
+		items:    map[int]*item{},
+
+		labels:    map[int]*label{},
+		notes:     map[int]*note{},
+		filters:   map[int]*filter{},
+		reminders: map[int]*reminder{},
+
+		files: map[string][]byte{},
+	}
+
+	inbox := true
+	s.projects[InboxProjectID] = &project{
+		ID:           InboxProjectID,
+		Name:         "Inbox",
+		Color:        48,
+		InboxProject: &inbox,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(APIPath+"/sync", s.handleSync)
+	mux.HandleFunc(APIPath+"/projects/get", s.handleProjectsGet)
+	mux.HandleFunc(APIPath+"/projects/get_data", s.handleProjectsGetData)
+	mux.HandleFunc(APIPath+"/projects/get_archived", s.handleProjectsGetArchived)
+	mux.HandleFunc(APIPath+"/templates/import_into_project", s.handleTemplatesImport)
+	mux.HandleFunc(APIPath+"/templates/export_as_file", s.handleTemplatesExportAsFile)
+	mux.HandleFunc(APIPath+"/templates/export_as_url", s.handleTemplatesExportAsURL)
+	mux.HandleFunc(filesPath, s.handleFiles)
+	mux.HandleFunc(APIPath+"/backups/get", s.handleBackupsGet)
+	mux.HandleFunc(backupsPath, s.handleBackupsDownload)
+	mux.HandleFunc(APIPath+"/uploads/add", s.handleUploadsAdd)
+	mux.HandleFunc(APIPath+"/uploads/get", s.handleUploadsGet)
+	mux.HandleFunc(APIPath+"/uploads/delete", s.handleUploadsDelete)
+
+	s.Server = httptest.NewServer(mux)
+
+	return s
+}
+
+// SyncURL returns the URL of the Server's /sync endpoint, suitable for use as
+// the client's BaseURL.
+func (s *Server) SyncURL() string {
+	return s.URL + APIPath + "/sync"
+}
+
+// Requests returns the requests received by the Server so far, in order.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Request(nil), s.requests...)
+}
+
+// Fault describes an error the Server should return instead of handling a
+// request normally.
+type Fault struct {
+	// Path restricts the fault to requests for an endpoint, relative to the API
+	// root (for example "sync" or "projects/get"). An empty Path matches every
+	// endpoint.
+	Path string
+
+	// Command turns the fault into a partial failure: the request succeeds, but
+	// every command of this type (for example "project_add") is reported as
+	// failed in sync_status and is not applied. Other commands in the same
+	// request are applied as usual.
+	Command string
+
+	// StatusCode is the HTTP status code of the error. Defaults to 500 for
+	// request faults and 400 for command faults.
+	StatusCode int
+
+	// Tag, Code and Message populate the error_tag, error_code and error fields
+	// of the error response. Sensible values are used when they are left empty.
+	Tag     string
+	Code    int
+	Message string
+
+	// RetryAfter, if set, is sent as the Retry-After header (in seconds) with
+	// request faults.
+	RetryAfter time.Duration
+
+	// Times is the number of times the fault fires before it is cleared. Zero
+	// means the fault fires until ClearFaults is called.
+	Times int
+
+	fired int
+}
+
+// InjectFault registers a fault. Faults are matched in the order they were
+// injected.
+func (s *Server) InjectFault(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = append(s.faults, &f)
+}
+
+// RateLimit makes the next n requests fail with 429 Too Many Requests.
+func (s *Server) RateLimit(n int) {
+	s.InjectFault(Fault{StatusCode: http.StatusTooManyRequests, Times: n, RetryAfter: time.Second})
+}
+
+// ClearFaults removes every registered fault.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = nil
+}
+
+// requestFault returns the first active request fault matching endpoint, and
+// marks it as fired. s.mu must be held.
+func (s *Server) requestFault(endpoint string) *Fault {
+	for _, f := range s.faults {
+		if f.Command != "" || (f.Path != "" && f.Path != endpoint) || f.exhausted() {
+			continue
+		}
+
+		f.fired++
+		return f
+	}
+
+	return nil
+}
+
+// commandFault returns the first active command fault matching cmdType, and
+// marks it as fired. s.mu must be held.
+func (s *Server) commandFault(cmdType string) *Fault {
+	for _, f := range s.faults {
+		if f.Command != cmdType || (f.Path != "" && f.Path != "sync") || f.exhausted() {
+			continue
+		}
+
+		f.fired++
+		return f
+	}
+
+	return nil
+}
+
+func (f *Fault) exhausted() bool {
+	return f.Times > 0 && f.fired >= f.Times
+}
+
+func (f *Fault) apiError(defaultStatus int) *apiError {
+	status := f.StatusCode
+	if status == 0 {
+		status = defaultStatus
+	}
+
+	e := &apiError{
+		Tag:      f.Tag,
+		Code:     f.Code,
+		Message:  f.Message,
+		HTTPCode: status,
+		Extra:    map[string]interface{}{},
+	}
+
+	if e.Tag == "" {
+		e.Tag = "INJECTED_FAULT"
+	}
+	if e.Message == "" {
+		e.Message = http.StatusText(status)
+	}
+	if f.RetryAfter > 0 {
+		e.Extra["retry_after"] = int(f.RetryAfter / time.Second)
+	}
+
+	return e
+}
+
+// apiError is the error object returned by the Todoist API, both as an HTTP
+// error response body and as a failed sync_status value.
+type apiError struct {
+	Tag      string                 `json:"error_tag"`
+	Code     int                    `json:"error_code"`
+	Message  string                 `json:"error"`
+	HTTPCode int                    `json:"http_code"`
+	Extra    map[string]interface{} `json:"error_extra"`
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("(%d) %s: %s", e.HTTPCode, e.Tag, e.Message)
+}
+
+func errNotFound(kind string) *apiError {
+	return &apiError{
+		Tag:      strings.ToUpper(kind) + "_NOT_FOUND",
+		Code:     22,
+		Message:  strings.ToUpper(kind[:1]) + kind[1:] + " not found",
+		HTTPCode: http.StatusNotFound,
+		Extra:    map[string]interface{}{},
+	}
+}
+
+func errInvalidArgument(name string) *apiError {
+	return &apiError{
+		Tag:      "INVALID_ARGUMENT_VALUE",
+		Code:     20,
+		Message:  "Invalid argument value",
+		HTTPCode: http.StatusBadRequest,
+		Extra:    map[string]interface{}{"argument": name},
+	}
+}
+
+func errForbidden(message string) *apiError {
+	return &apiError{
+		Tag:      "FORBIDDEN",
+		Code:     31,
+		Message:  message,
+		HTTPCode: http.StatusForbidden,
+		Extra:    map[string]interface{}{},
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, e *apiError, retryAfter time.Duration) {
+	if retryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter/time.Second)))
+	}
+
+	writeJSON(w, e.HTTPCode, e)
+}
+
+// begin performs the checks shared by every endpoint: it parses the form,
+// records the request, verifies the token and fires any matching request
+// fault. It returns false if a response has already been written. On success
+// s.mu is held and must be released by the caller.
+func (s *Server) begin(w http.ResponseWriter, r *http.Request, endpoint string) bool {
+	if r.Method != http.MethodPost && r.Method != http.MethodGet {
+		writeError(w, &apiError{
+			Tag:      "METHOD_NOT_ALLOWED",
+			Message:  "Method not allowed",
+			HTTPCode: http.StatusMethodNotAllowed,
+		}, 0)
+		return false
+	}
+
+	if err := r.ParseMultipartForm(maxUploadSize); err != nil && err != http.ErrNotMultipart {
+		writeError(w, errInvalidArgument("body"), 0)
+		return false
+	}
+
+	s.mu.Lock()
+
+	rec := Request{Path: endpoint, SyncToken: r.Form.Get("sync_token")}
+	_ = json.Unmarshal([]byte(r.Form.Get("resource_types")), &rec.ResourceTypes)
+	var cmds []command
+	_ = json.Unmarshal([]byte(r.Form.Get("commands")), &cmds)
+	for _, c := range cmds {
+		rec.Commands = append(rec.Commands, c.Type)
+	}
+	s.requests = append(s.requests, rec)
+
+	token := r.Form.Get("token")
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		token = strings.TrimPrefix(auth, "Bearer ")
+	}
+	if token != s.Token {
+		s.mu.Unlock()
+		writeError(w, &apiError{
+			Tag:      "AUTH_INVALID_TOKEN",
+			Code:     401,
+			Message:  "Invalid token",
+			HTTPCode: http.StatusUnauthorized,
+			Extra:    map[string]interface{}{},
+		}, 0)
+		return false
+	}
+
+	if f := s.requestFault(endpoint); f != nil {
+		s.mu.Unlock()
+		writeError(w, f.apiError(http.StatusInternalServerError), f.RetryAfter)
+		return false
+	}
+
+	return true
+}
+
+// syncToken encodes a change sequence number as an opaque sync token.
+func syncToken(seq int) string {
+	return "fake-" + strconv.Itoa(seq)
+}
+
+// parseSyncToken returns the sequence number encoded by token, or -1 (meaning
+// a full sync) if token is empty, "*" or unrecognized.
+func parseSyncToken(token string) int {
+	seq, err := strconv.Atoi(strings.TrimPrefix(token, "fake-"))
+	if err != nil || !strings.HasPrefix(token, "fake-") {
+		return -1
+	}
+
+	return seq
+}
+
+func (s *Server) handleSync(w http.ResponseWriter, r *http.Request) {
+	if !s.begin(w, r, "sync") {
+		return
+	}
+	defer s.mu.Unlock()
+
+	var resourceTypes []string
+	if v := r.Form.Get("resource_types"); v != "" {
+		if err := json.Unmarshal([]byte(v), &resourceTypes); err != nil {
+			writeError(w, errInvalidArgument("resource_types"), 0)
+			return
+		}
+	}
+
+	var cmds []command
+	if v := r.Form.Get("commands"); v != "" {
+		if err := json.Unmarshal([]byte(v), &cmds); err != nil {
+			writeError(w, errInvalidArgument("commands"), 0)
+			return
+		}
+	}
+
+	since := parseSyncToken(r.Form.Get("sync_token"))
+
+	resp := map[string]interface{}{}
+
+	if len(cmds) > 0 {
+		syncStatus := map[string]interface{}{}
+		tempIDMapping := map[string]int{}
+
+		for _, c := range cmds {
+			if f := s.commandFault(c.Type); f != nil {
+				syncStatus[c.UUID] = f.apiError(http.StatusBadRequest)
+				continue
+			}
+
+			id, err := s.apply(c, tempIDMapping)
+			if err != nil {
+				syncStatus[c.UUID] = err
+				continue
+			}
+
+			if id != 0 && c.TempID != "" {
+				tempIDMapping[c.TempID] = id
+			}
+			syncStatus[c.UUID] = "ok"
+		}
+
+		resp["sync_status"] = syncStatus
+		resp["temp_id_mapping"] = tempIDMapping
+	}
+
+	resp["full_sync"] = since < 0
+	resp["sync_token"] = syncToken(s.seq)
+
+	for _, rt := range s.expandResourceTypes(resourceTypes) {
+		switch rt {
+		case "projects":
+			resp["projects"] = s.changedProjects(since)
+		case "sections":
+			resp["sections"] = s.changedSections(since)
+		case "items":
+			resp["items"] = s.changedItems(since)
+		case "labels":
+			resp["labels"] = s.changedLabels(since)
+		case "notes":
+			resp["notes"] = s.changedNotes(since, true)
+		case "project_notes":
+			resp["project_notes"] = s.changedNotes(since, false)
+		case "filters":
+			resp["filters"] = s.changedFilters(since)
+		case "reminders":
+			resp["reminders"] = s.changedReminders(since)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) expandResourceTypes(resourceTypes []string) []string {
+	all := []string{"projects", "sections", "items", "labels", "notes", "project_notes", "filters", "reminders"}
+
+	var out []string
+	for _, rt := range resourceTypes {
+		if rt == "all" {
+			return all
+		}
+		out = append(out, rt)
+	}
+
+	return out
+}
+
+func (s *Server) handleProjectsGet(w http.ResponseWriter, r *http.Request) {
+	if !s.begin(w, r, "projects/get") {
+		return
+	}
+	defer s.mu.Unlock()
+
+	p, ok := s.projects[atoi(r.Form.Get("project_id"))]
+	if !ok || p.IsDeleted == 1 {
+		writeError(w, errNotFound("project"), 0)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"project": p,
+		"notes":   []interface{}{},
+	})
+}
+
+func (s *Server) handleProjectsGetData(w http.ResponseWriter, r *http.Request) {
+	if !s.begin(w, r, "projects/get_data") {
+		return
+	}
+	defer s.mu.Unlock()
+
+	id := atoi(r.Form.Get("project_id"))
+	p, ok := s.projects[id]
+	if !ok || p.IsDeleted == 1 {
+		writeError(w, errNotFound("project"), 0)
+		return
+	}
+
+	sections := []*section{}
+	for _, sec := range sortedSections(s.sections) {
+		if sec.ProjectID == id && !sec.IsDeleted {
+			sections = append(sections, sec)
+		}
+	}
+
+	items := []*item{}
+	for _, it := range sortedItems(s.items) {
+		if it.ProjectID == id && it.IsDeleted == 0 && it.Checked == 0 {
+			items = append(items, it)
+		}
+	}
+
+	notes := []*note{}
+	for _, n := range s.changedNotes(-1, false) {
+		if n.ProjectID == id {
+			notes = append(notes, n)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"project":       p,
+		"project_notes": notes,
+		"sections":      sections,
+		"items":         items,
+	})
+}
+
+func (s *Server) handleProjectsGetArchived(w http.ResponseWriter, r *http.Request) {
+	if !s.begin(w, r, "projects/get_archived") {
+		return
+	}
+	defer s.mu.Unlock()
+
+	limit, offset := 500, 0
+	if v := r.Form.Get("limit"); v != "" {
+		limit = atoi(v)
+		if limit < 1 || limit > 500 {
+			writeError(w, errInvalidArgument("limit"), 0)
+			return
+		}
+	}
+	if v := r.Form.Get("offset"); v != "" {
+		offset = atoi(v)
+	}
+
+	archived := []*project{}
+	for _, p := range sortedProjects(s.projects) {
+		if p.IsArchived == 1 && p.IsDeleted == 0 {
+			archived = append(archived, p)
+		}
+	}
+
+	if offset > len(archived) {
+		offset = len(archived)
+	}
+	archived = archived[offset:]
+	if limit < len(archived) {
+		archived = archived[:limit]
+	}
+
+	writeJSON(w, http.StatusOK, archived)
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
@@ -0,0 +1,196 @@
+package todoisttest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/ides15/todoist"
+	"github.com/ides15/todoist/todoisttest"
+)
+
+func newClient(t *testing.T, srv *todoisttest.Server) *todoist.Client {
+	t.Helper()
+
+	client, err := todoist.NewClient(srv.Token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client.BaseURL, err = url.Parse(srv.SyncURL())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return client
+}
+
+func Test_Server_Projects(t *testing.T) {
+	srv := todoisttest.NewServer()
+	defer srv.Close()
+
+	client := newClient(t, srv)
+	ctx := context.Background()
+
+	projects, resp, err := client.Projects.List(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(projects) != 1 || projects[0].InboxProject == nil || !*projects[0].InboxProject {
+		t.Fatalf("expected only the inbox project, received %+v", projects)
+	}
+	if !resp.FullSync {
+		t.Error("expected a full sync for an empty sync token")
+	}
+	syncToken := resp.SyncToken
+
+	_, cmdResp, err := client.Projects.Add(ctx, "", todoist.AddProject{Name: "Parent", TempID: "parent"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mappedID, ok := cmdResp.TempIDMapping["parent"]
+	if !ok {
+		t.Fatalf("expected temp id mapping for \"parent\", received %v", cmdResp.TempIDMapping)
+	}
+	parentID := todoist.ProjectID{ID: mappedID}
+
+	_, _, err = client.Projects.Add(ctx, "", todoist.AddProject{Name: "Child", ParentID: &parentID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	projects, resp, err = client.Projects.List(ctx, syncToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.FullSync {
+		t.Error("expected an incremental sync for a previous sync token")
+	}
+	if len(projects) != 2 {
+		t.Fatalf("expected 2 changed projects, received %d", len(projects))
+	}
+	if projects[1].ParentID == nil || *projects[1].ParentID != parentID {
+		t.Errorf("expected child project to have parent %s, received %v", parentID, projects[1].ParentID)
+	}
+
+	_, _, err = client.Projects.Archive(ctx, "", todoist.ArchiveProject{ID: parentID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	archived, err := client.Projects.GetArchivedProjects(ctx, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(archived) != 2 {
+		t.Errorf("expected the project and its child to be archived, received %d archived projects", len(archived))
+	}
+
+	info, err := client.Projects.GetProjectInfo(ctx, "", parentID, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Project.Name != "Parent" {
+		t.Errorf("expected project info for \"Parent\", received %q", info.Project.Name)
+	}
+
+	_, err = client.Projects.GetProjectData(ctx, "", todoist.ProjectID{ID: todoist.NewIntID(12345)})
+	var notFound todoist.NotFoundError
+	if !errors.As(err, &notFound) {
+		t.Errorf("expected a NotFoundError for an unknown project, received %v", err)
+	}
+}
+
+func Test_Server_TempIDsInOneRequest(t *testing.T) {
+	srv := todoisttest.NewServer()
+	defer srv.Close()
+
+	client := newClient(t, srv)
+
+	req, err := client.NewRequest("", []string{"projects", "sections", "items"}, []todoist.Command{
+		{Type: "project_add", Args: map[string]interface{}{"name": "Project"}, UUID: "1", TempID: "p"},
+		{Type: "section_add", Args: map[string]interface{}{"name": "Section", "project_id": "p"}, UUID: "2", TempID: "s"},
+		{Type: "item_add", Args: map[string]interface{}{"content": "Task", "section_id": "s"}, UUID: "3", TempID: "i"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var resp todoist.CommandResponse
+	if _, err = client.Do(context.Background(), req, &resp); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resp.TempIDMapping) != 3 {
+		t.Fatalf("expected 3 temp id mappings, received %v", resp.TempIDMapping)
+	}
+	if len(resp.Tasks) != 1 || resp.Tasks[0].ProjectID.ID != resp.TempIDMapping["p"] {
+		t.Errorf("expected the task to be added to the new project, received %+v", resp.Tasks)
+	}
+	if resp.Tasks[0].SectionID == nil || resp.Tasks[0].SectionID.ID != resp.TempIDMapping["s"] {
+		t.Errorf("expected the task to be added to the new section, received %+v", resp.Tasks[0].SectionID)
+	}
+}
+
+func Test_Server_Faults(t *testing.T) {
+	srv := todoisttest.NewServer()
+	defer srv.Close()
+
+	client := newClient(t, srv)
+	ctx := context.Background()
+
+	srv.RateLimit(1)
+
+	_, _, err := client.Projects.List(ctx, "")
+	var tooManyRequests todoist.TooManyRequestsError
+	if !errors.As(err, &tooManyRequests) {
+		t.Fatalf("expected a TooManyRequestsError, received %v", err)
+	}
+
+	if _, _, err = client.Projects.List(ctx, ""); err != nil {
+		t.Fatalf("expected the rate limit to be lifted, received %v", err)
+	}
+
+	srv.InjectFault(todoisttest.Fault{Path: "projects/get_archived", Times: 1})
+
+	_, err = client.Projects.GetArchivedProjects(ctx, "", nil)
+	var internal todoist.InternalServerError
+	if !errors.As(err, &internal) {
+		t.Fatalf("expected an InternalServerError, received %v", err)
+	}
+
+	srv.InjectFault(todoisttest.Fault{Command: "project_add", Tag: "MAX_PROJECTS_LIMIT_REACHED", Times: 1})
+
+	_, _, err = client.Projects.Add(ctx, "", todoist.AddProject{Name: "Rejected"})
+	var syncErr todoist.SyncError
+	if !errors.As(err, &syncErr) {
+		t.Fatalf("expected a SyncError, received %v", err)
+	}
+	if syncErr.Tag != "MAX_PROJECTS_LIMIT_REACHED" || syncErr.HTTPCode != http.StatusBadRequest {
+		t.Errorf("unexpected sync error %+v", syncErr)
+	}
+
+	projects, _, err := client.Projects.List(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(projects) != 1 {
+		t.Errorf("expected the failed command not to be applied, received %d projects", len(projects))
+	}
+}
+
+func Test_Server_Unauthorized(t *testing.T) {
+	srv := todoisttest.NewServer()
+	defer srv.Close()
+
+	client := newClient(t, srv)
+	client.APIToken = "wrong"
+
+	_, _, err := client.Projects.List(context.Background(), "")
+	var unauthorized todoist.UnauthorizedError
+	if !errors.As(err, &unauthorized) {
+		t.Errorf("expected an UnauthorizedError, received %v", err)
+	}
+}
@@ -0,0 +1,308 @@
+package todoisttest
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// maxUploadSize is the size of uploaded files kept in memory.
+	maxUploadSize = 10 << 20
+
+	// filesPath is the path exported files are served under.
+	filesPath = "/files/"
+)
+
+// templateColumns are the columns of an exported template.
+var templateColumns = []string{"TYPE", "CONTENT", "DESCRIPTION", "PRIORITY", "INDENT", "AUTHOR", "RESPONSIBLE", "DATE", "DATE_LANG", "TIMEZONE"}
+
+// argsOf builds command arguments from plain values, so that templates can be
+// imported with the same code as the commands they correspond to.
+func argsOf(values map[string]interface{}) args {
+	a := args{raw: map[string]json.RawMessage{}}
+	for k, v := range values {
+		a.raw[k], _ = json.Marshal(v)
+	}
+
+	return a
+}
+
+func (s *Server) handleTemplatesImport(w http.ResponseWriter, r *http.Request) {
+	if !s.begin(w, r, "templates/import_into_project") {
+		return
+	}
+	defer s.mu.Unlock()
+
+	p, e := s.activeProject(atoi(r.Form.Get("project_id")))
+	if e != nil {
+		writeError(w, e, 0)
+		return
+	}
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		writeError(w, errInvalidArgument("file"), 0)
+		return
+	}
+	defer file.Close()
+
+	sections, items, e := s.importTemplate(p.ID, file)
+	if e != nil {
+		writeError(w, e, 0)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":        "ok",
+		"template_type": "tasks",
+		"projects":      []interface{}{},
+		"sections":      sections,
+		"tasks":         items,
+		"comments":      []interface{}{},
+		"project_notes": []interface{}{},
+	})
+}
+
+// importTemplate adds the sections and tasks of a CSV template to a project.
+// Notes and meta rows are ignored. s.mu must be held.
+func (s *Server) importTemplate(projectID int, file io.Reader) ([]*section, []*item, *apiError) {
+	cr := csv.NewReader(file)
+	cr.FieldsPerRecord = -1
+
+	records, err := cr.ReadAll()
+	if err != nil || len(records) == 0 {
+		return nil, nil, errInvalidArgument("file")
+	}
+
+	columns := map[string]int{}
+	for i, name := range records[0] {
+		columns[strings.ToUpper(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	if _, ok := columns["TYPE"]; !ok {
+		return nil, nil, errInvalidArgument("file")
+	}
+
+	sections := []*section{}
+	items := []*item{}
+
+	var sectionID int
+	var parents []int
+	for _, record := range records[1:] {
+		col := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		switch strings.ToLower(col("TYPE")) {
+		case "section":
+			id, e := s.sectionAdd(argsOf(map[string]interface{}{
+				"name":          col("CONTENT"),
+				"project_id":    projectID,
+				"section_order": len(sections) + 1,
+			}))
+			if e != nil {
+				return nil, nil, e
+			}
+			sections = append(sections, s.sections[id])
+			sectionID, parents = id, nil
+		case "task":
+			indent := 1
+			if v := col("INDENT"); v != "" {
+				indent = atoi(v)
+			}
+			if indent < 1 || indent > len(parents)+1 {
+				return nil, nil, errInvalidArgument("file")
+			}
+			parents = parents[:indent-1]
+
+			a := map[string]interface{}{
+				"content":     col("CONTENT"),
+				"description": col("DESCRIPTION"),
+				"project_id":  projectID,
+				"priority":    1,
+				"child_order": len(items) + 1,
+			}
+			if p := atoi(col("PRIORITY")); p >= 1 && p <= 4 {
+				a["priority"] = 5 - p
+			}
+			if sectionID != 0 {
+				a["section_id"] = sectionID
+			}
+			if len(parents) > 0 {
+				a["parent_id"] = parents[len(parents)-1]
+			}
+			if date := col("DATE"); date != "" {
+				lang := col("DATE_LANG")
+				if lang == "" {
+					lang = "en"
+				}
+				a["due"] = map[string]interface{}{"string": date, "lang": lang, "is_recurring": false}
+			}
+
+			id, e := s.itemAdd(argsOf(a))
+			if e != nil {
+				return nil, nil, e
+			}
+			items = append(items, s.items[id])
+			parents = append(parents, id)
+		case "note", "meta", "":
+		default:
+			return nil, nil, errInvalidArgument("file")
+		}
+	}
+
+	return sections, items, nil
+}
+
+// exportTemplate writes the uncompleted tasks of a project as a CSV template,
+// with the tasks outside of sections first. s.mu must be held.
+func (s *Server) exportTemplate(projectID int) []byte {
+	children := map[int][]*item{}
+	var roots []*item
+	for _, it := range sortedItems(s.items) {
+		if it.ProjectID != projectID || it.IsDeleted == 1 || it.Checked == 1 {
+			continue
+		}
+		if it.ParentID != nil {
+			children[*it.ParentID] = append(children[*it.ParentID], it)
+		} else {
+			roots = append(roots, it)
+		}
+	}
+	byOrder := func(items []*item) {
+		sort.SliceStable(items, func(i, j int) bool { return items[i].ChildOrder < items[j].ChildOrder })
+	}
+
+	var buf bytes.Buffer
+	cw := csv.NewWriter(&buf)
+	_ = cw.Write(templateColumns)
+
+	var writeItem func(it *item, indent int)
+	writeItem = func(it *item, indent int) {
+		var due struct {
+			Date     string  `json:"date"`
+			String   string  `json:"string"`
+			Lang     string  `json:"lang"`
+			Timezone *string `json:"timezone"`
+		}
+		_ = json.Unmarshal(it.Due, &due)
+
+		date := due.String
+		if date == "" {
+			date = due.Date
+		}
+		timezone := ""
+		if due.Timezone != nil {
+			timezone = *due.Timezone
+		}
+
+		_ = cw.Write([]string{"task", it.Content, it.Description, strconv.Itoa(5 - it.Priority), strconv.Itoa(indent), "", "", date, due.Lang, timezone})
+
+		byOrder(children[it.ID])
+		for _, child := range children[it.ID] {
+			writeItem(child, indent+1)
+		}
+	}
+
+	writeSection := func(sectionID *int) {
+		var items []*item
+		for _, it := range roots {
+			if (it.SectionID == nil && sectionID == nil) || (it.SectionID != nil && sectionID != nil && *it.SectionID == *sectionID) {
+				items = append(items, it)
+			}
+		}
+		byOrder(items)
+		for _, it := range items {
+			writeItem(it, 1)
+		}
+	}
+
+	writeSection(nil)
+
+	var sections []*section
+	for _, sec := range sortedSections(s.sections) {
+		if sec.ProjectID == projectID && !sec.IsDeleted && !sec.IsArchived {
+			sections = append(sections, sec)
+		}
+	}
+	sort.SliceStable(sections, func(i, j int) bool { return sections[i].SectionOrder < sections[j].SectionOrder })
+	for _, sec := range sections {
+		_ = cw.Write([]string{"section", sec.Name, "", "", "", "", "", "", "", ""})
+		writeSection(&sec.ID)
+	}
+
+	cw.Flush()
+
+	return buf.Bytes()
+}
+
+func (s *Server) handleTemplatesExportAsFile(w http.ResponseWriter, r *http.Request) {
+	if !s.begin(w, r, "templates/export_as_file") {
+		return
+	}
+	defer s.mu.Unlock()
+
+	p, e := s.activeProject(atoi(r.Form.Get("project_id")))
+	if e != nil {
+		writeError(w, e, 0)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+p.Name+`.csv"`)
+	_, _ = w.Write(s.exportTemplate(p.ID))
+}
+
+func (s *Server) handleTemplatesExportAsURL(w http.ResponseWriter, r *http.Request) {
+	if !s.begin(w, r, "templates/export_as_url") {
+		return
+	}
+	defer s.mu.Unlock()
+
+	p, e := s.activeProject(atoi(r.Form.Get("project_id")))
+	if e != nil {
+		writeError(w, e, 0)
+		return
+	}
+
+	name := p.Name + ".csv"
+	s.files[name] = s.exportTemplate(p.ID)
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"file_name": name,
+		"file_url":  s.URL + filesPath + url.PathEscape(name),
+	})
+}
+
+// handleFiles serves exported and uploaded files. Like the files Todoist
+// hosts, they can be downloaded without a token.
+func (s *Server) handleFiles(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	name := strings.TrimPrefix(r.URL.Path, filesPath)
+	file, ok := s.files[name]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	contentType := "text/csv; charset=utf-8"
+	for _, u := range s.uploads {
+		if u.path == name {
+			contentType = u.FileType
+		}
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	_, _ = w.Write(file)
+}
@@ -0,0 +1,114 @@
+package todoisttest
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// upload is a file uploaded with /uploads/add, served under filesPath.
+type upload struct {
+	FileName     string `json:"file_name"`
+	FileSize     int    `json:"file_size"`
+	FileType     string `json:"file_type"`
+	FileURL      string `json:"file_url"`
+	ResourceType string `json:"resource_type"`
+	UploadState  string `json:"upload_state"`
+
+	path string // the key of the file in s.files
+}
+
+func (s *Server) handleUploadsAdd(w http.ResponseWriter, r *http.Request) {
+	if !s.begin(w, r, "uploads/add") {
+		return
+	}
+	defer s.mu.Unlock()
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		writeError(w, errInvalidArgument("file"), 0)
+		return
+	}
+	defer file.Close()
+
+	data, err := ioutil.ReadAll(file)
+	if err != nil {
+		writeError(w, errInvalidArgument("file"), 0)
+		return
+	}
+
+	name := r.Form.Get("file_name")
+	if name == "" {
+		name = header.Filename
+	}
+	fileType := header.Header.Get("Content-Type")
+	if fileType == "" {
+		fileType = "application/octet-stream"
+	}
+	resourceType := "file"
+	for _, prefix := range []string{"image", "video", "audio"} {
+		if strings.HasPrefix(fileType, prefix+"/") {
+			resourceType = prefix
+		}
+	}
+
+	path := "uploads/" + strconv.Itoa(s.newID()) + "/" + name
+	s.files[path] = data
+
+	u := &upload{
+		FileName:     name,
+		FileSize:     len(data),
+		FileType:     fileType,
+		FileURL:      s.URL + filesPath + (&url.URL{Path: path}).EscapedPath(),
+		ResourceType: resourceType,
+		UploadState:  "completed",
+		path:         path,
+	}
+	s.uploads = append(s.uploads, u)
+
+	writeJSON(w, http.StatusOK, u)
+}
+
+// handleUploadsGet lists the uploaded files, newest first.
+func (s *Server) handleUploadsGet(w http.ResponseWriter, r *http.Request) {
+	if !s.begin(w, r, "uploads/get") {
+		return
+	}
+	defer s.mu.Unlock()
+
+	limit := 30
+	if n := atoi(r.Form.Get("limit")); n > 0 {
+		limit = n
+	}
+	if limit > 50 {
+		limit = 50
+	}
+
+	uploads := []*upload{}
+	for i := len(s.uploads) - 1; i >= 0 && len(uploads) < limit; i-- {
+		uploads = append(uploads, s.uploads[i])
+	}
+
+	writeJSON(w, http.StatusOK, uploads)
+}
+
+func (s *Server) handleUploadsDelete(w http.ResponseWriter, r *http.Request) {
+	if !s.begin(w, r, "uploads/delete") {
+		return
+	}
+	defer s.mu.Unlock()
+
+	fileURL := r.Form.Get("file_url")
+	for i, u := range s.uploads {
+		if u.FileURL == fileURL {
+			delete(s.files, u.path)
+			s.uploads = append(s.uploads[:i], s.uploads[i+1:]...)
+			writeJSON(w, http.StatusOK, "ok")
+			return
+		}
+	}
+
+	writeError(w, errNotFound("file"), 0)
+}
@@ -0,0 +1,169 @@
+package todoist
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	todoTxtPriorityPattern = regexp.MustCompile(`^\(([A-D])\)$`)
+	todoTxtDatePattern     = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+)
+
+// ReadTodoTxt reads a checklist from todo.txt lines:
+//
+//	(A) Pay rent +Home @bills due:2022-04-01
+//	x Mow the lawn +Home section:Garden
+//
+// Priorities (A) to (D) are p1 to p4, a leading "x" marks a completed task
+// (whose priority may be kept as a pri: tag), and completion and creation
+// dates are skipped. The first +project tag is the task's project, and tasks
+// without one belong to the Inbox; @context tags are labels; due: and section:
+// tags set the due date and the section. Underscores in project and section
+// names stand for spaces. Other words, including other key:value tags, are
+// kept in the content.
+//
+// todo.txt has no sub-tasks, so every task is read as a top-level task.
+func ReadTodoTxt(r io.Reader) (*Checklist, error) {
+	c := &Checklist{}
+
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		words := strings.Fields(scanner.Text())
+		if len(words) == 0 {
+			continue
+		}
+
+		task := &ChecklistTask{}
+		if words[0] == "x" {
+			task.Checked = true
+			words = words[1:]
+		}
+		if len(words) > 0 {
+			if m := todoTxtPriorityPattern.FindStringSubmatch(words[0]); m != nil {
+				task.Priority = int(m[1][0]-'A') + 1
+				words = words[1:]
+			}
+		}
+		for len(words) > 0 && todoTxtDatePattern.MatchString(words[0]) {
+			words = words[1:]
+		}
+
+		var projectName, sectionName string
+		var content []string
+		for _, word := range words {
+			key, value := "", ""
+			if i := strings.IndexByte(word, ':'); i > 0 {
+				key, value = word[:i], word[i+1:]
+			}
+
+			switch {
+			case len(word) > 1 && word[0] == '+':
+				if projectName == "" {
+					projectName = todoTxtName(word[1:])
+				}
+			case len(word) > 1 && word[0] == '@':
+				task.Labels = append(task.Labels, word[1:])
+			case key == "due" && value != "":
+				task.DueDate = value
+			case key == "section" && value != "":
+				sectionName = todoTxtName(value)
+			case key == "pri" && len(value) == 1 && value[0] >= 'A' && value[0] <= 'D':
+				task.Priority = int(value[0]-'A') + 1
+			default:
+				content = append(content, word)
+			}
+		}
+
+		task.Content = strings.Join(content, " ")
+		if task.Content == "" {
+			return nil, errors.Errorf("line %d: task without content", n)
+		}
+
+		project := c.project(projectName)
+		if sectionName != "" {
+			section := project.section(sectionName)
+			section.Tasks = append(section.Tasks, task)
+		} else {
+			project.Tasks = append(project.Tasks, task)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "unable to read todo.txt")
+	}
+
+	return c, nil
+}
+
+// todoTxtName decodes a project or section name from a todo.txt tag.
+func todoTxtName(tag string) string {
+	return strings.ReplaceAll(tag, "_", " ")
+}
+
+// todoTxtTag encodes a project or section name as a todo.txt tag.
+func todoTxtTag(name string) string {
+	return strings.Join(strings.Fields(name), "_")
+}
+
+// WriteTodoTxt writes the checklist as todo.txt lines, in the format read by
+// ReadTodoTxt. Sub-tasks are written as lines of their own after their
+// parent, as todo.txt has no sub-tasks. Only due dates are written, as due:
+// tags, so recurring due strings are lost; descriptions are left out, as is
+// the p4 priority, which is the default.
+func (c *Checklist) WriteTodoTxt(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	var writeTasks func(tasks []*ChecklistTask, project, section string)
+	writeTasks = func(tasks []*ChecklistTask, project, section string) {
+		for _, task := range tasks {
+			// Completed tasks keep their priority as a pri: tag, as todo.txt
+			// only allows priorities at the start of incomplete tasks.
+			var words []string
+			var priority rune
+			if task.Priority >= 1 && task.Priority <= 3 {
+				priority = 'A' + rune(task.Priority-1)
+			}
+			switch {
+			case task.Checked:
+				words = append(words, "x")
+			case priority != 0:
+				words = append(words, fmt.Sprintf("(%c)", priority))
+			}
+			words = append(words, task.Content)
+			if project != "" {
+				words = append(words, "+"+todoTxtTag(project))
+			}
+			for _, label := range task.Labels {
+				words = append(words, "@"+label)
+			}
+			if section != "" {
+				words = append(words, "section:"+todoTxtTag(section))
+			}
+			if task.DueDate != "" {
+				words = append(words, "due:"+task.DueDate)
+			} else if todoTxtDatePattern.MatchString(task.Due) {
+				words = append(words, "due:"+task.Due)
+			}
+			if task.Checked && priority != 0 {
+				words = append(words, fmt.Sprintf("pri:%c", priority))
+			}
+			fmt.Fprintln(bw, strings.Join(words, " "))
+
+			writeTasks(task.Children, project, section)
+		}
+	}
+
+	for _, p := range c.Projects {
+		writeTasks(p.Tasks, p.Name, "")
+		for _, s := range p.Sections {
+			writeTasks(s.Tasks, p.Name, s.Name)
+		}
+	}
+
+	return bw.Flush()
+}
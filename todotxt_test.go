@@ -0,0 +1,89 @@
+package todoist
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_ReadTodoTxt(t *testing.T) {
+	in := `(A) Pay rent +Home @bills @monthly due:2022-04-01
+x 2022-03-10 2022-03-01 Mow the lawn +Home section:Front_garden pri:B
+Call mum see https://example.com/call
+
+(D) 2022-03-01 Review PR +Work +Home @work
+x Plant tomatoes section:Front_garden +Home
+`
+
+	got, err := ReadTodoTxt(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &Checklist{Projects: []*ChecklistProject{
+		{Name: "Home", Tasks: []*ChecklistTask{
+			{Content: "Pay rent", Priority: 1, Labels: []string{"bills", "monthly"}, DueDate: "2022-04-01"},
+		}, Sections: []*ChecklistSection{
+			{Name: "Front garden", Tasks: []*ChecklistTask{
+				{Content: "Mow the lawn", Checked: true, Priority: 2},
+				{Content: "Plant tomatoes", Checked: true},
+			}},
+		}},
+		{Name: "", Tasks: []*ChecklistTask{
+			{Content: "Call mum see https://example.com/call"},
+		}},
+		{Name: "Work", Tasks: []*ChecklistTask{
+			{Content: "Review PR", Priority: 4, Labels: []string{"work"}},
+		}},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected\n%s\nreceived\n%s", dumpChecklist(want), dumpChecklist(got))
+	}
+
+	if _, err = ReadTodoTxt(strings.NewReader("(A) +Home @bills\n")); err == nil {
+		t.Error("expected an error for a task without content")
+	}
+}
+
+func Test_Checklist_WriteTodoTxt(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testChecklist().WriteTodoTxt(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `(B) Call mum
+Plain list item
+(A) Pay rent +Home @bills @monthly
+x Find the IBAN +Home
+Ask the landlord +Home @waiting
+Water plants +Home
+x Mow the lawn +Home section:Garden due:2022-03-12
+`
+	if got := buf.String(); got != want {
+		t.Errorf("expected\n%s\nreceived\n%s", want, got)
+	}
+
+	// Completed tasks keep their priority as a tag, and project and section
+	// names with spaces are written with underscores.
+	c := &Checklist{Projects: []*ChecklistProject{{Name: "Home Office", Sections: []*ChecklistSection{{Name: "Desk setup", Tasks: []*ChecklistTask{
+		{Content: "Buy a lamp", Checked: true, Priority: 3, DueDate: "2022-03-14", Due: "next monday"},
+	}}}}}}
+
+	buf.Reset()
+	if err := c.WriteTodoTxt(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := buf.String(), "x Buy a lamp +Home_Office section:Desk_setup due:2022-03-14 pri:C\n"; got != want {
+		t.Errorf("expected %q, received %q", want, got)
+	}
+
+	again, err := ReadTodoTxt(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	task := again.Projects[0].Sections[0].Tasks[0]
+	if again.Projects[0].Name != "Home Office" || again.Projects[0].Sections[0].Name != "Desk setup" || !task.Checked || task.Priority != 3 || task.DueDate != "2022-03-14" {
+		t.Errorf("unexpected checklist read back\n%s", dumpChecklist(again))
+	}
+}
@@ -0,0 +1,141 @@
+package todoist
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// UploadsService handles the /uploads endpoints of the Sync API, which host
+// the files attached to comments.
+//
+// Todoist API docs: https://developer.todoist.com/sync/v8/#uploads
+type UploadsService service
+
+// Add uploads a file, streaming it from file, and returns its metadata. The
+// file can then be attached to a comment with AddNote.
+func (s *UploadsService) Add(ctx context.Context, fileName, contentType string, file io.Reader) (FileAttachment, error) {
+	s.client.Logln("---------- Uploads.Add")
+
+	if fileName == "" {
+		return FileAttachment{}, errors.New("file name cannot be empty")
+	}
+
+	form := url.Values{}
+	form.Set("file_name", fileName)
+
+	req, err := s.client.newUploadRequest("uploads/add", form, fileName, contentType, file)
+	if err != nil {
+		return FileAttachment{}, err
+	}
+
+	var attachment FileAttachment
+	if _, err = s.client.Do(ctx, req, &attachment); err != nil {
+		return FileAttachment{}, err
+	}
+
+	return attachment, nil
+}
+
+// List the files uploaded by the user, newest first. limit is the maximum
+// number of files to return (at most 50); if 0, the API's default of 30 is
+// used.
+func (s *UploadsService) List(ctx context.Context, limit int) ([]FileAttachment, error) {
+	s.client.Logln("---------- Uploads.List")
+
+	form := url.Values{}
+	if limit > 0 {
+		form.Set("limit", strconv.Itoa(limit))
+	}
+
+	req, err := s.client.newEndpointRequest("uploads/get", form)
+	if err != nil {
+		return nil, err
+	}
+
+	var attachments []FileAttachment
+	if _, err = s.client.Do(ctx, req, &attachments); err != nil {
+		return nil, err
+	}
+
+	return attachments, nil
+}
+
+// Delete an uploaded file, by its URL.
+func (s *UploadsService) Delete(ctx context.Context, fileURL string) error {
+	s.client.Logln("---------- Uploads.Delete")
+
+	form := url.Values{}
+	form.Set("file_url", fileURL)
+
+	req, err := s.client.newEndpointRequest("uploads/delete", form)
+	if err != nil {
+		return err
+	}
+
+	var status string
+	_, err = s.client.Do(ctx, req, &status)
+
+	return err
+}
+
+// AddNote adds a comment to a task, with an optional file attachment.
+type AddNote struct {
+	// The task to comment on (could be a temp id).
+	TaskID TaskID `json:"item_id"`
+
+	// The content of the comment. This value may contain markdown-formatted text and hyperlinks.
+	Content string `json:"content"`
+
+	// A file returned by UploadsService.Add, to attach to the comment.
+	FileAttachment *FileAttachment `json:"file_attachment,omitempty"`
+
+	TempID string `json:"-"`
+}
+
+// Commands returns the note_add command adding the comment, so that it can be
+// sent along with other commands, such as the item_add of the task.
+func (n AddNote) Commands() []Command {
+	tempID := n.TempID
+	if tempID == "" {
+		tempID = uuid.New().String()
+	}
+
+	return []Command{{Type: "note_add", Args: n, UUID: uuid.New().String(), TempID: tempID}}
+}
+
+// AddToTask uploads a file and adds a comment with the file attached to a
+// task, returning the comment.
+func (s *UploadsService) AddToTask(ctx context.Context, syncToken string, taskID TaskID, content, fileName, contentType string, file io.Reader) (Comment, CommandResponse, error) {
+	attachment, err := s.Add(ctx, fileName, contentType, file)
+	if err != nil {
+		return Comment{}, CommandResponse{}, err
+	}
+
+	s.client.Logln("---------- Uploads.AddToTask")
+
+	commands := AddNote{TaskID: taskID, Content: content, FileAttachment: &attachment}.Commands()
+
+	req, err := s.client.NewRequest(syncToken, []string{"notes"}, commands)
+	if err != nil {
+		return Comment{}, CommandResponse{}, err
+	}
+
+	var commandResponse CommandResponse
+	if _, err = s.client.Do(ctx, req, &commandResponse); err != nil {
+		return Comment{}, commandResponse, err
+	}
+
+	id := s.client.ResolveID(NewTempID(commands[0].TempID))
+	for _, note := range commandResponse.Notes {
+		if note.ID.ID == id {
+			return note, commandResponse, nil
+		}
+	}
+
+	return Comment{ID: CommentID{id}, TaskID: &taskID, Content: content, Attachment: &attachment}, commandResponse, nil
+}
@@ -0,0 +1,142 @@
+package todoist
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ides15/todoist/todoisttest"
+)
+
+func Test_Uploads(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newFakeClient(t)
+
+	attachment, err := client.Uploads.Add(ctx, "receipt \"march\".pdf", "application/pdf", strings.NewReader("%PDF-1.4"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attachment.FileName != `receipt "march".pdf` || attachment.FileType != "application/pdf" || attachment.FileSize != 8 || attachment.UploadState != "completed" {
+		t.Errorf("unexpected attachment %+v", attachment)
+	}
+
+	resp, err := http.Get(attachment.FileURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(b) != "%PDF-1.4" || resp.Header.Get("Content-Type") != "application/pdf" {
+		t.Errorf("unexpected file %q of type %s", b, resp.Header.Get("Content-Type"))
+	}
+
+	// Without a MIME type, files are uploaded as binary data.
+	other, err := client.Uploads.Add(ctx, "notes.bin", "", strings.NewReader("data"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other.FileType != "application/octet-stream" {
+		t.Errorf("expected application/octet-stream, received %s", other.FileType)
+	}
+
+	uploads, err := client.Uploads.List(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(uploads) != 1 || uploads[0].FileURL != other.FileURL {
+		t.Errorf("expected the newest upload, received %+v", uploads)
+	}
+
+	if err = client.Uploads.Delete(ctx, other.FileURL); err != nil {
+		t.Fatal(err)
+	}
+	if err = client.Uploads.Delete(ctx, other.FileURL); err == nil {
+		t.Error("expected an error deleting a file twice")
+	}
+
+	// A file can be attached to a task created in the same request.
+	commands := []Command{{Type: "item_add", Args: AddTask{Content: "File taxes"}, UUID: "add-uuid", TempID: "taxes"}}
+	commands = append(commands, AddNote{TaskID: TaskID{NewTempID("taxes")}, Content: "Receipt", FileAttachment: &attachment}.Commands()...)
+	req, err := client.NewRequest("", []string{"notes"}, commands)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var commandResponse CommandResponse
+	if _, err = client.Do(ctx, req, &commandResponse); err != nil {
+		t.Fatal(err)
+	}
+	if len(commandResponse.Notes) != 1 || commandResponse.Notes[0].Attachment == nil || commandResponse.Notes[0].Attachment.FileURL != attachment.FileURL {
+		t.Fatalf("expected a note with the attachment, received %+v", commandResponse.Notes)
+	}
+
+	taskID := TaskID{client.ResolveID(NewTempID("taxes"))}
+	note, _, err := client.Uploads.AddToTask(ctx, "", taskID, "Scan", "scan.png", "image/png", strings.NewReader("png"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if note.ID.IsTemp() || note.TaskID == nil || *note.TaskID != taskID || note.Content != "Scan" ||
+		note.Attachment == nil || note.Attachment.FileName != "scan.png" || note.Attachment.ResourceType != "image" {
+		t.Errorf("unexpected note %+v", note)
+	}
+}
+
+type errReader struct{}
+
+func (errReader) Read([]byte) (int, error) {
+	return 0, errors.New("disk on fire")
+}
+
+func Test_Uploads_ReadError(t *testing.T) {
+	client, _ := newFakeClient(t)
+
+	_, err := client.Uploads.Add(context.Background(), "broken.txt", "text/plain", io.MultiReader(strings.NewReader("half"), errReader{}))
+	if err == nil || !strings.Contains(err.Error(), "disk on fire") {
+		t.Errorf("expected the read error, received %v", err)
+	}
+}
+
+func Test_Uploads_NotRetried(t *testing.T) {
+	client, srv := newFakeClient(t)
+	client.SetRetryPolicy(RetryPolicy{MaxRetries: 2, MaxWait: time.Millisecond})
+
+	srv.InjectFault(todoisttest.Fault{Path: "uploads/add", StatusCode: http.StatusServiceUnavailable, Times: 1})
+
+	var unavailable ServiceUnavailableError
+	_, err := client.Uploads.Add(context.Background(), "notes.txt", "text/plain", strings.NewReader("data"))
+	if !errors.As(err, &unavailable) {
+		t.Errorf("expected the upload not to be retried, received %v", err)
+	}
+}
+
+func Test_Uploads_Unsent(t *testing.T) {
+	client, _ := newFakeClient(t)
+
+	file := &countingReader{r: strings.NewReader("data")}
+	req, err := client.newUploadRequest("uploads/add", url.Values{}, "notes.txt", "", file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = req.Body.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if file.reads != 0 {
+		t.Errorf("expected an unsent upload not to read the file, received %d reads", file.reads)
+	}
+}
+
+type countingReader struct {
+	r     io.Reader
+	reads int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	c.reads++
+	return c.r.Read(p)
+}
@@ -0,0 +1,104 @@
+package todoist
+
+import (
+	"time"
+)
+
+// TZInfo is the timezone of a user.
+type TZInfo struct {
+	// The name of the timezone, such as "Europe/Lisbon".
+	Timezone string `json:"timezone"`
+
+	// The offset from GMT, such as "+01:00".
+	GMTString string `json:"gmt_string"`
+
+	// The hours and minutes of the offset from GMT.
+	Hours   int `json:"hours"`
+	Minutes int `json:"minutes"`
+
+	// Whether daylight saving time is in effect.
+	IsDST Bool `json:"is_dst"`
+}
+
+// User is the Todoist user, returned by a sync of the "user" resource type.
+//
+// Todoist API docs: https://developer.todoist.com/sync/v8/#user
+type User struct {
+	// The user's ID.
+	ID UserID `json:"id"`
+
+	// The user's email.
+	Email string `json:"email"`
+
+	// The user's real name formatted as Firstname Lastname.
+	FullName string `json:"full_name"`
+
+	// The ID of the user's Inbox project.
+	InboxProject *ProjectID `json:"inbox_project,omitempty"`
+
+	// The user's language, which can take one of the following values: da, de, en, es, fi, fr, it, ja, ko, nl, pl, pt_BR, ru, sv, tr, zh_CN, zh_TW.
+	Lang string `json:"lang"`
+
+	// The first day of the week (between 1 and 7, where 1 is Monday and 7 is Sunday).
+	StartDay int `json:"start_day"`
+
+	// The day of the next week, that tasks will be postponed to (between 1 and 7, where 1 is Monday and 7 is Sunday).
+	NextWeek int `json:"next_week"`
+
+	// Whether to use the DD-MM-YYYY date format (if set to 0), or the MM-DD-YYYY format (if set to 1).
+	DateFormat int `json:"date_format"`
+
+	// Whether to use a 24h format such as 13:00 (if set to 0) when displaying time, or a 12h format such as 1:00pm (if set to 1).
+	TimeFormat int `json:"time_format"`
+
+	// The user's timezone.
+	TZInfo TZInfo `json:"tz_info"`
+
+	// Whether the user has a Todoist Premium subscription.
+	IsPremium Bool `json:"is_premium"`
+}
+
+// Location returns the user's timezone. If the timezone is unknown to the
+// system, a fixed zone with the user's offset from GMT is returned.
+func (u User) Location() *time.Location {
+	if u.TZInfo.Timezone != "" {
+		if loc, err := time.LoadLocation(u.TZInfo.Timezone); err == nil {
+			return loc
+		}
+	}
+
+	offset := (u.TZInfo.Hours*60 + u.TZInfo.Minutes) * 60
+	if u.TZInfo.Hours < 0 {
+		offset = (u.TZInfo.Hours*60 - u.TZInfo.Minutes) * 60
+	}
+	if offset == 0 && u.TZInfo.Timezone == "" {
+		return time.UTC
+	}
+
+	name := u.TZInfo.Timezone
+	if name == "" {
+		name = "GMT " + u.TZInfo.GMTString
+	}
+
+	return time.FixedZone(name, offset)
+}
+
+// DueSettings returns the settings to parse due strings for the user with.
+func (u User) DueSettings() DueSettings {
+	return DueSettings{
+		Location: u.Location(),
+		StartDay: isoWeekday(u.StartDay, time.Monday),
+		NextWeek: isoWeekday(u.NextWeek, isoWeekday(u.StartDay, time.Monday)),
+		DayFirst: u.DateFormat == 0,
+	}
+}
+
+// isoWeekday converts a day between 1 (Monday) and 7 (Sunday) to a weekday,
+// or returns def for days out of range.
+func isoWeekday(day int, def time.Weekday) time.Weekday {
+	if day < 1 || day > 7 {
+		return def
+	}
+
+	return time.Weekday(day % 7)
+}
@@ -0,0 +1,176 @@
+package todoist
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// APIVersion is a version of the Todoist Sync API.
+type APIVersion string
+
+const (
+	// APIVersionV8 is the v8 Sync API, which uses numeric IDs and integer flags.
+	APIVersionV8 APIVersion = "v8"
+
+	// APIVersionV9 is the v9 Sync API, which uses string IDs, boolean flags,
+	// color names and renamed timestamp fields (added_at, completed_at, ...).
+	APIVersionV9 APIVersion = "v9"
+)
+
+// ClientOption configures a Client in NewClient.
+type ClientOption func(*Client) error
+
+// WithAPIVersion selects the version of the Sync API the client talks to.
+// Defaults to APIVersionV8.
+//
+// The resource structs (Project, Section, Task) are version-neutral: they
+// decode the responses of either version, so code reading them does not need
+// to change when switching versions. Command arguments are translated to the
+// selected version when a request is built.
+func WithAPIVersion(version APIVersion) ClientOption {
+	return func(c *Client) error {
+		switch version {
+		case APIVersionV8, APIVersionV9:
+			c.version = version
+			return nil
+		default:
+			return errors.Errorf("unsupported API version %q", version)
+		}
+	}
+}
+
+// APIVersion returns the version of the Sync API the client talks to.
+func (c *Client) APIVersion() APIVersion {
+	return c.version
+}
+
+// Bool is a boolean that decodes from both the 0/1 integer flags used by the
+// v8 Sync API and the JSON booleans used by v9.
+type Bool bool
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (b *Bool) UnmarshalJSON(data []byte) error {
+	switch string(data) {
+	case "true", "1":
+		*b = true
+	case "false", "0", "null":
+		*b = false
+	default:
+		return errors.Errorf("unable to unmarshal %s into a Bool", data)
+	}
+
+	return nil
+}
+
+// Int returns the v8 integer flag of b: 1 for true and 0 for false.
+func (b Bool) Int() int {
+	if b {
+		return 1
+	}
+
+	return 0
+}
+
+// colorNames maps the numeric color IDs used by the v8 API to the color names
+// used by v9.
+//
+// Todoist API docs: https://developer.todoist.com/guides/#colors
+var colorNames = map[int]Color{
+	30: "berry_red",
+	31: "red",
+	32: "orange",
+	33: "yellow",
+	34: "olive_green",
+	35: "lime_green",
+	36: "green",
+	37: "mint_green",
+	38: "teal",
+	39: "sky_blue",
+	40: "light_blue",
+	41: "blue",
+	42: "grape",
+	43: "violet",
+	44: "lavender",
+	45: "magenta",
+	46: "salmon",
+	47: "charcoal",
+	48: "grey",
+	49: "taupe",
+}
+
+// Color is the name of a Todoist color, such as "berry_red". It decodes from
+// both the numeric color IDs of the v8 API and the color names of v9.
+type Color string
+
+// ColorFromID returns the color with the given v8 numeric ID, or an empty
+// Color if the ID is unknown.
+func ColorFromID(id int) Color {
+	return colorNames[id]
+}
+
+// ID returns the v8 numeric ID of the color, or 0 if the color is unknown.
+func (c Color) ID() int {
+	for id, name := range colorNames {
+		if name == c {
+			return id
+		}
+	}
+
+	return 0
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (c *Color) UnmarshalJSON(data []byte) error {
+	var id int
+	if err := json.Unmarshal(data, &id); err == nil {
+		*c = ColorFromID(id)
+		return nil
+	}
+
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return errors.Wrap(err, "unable to unmarshal color")
+	}
+	*c = Color(name)
+
+	return nil
+}
+
+// v9FlagArgs are the command arguments that are 0/1 integers in v8 and
+// booleans in v9.
+var v9FlagArgs = []string{"collapsed", "is_favorite"}
+
+// argsForVersion translates command arguments built for the v8 API (as the
+// command structs in this package are) into the format expected by the
+// client's API version.
+func (c *Client) argsForVersion(args interface{}) (interface{}, error) {
+	if c.version != APIVersionV9 || args == nil {
+		return args, nil
+	}
+
+	b, err := json.Marshal(args)
+	if err != nil {
+		return nil, err
+	}
+
+	var m map[string]interface{}
+	if err = json.Unmarshal(b, &m); err != nil {
+		// Not an object, so there is nothing to translate.
+		return args, nil
+	}
+
+	for _, name := range v9FlagArgs {
+		if n, ok := m[name].(float64); ok {
+			m[name] = n != 0
+		}
+	}
+
+	if n, ok := m["color"].(float64); ok {
+		if color := ColorFromID(int(n)); color != "" {
+			m["color"] = color
+		}
+	}
+
+	return m, nil
+}
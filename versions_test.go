@@ -0,0 +1,174 @@
+package todoist
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func Test_Bool_JSON(t *testing.T) {
+	tests := []struct {
+		json string
+		want Bool
+	}{
+		{json: `1`, want: true},
+		{json: `0`, want: false},
+		{json: `true`, want: true},
+		{json: `false`, want: false},
+		{json: `null`, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.json, func(t *testing.T) {
+			var b Bool
+			if err := json.Unmarshal([]byte(tt.json), &b); err != nil {
+				t.Fatal(err)
+			}
+			if b != tt.want {
+				t.Errorf("expected %v, received %v", tt.want, b)
+			}
+		})
+	}
+
+	var b Bool
+	if err := json.Unmarshal([]byte(`"yes"`), &b); err == nil {
+		t.Error("expected an error for a string flag")
+	}
+}
+
+func Test_Color_JSON(t *testing.T) {
+	var c Color
+	if err := json.Unmarshal([]byte(`30`), &c); err != nil {
+		t.Fatal(err)
+	}
+	if c != "berry_red" {
+		t.Errorf("expected berry_red, received %q", c)
+	}
+
+	if err := json.Unmarshal([]byte(`"sky_blue"`), &c); err != nil {
+		t.Fatal(err)
+	}
+	if c != "sky_blue" || c.ID() != 39 {
+		t.Errorf("expected sky_blue (39), received %q (%d)", c, c.ID())
+	}
+}
+
+func Test_WithAPIVersion(t *testing.T) {
+	client, err := NewClient("token", WithAPIVersion(APIVersionV9))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client.APIVersion() != APIVersionV9 {
+		t.Errorf("expected %s, received %s", APIVersionV9, client.APIVersion())
+	}
+	if want := "https://api.todoist.com/sync/v9/sync"; client.BaseURL.String() != want {
+		t.Errorf("expected base URL %s, received %s", want, client.BaseURL)
+	}
+
+	if _, err = NewClient("token", WithAPIVersion("v7")); err == nil {
+		t.Error("expected an error for an unsupported version")
+	}
+}
+
+func Test_V9_Sync(t *testing.T) {
+	var commands []map[string]interface{}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/sync/v9/sync" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if err := json.Unmarshal([]byte(r.FormValue("commands")), &commands); err != nil {
+			t.Error(err)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"full_sync": true,
+			"sync_token": "v9-token",
+			"sync_status": {"1": "ok"},
+			"temp_id_mapping": {"p": "6Jf8VQXxpwv56VQ7"},
+			"projects": [{
+				"id": "6Jf8VQXxpwv56VQ7",
+				"name": "Project",
+				"color": "berry_red",
+				"parent_id": null,
+				"collapsed": true,
+				"is_deleted": false,
+				"is_archived": false,
+				"is_favorite": true,
+				"sync_id": null,
+				"view_style": "board"
+			}],
+			"sections": [{
+				"id": "6X7rM8997g3RQmvh",
+				"name": "Section",
+				"project_id": "6Jf8VQXxpwv56VQ7",
+				"added_at": "2022-01-02T03:04:05Z",
+				"archived_at": null
+			}],
+			"items": [{
+				"id": "6X7rfFVPjhvv84XG",
+				"project_id": "6Jf8VQXxpwv56VQ7",
+				"content": "Task",
+				"labels": ["errands"],
+				"checked": true,
+				"collapsed": false,
+				"is_deleted": false,
+				"added_at": "2022-01-02T03:04:05Z",
+				"completed_at": "2022-01-03T03:04:05Z"
+			}]
+		}`))
+	}))
+	defer srv.Close()
+
+	client, err := NewClient("token", WithAPIVersion(APIVersionV9))
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.BaseURL, _ = url.Parse(srv.URL + "/sync/v9/sync")
+
+	req, err := client.NewRequest("", []string{"projects", "sections", "items"}, []Command{
+		{Type: "project_add", Args: AddProject{Name: "Project", Color: 30, IsFavorite: 1}, UUID: "1", TempID: "p"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var resp CommandResponse
+	if _, err = client.Do(context.Background(), req, &resp); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(commands) != 1 {
+		t.Fatalf("expected 1 command, received %v", commands)
+	}
+	args := commands[0]["args"].(map[string]interface{})
+	if args["color"] != "berry_red" || args["is_favorite"] != true {
+		t.Errorf("expected v9 color and flag args, received %v", args)
+	}
+
+	if resp.TempIDMapping["p"] != NewID("6Jf8VQXxpwv56VQ7") {
+		t.Errorf("unexpected temp id mapping %v", resp.TempIDMapping)
+	}
+
+	p := resp.Projects[0]
+	if p.Color != "berry_red" || !p.Collapsed || !p.IsFavorite || p.ViewStyle != "board" {
+		t.Errorf("unexpected project %+v", p)
+	}
+
+	s := resp.Sections[0]
+	if s.ProjectID != p.ID || s.DateAdded != "2022-01-02T03:04:05Z" || s.DateArchived != nil {
+		t.Errorf("unexpected section %+v", s)
+	}
+
+	task := resp.Tasks[0]
+	if !task.Checked || task.DateAdded != "2022-01-02T03:04:05Z" || task.DateCompleted == nil || *task.DateCompleted != "2022-01-03T03:04:05Z" {
+		t.Errorf("unexpected task %+v", task)
+	}
+	if len(task.Labels) != 1 || task.Labels[0].String() != "errands" {
+		t.Errorf("expected label names, received %v", task.Labels)
+	}
+}
@@ -0,0 +1,240 @@
+package todoist
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultWatchMinInterval = 5 * time.Second
+	defaultWatchMaxInterval = 2 * time.Minute
+)
+
+// WatchEventType is the kind of change reported by a WatchEvent.
+type WatchEventType string
+
+const (
+	// WatchCreated reports a resource that did not exist in the previous sync.
+	WatchCreated WatchEventType = "created"
+
+	// WatchUpdated reports a resource that differs from the previous sync.
+	WatchUpdated WatchEventType = "updated"
+
+	// WatchDeleted reports a resource that has been marked as deleted.
+	WatchDeleted WatchEventType = "deleted"
+
+	// WatchCompleted reports a task that has been marked as completed.
+	WatchCompleted WatchEventType = "completed"
+
+	// WatchError reports a failed sync. Watching continues after errors, with
+	// a longer interval.
+	WatchError WatchEventType = "error"
+)
+
+// WatchEvent is a change observed by Client.Watch. Depending on the resource
+// that changed, one of Project, Section or Task is set.
+type WatchEvent struct {
+	Type WatchEventType
+
+	Project *Project
+	Section *Section
+	Task    *Task
+
+	// The error for WatchError events.
+	Err error
+
+	// The sync token after the sync the change was observed in. Storing it and
+	// passing it to WatchSyncToken resumes watching from that point.
+	SyncToken string
+}
+
+// WatchOption configures Client.Watch.
+type WatchOption func(*watcher)
+
+// WatchInterval sets the bounds of the polling interval. Polling starts at
+// minInterval, doubles up to maxInterval while nothing changes, and drops back
+// to minInterval as soon as something does. Defaults to 5 seconds and 2 minutes.
+func WatchInterval(minInterval, maxInterval time.Duration) WatchOption {
+	return func(w *watcher) {
+		w.minInterval, w.maxInterval = minInterval, maxInterval
+	}
+}
+
+// WatchSyncToken resumes watching from a sync token stored from an earlier
+// WatchEvent. Every resource changed since then is reported; without a token,
+// Watch starts with a full sync and only reports changes made after it returns.
+//
+// A resumed Watch has not seen the earlier versions of the resources, so the
+// ones changed since the token are reported as created, even if they existed
+// before. Deletions and completions are reported as such.
+func WatchSyncToken(syncToken string) WatchOption {
+	return func(w *watcher) {
+		w.syncToken = syncToken
+	}
+}
+
+// watchableResourceTypes are the resource types Watch can report changes for.
+var watchableResourceTypes = map[string]bool{"projects": true, "sections": true, "items": true}
+
+// Watch polls the Sync API for changes to the given resource types
+// ("projects", "sections", "items" or "all") and sends them on the returned
+// channel, until ctx is done, after which the channel is closed.
+//
+// Changes are found by diffing successive sync responses. The polling interval
+// adapts to activity (see WatchInterval), and grows when the API responds with
+// a rate limit error, waiting at least as long as the API asks to.
+//
+// Watch is an alternative to webhooks for applications without a public
+// endpoint.
+func (c *Client) Watch(ctx context.Context, resourceTypes []string, opts ...WatchOption) (<-chan WatchEvent, error) {
+	if ctx == nil {
+		return nil, errors.New("context must not be nil")
+	}
+
+	if len(resourceTypes) == 0 {
+		resourceTypes = []string{"all"}
+	}
+	for _, rt := range resourceTypes {
+		if rt != "all" && !watchableResourceTypes[rt] {
+			return nil, errors.Errorf("unable to watch resource type %q", rt)
+		}
+	}
+
+	w := &watcher{
+		client:        c,
+		resourceTypes: resourceTypes,
+		minInterval:   defaultWatchMinInterval,
+		maxInterval:   defaultWatchMaxInterval,
+		differ:        newDiffer(),
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	if w.minInterval <= 0 || w.maxInterval < w.minInterval {
+		return nil, errors.Errorf("invalid watch interval [%s, %s]", w.minInterval, w.maxInterval)
+	}
+
+	// Without a stored sync token, a full sync builds the state to diff
+	// against, so that every change made after Watch returns is reported.
+	if w.syncToken == "" {
+		if _, _, err := w.poll(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	events := make(chan WatchEvent)
+	go w.run(ctx, events)
+
+	return events, nil
+}
+
+// watcher holds the state of a Watch call.
+type watcher struct {
+	client        *Client
+	resourceTypes []string
+
+	minInterval time.Duration
+	maxInterval time.Duration
+
+	syncToken string
+
+	// The last known version of each resource.
+	differ *differ
+}
+
+func (w *watcher) run(ctx context.Context, events chan<- WatchEvent) {
+	defer close(events)
+
+	interval := w.minInterval
+	for {
+		changes, resp, err := w.poll(ctx)
+		wait := interval
+
+		switch {
+		case err != nil:
+			if ctx.Err() != nil {
+				return
+			}
+
+			interval = w.grow(interval)
+			wait = interval
+
+			var tooManyRequests TooManyRequestsError
+			if errors.As(err, &tooManyRequests) {
+				w.client.Logf("watch: rate limited: %v\n", err)
+				if after := retryAfter(resp, err); after > wait {
+					wait = after
+				}
+			} else if !w.send(ctx, events, WatchEvent{Type: WatchError, Err: err, SyncToken: w.syncToken}) {
+				return
+			}
+
+		case len(changes) == 0:
+			interval = w.grow(interval)
+			wait = interval
+
+		default:
+			for _, change := range changes {
+				if !w.send(ctx, events, change) {
+					return
+				}
+			}
+			interval = w.minInterval
+			wait = interval
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+func (w *watcher) grow(interval time.Duration) time.Duration {
+	interval *= 2
+	if interval > w.maxInterval {
+		interval = w.maxInterval
+	}
+
+	return interval
+}
+
+func (w *watcher) send(ctx context.Context, events chan<- WatchEvent, event WatchEvent) bool {
+	select {
+	case events <- event:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// poll syncs once, updating the state and sync token, and returns the changes.
+func (w *watcher) poll(ctx context.Context) ([]WatchEvent, *http.Response, error) {
+	req, err := w.client.NewRequest(w.syncToken, w.resourceTypes, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var readResponse ReadResponse
+	resp, err := w.client.Do(ctx, req, &readResponse)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	w.syncToken = readResponse.SyncToken
+
+	changes := w.differ.diff(readResponse.Projects, readResponse.Sections, readResponse.Tasks, readResponse.FullSync)
+
+	for i := range changes {
+		changes[i].SyncToken = w.syncToken
+	}
+
+	return changes, resp, nil
+}
@@ -0,0 +1,143 @@
+package todoist
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ides15/todoist/todoisttest"
+)
+
+// nextEvent returns the next watch event, failing the test if none arrives.
+func nextEvent(t *testing.T, events <-chan WatchEvent) WatchEvent {
+	t.Helper()
+
+	select {
+	case event, ok := <-events:
+		if !ok {
+			t.Fatal("watch channel closed")
+		}
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a watch event")
+	}
+
+	return WatchEvent{}
+}
+
+func Test_Watch(t *testing.T) {
+	client, _ := newFakeClient(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := client.Watch(ctx, []string{"projects", "items"}, WatchInterval(time.Millisecond, 10*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, resp, err := client.Projects.Add(ctx, "", AddProject{Name: "Watched", TempID: "watched"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	projectID := ProjectID{resp.TempIDMapping["watched"]}
+
+	event := nextEvent(t, events)
+	if event.Type != WatchCreated || event.Project == nil || event.Project.ID != projectID {
+		t.Fatalf("expected the project to be created, received %+v", event)
+	}
+	if event.SyncToken == "" {
+		t.Error("expected the event to carry the sync token")
+	}
+
+	if _, _, err = client.Projects.Update(ctx, "", UpdateProject{ID: projectID, Name: "Renamed"}); err != nil {
+		t.Fatal(err)
+	}
+	event = nextEvent(t, events)
+	if event.Type != WatchUpdated || event.Project.Name != "Renamed" {
+		t.Fatalf("expected the project to be updated, received %+v", event)
+	}
+
+	_, resp, err = client.Tasks.Add(ctx, "", AddTask{Content: "Watched task", ProjectID: &projectID, TempID: "task"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	taskID := TaskID{resp.TempIDMapping["task"]}
+
+	event = nextEvent(t, events)
+	if event.Type != WatchCreated || event.Task == nil || event.Task.ID != taskID {
+		t.Fatalf("expected the task to be created, received %+v", event)
+	}
+
+	req, err := client.NewRequest("", nil, []Command{{Type: "item_close", Args: map[string]interface{}{"id": taskID}, UUID: "close"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.Do(ctx, req, &CommandResponse{}); err != nil {
+		t.Fatal(err)
+	}
+
+	event = nextEvent(t, events)
+	if event.Type != WatchCompleted || event.Task.ID != taskID {
+		t.Fatalf("expected the task to be completed, received %+v", event)
+	}
+
+	if _, _, err = client.Projects.Delete(ctx, "", DeleteProject{ID: projectID}); err != nil {
+		t.Fatal(err)
+	}
+
+	event = nextEvent(t, events)
+	if event.Type != WatchDeleted || event.Project == nil || event.Project.ID != projectID {
+		t.Fatalf("expected the project to be deleted, received %+v", event)
+	}
+
+	cancel()
+	for range events {
+	}
+}
+
+func Test_Watch_ResumeAndErrors(t *testing.T) {
+	client, srv := newFakeClient(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, resp, err := client.Projects.List(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err = client.Projects.Add(ctx, "", AddProject{Name: "Added while away"}); err != nil {
+		t.Fatal(err)
+	}
+
+	srv.InjectFault(todoisttest.Fault{Path: "sync", StatusCode: http.StatusTooManyRequests, Times: 1})
+	srv.InjectFault(todoisttest.Fault{Path: "sync", Times: 1})
+
+	events, err := client.Watch(ctx, nil, WatchSyncToken(resp.SyncToken), WatchInterval(time.Millisecond, 10*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The rate limit is waited out silently, the server error is reported.
+	event := nextEvent(t, events)
+	if event.Type != WatchError || event.Err == nil {
+		t.Fatalf("expected a watch error, received %+v", event)
+	}
+
+	event = nextEvent(t, events)
+	if event.Type != WatchCreated || event.Project == nil || event.Project.Name != "Added while away" {
+		t.Fatalf("expected the project added before resuming, received %+v", event)
+	}
+}
+
+func Test_Watch_InvalidArguments(t *testing.T) {
+	client, _ := newFakeClient(t)
+	ctx := context.Background()
+
+	if _, err := client.Watch(ctx, []string{"labels"}); err == nil {
+		t.Error("expected an error for an unsupported resource type")
+	}
+	if _, err := client.Watch(ctx, nil, WatchInterval(time.Second, time.Millisecond)); err == nil {
+		t.Error("expected an error for an invalid interval")
+	}
+}
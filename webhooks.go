@@ -0,0 +1,329 @@
+package todoist
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// WebhookSignatureHeader is the header carrying the base64 encoded
+	// HMAC-SHA256 of the request body, keyed with the application's client secret.
+	WebhookSignatureHeader = "X-Todoist-Hmac-SHA256"
+
+	// WebhookDeliveryIDHeader is the header carrying the ID of a webhook
+	// delivery. Redeliveries of the same event reuse the ID.
+	WebhookDeliveryIDHeader = "X-Todoist-Delivery-ID"
+
+	maxWebhookBodySize  = 1 << 20
+	webhookDeliveryKeep = 1024
+)
+
+// EventName is the name of a webhook event, such as "item:added".
+//
+// Todoist API docs: https://developer.todoist.com/sync/v8/#webhooks
+type EventName string
+
+// Webhook events, named after the resource (items are tasks, notes are
+// comments) and what happened to it.
+const (
+	EventItemAdded       EventName = "item:added"
+	EventItemUpdated     EventName = "item:updated"
+	EventItemDeleted     EventName = "item:deleted"
+	EventItemCompleted   EventName = "item:completed"
+	EventItemUncompleted EventName = "item:uncompleted"
+
+	EventNoteAdded   EventName = "note:added"
+	EventNoteUpdated EventName = "note:updated"
+	EventNoteDeleted EventName = "note:deleted"
+
+	EventProjectAdded      EventName = "project:added"
+	EventProjectUpdated    EventName = "project:updated"
+	EventProjectDeleted    EventName = "project:deleted"
+	EventProjectArchived   EventName = "project:archived"
+	EventProjectUnarchived EventName = "project:unarchived"
+
+	EventSectionAdded      EventName = "section:added"
+	EventSectionUpdated    EventName = "section:updated"
+	EventSectionDeleted    EventName = "section:deleted"
+	EventSectionArchived   EventName = "section:archived"
+	EventSectionUnarchived EventName = "section:unarchived"
+
+	EventLabelAdded   EventName = "label:added"
+	EventLabelDeleted EventName = "label:deleted"
+	EventLabelUpdated EventName = "label:updated"
+)
+
+// Resource returns the resource part of the event name, such as "item".
+func (n EventName) Resource() string {
+	if i := strings.IndexByte(string(n), ':'); i >= 0 {
+		return string(n)[:i]
+	}
+
+	return string(n)
+}
+
+// WebhookInitiator is the user who triggered a webhook event.
+type WebhookInitiator struct {
+	// The ID of the user.
+	ID UserID `json:"id"`
+
+	// The email of the user.
+	Email string `json:"email"`
+
+	// The full name of the user.
+	FullName string `json:"full_name"`
+
+	// The image ID of the user's avatar.
+	ImageID *string `json:"image_id"`
+
+	// Whether the user has a premium subscription.
+	IsPremium bool `json:"is_premium"`
+}
+
+// WebhookEvent is a webhook event delivered by Todoist. Depending on the
+// resource the event is about, one of Task, Project, Section, Comment or Label
+// holds the decoded event data.
+type WebhookEvent struct {
+	// The event name, such as "item:added".
+	Name EventName `json:"event_name"`
+
+	// The ID of the user that is the destination for the event.
+	UserID UserID `json:"user_id"`
+
+	// The user who triggered the event.
+	Initiator WebhookInitiator `json:"initiator"`
+
+	// The version of the webhook payload.
+	Version string `json:"version"`
+
+	// The raw event data.
+	Data json.RawMessage `json:"event_data"`
+
+	// The delivery ID from the X-Todoist-Delivery-ID header.
+	DeliveryID string `json:"-"`
+
+	// The decoded event data, for item:* events.
+	Task *Task `json:"-"`
+
+	// The decoded event data, for project:* events.
+	Project *Project `json:"-"`
+
+	// The decoded event data, for section:* events.
+	Section *Section `json:"-"`
+
+	// The decoded event data, for note:* events.
+	Comment *Comment `json:"-"`
+
+	// The decoded event data, for label:* events.
+	Label *Label `json:"-"`
+}
+
+// decodeData decodes the event data into the field matching the event's resource.
+func (e *WebhookEvent) decodeData() error {
+	var v interface{}
+	switch e.Name.Resource() {
+	case "item":
+		e.Task = &Task{}
+		v = e.Task
+	case "project":
+		e.Project = &Project{}
+		v = e.Project
+	case "section":
+		e.Section = &Section{}
+		v = e.Section
+	case "note":
+		e.Comment = &Comment{}
+		v = e.Comment
+	case "label":
+		e.Label = &Label{}
+		v = e.Label
+	default:
+		// Unknown resources are left in Data.
+		return nil
+	}
+
+	return errors.Wrapf(json.Unmarshal(e.Data, v), "unable to decode %s event data", e.Name)
+}
+
+// WebhookFunc is a callback for webhook events. Returning an error responds
+// to the delivery with a 500 status code, so that Todoist delivers it again.
+type WebhookFunc func(ctx context.Context, event *WebhookEvent) error
+
+// VerifyWebhookSignature reports whether signature (the value of the
+// X-Todoist-Hmac-SHA256 header) is valid for body and the application's
+// client secret.
+func VerifyWebhookSignature(clientSecret string, body []byte, signature string) bool {
+	got, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(clientSecret))
+	mac.Write(body)
+
+	return hmac.Equal(got, mac.Sum(nil))
+}
+
+// WebhookHandler is an http.Handler receiving Todoist webhooks. It verifies
+// the signature of every request, decodes the event, ignores deliveries it
+// has already handled, and dispatches the event to the registered callbacks.
+//
+// Todoist API docs: https://developer.todoist.com/sync/v8/#webhooks
+type WebhookHandler struct {
+	clientSecret string
+	logf         func(format string, args ...interface{})
+
+	mu           sync.Mutex
+	callbacks    map[EventName][]WebhookFunc
+	anyCallbacks []WebhookFunc
+
+	delivered     map[string]bool // Delivery IDs that have been handled, or are being handled.
+	deliveryOrder []string        // Delivery IDs in the order they were received, to bound delivered.
+}
+
+// NewWebhookHandler returns a WebhookHandler verifying requests with the
+// client secret of the Todoist application the webhooks are configured for.
+// Callback errors are logged with logf, such as a client's Logf, unless it is
+// nil.
+func NewWebhookHandler(clientSecret string, logf func(format string, args ...interface{})) *WebhookHandler {
+	return &WebhookHandler{
+		clientSecret: clientSecret,
+		logf:         logf,
+		callbacks:    map[EventName][]WebhookFunc{},
+		delivered:    map[string]bool{},
+	}
+}
+
+// On registers a callback for events with the given name. Callbacks are called
+// in the order they were registered.
+func (h *WebhookHandler) On(name EventName, fn WebhookFunc) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.callbacks[name] = append(h.callbacks[name], fn)
+}
+
+// OnAny registers a callback for every event, called after the callbacks
+// registered for the event's name.
+func (h *WebhookHandler) OnAny(fn WebhookFunc) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.anyCallbacks = append(h.anyCallbacks, fn)
+}
+
+// ServeHTTP implements http.Handler.
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBodySize))
+	if err != nil {
+		http.Error(w, "unable to read body", http.StatusBadRequest)
+		return
+	}
+
+	if !VerifyWebhookSignature(h.clientSecret, body, r.Header.Get(WebhookSignatureHeader)) {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	var event WebhookEvent
+	if err = json.Unmarshal(body, &event); err != nil {
+		http.Error(w, "invalid event", http.StatusBadRequest)
+		return
+	}
+	if err = event.decodeData(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	event.DeliveryID = r.Header.Get(WebhookDeliveryIDHeader)
+
+	if !h.startDelivery(event.DeliveryID) {
+		// Already handled: acknowledge so that Todoist stops redelivering.
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if err = h.dispatch(r.Context(), &event); err != nil {
+		h.forgetDelivery(event.DeliveryID)
+		if h.logf != nil {
+			h.logf("webhook %s: %v\n", event.Name, err)
+		}
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// dispatch calls the callbacks registered for the event, stopping at the
+// first error.
+func (h *WebhookHandler) dispatch(ctx context.Context, event *WebhookEvent) error {
+	h.mu.Lock()
+	callbacks := append(append([]WebhookFunc(nil), h.callbacks[event.Name]...), h.anyCallbacks...)
+	h.mu.Unlock()
+
+	for _, fn := range callbacks {
+		if err := fn(ctx, event); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// startDelivery records a delivery ID, reporting false if it was already
+// recorded. Deliveries without an ID are always handled.
+func (h *WebhookHandler) startDelivery(id string) bool {
+	if id == "" {
+		return true
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.delivered[id] {
+		return false
+	}
+
+	h.delivered[id] = true
+	h.deliveryOrder = append(h.deliveryOrder, id)
+	if len(h.deliveryOrder) > webhookDeliveryKeep {
+		delete(h.delivered, h.deliveryOrder[0])
+		h.deliveryOrder = h.deliveryOrder[1:]
+	}
+
+	return true
+}
+
+// forgetDelivery removes a delivery ID whose handling failed, so that the
+// redelivery is handled again.
+func (h *WebhookHandler) forgetDelivery(id string) {
+	if id == "" {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.delivered, id)
+	for i, d := range h.deliveryOrder {
+		if d == id {
+			h.deliveryOrder = append(h.deliveryOrder[:i], h.deliveryOrder[i+1:]...)
+			break
+		}
+	}
+}
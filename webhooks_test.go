@@ -0,0 +1,205 @@
+package todoist
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+const testWebhookSecret = "webhook-secret"
+
+func sign(body string) string {
+	mac := hmac.New(sha256.New, []byte(testWebhookSecret))
+	mac.Write([]byte(body))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func deliver(h http.Handler, body, signature, deliveryID string) int {
+	req := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(body))
+	req.Header.Set(WebhookSignatureHeader, signature)
+	if deliveryID != "" {
+		req.Header.Set(WebhookDeliveryIDHeader, deliveryID)
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	return rec.Code
+}
+
+const itemAddedEvent = `{
+	"event_name": "item:added",
+	"user_id": "2671355",
+	"event_data": {"id": "2995104339", "project_id": "2203306141", "content": "Buy Milk", "checked": false, "labels": [], "added_at": "2021-02-10T10:33:38.000000Z"},
+	"initiator": {"id": "2671355", "email": "alice@example.com", "full_name": "Alice", "is_premium": true},
+	"version": "9"
+}`
+
+func Test_WebhookHandler_Dispatch(t *testing.T) {
+	h := NewWebhookHandler(testWebhookSecret, nil)
+
+	var tasks []*Task
+	h.On(EventItemAdded, func(ctx context.Context, event *WebhookEvent) error {
+		tasks = append(tasks, event.Task)
+		return nil
+	})
+
+	var names []EventName
+	h.OnAny(func(ctx context.Context, event *WebhookEvent) error {
+		names = append(names, event.Name)
+		return nil
+	})
+
+	var projects []*Project
+	h.On(EventProjectUpdated, func(ctx context.Context, event *WebhookEvent) error {
+		projects = append(projects, event.Project)
+		return nil
+	})
+
+	if code := deliver(h, itemAddedEvent, sign(itemAddedEvent), "1"); code != http.StatusOK {
+		t.Fatalf("expected 200, received %d", code)
+	}
+
+	projectEvent := `{"event_name": "project:updated", "user_id": "2671355", "event_data": {"id": "2203306141", "name": "Shopping", "color": "red", "is_archived": false}, "initiator": {"id": "2671355"}, "version": "9"}`
+	if code := deliver(h, projectEvent, sign(projectEvent), "2"); code != http.StatusOK {
+		t.Fatalf("expected 200, received %d", code)
+	}
+
+	if len(tasks) != 1 || tasks[0].Content != "Buy Milk" || tasks[0].DateAdded != "2021-02-10T10:33:38.000000Z" {
+		t.Errorf("unexpected tasks %+v", tasks)
+	}
+	if len(projects) != 1 || projects[0].Name != "Shopping" || projects[0].Color != "red" {
+		t.Errorf("unexpected projects %+v", projects)
+	}
+	if len(names) != 2 || names[0] != EventItemAdded || names[1] != EventProjectUpdated {
+		t.Errorf("expected OnAny to receive both events, received %v", names)
+	}
+}
+
+func Test_WebhookHandler_Signature(t *testing.T) {
+	h := NewWebhookHandler(testWebhookSecret, nil)
+
+	called := false
+	h.OnAny(func(ctx context.Context, event *WebhookEvent) error {
+		called = true
+		return nil
+	})
+
+	tests := []struct {
+		name      string
+		signature string
+	}{
+		{name: "missing", signature: ""},
+		{name: "not base64", signature: "%%%"},
+		{name: "wrong secret", signature: base64.StdEncoding.EncodeToString([]byte("wrong"))},
+		{name: "other body", signature: sign(itemAddedEvent + " ")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := deliver(h, itemAddedEvent, tt.signature, ""); code != http.StatusUnauthorized {
+				t.Errorf("expected 401, received %d", code)
+			}
+		})
+	}
+
+	if called {
+		t.Error("expected no callbacks for unsigned deliveries")
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/webhooks", nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405 for GET, received %d", rec.Code)
+	}
+}
+
+func Test_WebhookHandler_Dedupe(t *testing.T) {
+	h := NewWebhookHandler(testWebhookSecret, nil)
+
+	calls := 0
+	failNext := true
+	h.On(EventItemAdded, func(ctx context.Context, event *WebhookEvent) error {
+		calls++
+		if failNext {
+			failNext = false
+			return errors.New("temporary failure")
+		}
+		return nil
+	})
+
+	signature := sign(itemAddedEvent)
+
+	if code := deliver(h, itemAddedEvent, signature, "delivery-1"); code != http.StatusInternalServerError {
+		t.Fatalf("expected 500 for a failed callback, received %d", code)
+	}
+	if code := deliver(h, itemAddedEvent, signature, "delivery-1"); code != http.StatusOK {
+		t.Fatalf("expected the redelivery of a failed delivery to be handled, received %d", code)
+	}
+	if code := deliver(h, itemAddedEvent, signature, "delivery-1"); code != http.StatusOK {
+		t.Fatalf("expected 200 for a duplicate delivery, received %d", code)
+	}
+
+	if len(h.deliveryOrder) != 1 {
+		t.Errorf("expected 1 remembered delivery after a redelivery, received %d", len(h.deliveryOrder))
+	}
+
+	if calls != 2 {
+		t.Errorf("expected 2 calls (failure and redelivery), received %d", calls)
+	}
+
+	if code := deliver(h, itemAddedEvent, signature, "delivery-2"); code != http.StatusOK || calls != 3 {
+		t.Errorf("expected a new delivery to be handled, received %d after %d calls", code, calls)
+	}
+}
+
+func Test_WebhookHandler_DedupeBounded(t *testing.T) {
+	h := NewWebhookHandler(testWebhookSecret, nil)
+
+	for i := 0; i < webhookDeliveryKeep+10; i++ {
+		h.startDelivery(strconv.Itoa(i))
+	}
+
+	if len(h.delivered) != webhookDeliveryKeep || len(h.deliveryOrder) != webhookDeliveryKeep {
+		t.Errorf("expected %d remembered deliveries, received %d", webhookDeliveryKeep, len(h.delivered))
+	}
+}
+
+func Test_WebhookHandler_CallbackError(t *testing.T) {
+	var logged []string
+	logf := func(format string, args ...interface{}) {
+		logged = append(logged, fmt.Sprintf(format, args...))
+	}
+
+	for _, h := range []*WebhookHandler{NewWebhookHandler(testWebhookSecret, nil), NewWebhookHandler(testWebhookSecret, logf)} {
+		h.On(EventItemAdded, func(ctx context.Context, event *WebhookEvent) error {
+			return errors.New("database password is hunter2")
+		})
+
+		req := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(itemAddedEvent))
+		req.Header.Set(WebhookSignatureHeader, sign(itemAddedEvent))
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusInternalServerError {
+			t.Errorf("expected 500 for a failed callback, received %d", rec.Code)
+		}
+		if strings.Contains(rec.Body.String(), "hunter2") {
+			t.Errorf("expected the callback error not to be sent, received %q", rec.Body.String())
+		}
+	}
+
+	if len(logged) != 1 || !strings.Contains(logged[0], "hunter2") {
+		t.Errorf("expected the callback error to be logged once, received %q", logged)
+	}
+}